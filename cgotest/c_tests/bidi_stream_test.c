@@ -87,7 +87,9 @@ static void on_read_native(uint64_t call_id, void *result_ptr, int result_len, F
 }
 
 int main(void) {
-    uint64_t rc = Ygrpc_SetProtocol(YGRPC_PROTOCOL_UNSET);
+    ygrpc_expect_err0_i64(Ygrpc_Init(YGRPC_DEFAULT_RUNTIME, NULL, 0), "Ygrpc_Init");
+
+    uint64_t rc = Ygrpc_SetProtocol(YGRPC_DEFAULT_RUNTIME, YGRPC_PROTOCOL_UNSET);
     ygrpc_expect_err0_i64(rc, "Ygrpc_SetProtocol");
 
     // Binary bidi-streaming
@@ -99,7 +101,7 @@ int main(void) {
         g_bidi_call_id = 0;
 
         GoUint64 handle = 0;
-        uint64_t err_id = Ygrpc_StreamService_BidiStreamCallStart((void *)on_read_bytes, (void *)on_done, &handle);
+        uint64_t err_id = Ygrpc_StreamService_BidiStreamCallStart(YGRPC_DEFAULT_RUNTIME, (void *)on_read_bytes, (void *)on_done, &handle);
         YGRPC_ASSERTF(err_id == 0 && handle != 0, "BidiStart failed: err=%" PRIu64 " handle=%llu\n", err_id, (unsigned long long)handle);
 
         const char* msgs[] = {"X", "Y", "Z"};
@@ -141,7 +143,7 @@ int main(void) {
         g_bidi_call_id = 0;
 
         uint64_t handle = 0;
-        uint64_t err_id = Ygrpc_StreamService_BidiStreamCallStart_Native((void *)on_read_native, (void *)on_done, &handle);
+        uint64_t err_id = Ygrpc_StreamService_BidiStreamCallStart_Native(YGRPC_DEFAULT_RUNTIME, (void *)on_read_native, (void *)on_done, &handle);
         YGRPC_ASSERTF(err_id == 0 && handle != 0, "BidiStart_Native failed: err=%" PRIu64 " handle=%llu\n", err_id, (unsigned long long)handle);
 
         const char* msgs[] = {"X", "Y", "Z"};
@@ -16,8 +16,20 @@ static void counting_free(void* p) {
     free(p);
 }
 
+static void test_invalid_handle(void) {
+    uint8_t req_buf[1] = {0};
+    uint64_t err_id = Ygrpc_StreamService_ClientStreamCallSend(0xFFFFFFFFu, req_buf, 0);
+    YGRPC_ASSERTF(err_id != 0, "expected error for invalid stream handle\n");
+    YGRPC_ASSERTF(Ygrpc_ErrorIs(err_id, YGRPC_ERR_KIND_INVALID_STREAM_HANDLE) == 1,
+                  "expected YGRPC_ERR_KIND_INVALID_STREAM_HANDLE for err_id=%" PRIu64 "\n", err_id);
+    YGRPC_ASSERTF(Ygrpc_ErrorIs(err_id, YGRPC_ERR_KIND_SERVICE_NOT_REGISTERED) == 0,
+                  "unexpected YGRPC_ERR_KIND_SERVICE_NOT_REGISTERED for err_id=%" PRIu64 "\n", err_id);
+}
+
 int main(void) {
-    uint64_t rc = Ygrpc_SetProtocol(YGRPC_PROTOCOL_UNSET);
+    ygrpc_expect_err0_i64(Ygrpc_Init(YGRPC_DEFAULT_RUNTIME, NULL, 0), "Ygrpc_Init");
+
+    uint64_t rc = Ygrpc_SetProtocol(YGRPC_DEFAULT_RUNTIME, YGRPC_PROTOCOL_UNSET);
     if (rc != 0)
     {
         fprintf(stderr, "Ygrpc_SetProtocol failed: %" PRIu64 "\n", rc);
@@ -26,7 +38,7 @@ int main(void) {
 
     {
         GoUint64 handle = 0;
-        uint64_t err_id = Ygrpc_StreamService_ClientStreamCallStart_Native(&handle);
+        uint64_t err_id = Ygrpc_StreamService_ClientStreamCallStart_Native(YGRPC_DEFAULT_RUNTIME, &handle);
         if (err_id != 0 || handle == 0) {
             fprintf(stderr, "Start_Native failed: err=%" PRIu64 " handle=%llu\n", err_id, (unsigned long long)handle);
             return 1;
@@ -74,7 +86,7 @@ int main(void) {
 
     {
         GoUint64 handle = 0;
-        uint64_t err_id = Ygrpc_StreamService_ClientStreamCallStart_Native(&handle);
+        uint64_t err_id = Ygrpc_StreamService_ClientStreamCallStart_Native(YGRPC_DEFAULT_RUNTIME, &handle);
         if (err_id != 0 || handle == 0) {
             fprintf(stderr, "Start_Native failed: err=%" PRIu64 " handle=%llu\n", err_id, (unsigned long long)handle);
             return 1;
@@ -106,7 +118,7 @@ int main(void) {
 
     {
         GoUint64 handle = 0;
-        uint64_t err_id = Ygrpc_StreamService_ClientStreamCallStart_Native(&handle);
+        uint64_t err_id = Ygrpc_StreamService_ClientStreamCallStart_Native(YGRPC_DEFAULT_RUNTIME, &handle);
         if (err_id != 0 || handle == 0) {
             fprintf(stderr, "Start_Native failed: err=%" PRIu64 " handle=%llu\n", err_id, (unsigned long long)handle);
             return 1;
@@ -141,6 +153,8 @@ int main(void) {
         if (out_result_free) out_result_free(out_result);
     }
 
+    test_invalid_handle();
+
     printf("client_stream_test OK\n");
     return 0;
 }
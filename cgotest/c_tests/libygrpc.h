@@ -21,7 +21,7 @@ extern const char *_GoStringPtr(_GoString_ s);
 /* Start of preamble from import "C" comments.  */
 
 
-#line 7 "cgo_helpers.go"
+#line 11 "cgo_helpers.go"
 
 #include "ygrpc_cgo_common.h"
 
@@ -142,41 +142,51 @@ extern "C" {
 #endif
 
 extern void Ygrpc_Free(void* ptr);
-extern GoUint64 Ygrpc_SetProtocol(GoInt protocol);
+extern GoUint64 Ygrpc_NewRuntime(GoUint64* outRuntime);
+extern GoUint64 Ygrpc_Init(GoUint64 rtHandle, void* configPtr, GoInt configLen);
+extern GoUint64 Ygrpc_SetProtocol(GoUint64 rtHandle, GoInt protocol);
+extern GoUint64 Ygrpc_SetProtocolPreference(GoUint64 rtHandle, char* target, int targetLen, int* protocols, int protocolsLen);
 extern GoUint64 Ygrpc_GetErrorMsg(GoUint64 errorID, void** msgPtr, GoInt* msgLen, void** msgFree);
-extern GoUint64 Ygrpc_StreamService_UnaryCall(void* reqPtr, GoInt reqLen, void** respPtr, GoInt* respLen, void** respFree);
-extern GoUint64 Ygrpc_StreamService_UnaryCall_TakeReq(void* reqPtr, GoInt reqLen, void* reqFree, void** respPtr, GoInt* respLen, void** respFree);
-extern uint64_t Ygrpc_StreamService_UnaryCall_Native(char* req_data, int req_data_len, int32_t req_sequence, char** resp_result, int* resp_result_len, FreeFunc* resp_result_free, int32_t* resp_sequence);
-extern uint64_t Ygrpc_StreamService_UnaryCall_Native_TakeReq(char* req_data, int req_data_len, FreeFunc req_data_free, int32_t req_sequence, char** resp_result, int* resp_result_len, FreeFunc* resp_result_free, int32_t* resp_sequence);
-extern GoUint64 Ygrpc_StreamService_ClientStreamCallStart(GoUint64* outHandle);
+extern GoInt Ygrpc_ErrorIs(GoUint64 errorID, GoInt kind);
+extern void Ygrpc_SetLogCallback(GoInt level, void* fn);
+extern void Ygrpc_SetErrorHook(void* fn);
+extern GoUint64 Ygrpc_SetRegistryWatcher(GoUint64 rtHandle, void* fn);
+extern GoUint64 Ygrpc_Shutdown(GoUint64 rtHandle, GoInt timeoutMs);
+extern GoUint64 Ygrpc_GetLastError(void);
+extern void Ygrpc_ClearLastError(void);
+extern GoUint64 Ygrpc_StreamService_UnaryCall(GoUint64 rtHandle, void* reqPtr, GoInt reqLen, void** respPtr, GoInt* respLen, void** respFree);
+extern GoUint64 Ygrpc_StreamService_UnaryCall_TakeReq(GoUint64 rtHandle, void* reqPtr, GoInt reqLen, void* reqFree, void** respPtr, GoInt* respLen, void** respFree);
+extern uint64_t Ygrpc_StreamService_UnaryCall_Native(GoUint64 rtHandle, char* req_data, int req_data_len, int32_t req_sequence, char** resp_result, int* resp_result_len, FreeFunc* resp_result_free, int32_t* resp_sequence);
+extern uint64_t Ygrpc_StreamService_UnaryCall_Native_TakeReq(GoUint64 rtHandle, char* req_data, int req_data_len, FreeFunc req_data_free, int32_t req_sequence, char** resp_result, int* resp_result_len, FreeFunc* resp_result_free, int32_t* resp_sequence);
+extern GoUint64 Ygrpc_StreamService_ClientStreamCallStart(GoUint64 rtHandle, GoUint64* outHandle);
 extern uint64_t Ygrpc_StreamService_ClientStreamCallSend(uint64_t streamHandle, void* reqPtr, int reqLen);
 extern uint64_t Ygrpc_StreamService_ClientStreamCallSend_TakeReq(uint64_t streamHandle, void* reqPtr, int reqLen, FreeFunc reqFree);
 extern GoUint64 Ygrpc_StreamService_ClientStreamCallFinish(GoUint64 streamHandle, void** respPtr, GoInt* respLen, void** respFree);
-extern GoUint64 Ygrpc_StreamService_ClientStreamCallStart_Native(GoUint64* outHandle);
+extern GoUint64 Ygrpc_StreamService_ClientStreamCallStart_Native(GoUint64 rtHandle, GoUint64* outHandle);
 extern uint64_t Ygrpc_StreamService_ClientStreamCallSend_Native(uint64_t streamHandle, char* req_data, int req_data_len, int32_t req_sequence);
 extern uint64_t Ygrpc_StreamService_ClientStreamCallSend_Native_TakeReq(uint64_t streamHandle, char* req_data, int req_data_len, FreeFunc req_data_free, int32_t req_sequence);
 extern uint64_t Ygrpc_StreamService_ClientStreamCallFinish_Native(uint64_t streamHandle, char** resp_result, int* resp_result_len, FreeFunc* resp_result_free, int32_t* resp_sequence);
-extern GoUint64 Ygrpc_StreamService_ServerStreamCall(void* reqPtr, GoInt reqLen, void* onReadBytes, void* onDone, GoUint64 callID);
-extern GoUint64 Ygrpc_StreamService_ServerStreamCall_TakeReq(void* reqPtr, GoInt reqLen, void* reqFree, void* onReadBytes, void* onDone, GoUint64 callID);
-extern uint64_t Ygrpc_StreamService_ServerStreamCall_Native(char* req_data, int req_data_len, int32_t req_sequence, void* onReadNative, void* onDone, uint64_t callID);
-extern uint64_t Ygrpc_StreamService_ServerStreamCall_Native_TakeReq(char* req_data, int req_data_len, FreeFunc req_data_free, int32_t req_sequence, void* onReadNative, void* onDone, uint64_t callID);
-extern GoUint64 Ygrpc_StreamService_BidiStreamCallStart(void* onReadBytes, void* onDone, GoUint64* outHandle);
+extern GoUint64 Ygrpc_StreamService_ServerStreamCall(GoUint64 rtHandle, void* reqPtr, GoInt reqLen, void* onReadBytes, void* onDone, GoUint64 callID);
+extern GoUint64 Ygrpc_StreamService_ServerStreamCall_TakeReq(GoUint64 rtHandle, void* reqPtr, GoInt reqLen, void* reqFree, void* onReadBytes, void* onDone, GoUint64 callID);
+extern uint64_t Ygrpc_StreamService_ServerStreamCall_Native(GoUint64 rtHandle, char* req_data, int req_data_len, int32_t req_sequence, void* onReadNative, void* onDone, uint64_t callID);
+extern uint64_t Ygrpc_StreamService_ServerStreamCall_Native_TakeReq(GoUint64 rtHandle, char* req_data, int req_data_len, FreeFunc req_data_free, int32_t req_sequence, void* onReadNative, void* onDone, uint64_t callID);
+extern GoUint64 Ygrpc_StreamService_BidiStreamCallStart(GoUint64 rtHandle, void* onReadBytes, void* onDone, GoUint64* outHandle);
 extern GoUint64 Ygrpc_StreamService_BidiStreamCallSend(GoUint64 streamHandle, void* reqPtr, GoInt reqLen);
 extern GoUint64 Ygrpc_StreamService_BidiStreamCallSend_TakeReq(GoUint64 streamHandle, void* reqPtr, GoInt reqLen, void* reqFree);
 extern GoUint64 Ygrpc_StreamService_BidiStreamCallCloseSend(GoUint64 streamHandle);
-extern uint64_t Ygrpc_StreamService_BidiStreamCallStart_Native(void* onReadNative, void* onDone, uint64_t* outHandle);
+extern uint64_t Ygrpc_StreamService_BidiStreamCallStart_Native(GoUint64 rtHandle, void* onReadNative, void* onDone, uint64_t* outHandle);
 extern uint64_t Ygrpc_StreamService_BidiStreamCallSend_Native(uint64_t streamHandle, char* req_data, int req_data_len, int32_t req_sequence);
 extern uint64_t Ygrpc_StreamService_BidiStreamCallSend_Native_TakeReq(uint64_t streamHandle, char* req_data, int req_data_len, FreeFunc req_data_free, int32_t req_sequence);
 extern GoUint64 Ygrpc_StreamService_BidiStreamCallCloseSend_Native(GoUint64 streamHandle);
-extern GoUint64 Ygrpc_TestService_Ping(void* reqPtr, GoInt reqLen, void** respPtr, GoInt* respLen, void** respFree);
-extern GoUint64 Ygrpc_TestService_Ping_TakeReq(void* reqPtr, GoInt reqLen, void* reqFree, void** respPtr, GoInt* respLen, void** respFree);
-extern uint64_t Ygrpc_TestService_Ping_Native(char* req_msg, int req_msg_len, char** resp_msg, int* resp_msg_len, FreeFunc* resp_msg_free);
-extern uint64_t Ygrpc_TestService_Ping_Native_TakeReq(char* req_msg, int req_msg_len, FreeFunc req_msg_free, char** resp_msg, int* resp_msg_len, FreeFunc* resp_msg_free);
-extern GoUint64 Ygrpc_TestService_PingOpt1_TakeReq(void* reqPtr, GoInt reqLen, void* reqFree, void** respPtr, GoInt* respLen, void** respFree);
-extern uint64_t Ygrpc_TestService_PingOpt1_Native_TakeReq(char* req_msg, int req_msg_len, FreeFunc req_msg_free, char** resp_msg, int* resp_msg_len, FreeFunc* resp_msg_free);
-extern GoUint64 Ygrpc_TestService_PingOpt2(void* reqPtr, GoInt reqLen, void** respPtr, GoInt* respLen, void** respFree);
-extern GoUint64 Ygrpc_TestService_NonFlat(void* reqPtr, GoInt reqLen, void** respPtr, GoInt* respLen, void** respFree);
-extern GoUint64 Ygrpc_TestService_NonFlat_TakeReq(void* reqPtr, GoInt reqLen, void* reqFree, void** respPtr, GoInt* respLen, void** respFree);
+extern GoUint64 Ygrpc_TestService_Ping(GoUint64 rtHandle, void* reqPtr, GoInt reqLen, void** respPtr, GoInt* respLen, void** respFree);
+extern GoUint64 Ygrpc_TestService_Ping_TakeReq(GoUint64 rtHandle, void* reqPtr, GoInt reqLen, void* reqFree, void** respPtr, GoInt* respLen, void** respFree);
+extern uint64_t Ygrpc_TestService_Ping_Native(GoUint64 rtHandle, char* req_msg, int req_msg_len, char** resp_msg, int* resp_msg_len, FreeFunc* resp_msg_free);
+extern uint64_t Ygrpc_TestService_Ping_Native_TakeReq(GoUint64 rtHandle, char* req_msg, int req_msg_len, FreeFunc req_msg_free, char** resp_msg, int* resp_msg_len, FreeFunc* resp_msg_free);
+extern GoUint64 Ygrpc_TestService_PingOpt1_TakeReq(GoUint64 rtHandle, void* reqPtr, GoInt reqLen, void* reqFree, void** respPtr, GoInt* respLen, void** respFree);
+extern uint64_t Ygrpc_TestService_PingOpt1_Native_TakeReq(GoUint64 rtHandle, char* req_msg, int req_msg_len, FreeFunc req_msg_free, char** resp_msg, int* resp_msg_len, FreeFunc* resp_msg_free);
+extern GoUint64 Ygrpc_TestService_PingOpt2(GoUint64 rtHandle, void* reqPtr, GoInt reqLen, void** respPtr, GoInt* respLen, void** respFree);
+extern GoUint64 Ygrpc_TestService_NonFlat(GoUint64 rtHandle, void* reqPtr, GoInt reqLen, void** respPtr, GoInt* respLen, void** respFree);
+extern GoUint64 Ygrpc_TestService_NonFlat_TakeReq(GoUint64 rtHandle, void* reqPtr, GoInt reqLen, void* reqFree, void** respPtr, GoInt* respLen, void** respFree);
 
 #ifdef __cplusplus
 }
@@ -58,7 +58,9 @@ static void on_read_native(uint64_t call_id, void *result_ptr, int result_len, F
 }
 
 int main(void) {
-    uint64_t rc = Ygrpc_SetProtocol(YGRPC_PROTOCOL_UNSET);
+    ygrpc_expect_err0_i64(Ygrpc_Init(YGRPC_DEFAULT_RUNTIME, NULL, 0), "Ygrpc_Init");
+
+    uint64_t rc = Ygrpc_SetProtocol(YGRPC_DEFAULT_RUNTIME, YGRPC_PROTOCOL_UNSET);
     ygrpc_expect_err0_i64(rc, "Ygrpc_SetProtocol");
 
     // Binary server-streaming
@@ -79,7 +81,7 @@ int main(void) {
         int req_len = (int)ostream.bytes_written;
 
         uint64_t call_id = (uint64_t)(uintptr_t)&st;
-        uint64_t err_id = Ygrpc_StreamService_ServerStreamCall(req_buf, req_len, (void *)on_read_bytes, (void *)on_done, call_id);
+        uint64_t err_id = Ygrpc_StreamService_ServerStreamCall(YGRPC_DEFAULT_RUNTIME, req_buf, req_len, (void *)on_read_bytes, (void *)on_done, call_id);
 
         ygrpc_expect_err0_i64(err_id, "ServerStreamCall");
         YGRPC_ASSERTF(st.done && st.done_error_id == 0, "expected done with error=0, got done=%d err=%" PRIu64 "\n", st.done, st.done_error_id);
@@ -93,7 +95,7 @@ int main(void) {
         stream_state st;
         memset(&st, 0, sizeof(st));
 
-        uint64_t err_id = Ygrpc_StreamService_ServerStreamCall_Native(
+        uint64_t err_id = Ygrpc_StreamService_ServerStreamCall_Native(YGRPC_DEFAULT_RUNTIME, 
             (char *)"test", 4, (int32_t)7,
             (void *)on_read_native,
             (void *)on_done,
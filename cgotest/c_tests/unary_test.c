@@ -16,18 +16,240 @@ static void counting_free(void* p) {
     free(p);
 }
 
+static uint64_t g_hook_error_id = 0;
+static void on_error_hook(uint64_t error_id, const char* msg, int msg_len) {
+    (void)msg;
+    if (msg_len > 0) {
+        g_hook_error_id = error_id;
+    }
+}
+
+static int g_registry_seen = 0;
+static void on_registry_event(int kind, int protocol, const char* service_name, int service_name_len) {
+    const char* want = "cgotest.TestService";
+    if (kind == YGRPC_REGISTRY_REGISTERED &&
+        (protocol == YGRPC_PROTOCOL_GRPC || protocol == YGRPC_PROTOCOL_CONNECTRPC) &&
+        service_name_len == (int)strlen(want) && memcmp(service_name, want, (size_t)service_name_len) == 0) {
+        g_registry_seen = 1;
+    }
+}
+
+static void test_registry_watcher(void) {
+    Ygrpc_SetRegistryWatcher(YGRPC_DEFAULT_RUNTIME, (void*)on_registry_event);
+    Ygrpc_SetRegistryWatcher(YGRPC_DEFAULT_RUNTIME, NULL);
+    if (!g_registry_seen) {
+        fprintf(stderr, "expected registry watcher to replay cgotest.TestService\n");
+        abort();
+    }
+}
+
+static void test_init(void) {
+    uint8_t empty[1] = {0};
+    void* resp_ptr = NULL;
+    GoInt resp_len = 0;
+    void* resp_free = NULL;
+
+    uint64_t err_id = Ygrpc_TestService_Ping(YGRPC_DEFAULT_RUNTIME, empty, 0, &resp_ptr, &resp_len, &resp_free);
+    if (err_id == 0 || !Ygrpc_ErrorIs(err_id, YGRPC_ERR_KIND_NOT_INITIALIZED)) {
+        fprintf(stderr, "expected not-initialized error before Ygrpc_Init, got %" PRIu64 "\n", err_id);
+        abort();
+    }
+
+    const char* config = "{}";
+    uint64_t rc = Ygrpc_Init(YGRPC_DEFAULT_RUNTIME, (void*)config, (GoInt)strlen(config));
+    if (rc != 0) {
+        fprintf(stderr, "Ygrpc_Init failed: %" PRIu64 "\n", rc);
+        abort();
+    }
+    if (Ygrpc_Init(YGRPC_DEFAULT_RUNTIME, NULL, 0) == 0) {
+        fprintf(stderr, "expected second Ygrpc_Init to fail\n");
+        abort();
+    }
+}
+
+static void test_protocol_preference(void) {
+    const char* service = "cgotest.TestService";
+    int order[] = {YGRPC_PROTOCOL_CONNECTRPC, YGRPC_PROTOCOL_GRPC};
+    uint64_t rc = Ygrpc_SetProtocolPreference(YGRPC_DEFAULT_RUNTIME, (char*)service, (int)strlen(service), order, 2);
+    if (rc != 0) {
+        fprintf(stderr, "Ygrpc_SetProtocolPreference failed: %" PRIu64 "\n", rc);
+        abort();
+    }
+
+    int bad[] = {42};
+    rc = Ygrpc_SetProtocolPreference(YGRPC_DEFAULT_RUNTIME, NULL, 0, bad, 1);
+    if (rc == 0 || !Ygrpc_ErrorIs(rc, YGRPC_ERR_KIND_UNKNOWN_PROTOCOL)) {
+        fprintf(stderr, "expected unknown-protocol error, got %" PRIu64 "\n", rc);
+        abort();
+    }
+
+    const char* msg = "pref";
+    cgotest_PingRequest req = cgotest_PingRequest_init_zero;
+    strncpy(req.msg, msg, sizeof(req.msg) - 1);
+    uint8_t req_buf[cgotest_PingRequest_size];
+    pb_ostream_t ostream = pb_ostream_from_buffer(req_buf, sizeof(req_buf));
+    if (!pb_encode(&ostream, cgotest_PingRequest_fields, &req)) {
+        fprintf(stderr, "pb_encode PingRequest failed: %s\n", PB_GET_ERROR(&ostream));
+        abort();
+    }
+
+    void* resp_ptr = NULL;
+    GoInt resp_len = 0;
+    void* resp_free = NULL;
+    uint64_t err_id = Ygrpc_TestService_Ping(YGRPC_DEFAULT_RUNTIME, req_buf, (int)ostream.bytes_written, &resp_ptr, &resp_len, &resp_free);
+    ygrpc_expect_err0_i64(err_id, "Ping with protocol preference");
+    call_free_func((FreeFunc)resp_free, resp_ptr);
+
+    rc = Ygrpc_SetProtocolPreference(YGRPC_DEFAULT_RUNTIME, (char*)service, (int)strlen(service), NULL, 0);
+    if (rc != 0) {
+        fprintf(stderr, "clearing protocol preference failed: %" PRIu64 "\n", rc);
+        abort();
+    }
+
+    const char* method = "/cgotest.TestService/Ping";
+    rc = Ygrpc_SetProtocolPreference(YGRPC_DEFAULT_RUNTIME, (char*)method, (int)strlen(method), order, 1);
+    rc |= Ygrpc_SetProtocolPreference(YGRPC_DEFAULT_RUNTIME, (char*)method, (int)strlen(method), NULL, 0);
+    if (rc != 0) {
+        fprintf(stderr, "method protocol route failed: %" PRIu64 "\n", rc);
+        abort();
+    }
+}
+
+static void test_protocol_go(void) {
+    uint64_t rc = Ygrpc_SetProtocol(YGRPC_DEFAULT_RUNTIME, YGRPC_PROTOCOL_GO);
+    rc |= Ygrpc_SetProtocol(YGRPC_DEFAULT_RUNTIME, YGRPC_PROTOCOL_UNSET);
+    if (rc != 0) {
+        fprintf(stderr, "Ygrpc_SetProtocol(YGRPC_PROTOCOL_GO) failed: %" PRIu64 "\n", rc);
+        abort();
+    }
+
+    int order[] = {YGRPC_PROTOCOL_GO, YGRPC_PROTOCOL_GRPC, YGRPC_PROTOCOL_CONNECTRPC};
+    rc = Ygrpc_SetProtocolPreference(YGRPC_DEFAULT_RUNTIME, NULL, 0, order, 3);
+    rc |= Ygrpc_SetProtocolPreference(YGRPC_DEFAULT_RUNTIME, NULL, 0, NULL, 0);
+    if (rc != 0) {
+        fprintf(stderr, "protocol preference with YGRPC_PROTOCOL_GO failed: %" PRIu64 "\n", rc);
+        abort();
+    }
+}
+
+static void test_protocol_twirp(void) {
+    uint64_t rc = Ygrpc_SetProtocol(YGRPC_DEFAULT_RUNTIME, YGRPC_PROTOCOL_TWIRP);
+    rc |= Ygrpc_SetProtocol(YGRPC_DEFAULT_RUNTIME, YGRPC_PROTOCOL_UNSET);
+    if (rc != 0) {
+        fprintf(stderr, "Ygrpc_SetProtocol(YGRPC_PROTOCOL_TWIRP) failed: %" PRIu64 "\n", rc);
+        abort();
+    }
+
+    int order[] = {YGRPC_PROTOCOL_TWIRP, YGRPC_PROTOCOL_GRPC};
+    rc = Ygrpc_SetProtocolPreference(YGRPC_DEFAULT_RUNTIME, NULL, 0, order, 2);
+    rc |= Ygrpc_SetProtocolPreference(YGRPC_DEFAULT_RUNTIME, NULL, 0, NULL, 0);
+    if (rc != 0) {
+        fprintf(stderr, "protocol preference with YGRPC_PROTOCOL_TWIRP failed: %" PRIu64 "\n", rc);
+        abort();
+    }
+}
+
+static uint64_t ping_native(uint64_t rt) {
+    const char* msg = "iso";
+    char* out_msg = NULL;
+    int out_len = 0;
+    FreeFunc out_free = NULL;
+
+    uint64_t err_id = Ygrpc_TestService_Ping_Native(rt, (char*)msg, (int)strlen(msg), &out_msg, &out_len, &out_free);
+    if (err_id == 0) {
+        ygrpc_expect_eq_str(out_msg, out_len, "pong: iso");
+        out_free(out_msg);
+    }
+    return err_id;
+}
+
+static void test_isolated_runtimes(void) {
+    GoUint64 rt_a = 0;
+    GoUint64 rt_b = 0;
+    ygrpc_expect_err0_i64(Ygrpc_NewRuntime(&rt_a), "Ygrpc_NewRuntime");
+    ygrpc_expect_err0_i64(Ygrpc_NewRuntime(&rt_b), "Ygrpc_NewRuntime");
+    if (rt_a == YGRPC_DEFAULT_RUNTIME || rt_b == YGRPC_DEFAULT_RUNTIME || rt_a == rt_b) {
+        fprintf(stderr, "expected distinct runtime handles, got %llu and %llu\n", (unsigned long long)rt_a, (unsigned long long)rt_b);
+        abort();
+    }
+
+    // Both new runtimes start uninitialized although the default one is initialized.
+    uint64_t err_id = ping_native(rt_a);
+    if (err_id == 0 || !Ygrpc_ErrorIs(err_id, YGRPC_ERR_KIND_NOT_INITIALIZED)) {
+        fprintf(stderr, "expected not-initialized error from a new runtime, got %" PRIu64 "\n", err_id);
+        abort();
+    }
+    ygrpc_expect_err0_i64(Ygrpc_Init(rt_a, NULL, 0), "Ygrpc_Init(rt_a)");
+    ygrpc_expect_err0_i64(ping_native(rt_a), "Ping on rt_a");
+    err_id = ping_native(rt_b);
+    if (err_id == 0 || !Ygrpc_ErrorIs(err_id, YGRPC_ERR_KIND_NOT_INITIALIZED)) {
+        fprintf(stderr, "expected rt_b to stay uninitialized, got %" PRIu64 "\n", err_id);
+        abort();
+    }
+
+    // Settings of one runtime do not leak into another.
+    ygrpc_expect_err0_i64(Ygrpc_SetProtocol(rt_a, YGRPC_PROTOCOL_TWIRP), "Ygrpc_SetProtocol(rt_a)");
+    if (ping_native(rt_a) == 0) {
+        fprintf(stderr, "expected rt_a to have no Twirp handler\n");
+        abort();
+    }
+    ygrpc_expect_err0_i64(ping_native(YGRPC_DEFAULT_RUNTIME), "Ping on the default runtime");
+
+    // Shutting down a runtime releases its handle and leaves the others running.
+    ygrpc_expect_err0_i64(Ygrpc_Shutdown(rt_a, 1000), "Ygrpc_Shutdown(rt_a)");
+    err_id = ping_native(rt_a);
+    if (err_id == 0 || !Ygrpc_ErrorIs(err_id, YGRPC_ERR_KIND_INVALID_RUNTIME_HANDLE)) {
+        fprintf(stderr, "expected invalid-runtime-handle error after shutdown, got %" PRIu64 "\n", err_id);
+        abort();
+    }
+    ygrpc_expect_err0_i64(ping_native(YGRPC_DEFAULT_RUNTIME), "Ping on the default runtime");
+    ygrpc_expect_err0_i64(Ygrpc_Shutdown(rt_b, 1000), "Ygrpc_Shutdown(rt_b)");
+}
+
+static void test_shutdown(void) {
+    uint64_t rc = Ygrpc_Shutdown(YGRPC_DEFAULT_RUNTIME, 1000);
+    if (rc != 0) {
+        fprintf(stderr, "Ygrpc_Shutdown failed: %" PRIu64 "\n", rc);
+        abort();
+    }
+
+    uint8_t empty[1] = {0};
+    void* resp_ptr = NULL;
+    GoInt resp_len = 0;
+    void* resp_free = NULL;
+
+    uint64_t err_id = Ygrpc_TestService_Ping(YGRPC_DEFAULT_RUNTIME, empty, 0, &resp_ptr, &resp_len, &resp_free);
+    if (err_id == 0 || !Ygrpc_ErrorIs(err_id, YGRPC_ERR_KIND_UNAVAILABLE)) {
+        fprintf(stderr, "expected unavailable error after shutdown, got %" PRIu64 "\n", err_id);
+        abort();
+    }
+}
+
 static void test_error_path(void) {
     uint8_t bad[1] = {0xFF};
 
+    Ygrpc_SetErrorHook((void*)on_error_hook);
+
     void* resp_ptr = NULL;
     GoInt resp_len = 0;
     void* resp_free = NULL;
 
-    uint64_t err_id = Ygrpc_TestService_Ping(bad, 1, &resp_ptr, &resp_len, &resp_free);
+    uint64_t err_id = Ygrpc_TestService_Ping(YGRPC_DEFAULT_RUNTIME, bad, 1, &resp_ptr, &resp_len, &resp_free);
     if (err_id == 0) {
         fprintf(stderr, "expected non-zero error id in invalid-protobuf test\n");
         abort();
     }
+    Ygrpc_SetErrorHook(NULL);
+    if (g_hook_error_id != err_id) {
+        fprintf(stderr, "expected error hook for %" PRIu64 ", got %" PRIu64 "\n", err_id, g_hook_error_id);
+        abort();
+    }
+
+    uint64_t last_err = Ygrpc_GetLastError();
+    if (last_err != err_id) {
+        fprintf(stderr, "expected last error %" PRIu64 ", got %" PRIu64 "\n", err_id, last_err);
+        abort();
+    }
 
     void* emsg_ptr = NULL;
     GoInt emsg_len = 0;
@@ -39,10 +261,18 @@ static void test_error_path(void) {
     }
 
     call_free_func((FreeFunc)emsg_free, emsg_ptr);
+
+    Ygrpc_ClearLastError();
+    if (Ygrpc_GetLastError() != 0) {
+        fprintf(stderr, "expected last error to be cleared\n");
+        abort();
+    }
 }
 
 int main(void) {
-    uint64_t rc = Ygrpc_SetProtocol(YGRPC_PROTOCOL_UNSET);
+    test_init();
+
+    uint64_t rc = Ygrpc_SetProtocol(YGRPC_DEFAULT_RUNTIME, YGRPC_PROTOCOL_UNSET);
     if (rc != 0)
     {
         fprintf(stderr, "Ygrpc_SetProtocol failed: %" PRIu64 "\n", rc);
@@ -66,7 +296,7 @@ int main(void) {
         GoInt resp_len = 0;
         void* resp_free = NULL;
 
-        uint64_t err_id = Ygrpc_TestService_Ping(req_buf, req_len, &resp_ptr, &resp_len, &resp_free);
+        uint64_t err_id = Ygrpc_TestService_Ping(YGRPC_DEFAULT_RUNTIME, req_buf, req_len, &resp_ptr, &resp_len, &resp_free);
 
         if (err_id != 0) {
             fprintf(stderr, "Ygrpc_TestService_Ping failed: %" PRIu64 "\n", err_id);
@@ -112,7 +342,7 @@ int main(void) {
         GoInt resp_len = 0;
         void* resp_free = NULL;
 
-        uint64_t err_id = Ygrpc_TestService_Ping_TakeReq(req_heap, (int)req_len, (void*)counting_free, &resp_ptr, &resp_len, &resp_free);
+        uint64_t err_id = Ygrpc_TestService_Ping_TakeReq(YGRPC_DEFAULT_RUNTIME, req_heap, (int)req_len, (void*)counting_free, &resp_ptr, &resp_len, &resp_free);
 
         if (err_id != 0) {
             fprintf(stderr, "Ygrpc_TestService_Ping_TakeReq failed: %" PRIu64 "\n", err_id);
@@ -132,7 +362,7 @@ int main(void) {
         int out_len = 0;
         FreeFunc out_free = NULL;
 
-        uint64_t err_id = Ygrpc_TestService_Ping_Native((char*)msg, (int)strlen(msg), &out_msg, &out_len, &out_free);
+        uint64_t err_id = Ygrpc_TestService_Ping_Native(YGRPC_DEFAULT_RUNTIME, (char*)msg, (int)strlen(msg), &out_msg, &out_len, &out_free);
         if (err_id != 0) {
             fprintf(stderr, "Ygrpc_TestService_Ping_Native failed: %" PRIu64 "\n", err_id);
             return 1;
@@ -146,6 +376,12 @@ int main(void) {
     }
 
     test_error_path();
+    test_registry_watcher();
+    test_protocol_preference();
+    test_protocol_go();
+    test_protocol_twirp();
+    test_isolated_runtimes();
+    test_shutdown();
 
     printf("unary_test OK\n");
     return 0;
@@ -71,6 +71,17 @@ func (s *streamServiceConnect) BidiStreamCall(ctx context.Context, stream *conne
 }
 
 func init() {
-	_, _ = rpcruntime.RegisterConnectHandler(cgotest_connect.TestService_ServiceName, &testServiceConnect{})
-	_, _ = rpcruntime.RegisterConnectHandler(cgotest_connect.StreamService_ServiceName, &streamServiceConnect{})
+	// Handlers are registered once the host calls Ygrpc_Init.
+	// The Runtime being initialized is the default one or one created with
+	// Ygrpc_NewRuntime.
+	rpcruntime.OnInit(func(ctx context.Context, config []byte) error {
+		rt := rpcruntime.RuntimeFromContext(ctx)
+		if _, err := rt.RegisterConnectHandler(cgotest_connect.TestService_ServiceName, &testServiceConnect{}); err != nil {
+			return err
+		}
+		if _, err := rt.RegisterConnectHandler(cgotest_connect.StreamService_ServiceName, &streamServiceConnect{}); err != nil {
+			return err
+		}
+		return nil
+	})
 }
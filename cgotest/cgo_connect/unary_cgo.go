@@ -21,6 +21,7 @@ import "C"
 
 //export Ygrpc_TestService_Ping
 func Ygrpc_TestService_Ping(
+	rtHandle uint64,
 	reqPtr unsafe.Pointer,
 	reqLen int,
 	respPtr *unsafe.Pointer,
@@ -30,18 +31,22 @@ func Ygrpc_TestService_Ping(
 	reqBytes := unsafe.Slice((*byte)(reqPtr), reqLen)
 	req := &connect.PingRequest{}
 	if err := proto.Unmarshal(reqBytes, req); err != nil {
-		return uint64(rpcruntime.StoreError(err))
+		return uint64(rpcruntime.StoreLastError(err))
 	}
 
-	ctx := rpcruntime.BackgroundContext()
+	rt, ok := rpcruntime.RuntimeFromHandle(rpcruntime.RuntimeHandle(rtHandle))
+	if !ok {
+		return uint64(rpcruntime.StoreLastError(rpcruntime.ErrInvalidRuntimeHandle))
+	}
+	ctx := rt.BackgroundContext()
 	resp, err := connect.TestService_Ping(ctx, req)
 	if err != nil {
-		return uint64(rpcruntime.StoreError(err))
+		return uint64(rpcruntime.StoreLastError(err))
 	}
 
 	respBytes, err := proto.Marshal(resp)
 	if err != nil {
-		return uint64(rpcruntime.StoreError(err))
+		return uint64(rpcruntime.StoreLastError(err))
 	}
 
 	buf := C.CBytes(respBytes)
@@ -53,6 +58,7 @@ func Ygrpc_TestService_Ping(
 
 //export Ygrpc_TestService_Ping_TakeReq
 func Ygrpc_TestService_Ping_TakeReq(
+	rtHandle uint64,
 	reqPtr unsafe.Pointer,
 	reqLen int,
 	reqFree unsafe.Pointer,
@@ -66,21 +72,25 @@ func Ygrpc_TestService_Ping_TakeReq(
 		if reqFree != nil {
 			C.call_free_func((C.FreeFunc)(reqFree), reqPtr)
 		}
-		return uint64(rpcruntime.StoreError(err))
+		return uint64(rpcruntime.StoreLastError(err))
 	}
 	if reqFree != nil {
 		C.call_free_func((C.FreeFunc)(reqFree), reqPtr)
 	}
 
-	ctx := rpcruntime.BackgroundContext()
+	rt, ok := rpcruntime.RuntimeFromHandle(rpcruntime.RuntimeHandle(rtHandle))
+	if !ok {
+		return uint64(rpcruntime.StoreLastError(rpcruntime.ErrInvalidRuntimeHandle))
+	}
+	ctx := rt.BackgroundContext()
 	resp, err := connect.TestService_Ping(ctx, req)
 	if err != nil {
-		return uint64(rpcruntime.StoreError(err))
+		return uint64(rpcruntime.StoreLastError(err))
 	}
 
 	respBytes, err := proto.Marshal(resp)
 	if err != nil {
-		return uint64(rpcruntime.StoreError(err))
+		return uint64(rpcruntime.StoreLastError(err))
 	}
 
 	buf := C.CBytes(respBytes)
@@ -92,6 +102,7 @@ func Ygrpc_TestService_Ping_TakeReq(
 
 //export Ygrpc_TestService_Ping_Native
 func Ygrpc_TestService_Ping_Native(
+	rtHandle uint64,
 	req_msg *C.char,
 	req_msg_len C.int,
 	resp_msg **C.char,
@@ -101,10 +112,14 @@ func Ygrpc_TestService_Ping_Native(
 	req := &connect.PingRequest{}
 	req.Msg = C.GoStringN(req_msg, req_msg_len)
 
-	ctx := rpcruntime.BackgroundContext()
+	rt, ok := rpcruntime.RuntimeFromHandle(rpcruntime.RuntimeHandle(rtHandle))
+	if !ok {
+		return C.uint64_t(rpcruntime.StoreLastError(rpcruntime.ErrInvalidRuntimeHandle))
+	}
+	ctx := rt.BackgroundContext()
 	resp, err := connect.TestService_Ping(ctx, req)
 	if err != nil {
-		return C.uint64_t(rpcruntime.StoreError(err))
+		return C.uint64_t(rpcruntime.StoreLastError(err))
 	}
 
 	if len(resp.Msg) > 0 {
@@ -122,6 +137,7 @@ func Ygrpc_TestService_Ping_Native(
 
 //export Ygrpc_TestService_Ping_Native_TakeReq
 func Ygrpc_TestService_Ping_Native_TakeReq(
+	rtHandle uint64,
 	req_msg *C.char,
 	req_msg_len C.int,
 	req_msg_free C.FreeFunc,
@@ -135,10 +151,14 @@ func Ygrpc_TestService_Ping_Native_TakeReq(
 		C.call_free_func(req_msg_free, unsafe.Pointer(req_msg))
 	}
 
-	ctx := rpcruntime.BackgroundContext()
+	rt, ok := rpcruntime.RuntimeFromHandle(rpcruntime.RuntimeHandle(rtHandle))
+	if !ok {
+		return C.uint64_t(rpcruntime.StoreLastError(rpcruntime.ErrInvalidRuntimeHandle))
+	}
+	ctx := rt.BackgroundContext()
 	resp, err := connect.TestService_Ping(ctx, req)
 	if err != nil {
-		return C.uint64_t(rpcruntime.StoreError(err))
+		return C.uint64_t(rpcruntime.StoreLastError(err))
 	}
 
 	if len(resp.Msg) > 0 {
@@ -156,6 +176,7 @@ func Ygrpc_TestService_Ping_Native_TakeReq(
 
 //export Ygrpc_TestService_PingOpt1_TakeReq
 func Ygrpc_TestService_PingOpt1_TakeReq(
+	rtHandle uint64,
 	reqPtr unsafe.Pointer,
 	reqLen int,
 	reqFree unsafe.Pointer,
@@ -169,21 +190,25 @@ func Ygrpc_TestService_PingOpt1_TakeReq(
 		if reqFree != nil {
 			C.call_free_func((C.FreeFunc)(reqFree), reqPtr)
 		}
-		return uint64(rpcruntime.StoreError(err))
+		return uint64(rpcruntime.StoreLastError(err))
 	}
 	if reqFree != nil {
 		C.call_free_func((C.FreeFunc)(reqFree), reqPtr)
 	}
 
-	ctx := rpcruntime.BackgroundContext()
+	rt, ok := rpcruntime.RuntimeFromHandle(rpcruntime.RuntimeHandle(rtHandle))
+	if !ok {
+		return uint64(rpcruntime.StoreLastError(rpcruntime.ErrInvalidRuntimeHandle))
+	}
+	ctx := rt.BackgroundContext()
 	resp, err := connect.TestService_PingOpt1(ctx, req)
 	if err != nil {
-		return uint64(rpcruntime.StoreError(err))
+		return uint64(rpcruntime.StoreLastError(err))
 	}
 
 	respBytes, err := proto.Marshal(resp)
 	if err != nil {
-		return uint64(rpcruntime.StoreError(err))
+		return uint64(rpcruntime.StoreLastError(err))
 	}
 
 	buf := C.CBytes(respBytes)
@@ -195,6 +220,7 @@ func Ygrpc_TestService_PingOpt1_TakeReq(
 
 //export Ygrpc_TestService_PingOpt1_Native_TakeReq
 func Ygrpc_TestService_PingOpt1_Native_TakeReq(
+	rtHandle uint64,
 	req_msg *C.char,
 	req_msg_len C.int,
 	req_msg_free C.FreeFunc,
@@ -208,10 +234,14 @@ func Ygrpc_TestService_PingOpt1_Native_TakeReq(
 		C.call_free_func(req_msg_free, unsafe.Pointer(req_msg))
 	}
 
-	ctx := rpcruntime.BackgroundContext()
+	rt, ok := rpcruntime.RuntimeFromHandle(rpcruntime.RuntimeHandle(rtHandle))
+	if !ok {
+		return C.uint64_t(rpcruntime.StoreLastError(rpcruntime.ErrInvalidRuntimeHandle))
+	}
+	ctx := rt.BackgroundContext()
 	resp, err := connect.TestService_PingOpt1(ctx, req)
 	if err != nil {
-		return C.uint64_t(rpcruntime.StoreError(err))
+		return C.uint64_t(rpcruntime.StoreLastError(err))
 	}
 
 	if len(resp.Msg) > 0 {
@@ -229,6 +259,7 @@ func Ygrpc_TestService_PingOpt1_Native_TakeReq(
 
 //export Ygrpc_TestService_PingOpt2
 func Ygrpc_TestService_PingOpt2(
+	rtHandle uint64,
 	reqPtr unsafe.Pointer,
 	reqLen int,
 	respPtr *unsafe.Pointer,
@@ -238,18 +269,22 @@ func Ygrpc_TestService_PingOpt2(
 	reqBytes := unsafe.Slice((*byte)(reqPtr), reqLen)
 	req := &connect.PingRequestOpt2{}
 	if err := proto.Unmarshal(reqBytes, req); err != nil {
-		return uint64(rpcruntime.StoreError(err))
+		return uint64(rpcruntime.StoreLastError(err))
 	}
 
-	ctx := rpcruntime.BackgroundContext()
+	rt, ok := rpcruntime.RuntimeFromHandle(rpcruntime.RuntimeHandle(rtHandle))
+	if !ok {
+		return uint64(rpcruntime.StoreLastError(rpcruntime.ErrInvalidRuntimeHandle))
+	}
+	ctx := rt.BackgroundContext()
 	resp, err := connect.TestService_PingOpt2(ctx, req)
 	if err != nil {
-		return uint64(rpcruntime.StoreError(err))
+		return uint64(rpcruntime.StoreLastError(err))
 	}
 
 	respBytes, err := proto.Marshal(resp)
 	if err != nil {
-		return uint64(rpcruntime.StoreError(err))
+		return uint64(rpcruntime.StoreLastError(err))
 	}
 
 	buf := C.CBytes(respBytes)
@@ -261,6 +296,7 @@ func Ygrpc_TestService_PingOpt2(
 
 //export Ygrpc_TestService_NonFlat
 func Ygrpc_TestService_NonFlat(
+	rtHandle uint64,
 	reqPtr unsafe.Pointer,
 	reqLen int,
 	respPtr *unsafe.Pointer,
@@ -270,18 +306,22 @@ func Ygrpc_TestService_NonFlat(
 	reqBytes := unsafe.Slice((*byte)(reqPtr), reqLen)
 	req := &connect.NonFlatRequest{}
 	if err := proto.Unmarshal(reqBytes, req); err != nil {
-		return uint64(rpcruntime.StoreError(err))
+		return uint64(rpcruntime.StoreLastError(err))
 	}
 
-	ctx := rpcruntime.BackgroundContext()
+	rt, ok := rpcruntime.RuntimeFromHandle(rpcruntime.RuntimeHandle(rtHandle))
+	if !ok {
+		return uint64(rpcruntime.StoreLastError(rpcruntime.ErrInvalidRuntimeHandle))
+	}
+	ctx := rt.BackgroundContext()
 	resp, err := connect.TestService_NonFlat(ctx, req)
 	if err != nil {
-		return uint64(rpcruntime.StoreError(err))
+		return uint64(rpcruntime.StoreLastError(err))
 	}
 
 	respBytes, err := proto.Marshal(resp)
 	if err != nil {
-		return uint64(rpcruntime.StoreError(err))
+		return uint64(rpcruntime.StoreLastError(err))
 	}
 
 	buf := C.CBytes(respBytes)
@@ -293,6 +333,7 @@ func Ygrpc_TestService_NonFlat(
 
 //export Ygrpc_TestService_NonFlat_TakeReq
 func Ygrpc_TestService_NonFlat_TakeReq(
+	rtHandle uint64,
 	reqPtr unsafe.Pointer,
 	reqLen int,
 	reqFree unsafe.Pointer,
@@ -306,21 +347,25 @@ func Ygrpc_TestService_NonFlat_TakeReq(
 		if reqFree != nil {
 			C.call_free_func((C.FreeFunc)(reqFree), reqPtr)
 		}
-		return uint64(rpcruntime.StoreError(err))
+		return uint64(rpcruntime.StoreLastError(err))
 	}
 	if reqFree != nil {
 		C.call_free_func((C.FreeFunc)(reqFree), reqPtr)
 	}
 
-	ctx := rpcruntime.BackgroundContext()
+	rt, ok := rpcruntime.RuntimeFromHandle(rpcruntime.RuntimeHandle(rtHandle))
+	if !ok {
+		return uint64(rpcruntime.StoreLastError(rpcruntime.ErrInvalidRuntimeHandle))
+	}
+	ctx := rt.BackgroundContext()
 	resp, err := connect.TestService_NonFlat(ctx, req)
 	if err != nil {
-		return uint64(rpcruntime.StoreError(err))
+		return uint64(rpcruntime.StoreLastError(err))
 	}
 
 	respBytes, err := proto.Marshal(resp)
 	if err != nil {
-		return uint64(rpcruntime.StoreError(err))
+		return uint64(rpcruntime.StoreLastError(err))
 	}
 
 	buf := C.CBytes(respBytes)
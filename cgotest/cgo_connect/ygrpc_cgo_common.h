@@ -1,5 +1,10 @@
 // Code generated by protoc-gen-rpc-cgo. DO NOT EDIT.
 
+// Exports that start calls or configure a Runtime take the handle of the
+// rpcruntime Runtime to use as their first argument (see Ygrpc_NewRuntime);
+// YGRPC_DEFAULT_RUNTIME selects the default Runtime. Stream handles remember
+// their Runtime, and error ids and callbacks are process-wide.
+
 #ifndef YGRPC_CGO_COMMON_H
 #define YGRPC_CGO_COMMON_H
 
@@ -9,12 +14,36 @@
 
 typedef void (*FreeFunc)(void*);
 
+#define YGRPC_DEFAULT_RUNTIME 0
+
 typedef enum {
     YGRPC_PROTOCOL_UNSET = 0,
     YGRPC_PROTOCOL_GRPC = 1,
     YGRPC_PROTOCOL_CONNECTRPC = 2,
+    YGRPC_PROTOCOL_GO = 3,
+    YGRPC_PROTOCOL_TWIRP = 4,
 } YgrpcProtocol;
 
+// Values match rpcruntime.ErrorKind; see Ygrpc_ErrorIs.
+typedef enum {
+    YGRPC_ERR_KIND_UNSPECIFIED = 0,
+    YGRPC_ERR_KIND_SERVICE_NOT_REGISTERED = 1,
+    YGRPC_ERR_KIND_HANDLER_TYPE_MISMATCH = 2,
+    YGRPC_ERR_KIND_INVALID_STREAM_HANDLE = 3,
+    YGRPC_ERR_KIND_UNKNOWN_PROTOCOL = 4,
+    YGRPC_ERR_KIND_STREAM_MESSAGE_TYPE_MISMATCH = 5,
+    YGRPC_ERR_KIND_EMPTY_SERVICE_NAME = 6,
+    YGRPC_ERR_KIND_NIL_HANDLER = 7,
+    YGRPC_ERR_KIND_UNAVAILABLE = 8,
+    YGRPC_ERR_KIND_NOT_INITIALIZED = 9,
+    YGRPC_ERR_KIND_RESOURCE_EXHAUSTED = 10,
+    YGRPC_ERR_KIND_ALREADY_INITIALIZED = 11,
+    YGRPC_ERR_KIND_INVALID_CONNECT_STREAM_BRIDGE = 12,
+    YGRPC_ERR_KIND_NIL_REMOTE = 13,
+    YGRPC_ERR_KIND_ALIAS_CYCLE = 14,
+    YGRPC_ERR_KIND_INVALID_RUNTIME_HANDLE = 15,
+} YgrpcErrKind;
+
 extern void Ygrpc_Free(void* ptr);
 
 static inline void call_free_func(FreeFunc fn, void* ptr) {
@@ -32,4 +61,38 @@ static inline void call_on_done(void* fn, uint64_t call_id, uint64_t error_id) {
     if(fn) ((OnDoneFunc)fn)(call_id, error_id);
 }
 
+// Values match rpcruntime.LogLevel; see Ygrpc_SetLogCallback.
+typedef enum {
+    YGRPC_LOG_DEBUG = 0,
+    YGRPC_LOG_INFO = 1,
+    YGRPC_LOG_WARN = 2,
+    YGRPC_LOG_ERROR = 3,
+} YgrpcLogLevel;
+
+// msg is only valid for the duration of the callback.
+typedef void (*OnLogFunc)(int level, const char* msg, int msg_len);
+typedef void (*OnErrorFunc)(uint64_t error_id, const char* msg, int msg_len);
+
+static inline void call_on_log(void* fn, int level, const char* msg, int msg_len) {
+    if(fn) ((OnLogFunc)fn)(level, msg, msg_len);
+}
+
+static inline void call_on_error(void* fn, uint64_t error_id, const char* msg, int msg_len) {
+    if(fn) ((OnErrorFunc)fn)(error_id, msg, msg_len);
+}
+
+// Values match rpcruntime.RegistryEventKind; see Ygrpc_SetRegistryWatcher.
+typedef enum {
+    YGRPC_REGISTRY_REGISTERED = 1,
+    YGRPC_REGISTRY_REPLACED = 2,
+    YGRPC_REGISTRY_UNREGISTERED = 3,
+} YgrpcRegistryEventKind;
+
+// protocol is a YgrpcProtocol value; service_name is only valid for the duration of the callback.
+typedef void (*OnRegistryEventFunc)(int kind, int protocol, const char* service_name, int service_name_len);
+
+static inline void call_on_registry_event(void* fn, int kind, int protocol, const char* service_name, int service_name_len) {
+    if(fn) ((OnRegistryEventFunc)fn)(kind, protocol, service_name, service_name_len);
+}
+
 #endif
@@ -67,6 +67,17 @@ func (s *streamServiceConnectSuffix) BidiStreamCall(ctx context.Context, stream
 }
 
 func init() {
-	_, _ = rpcruntime.RegisterConnectHandler(cgotest_connect_suffix.TestService_ServiceName, &testServiceConnectSuffix{})
-	_, _ = rpcruntime.RegisterConnectHandler(cgotest_connect_suffix.StreamService_ServiceName, &streamServiceConnectSuffix{})
+	// Handlers are registered once the host calls Ygrpc_Init.
+	// The Runtime being initialized is the default one or one created with
+	// Ygrpc_NewRuntime.
+	rpcruntime.OnInit(func(ctx context.Context, config []byte) error {
+		rt := rpcruntime.RuntimeFromContext(ctx)
+		if _, err := rt.RegisterConnectHandler(cgotest_connect_suffix.TestService_ServiceName, &testServiceConnectSuffix{}); err != nil {
+			return err
+		}
+		if _, err := rt.RegisterConnectHandler(cgotest_connect_suffix.StreamService_ServiceName, &streamServiceConnectSuffix{}); err != nil {
+			return err
+		}
+		return nil
+	})
 }
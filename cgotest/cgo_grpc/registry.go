@@ -89,6 +89,17 @@ func (s *streamServiceGrpc) BidiStreamCall(stream cgotest_grpc.StreamService_Bid
 }
 
 func init() {
-	_, _ = rpcruntime.RegisterGrpcHandler(cgotest_grpc.TestService_ServiceName, &testServiceGrpc{})
-	_, _ = rpcruntime.RegisterGrpcHandler(cgotest_grpc.StreamService_ServiceName, &streamServiceGrpc{})
+	// Handlers are registered once the host calls Ygrpc_Init.
+	// The Runtime being initialized is the default one or one created with
+	// Ygrpc_NewRuntime.
+	rpcruntime.OnInit(func(ctx context.Context, config []byte) error {
+		rt := rpcruntime.RuntimeFromContext(ctx)
+		if _, err := rt.RegisterGrpcHandler(cgotest_grpc.TestService_ServiceName, &testServiceGrpc{}); err != nil {
+			return err
+		}
+		if _, err := rt.RegisterGrpcHandler(cgotest_grpc.StreamService_ServiceName, &streamServiceGrpc{}); err != nil {
+			return err
+		}
+		return nil
+	})
 }
@@ -62,6 +62,7 @@ import "C"
 
 //export Ygrpc_StreamService_UnaryCall
 func Ygrpc_StreamService_UnaryCall(
+	rtHandle uint64,
 	reqPtr unsafe.Pointer,
 	reqLen int,
 	respPtr *unsafe.Pointer,
@@ -71,18 +72,22 @@ func Ygrpc_StreamService_UnaryCall(
 	reqBytes := unsafe.Slice((*byte)(reqPtr), reqLen)
 	req := &grpc.StreamRequest{}
 	if err := proto.Unmarshal(reqBytes, req); err != nil {
-		return uint64(rpcruntime.StoreError(err))
+		return uint64(rpcruntime.StoreLastError(err))
 	}
 
-	ctx := rpcruntime.BackgroundContext()
+	rt, ok := rpcruntime.RuntimeFromHandle(rpcruntime.RuntimeHandle(rtHandle))
+	if !ok {
+		return uint64(rpcruntime.StoreLastError(rpcruntime.ErrInvalidRuntimeHandle))
+	}
+	ctx := rt.BackgroundContext()
 	resp, err := grpc.StreamService_UnaryCall(ctx, req)
 	if err != nil {
-		return uint64(rpcruntime.StoreError(err))
+		return uint64(rpcruntime.StoreLastError(err))
 	}
 
 	respBytes, err := proto.Marshal(resp)
 	if err != nil {
-		return uint64(rpcruntime.StoreError(err))
+		return uint64(rpcruntime.StoreLastError(err))
 	}
 
 	buf := C.CBytes(respBytes)
@@ -94,6 +99,7 @@ func Ygrpc_StreamService_UnaryCall(
 
 //export Ygrpc_StreamService_UnaryCall_TakeReq
 func Ygrpc_StreamService_UnaryCall_TakeReq(
+	rtHandle uint64,
 	reqPtr unsafe.Pointer,
 	reqLen int,
 	reqFree unsafe.Pointer,
@@ -107,21 +113,25 @@ func Ygrpc_StreamService_UnaryCall_TakeReq(
 		if reqFree != nil {
 			C.call_free_func((C.FreeFunc)(reqFree), reqPtr)
 		}
-		return uint64(rpcruntime.StoreError(err))
+		return uint64(rpcruntime.StoreLastError(err))
 	}
 	if reqFree != nil {
 		C.call_free_func((C.FreeFunc)(reqFree), reqPtr)
 	}
 
-	ctx := rpcruntime.BackgroundContext()
+	rt, ok := rpcruntime.RuntimeFromHandle(rpcruntime.RuntimeHandle(rtHandle))
+	if !ok {
+		return uint64(rpcruntime.StoreLastError(rpcruntime.ErrInvalidRuntimeHandle))
+	}
+	ctx := rt.BackgroundContext()
 	resp, err := grpc.StreamService_UnaryCall(ctx, req)
 	if err != nil {
-		return uint64(rpcruntime.StoreError(err))
+		return uint64(rpcruntime.StoreLastError(err))
 	}
 
 	respBytes, err := proto.Marshal(resp)
 	if err != nil {
-		return uint64(rpcruntime.StoreError(err))
+		return uint64(rpcruntime.StoreLastError(err))
 	}
 
 	buf := C.CBytes(respBytes)
@@ -133,6 +143,7 @@ func Ygrpc_StreamService_UnaryCall_TakeReq(
 
 //export Ygrpc_StreamService_UnaryCall_Native
 func Ygrpc_StreamService_UnaryCall_Native(
+	rtHandle uint64,
 	req_data *C.char,
 	req_data_len C.int,
 	req_sequence C.int32_t,
@@ -145,10 +156,14 @@ func Ygrpc_StreamService_UnaryCall_Native(
 	req.Data = C.GoStringN(req_data, req_data_len)
 	req.Sequence = int32(req_sequence)
 
-	ctx := rpcruntime.BackgroundContext()
+	rt, ok := rpcruntime.RuntimeFromHandle(rpcruntime.RuntimeHandle(rtHandle))
+	if !ok {
+		return C.uint64_t(rpcruntime.StoreLastError(rpcruntime.ErrInvalidRuntimeHandle))
+	}
+	ctx := rt.BackgroundContext()
 	resp, err := grpc.StreamService_UnaryCall(ctx, req)
 	if err != nil {
-		return C.uint64_t(rpcruntime.StoreError(err))
+		return C.uint64_t(rpcruntime.StoreLastError(err))
 	}
 
 	if len(resp.Result) > 0 {
@@ -167,6 +182,7 @@ func Ygrpc_StreamService_UnaryCall_Native(
 
 //export Ygrpc_StreamService_UnaryCall_Native_TakeReq
 func Ygrpc_StreamService_UnaryCall_Native_TakeReq(
+	rtHandle uint64,
 	req_data *C.char,
 	req_data_len C.int,
 	req_data_free C.FreeFunc,
@@ -183,10 +199,14 @@ func Ygrpc_StreamService_UnaryCall_Native_TakeReq(
 	}
 	req.Sequence = int32(req_sequence)
 
-	ctx := rpcruntime.BackgroundContext()
+	rt, ok := rpcruntime.RuntimeFromHandle(rpcruntime.RuntimeHandle(rtHandle))
+	if !ok {
+		return C.uint64_t(rpcruntime.StoreLastError(rpcruntime.ErrInvalidRuntimeHandle))
+	}
+	ctx := rt.BackgroundContext()
 	resp, err := grpc.StreamService_UnaryCall(ctx, req)
 	if err != nil {
-		return C.uint64_t(rpcruntime.StoreError(err))
+		return C.uint64_t(rpcruntime.StoreLastError(err))
 	}
 
 	if len(resp.Result) > 0 {
@@ -204,12 +224,17 @@ func Ygrpc_StreamService_UnaryCall_Native_TakeReq(
 }
 
 //export Ygrpc_StreamService_ClientStreamCallStart
-func Ygrpc_StreamService_ClientStreamCallStart(outHandle *uint64) uint64 {
-	ctx := rpcruntime.BackgroundContext()
+func Ygrpc_StreamService_ClientStreamCallStart(rtHandle uint64, outHandle *uint64) uint64 {
+	rt, ok := rpcruntime.RuntimeFromHandle(rpcruntime.RuntimeHandle(rtHandle))
+	if !ok {
+		*outHandle = 0
+		return uint64(rpcruntime.StoreLastError(rpcruntime.ErrInvalidRuntimeHandle))
+	}
+	ctx := rt.BackgroundContext()
 	handle, err := grpc.StreamService_ClientStreamCallStart(ctx)
 	if err != nil {
 		*outHandle = 0
-		return uint64(rpcruntime.StoreError(err))
+		return uint64(rpcruntime.StoreLastError(err))
 	}
 	*outHandle = uint64(handle)
 	return 0
@@ -224,10 +249,10 @@ func Ygrpc_StreamService_ClientStreamCallSend(
 	reqBytes := C.GoBytes(reqPtr, reqLen)
 	req := &grpc.StreamRequest{}
 	if err := proto.Unmarshal(reqBytes, req); err != nil {
-		return C.uint64_t(rpcruntime.StoreError(err))
+		return C.uint64_t(rpcruntime.StoreLastError(err))
 	}
 	if err := grpc.StreamService_ClientStreamCallSend(uint64(streamHandle), req); err != nil {
-		return C.uint64_t(rpcruntime.StoreError(err))
+		return C.uint64_t(rpcruntime.StoreLastError(err))
 	}
 	return 0
 }
@@ -245,10 +270,10 @@ func Ygrpc_StreamService_ClientStreamCallSend_TakeReq(
 	}
 	req := &grpc.StreamRequest{}
 	if err := proto.Unmarshal(reqBytes, req); err != nil {
-		return C.uint64_t(rpcruntime.StoreError(err))
+		return C.uint64_t(rpcruntime.StoreLastError(err))
 	}
 	if err := grpc.StreamService_ClientStreamCallSend(uint64(streamHandle), req); err != nil {
-		return C.uint64_t(rpcruntime.StoreError(err))
+		return C.uint64_t(rpcruntime.StoreLastError(err))
 	}
 	return 0
 }
@@ -262,7 +287,7 @@ func Ygrpc_StreamService_ClientStreamCallFinish(
 ) uint64 {
 	resp, err := grpc.StreamService_ClientStreamCallFinish(uint64(streamHandle))
 	if err != nil {
-		return uint64(rpcruntime.StoreError(err))
+		return uint64(rpcruntime.StoreLastError(err))
 	}
 	if resp == nil {
 		*respPtr = nil
@@ -272,7 +297,7 @@ func Ygrpc_StreamService_ClientStreamCallFinish(
 	}
 	respBytes, err := proto.Marshal(resp)
 	if err != nil {
-		return uint64(rpcruntime.StoreError(err))
+		return uint64(rpcruntime.StoreLastError(err))
 	}
 	buf := C.CBytes(respBytes)
 	*respPtr = buf
@@ -282,12 +307,17 @@ func Ygrpc_StreamService_ClientStreamCallFinish(
 }
 
 //export Ygrpc_StreamService_ClientStreamCallStart_Native
-func Ygrpc_StreamService_ClientStreamCallStart_Native(outHandle *uint64) uint64 {
-	ctx := rpcruntime.BackgroundContext()
+func Ygrpc_StreamService_ClientStreamCallStart_Native(rtHandle uint64, outHandle *uint64) uint64 {
+	rt, ok := rpcruntime.RuntimeFromHandle(rpcruntime.RuntimeHandle(rtHandle))
+	if !ok {
+		*outHandle = 0
+		return uint64(rpcruntime.StoreLastError(rpcruntime.ErrInvalidRuntimeHandle))
+	}
+	ctx := rt.BackgroundContext()
 	handle, err := grpc.StreamService_ClientStreamCallStart(ctx)
 	if err != nil {
 		*outHandle = 0
-		return uint64(rpcruntime.StoreError(err))
+		return uint64(rpcruntime.StoreLastError(err))
 	}
 	*outHandle = uint64(handle)
 	return 0
@@ -304,7 +334,7 @@ func Ygrpc_StreamService_ClientStreamCallSend_Native(
 	req.Data = C.GoStringN(req_data, req_data_len)
 	req.Sequence = int32(req_sequence)
 	if err := grpc.StreamService_ClientStreamCallSend(uint64(streamHandle), req); err != nil {
-		return C.uint64_t(rpcruntime.StoreError(err))
+		return C.uint64_t(rpcruntime.StoreLastError(err))
 	}
 	return 0
 }
@@ -324,7 +354,7 @@ func Ygrpc_StreamService_ClientStreamCallSend_Native_TakeReq(
 	}
 	req.Sequence = int32(req_sequence)
 	if err := grpc.StreamService_ClientStreamCallSend(uint64(streamHandle), req); err != nil {
-		return C.uint64_t(rpcruntime.StoreError(err))
+		return C.uint64_t(rpcruntime.StoreLastError(err))
 	}
 	return 0
 }
@@ -339,7 +369,7 @@ func Ygrpc_StreamService_ClientStreamCallFinish_Native(
 ) C.uint64_t {
 	resp, err := grpc.StreamService_ClientStreamCallFinish(uint64(streamHandle))
 	if err != nil {
-		return C.uint64_t(rpcruntime.StoreError(err))
+		return C.uint64_t(rpcruntime.StoreLastError(err))
 	}
 	if resp == nil {
 		resp = &grpc.StreamResponse{}
@@ -360,6 +390,7 @@ func Ygrpc_StreamService_ClientStreamCallFinish_Native(
 
 //export Ygrpc_StreamService_ServerStreamCall
 func Ygrpc_StreamService_ServerStreamCall(
+	rtHandle uint64,
 	reqPtr unsafe.Pointer,
 	reqLen int,
 	onReadBytes unsafe.Pointer,
@@ -369,9 +400,13 @@ func Ygrpc_StreamService_ServerStreamCall(
 	reqBytes := unsafe.Slice((*byte)(reqPtr), reqLen)
 	req := &grpc.StreamRequest{}
 	if err := proto.Unmarshal(reqBytes, req); err != nil {
-		return uint64(rpcruntime.StoreError(err))
+		return uint64(rpcruntime.StoreLastError(err))
 	}
-	ctx := rpcruntime.BackgroundContext()
+	rt, ok := rpcruntime.RuntimeFromHandle(rpcruntime.RuntimeHandle(rtHandle))
+	if !ok {
+		return uint64(rpcruntime.StoreLastError(rpcruntime.ErrInvalidRuntimeHandle))
+	}
+	ctx := rt.BackgroundContext()
 	var doneErrId atomic.Uint64
 	onRead := func(resp *grpc.StreamResponse) bool {
 		respBytes, err := proto.Marshal(resp)
@@ -395,6 +430,7 @@ func Ygrpc_StreamService_ServerStreamCall(
 		if doneErrId.Load() == 0 {
 			doneErrId.Store(rpcruntime.StoreError(err))
 		}
+		rpcruntime.SetLastError(doneErrId.Load())
 		return uint64(doneErrId.Load())
 	}
 	return 0
@@ -402,6 +438,7 @@ func Ygrpc_StreamService_ServerStreamCall(
 
 //export Ygrpc_StreamService_ServerStreamCall_TakeReq
 func Ygrpc_StreamService_ServerStreamCall_TakeReq(
+	rtHandle uint64,
 	reqPtr unsafe.Pointer,
 	reqLen int,
 	reqFree unsafe.Pointer,
@@ -415,12 +452,16 @@ func Ygrpc_StreamService_ServerStreamCall_TakeReq(
 		if reqFree != nil {
 			C.call_free_func((C.FreeFunc)(reqFree), reqPtr)
 		}
-		return uint64(rpcruntime.StoreError(err))
+		return uint64(rpcruntime.StoreLastError(err))
 	}
 	if reqFree != nil {
 		C.call_free_func((C.FreeFunc)(reqFree), reqPtr)
 	}
-	ctx := rpcruntime.BackgroundContext()
+	rt, ok := rpcruntime.RuntimeFromHandle(rpcruntime.RuntimeHandle(rtHandle))
+	if !ok {
+		return uint64(rpcruntime.StoreLastError(rpcruntime.ErrInvalidRuntimeHandle))
+	}
+	ctx := rt.BackgroundContext()
 	var doneErrId atomic.Uint64
 	onRead := func(resp *grpc.StreamResponse) bool {
 		respBytes, err := proto.Marshal(resp)
@@ -444,6 +485,7 @@ func Ygrpc_StreamService_ServerStreamCall_TakeReq(
 		if doneErrId.Load() == 0 {
 			doneErrId.Store(rpcruntime.StoreError(err))
 		}
+		rpcruntime.SetLastError(doneErrId.Load())
 		return uint64(doneErrId.Load())
 	}
 	return 0
@@ -451,6 +493,7 @@ func Ygrpc_StreamService_ServerStreamCall_TakeReq(
 
 //export Ygrpc_StreamService_ServerStreamCall_Native
 func Ygrpc_StreamService_ServerStreamCall_Native(
+	rtHandle uint64,
 	req_data *C.char,
 	req_data_len C.int,
 	req_sequence C.int32_t,
@@ -461,7 +504,11 @@ func Ygrpc_StreamService_ServerStreamCall_Native(
 	req := &grpc.StreamRequest{}
 	req.Data = C.GoStringN(req_data, req_data_len)
 	req.Sequence = int32(req_sequence)
-	ctx := rpcruntime.BackgroundContext()
+	rt, ok := rpcruntime.RuntimeFromHandle(rpcruntime.RuntimeHandle(rtHandle))
+	if !ok {
+		return C.uint64_t(rpcruntime.StoreLastError(rpcruntime.ErrInvalidRuntimeHandle))
+	}
+	ctx := rt.BackgroundContext()
 	var doneErrId atomic.Uint64
 	onRead := func(resp *grpc.StreamResponse) bool {
 
@@ -493,6 +540,7 @@ func Ygrpc_StreamService_ServerStreamCall_Native(
 		if doneErrId.Load() == 0 {
 			doneErrId.Store(rpcruntime.StoreError(err))
 		}
+		rpcruntime.SetLastError(doneErrId.Load())
 		return C.uint64_t(doneErrId.Load())
 	}
 	return 0
@@ -500,6 +548,7 @@ func Ygrpc_StreamService_ServerStreamCall_Native(
 
 //export Ygrpc_StreamService_ServerStreamCall_Native_TakeReq
 func Ygrpc_StreamService_ServerStreamCall_Native_TakeReq(
+	rtHandle uint64,
 	req_data *C.char,
 	req_data_len C.int,
 	req_data_free C.FreeFunc,
@@ -514,7 +563,11 @@ func Ygrpc_StreamService_ServerStreamCall_Native_TakeReq(
 		C.call_free_func(req_data_free, unsafe.Pointer(req_data))
 	}
 	req.Sequence = int32(req_sequence)
-	ctx := rpcruntime.BackgroundContext()
+	rt, ok := rpcruntime.RuntimeFromHandle(rpcruntime.RuntimeHandle(rtHandle))
+	if !ok {
+		return C.uint64_t(rpcruntime.StoreLastError(rpcruntime.ErrInvalidRuntimeHandle))
+	}
+	ctx := rt.BackgroundContext()
 	var doneErrId atomic.Uint64
 	onRead := func(resp *grpc.StreamResponse) bool {
 		var result_ptr unsafe.Pointer
@@ -545,6 +598,7 @@ func Ygrpc_StreamService_ServerStreamCall_Native_TakeReq(
 		if doneErrId.Load() == 0 {
 			doneErrId.Store(rpcruntime.StoreError(err))
 		}
+		rpcruntime.SetLastError(doneErrId.Load())
 		return C.uint64_t(doneErrId.Load())
 	}
 	return 0
@@ -552,11 +606,17 @@ func Ygrpc_StreamService_ServerStreamCall_Native_TakeReq(
 
 //export Ygrpc_StreamService_BidiStreamCallStart
 func Ygrpc_StreamService_BidiStreamCallStart(
+	rtHandle uint64,
 	onReadBytes unsafe.Pointer,
 	onDone unsafe.Pointer,
 	outHandle *uint64,
 ) uint64 {
-	ctx := rpcruntime.BackgroundContext()
+	rt, ok := rpcruntime.RuntimeFromHandle(rpcruntime.RuntimeHandle(rtHandle))
+	if !ok {
+		*outHandle = 0
+		return uint64(rpcruntime.StoreLastError(rpcruntime.ErrInvalidRuntimeHandle))
+	}
+	ctx := rt.BackgroundContext()
 	handleReady := make(chan struct{})
 	var streamHandle uint64
 	onRead := func(resp *grpc.StreamResponse) bool {
@@ -580,7 +640,7 @@ func Ygrpc_StreamService_BidiStreamCallStart(
 	handle, err := grpc.StreamService_BidiStreamCallStart(ctx, onRead, onDoneFunc)
 	if err != nil {
 		*outHandle = 0
-		return uint64(rpcruntime.StoreError(err))
+		return uint64(rpcruntime.StoreLastError(err))
 	}
 	streamHandle = handle
 	close(handleReady)
@@ -597,10 +657,10 @@ func Ygrpc_StreamService_BidiStreamCallSend(
 	reqBytes := unsafe.Slice((*byte)(reqPtr), reqLen)
 	req := &grpc.StreamRequest{}
 	if err := proto.Unmarshal(reqBytes, req); err != nil {
-		return uint64(rpcruntime.StoreError(err))
+		return uint64(rpcruntime.StoreLastError(err))
 	}
 	if err := grpc.StreamService_BidiStreamCallSend(uint64(streamHandle), req); err != nil {
-		return uint64(rpcruntime.StoreError(err))
+		return uint64(rpcruntime.StoreLastError(err))
 	}
 	return 0
 }
@@ -618,13 +678,13 @@ func Ygrpc_StreamService_BidiStreamCallSend_TakeReq(
 		if reqFree != nil {
 			C.call_free_func((C.FreeFunc)(reqFree), reqPtr)
 		}
-		return uint64(rpcruntime.StoreError(err))
+		return uint64(rpcruntime.StoreLastError(err))
 	}
 	if reqFree != nil {
 		C.call_free_func((C.FreeFunc)(reqFree), reqPtr)
 	}
 	if err := grpc.StreamService_BidiStreamCallSend(uint64(streamHandle), req); err != nil {
-		return uint64(rpcruntime.StoreError(err))
+		return uint64(rpcruntime.StoreLastError(err))
 	}
 	return 0
 }
@@ -632,18 +692,24 @@ func Ygrpc_StreamService_BidiStreamCallSend_TakeReq(
 //export Ygrpc_StreamService_BidiStreamCallCloseSend
 func Ygrpc_StreamService_BidiStreamCallCloseSend(streamHandle uint64) uint64 {
 	if err := grpc.StreamService_BidiStreamCallCloseSend(uint64(streamHandle)); err != nil {
-		return uint64(rpcruntime.StoreError(err))
+		return uint64(rpcruntime.StoreLastError(err))
 	}
 	return 0
 }
 
 //export Ygrpc_StreamService_BidiStreamCallStart_Native
 func Ygrpc_StreamService_BidiStreamCallStart_Native(
+	rtHandle uint64,
 	onReadNative unsafe.Pointer,
 	onDone unsafe.Pointer,
 	outHandle *C.uint64_t,
 ) C.uint64_t {
-	ctx := rpcruntime.BackgroundContext()
+	rt, ok := rpcruntime.RuntimeFromHandle(rpcruntime.RuntimeHandle(rtHandle))
+	if !ok {
+		*outHandle = 0
+		return C.uint64_t(rpcruntime.StoreLastError(rpcruntime.ErrInvalidRuntimeHandle))
+	}
+	ctx := rt.BackgroundContext()
 	handleReady := make(chan struct{})
 	var streamHandle uint64
 	onRead := func(resp *grpc.StreamResponse) bool {
@@ -674,7 +740,7 @@ func Ygrpc_StreamService_BidiStreamCallStart_Native(
 	handle, err := grpc.StreamService_BidiStreamCallStart(ctx, onRead, onDoneFunc)
 	if err != nil {
 		*outHandle = 0
-		return C.uint64_t(rpcruntime.StoreError(err))
+		return C.uint64_t(rpcruntime.StoreLastError(err))
 	}
 	streamHandle = handle
 	close(handleReady)
@@ -693,7 +759,7 @@ func Ygrpc_StreamService_BidiStreamCallSend_Native(
 	req.Data = C.GoStringN(req_data, req_data_len)
 	req.Sequence = int32(req_sequence)
 	if err := grpc.StreamService_BidiStreamCallSend(uint64(streamHandle), req); err != nil {
-		return C.uint64_t(rpcruntime.StoreError(err))
+		return C.uint64_t(rpcruntime.StoreLastError(err))
 	}
 	return 0
 }
@@ -713,7 +779,7 @@ func Ygrpc_StreamService_BidiStreamCallSend_Native_TakeReq(
 	}
 	req.Sequence = int32(req_sequence)
 	if err := grpc.StreamService_BidiStreamCallSend(uint64(streamHandle), req); err != nil {
-		return C.uint64_t(rpcruntime.StoreError(err))
+		return C.uint64_t(rpcruntime.StoreLastError(err))
 	}
 	return 0
 }
@@ -721,7 +787,7 @@ func Ygrpc_StreamService_BidiStreamCallSend_Native_TakeReq(
 //export Ygrpc_StreamService_BidiStreamCallCloseSend_Native
 func Ygrpc_StreamService_BidiStreamCallCloseSend_Native(streamHandle uint64) uint64 {
 	if err := grpc.StreamService_BidiStreamCallCloseSend(uint64(streamHandle)); err != nil {
-		return uint64(rpcruntime.StoreError(err))
+		return uint64(rpcruntime.StoreLastError(err))
 	}
 	return 0
 }
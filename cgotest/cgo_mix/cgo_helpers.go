@@ -4,14 +4,21 @@
 
 package main
 
+import (
+	rpcruntime "github.com/ygrpc/rpccgo/rpcruntime"
+)
+
 /*
 #include "ygrpc_cgo_common.h"
 */
 import "C"
 
 import (
+	"context"
+	"strings"
+	"sync"
+	"time"
 	"unsafe"
-	"github.com/ygrpc/rpccgo/rpcruntime"
 )
 
 //export Ygrpc_Free
@@ -19,27 +26,105 @@ func Ygrpc_Free(ptr unsafe.Pointer) {
 	C.free(ptr)
 }
 
+// Ygrpc_NewRuntime creates an isolated Runtime that runs the initializers of
+// the default Runtime when passed to Ygrpc_Init, and stores its handle in
+// outRuntime. Ygrpc_Shutdown releases the handle.
+//
+//export Ygrpc_NewRuntime
+func Ygrpc_NewRuntime(outRuntime *uint64) uint64 {
+	rt := rpcruntime.NewRuntimeFrom(rpcruntime.Default())
+	*outRuntime = uint64(rt.Handle())
+	return 0
+}
+
+//export Ygrpc_Init
+func Ygrpc_Init(rtHandle uint64, configPtr unsafe.Pointer, configLen int) uint64 {
+	rt, ok := rpcruntime.RuntimeFromHandle(rpcruntime.RuntimeHandle(rtHandle))
+	if !ok {
+		return uint64(rpcruntime.StoreLastError(rpcruntime.ErrInvalidRuntimeHandle))
+	}
+	var config []byte
+	if configPtr != nil && configLen > 0 {
+		config = C.GoBytes(configPtr, C.int(configLen))
+	}
+	if err := rt.Init(context.Background(), config); err != nil {
+		return uint64(rpcruntime.StoreLastError(err))
+	}
+	return 0
+}
+
 //export Ygrpc_SetProtocol
-func Ygrpc_SetProtocol(protocol int) uint64 {
+func Ygrpc_SetProtocol(rtHandle uint64, protocol int) uint64 {
+	rt, ok := rpcruntime.RuntimeFromHandle(rpcruntime.RuntimeHandle(rtHandle))
+	if !ok {
+		return uint64(rpcruntime.StoreLastError(rpcruntime.ErrInvalidRuntimeHandle))
+	}
 	switch protocol {
 	case 0:
-		rpcruntime.ClearDefaultProtocol()
+		rt.ClearDefaultProtocol()
 		return 0
 	case 1:
-		if err := rpcruntime.SetDefaultProtocol(rpcruntime.ProtocolGrpc); err != nil {
-			return uint64(rpcruntime.StoreError(err))
+		if err := rt.SetDefaultProtocol(rpcruntime.ProtocolGrpc); err != nil {
+			return uint64(rpcruntime.StoreLastError(err))
 		}
 		return 0
 	case 2:
-		if err := rpcruntime.SetDefaultProtocol(rpcruntime.ProtocolConnectRPC); err != nil {
-			return uint64(rpcruntime.StoreError(err))
+		if err := rt.SetDefaultProtocol(rpcruntime.ProtocolConnectRPC); err != nil {
+			return uint64(rpcruntime.StoreLastError(err))
+		}
+		return 0
+	case 3:
+		if err := rt.SetDefaultProtocol(rpcruntime.ProtocolGo); err != nil {
+			return uint64(rpcruntime.StoreLastError(err))
+		}
+		return 0
+	case 4:
+		if err := rt.SetDefaultProtocol(rpcruntime.ProtocolTwirp); err != nil {
+			return uint64(rpcruntime.StoreLastError(err))
 		}
 		return 0
 	default:
-		return uint64(rpcruntime.StoreError(rpcruntime.ErrUnknownProtocol))
+		return uint64(rpcruntime.StoreLastError(rpcruntime.ErrUnknownProtocol))
 	}
 }
 
+//export Ygrpc_SetProtocolPreference
+func Ygrpc_SetProtocolPreference(rtHandle uint64, target *C.char, targetLen C.int, protocols *C.int, protocolsLen C.int) uint64 {
+	rt, ok := rpcruntime.RuntimeFromHandle(rpcruntime.RuntimeHandle(rtHandle))
+	if !ok {
+		return uint64(rpcruntime.StoreLastError(rpcruntime.ErrInvalidRuntimeHandle))
+	}
+	var order []rpcruntime.Protocol
+	if protocols != nil && protocolsLen > 0 {
+		for _, p := range unsafe.Slice(protocols, int(protocolsLen)) {
+			switch p {
+			case 1:
+				order = append(order, rpcruntime.ProtocolGrpc)
+			case 2:
+				order = append(order, rpcruntime.ProtocolConnectRPC)
+			case 3:
+				order = append(order, rpcruntime.ProtocolGo)
+			case 4:
+				order = append(order, rpcruntime.ProtocolTwirp)
+			default:
+				return uint64(rpcruntime.StoreLastError(rpcruntime.ErrUnknownProtocol))
+			}
+		}
+	}
+	var err error
+	if target == nil || targetLen <= 0 {
+		err = rt.SetProtocolPreference(order...)
+	} else if name := C.GoStringN(target, targetLen); strings.HasPrefix(name, "/") {
+		err = rt.SetMethodProtocolPreference(name, order...)
+	} else {
+		err = rt.SetServiceProtocolPreference(name, order...)
+	}
+	if err != nil {
+		return uint64(rpcruntime.StoreLastError(err))
+	}
+	return 0
+}
+
 //export Ygrpc_GetErrorMsg
 func Ygrpc_GetErrorMsg(errorID uint64, msgPtr *unsafe.Pointer, msgLen *int, msgFree *unsafe.Pointer) uint64 {
 	msg, ok := rpcruntime.GetErrorMsgBytes(uint64(errorID))
@@ -58,3 +143,135 @@ func Ygrpc_GetErrorMsg(errorID uint64, msgPtr *unsafe.Pointer, msgLen *int, msgF
 	*msgFree = (unsafe.Pointer)(C.Ygrpc_Free)
 	return 0
 }
+
+//export Ygrpc_ErrorIs
+func Ygrpc_ErrorIs(errorID uint64, kind int) int {
+	if rpcruntime.ErrorIs(errorID, rpcruntime.ErrorKind(kind)) {
+		return 1
+	}
+	return 0
+}
+
+//export Ygrpc_SetLogCallback
+func Ygrpc_SetLogCallback(level int, fn unsafe.Pointer) {
+	if fn == nil {
+		rpcruntime.SetLogSink(0, nil)
+		return
+	}
+	rpcruntime.SetLogSink(rpcruntime.LogLevel(level), func(level rpcruntime.LogLevel, msg string) {
+		cmsg := C.CString(msg)
+		C.call_on_log(fn, C.int(level), cmsg, C.int(len(msg)))
+		C.free(unsafe.Pointer(cmsg))
+	})
+}
+
+//export Ygrpc_SetErrorHook
+func Ygrpc_SetErrorHook(fn unsafe.Pointer) {
+	if fn == nil {
+		rpcruntime.SetErrorHook(nil)
+		return
+	}
+	rpcruntime.SetErrorHook(func(errorID uint64, msg string) {
+		cmsg := C.CString(msg)
+		C.call_on_error(fn, C.uint64_t(errorID), cmsg, C.int(len(msg)))
+		C.free(unsafe.Pointer(cmsg))
+	})
+}
+
+// registryWatcher is the watcher installed for one runtime handle.
+type registryWatcher struct {
+	gen    uint64
+	cancel func()
+}
+
+// registryWatcherMu guards the installed watchers; it is not held while a
+// watcher runs, so the C callback may call back into the library.
+var (
+	registryWatcherMu  sync.Mutex
+	registryWatchers   = make(map[uint64]registryWatcher)
+	registryWatcherGen uint64
+)
+
+//export Ygrpc_SetRegistryWatcher
+func Ygrpc_SetRegistryWatcher(rtHandle uint64, fn unsafe.Pointer) uint64 {
+	registryWatcherMu.Lock()
+	registryWatcherGen++
+	gen := registryWatcherGen
+	prev := registryWatchers[rtHandle]
+	if fn == nil {
+		delete(registryWatchers, rtHandle)
+	} else {
+		registryWatchers[rtHandle] = registryWatcher{gen: gen}
+	}
+	registryWatcherMu.Unlock()
+	if prev.cancel != nil {
+		prev.cancel()
+	}
+	if fn == nil {
+		return 0
+	}
+	rt, ok := rpcruntime.RuntimeFromHandle(rpcruntime.RuntimeHandle(rtHandle))
+	if !ok {
+		return uint64(rpcruntime.StoreLastError(rpcruntime.ErrInvalidRuntimeHandle))
+	}
+	cancel := rt.WatchRegistry(func(event rpcruntime.RegistryEvent) {
+		protocol := 0
+		switch event.Protocol {
+		case rpcruntime.ProtocolGrpc:
+			protocol = 1
+		case rpcruntime.ProtocolConnectRPC:
+			protocol = 2
+		case rpcruntime.ProtocolGo:
+			protocol = 3
+		case rpcruntime.ProtocolTwirp:
+			protocol = 4
+		}
+		cname := C.CString(event.ServiceName)
+		C.call_on_registry_event(fn, C.int(event.Kind), C.int(protocol), cname, C.int(len(event.ServiceName)))
+		C.free(unsafe.Pointer(cname))
+	})
+
+	// A later call may have replaced fn while its initial events were delivered.
+	registryWatcherMu.Lock()
+	if registryWatchers[rtHandle].gen != gen {
+		registryWatcherMu.Unlock()
+		cancel()
+		return 0
+	}
+	registryWatchers[rtHandle] = registryWatcher{gen: gen, cancel: cancel}
+	registryWatcherMu.Unlock()
+	return 0
+}
+
+// Ygrpc_Shutdown rejects new calls, waits up to timeoutMs milliseconds for
+// in-flight calls and open streams to finish and cancels the rest.
+// A timeoutMs <= 0 waits without a deadline. The handle of a Runtime created
+// with Ygrpc_NewRuntime is released.
+//
+//export Ygrpc_Shutdown
+func Ygrpc_Shutdown(rtHandle uint64, timeoutMs int) uint64 {
+	rt, ok := rpcruntime.RuntimeFromHandle(rpcruntime.RuntimeHandle(rtHandle))
+	if !ok {
+		return uint64(rpcruntime.StoreLastError(rpcruntime.ErrInvalidRuntimeHandle))
+	}
+	ctx := context.Background()
+	if timeoutMs > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, time.Duration(timeoutMs)*time.Millisecond)
+		defer cancel()
+	}
+	if err := rt.Shutdown(ctx); err != nil {
+		return uint64(rpcruntime.StoreLastError(err))
+	}
+	return 0
+}
+
+//export Ygrpc_GetLastError
+func Ygrpc_GetLastError() uint64 {
+	return rpcruntime.LastError()
+}
+
+//export Ygrpc_ClearLastError
+func Ygrpc_ClearLastError() {
+	rpcruntime.ClearLastError()
+}
@@ -136,9 +136,24 @@ func (s *streamServiceMixGrpc) BidiStreamCall(stream cgotest_mix.StreamService_B
 }
 
 func init() {
-	_, _ = rpcruntime.RegisterConnectHandler(cgotest_mix.TestService_ServiceName, &testServiceMixConnect{})
-	_, _ = rpcruntime.RegisterConnectHandler(cgotest_mix.StreamService_ServiceName, &streamServiceMixConnect{})
+	// Handlers are registered once the host calls Ygrpc_Init.
+	// The Runtime being initialized is the default one or one created with
+	// Ygrpc_NewRuntime.
+	rpcruntime.OnInit(func(ctx context.Context, config []byte) error {
+		rt := rpcruntime.RuntimeFromContext(ctx)
+		if _, err := rt.RegisterConnectHandler(cgotest_mix.TestService_ServiceName, &testServiceMixConnect{}); err != nil {
+			return err
+		}
+		if _, err := rt.RegisterConnectHandler(cgotest_mix.StreamService_ServiceName, &streamServiceMixConnect{}); err != nil {
+			return err
+		}
 
-	_, _ = rpcruntime.RegisterGrpcHandler(cgotest_mix.TestService_ServiceName, &testServiceMixGrpc{})
-	_, _ = rpcruntime.RegisterGrpcHandler(cgotest_mix.StreamService_ServiceName, &streamServiceMixGrpc{})
+		if _, err := rt.RegisterGrpcHandler(cgotest_mix.TestService_ServiceName, &testServiceMixGrpc{}); err != nil {
+			return err
+		}
+		if _, err := rt.RegisterGrpcHandler(cgotest_mix.StreamService_ServiceName, &streamServiceMixGrpc{}); err != nil {
+			return err
+		}
+		return nil
+	})
 }
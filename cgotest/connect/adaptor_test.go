@@ -3,13 +3,22 @@ package cgotest_connect
 import (
 	"connectrpc.com/connect"
 	"context"
+	"errors"
 	"github.com/ygrpc/rpccgo/cgotest/testutil"
 	"github.com/ygrpc/rpccgo/rpcruntime"
+	"github.com/ygrpc/rpccgo/rpcruntime/connectrt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"sync"
 	"testing"
 	"time"
 )
 
+// The adaptor's simple-API interface must stay in sync with connect-go's handler.
+var _ TestService_ConnectHandler = TestServiceHandler(nil)
+
 type mockTestServiceHandler struct {
 	UnimplementedTestServiceHandler
 }
@@ -105,6 +114,13 @@ func TestConnectAdaptor(t *testing.T) {
 			return resp.GetMsg(), nil
 		}, "hello", "pong: hello")
 	})
+	t.Run("TypedRegistration", func(t *testing.T) {
+		_, err := RegisterTestServiceConnectHandler(&mockTestServiceHandler{})
+		testutil.RequireNoError(t, err)
+		resp, err := TestService_Ping(context.Background(), &PingRequest{Msg: "typed"})
+		testutil.RequireNoError(t, err)
+		testutil.RequireStringEqual(t, resp.GetMsg(), "pong: typed")
+	})
 	t.Run("ClientStreaming", func(t *testing.T) {
 		testutil.RunClientStreamTest(t, registerConnect(t, StreamService_ServiceName, &mockStreamServiceHandlerFull{}), func(ctx context.Context) (uint64, error) { return StreamService_ClientStreamCallStart(ctx) }, func(handle uint64, data string) error {
 			return StreamService_ClientStreamCallSend(handle, &StreamRequest{Data: data})
@@ -145,3 +161,326 @@ func TestConnectAdaptor(t *testing.T) {
 		}, func(handle uint64) { testutil.RequireNoError(t, StreamService_BidiStreamCallCloseSend(handle)) }, []string{"X", "Y", "Z"}, []string{"echo:X", "echo:Y", "echo:Z"})
 	})
 }
+
+// mockGenericStreamServiceHandler implements the Connect generic API and
+// echoes the X-Test request header back as a response header.
+type mockGenericStreamServiceHandler struct{}
+
+var _ StreamService_ConnectGenericHandler = (*mockGenericStreamServiceHandler)(nil)
+
+func (m *mockGenericStreamServiceHandler) UnaryCall(
+	_ context.Context,
+	req *connect.Request[StreamRequest],
+) (*connect.Response[StreamResponse], error) {
+	resp := connect.NewResponse(&StreamResponse{Result: "generic:" + req.Msg.GetData()})
+	resp.Header().Set("X-Echo", req.Header().Get("X-Test"))
+	resp.Trailer().Set("X-Done", "unary")
+	return resp, nil
+}
+
+func (m *mockGenericStreamServiceHandler) ClientStreamCall(
+	_ context.Context,
+	stream *connect.ClientStream[StreamRequest],
+) (*connect.Response[StreamResponse], error) {
+	var builder strings.Builder
+	for stream.Receive() {
+		builder.WriteString(stream.Msg().GetData())
+	}
+	if err := stream.Err(); err != nil {
+		return nil, err
+	}
+	resp := connect.NewResponse(&StreamResponse{Result: "generic received:" + builder.String()})
+	resp.Header().Set("X-Echo", stream.RequestHeader().Get("X-Test"))
+	return resp, nil
+}
+
+func (m *mockGenericStreamServiceHandler) ServerStreamCall(
+	_ context.Context,
+	req *connect.Request[StreamRequest],
+	stream *connect.ServerStream[StreamResponse],
+) error {
+	stream.ResponseHeader().Set("X-Echo", req.Header().Get("X-Test"))
+	if err := stream.Send(&StreamResponse{Result: "generic:" + req.Msg.GetData()}); err != nil {
+		return err
+	}
+	stream.ResponseTrailer().Set("X-Done", "server")
+	return nil
+}
+
+func (m *mockGenericStreamServiceHandler) BidiStreamCall(
+	_ context.Context,
+	stream *connect.BidiStream[StreamRequest, StreamResponse],
+) error {
+	stream.ResponseHeader().Set("X-Echo", stream.RequestHeader().Get("X-Test"))
+	for {
+		req, err := stream.Receive()
+		if err != nil {
+			return nil
+		}
+		if err := stream.Send(&StreamResponse{Result: "generic echo:" + req.GetData()}); err != nil {
+			return err
+		}
+	}
+}
+
+// TestConnectAdaptor_GenericAPI verifies generic-API handlers are detected and
+// that request and response headers propagate through rpcruntime, with both
+// Connect streaming bridges.
+func TestConnectAdaptor_GenericAPI(t *testing.T) {
+	for _, bridge := range []rpcruntime.ConnectStreamBridge{
+		rpcruntime.ConnectStreamBridgeReflect,
+		rpcruntime.ConnectStreamBridgeHTTP,
+	} {
+		t.Run(bridge.String(), func(t *testing.T) {
+			testConnectGenericAPI(t, bridge)
+		})
+	}
+}
+
+func testConnectGenericAPI(t *testing.T, bridge rpcruntime.ConnectStreamBridge) {
+	rt := rpcruntime.NewRuntime()
+	testutil.RequireNoError(t, rt.SetConnectStreamBridge(bridge))
+	_, err := rt.RegisterConnectHandler(StreamService_ServiceName, &mockGenericStreamServiceHandler{})
+	testutil.RequireNoError(t, err)
+
+	newCtx := func() (context.Context, *rpcruntime.ResponseMetadata) {
+		ctx := rpcruntime.WithRuntime(context.Background(), rt)
+		ctx = rpcruntime.WithRequestHeader(ctx, http.Header{"X-Test": []string{"v1"}})
+		return rpcruntime.WithResponseMetadata(ctx)
+	}
+
+	t.Run("Unary", func(t *testing.T) {
+		ctx, md := newCtx()
+		resp, err := StreamService_UnaryCall(ctx, &StreamRequest{Data: "u"})
+		testutil.RequireNoError(t, err)
+		testutil.RequireStringEqual(t, resp.GetResult(), "generic:u")
+		testutil.RequireStringEqual(t, md.Header.Get("X-Echo"), "v1")
+		testutil.RequireStringEqual(t, md.Trailer.Get("X-Done"), "unary")
+	})
+
+	t.Run("ClientStream", func(t *testing.T) {
+		ctx, md := newCtx()
+		handle, err := StreamService_ClientStreamCallStart(ctx)
+		testutil.RequireNoError(t, err)
+		for _, data := range []string{"A", "B"} {
+			testutil.RequireNoError(t, StreamService_ClientStreamCallSend(handle, &StreamRequest{Data: data}))
+		}
+		resp, err := StreamService_ClientStreamCallFinish(handle)
+		testutil.RequireNoError(t, err)
+		testutil.RequireStringEqual(t, resp.GetResult(), "generic received:AB")
+		testutil.RequireStringEqual(t, md.Header.Get("X-Echo"), "v1")
+	})
+
+	t.Run("ServerStream", func(t *testing.T) {
+		ctx, md := newCtx()
+		var got []string
+		err := StreamService_ServerStreamCall(ctx, &StreamRequest{Data: "s"}, func(resp *StreamResponse) bool {
+			got = append(got, resp.GetResult())
+			return true
+		}, func(error) {})
+		testutil.RequireNoError(t, err)
+		testutil.RequireStringEqual(t, strings.Join(got, ","), "generic:s")
+		testutil.RequireStringEqual(t, md.Header.Get("X-Echo"), "v1")
+		testutil.RequireStringEqual(t, md.Trailer.Get("X-Done"), "server")
+	})
+
+	t.Run("BidiStream", func(t *testing.T) {
+		ctx, md := newCtx()
+		var got []string
+		done := make(chan error, 1)
+		handle, err := StreamService_BidiStreamCallStart(ctx, func(resp *StreamResponse) bool {
+			got = append(got, resp.GetResult())
+			return true
+		}, func(err error) { done <- err })
+		testutil.RequireNoError(t, err)
+		testutil.RequireNoError(t, StreamService_BidiStreamCallSend(handle, &StreamRequest{Data: "X"}))
+		testutil.RequireNoError(t, StreamService_BidiStreamCallCloseSend(handle))
+		testutil.RequireNoError(t, <-done)
+		testutil.RequireStringEqual(t, strings.Join(got, ","), "generic echo:X")
+		testutil.RequireStringEqual(t, md.Header.Get("X-Echo"), "v1")
+	})
+}
+
+// TestConnectAdaptor_HTTPClient verifies generated Connect clients reach
+// registered handlers through connectrt.HTTPClient.
+func TestConnectAdaptor_HTTPClient(t *testing.T) {
+	rt := rpcruntime.NewRuntime()
+	_, err := rt.RegisterConnectHandler(StreamService_ServiceName, &mockGenericStreamServiceHandler{})
+	testutil.RequireNoError(t, err)
+	client := NewStreamServiceClient(connectrt.For(rt).HTTPClient(), "inproc://")
+
+	t.Run("Unary", func(t *testing.T) {
+		for _, opts := range [][]connect.ClientOption{nil, {connect.WithGRPC()}} {
+			ctx, info := connect.NewClientContext(context.Background())
+			info.RequestHeader().Set("X-Test", "v1")
+			resp, err := NewStreamServiceClient(connectrt.For(rt).HTTPClient(), "inproc://", opts...).
+				UnaryCall(ctx, &StreamRequest{Data: "u"})
+			testutil.RequireNoError(t, err)
+			testutil.RequireStringEqual(t, resp.GetResult(), "generic:u")
+			testutil.RequireStringEqual(t, info.ResponseHeader().Get("X-Echo"), "v1")
+			testutil.RequireStringEqual(t, info.ResponseTrailer().Get("X-Done"), "unary")
+		}
+	})
+
+	t.Run("ClientStream", func(t *testing.T) {
+		stream, err := client.ClientStreamCall(context.Background())
+		testutil.RequireNoError(t, err)
+		for _, data := range []string{"A", "B"} {
+			testutil.RequireNoError(t, stream.Send(&StreamRequest{Data: data}))
+		}
+		resp, err := stream.CloseAndReceive()
+		testutil.RequireNoError(t, err)
+		testutil.RequireStringEqual(t, resp.GetResult(), "generic received:AB")
+	})
+
+	t.Run("ServerStream", func(t *testing.T) {
+		ctx, info := connect.NewClientContext(context.Background())
+		info.RequestHeader().Set("X-Test", "v2")
+		stream, err := client.ServerStreamCall(ctx, &StreamRequest{Data: "s"})
+		testutil.RequireNoError(t, err)
+		var got []string
+		for stream.Receive() {
+			got = append(got, stream.Msg().GetResult())
+		}
+		testutil.RequireNoError(t, stream.Err())
+		testutil.RequireStringEqual(t, strings.Join(got, ","), "generic:s")
+		testutil.RequireStringEqual(t, stream.ResponseHeader().Get("X-Echo"), "v2")
+		testutil.RequireStringEqual(t, stream.ResponseTrailer().Get("X-Done"), "server")
+	})
+
+	t.Run("BidiStream", func(t *testing.T) {
+		stream, err := client.BidiStreamCall(context.Background())
+		testutil.RequireNoError(t, err)
+		testutil.RequireNoError(t, stream.Send(&StreamRequest{Data: "X"}))
+		resp, err := stream.Receive()
+		testutil.RequireNoError(t, err)
+		testutil.RequireStringEqual(t, resp.GetResult(), "generic echo:X")
+		testutil.RequireNoError(t, stream.CloseRequest())
+		_, err = stream.Receive()
+		testutil.RequireEqual(t, errors.Is(err, io.EOF), true)
+		testutil.RequireNoError(t, stream.CloseResponse())
+	})
+
+	t.Run("ServiceNotRegistered", func(t *testing.T) {
+		_, err := NewTestServiceClient(connectrt.For(rt).HTTPClient(), "inproc://").Ping(context.Background(), &PingRequest{})
+		testutil.RequireEqual(t, connect.CodeOf(err), connect.CodeUnimplemented)
+	})
+}
+
+// TestConnectAdaptor_HTTPHandler verifies registered handlers are
+// reachable through connectrt.HTTPHandler on a real HTTP server.
+func TestConnectAdaptor_HTTPHandler(t *testing.T) {
+	rt := rpcruntime.NewRuntime()
+	_, err := rt.RegisterConnectHandler(StreamService_ServiceName, &mockGenericStreamServiceHandler{})
+	testutil.RequireNoError(t, err)
+	server := httptest.NewServer(connectrt.For(rt).HTTPHandler())
+	defer server.Close()
+	client := NewStreamServiceClient(server.Client(), server.URL)
+
+	t.Run("Unary", func(t *testing.T) {
+		ctx, info := connect.NewClientContext(context.Background())
+		info.RequestHeader().Set("X-Test", "v1")
+		resp, err := client.UnaryCall(ctx, &StreamRequest{Data: "u"})
+		testutil.RequireNoError(t, err)
+		testutil.RequireStringEqual(t, resp.GetResult(), "generic:u")
+		testutil.RequireStringEqual(t, info.ResponseHeader().Get("X-Echo"), "v1")
+	})
+
+	t.Run("ServerStream", func(t *testing.T) {
+		stream, err := client.ServerStreamCall(context.Background(), &StreamRequest{Data: "s"})
+		testutil.RequireNoError(t, err)
+		var got []string
+		for stream.Receive() {
+			got = append(got, stream.Msg().GetResult())
+		}
+		testutil.RequireNoError(t, stream.Err())
+		testutil.RequireStringEqual(t, strings.Join(got, ","), "generic:s")
+	})
+
+	t.Run("UnknownMethod", func(t *testing.T) {
+		_, err := NewTestServiceClient(server.Client(), server.URL).Ping(context.Background(), &PingRequest{})
+		testutil.RequireEqual(t, connect.CodeOf(err), connect.CodeUnimplemented)
+	})
+}
+
+// TestConnectAdaptor_RemoteForwarding verifies adaptors forward calls for a
+// service without a local handler to its registered Connect remote, over both
+// the Connect and the gRPC protocol.
+func TestConnectAdaptor_RemoteForwarding(t *testing.T) {
+	server := rpcruntime.NewRuntime()
+	_, err := server.RegisterConnectHandler(StreamService_ServiceName, &mockGenericStreamServiceHandler{})
+	testutil.RequireNoError(t, err)
+
+	for _, opts := range [][]connect.ClientOption{nil, {connect.WithGRPC()}} {
+		local := rpcruntime.NewRuntime()
+		_, err := local.RegisterRemote(
+			StreamService_ServiceName,
+			connectrt.NewRemote(connectrt.For(server).HTTPClient(), "inproc://", opts...),
+		)
+		testutil.RequireNoError(t, err)
+		ctx := rpcruntime.WithRuntime(context.Background(), local)
+		ctx = rpcruntime.WithRequestHeader(ctx, http.Header{"X-Test": []string{"v1"}})
+
+		t.Run("Unary", func(t *testing.T) {
+			ctx, md := rpcruntime.WithResponseMetadata(ctx)
+			resp, err := StreamService_UnaryCall(ctx, &StreamRequest{Data: "u"})
+			testutil.RequireNoError(t, err)
+			testutil.RequireStringEqual(t, resp.GetResult(), "generic:u")
+			testutil.RequireStringEqual(t, md.Header.Get("X-Echo"), "v1")
+			testutil.RequireStringEqual(t, md.Trailer.Get("X-Done"), "unary")
+		})
+
+		t.Run("ClientStream", func(t *testing.T) {
+			ctx, md := rpcruntime.WithResponseMetadata(ctx)
+			handle, err := StreamService_ClientStreamCallStart(ctx)
+			testutil.RequireNoError(t, err)
+			for _, data := range []string{"A", "B"} {
+				testutil.RequireNoError(t, StreamService_ClientStreamCallSend(handle, &StreamRequest{Data: data}))
+			}
+			resp, err := StreamService_ClientStreamCallFinish(handle)
+			testutil.RequireNoError(t, err)
+			testutil.RequireStringEqual(t, resp.GetResult(), "generic received:AB")
+			testutil.RequireStringEqual(t, md.Header.Get("X-Echo"), "v1")
+		})
+
+		t.Run("ServerStream", func(t *testing.T) {
+			ctx, md := rpcruntime.WithResponseMetadata(ctx)
+			var got []string
+			done := make(chan error, 1)
+			err := StreamService_ServerStreamCall(ctx, &StreamRequest{Data: "s"}, func(resp *StreamResponse) bool {
+				got = append(got, resp.GetResult())
+				return true
+			}, func(err error) { done <- err })
+			testutil.RequireNoError(t, err)
+			testutil.RequireNoError(t, <-done)
+			testutil.RequireStringEqual(t, strings.Join(got, ","), "generic:s")
+			testutil.RequireStringEqual(t, md.Trailer.Get("X-Done"), "server")
+		})
+
+		t.Run("BidiStream", func(t *testing.T) {
+			recv := make(chan string, 2)
+			done := make(chan error, 1)
+			handle, err := StreamService_BidiStreamCallStart(ctx, func(resp *StreamResponse) bool {
+				recv <- resp.GetResult()
+				return true
+			}, func(err error) { done <- err })
+			testutil.RequireNoError(t, err)
+			for _, data := range []string{"X", "Y"} {
+				testutil.RequireNoError(t, StreamService_BidiStreamCallSend(handle, &StreamRequest{Data: data}))
+				testutil.RequireStringEqual(t, <-recv, "generic echo:"+data)
+			}
+			testutil.RequireNoError(t, StreamService_BidiStreamCallCloseSend(handle))
+			testutil.RequireNoError(t, <-done)
+		})
+
+		t.Run("RemoteError", func(t *testing.T) {
+			_, err := local.RegisterRemote(
+				TestService_ServiceName,
+				connectrt.NewRemote(connectrt.For(server).HTTPClient(), "inproc://", opts...),
+			)
+			testutil.RequireNoError(t, err)
+			_, err = TestService_Ping(ctx, &PingRequest{})
+			testutil.RequireEqual(t, connect.CodeOf(err), connect.CodeUnimplemented)
+		})
+	}
+}
@@ -9,6 +9,7 @@ import (
 	connect "connectrpc.com/connect"
 	context "context"
 	rpcruntime "github.com/ygrpc/rpccgo/rpcruntime"
+	connectrt "github.com/ygrpc/rpccgo/rpcruntime/connectrt"
 )
 
 // StreamService adaptor constants.
@@ -20,37 +21,101 @@ const (
 	StreamService_BidiStreamCall_FullMethod   = "/cgotest.StreamService/BidiStreamCall"
 )
 
-// StreamService_lookupHandler selects a protocol and looks up the registered handler.
+// StreamService_lookupHandler admits a call of fullMethod under the configured concurrency
+// limits, selects a protocol and acquires the registered handler.
+// The returned release func must be called once the call has finished.
+//
+// Handlers are looked up in rpcruntime.RuntimeFromContext(ctx).
+func StreamService_lookupHandler(ctx context.Context, fullMethod string) (rpcruntime.Protocol, any, func(), error) {
+	rt := rpcruntime.RuntimeFromContext(ctx)
+	releaseSlot, err := rt.AcquireCallSlot(ctx, fullMethod)
+	if err != nil {
+		return "", nil, nil, err
+	}
+	protocol, h, release, err := streamService_acquireHandler(ctx, rt)
+	if err != nil {
+		releaseSlot()
+		return protocol, nil, nil, err
+	}
+	return protocol, h, func() {
+		release()
+		releaseSlot()
+	}, nil
+}
+
+// streamService_acquireHandler selects a protocol and acquires the registered handler from rt.
 //
 // Selection rules:
 // - Supported protocol: connectrpc
 // - If ctx explicitly carries a protocol, it must match the supported protocol.
 // - Otherwise, the supported protocol is used.
-func StreamService_lookupHandler(ctx context.Context) (rpcruntime.Protocol, any, error) {
+func streamService_acquireHandler(ctx context.Context, rt *rpcruntime.Runtime) (rpcruntime.Protocol, any, func(), error) {
 	protocol, hasProtocol := rpcruntime.ProtocolFromContext(ctx)
 	if hasProtocol && protocol != rpcruntime.ProtocolConnectRPC {
-		return protocol, nil, rpcruntime.ErrUnknownProtocol
+		return protocol, nil, nil, rpcruntime.ErrUnknownProtocol
 	}
-	h, ok := rpcruntime.LookupConnectHandler(StreamService_ServiceName)
-	if !ok {
-		return "", nil, rpcruntime.ErrServiceNotRegistered
+	h, release, err := rt.AcquireHandler(rpcruntime.ProtocolConnectRPC, StreamService_ServiceName)
+	if err != nil {
+		return "", nil, nil, err
 	}
-	return rpcruntime.ProtocolConnectRPC, h, nil
+	return rpcruntime.ProtocolConnectRPC, h, release, nil
+}
+
+// StreamService_ConnectHandler is the Connect simple-API handler interface the StreamService adaptor
+// dispatches to.
+type StreamService_ConnectHandler interface {
+	UnaryCall(context.Context, *StreamRequest) (*StreamResponse, error)
+	ClientStreamCall(context.Context, *connect.ClientStream[StreamRequest]) (*StreamResponse, error)
+	ServerStreamCall(context.Context, *StreamRequest, *connect.ServerStream[StreamResponse]) error
+	BidiStreamCall(context.Context, *connect.BidiStream[StreamRequest, StreamResponse]) error
+}
+
+// RegisterStreamServiceConnectHandler registers h as the connectrpc handler for StreamService.
+// It returns true if a previous handler was replaced.
+func RegisterStreamServiceConnectHandler(h StreamService_ConnectHandler) (bool, error) {
+	return rpcruntime.RegisterConnectHandler(StreamService_ServiceName, h)
+}
+
+// StreamService_ConnectGenericHandler is the Connect generic-API handler interface the StreamService
+// adaptor dispatches to; requests and responses are wrapped in connect.Request and
+// connect.Response so handlers can read headers and set trailers.
+type StreamService_ConnectGenericHandler interface {
+	UnaryCall(context.Context, *connect.Request[StreamRequest]) (*connect.Response[StreamResponse], error)
+	ClientStreamCall(context.Context, *connect.ClientStream[StreamRequest]) (*connect.Response[StreamResponse], error)
+	ServerStreamCall(context.Context, *connect.Request[StreamRequest], *connect.ServerStream[StreamResponse]) error
+	BidiStreamCall(context.Context, *connect.BidiStream[StreamRequest, StreamResponse]) error
+}
+
+// RegisterStreamServiceConnectGenericHandler registers h as the connectrpc handler for StreamService.
+// It returns true if a previous handler was replaced.
+func RegisterStreamServiceConnectGenericHandler(h StreamService_ConnectGenericHandler) (bool, error) {
+	return rpcruntime.RegisterConnectHandler(StreamService_ServiceName, h)
 }
 
 // StreamService_UnaryCall calls cgotest.StreamService.UnaryCall via the registered handler.
 func StreamService_UnaryCall(ctx context.Context, req *StreamRequest) (*StreamResponse, error) {
-	_, h, err := StreamService_lookupHandler(ctx)
+	_, h, release, err := StreamService_lookupHandler(ctx, StreamService_UnaryCall_FullMethod)
 	if err != nil {
+		if remote, ok := rpcruntime.RuntimeFromContext(ctx).RemoteFallback(StreamService_ServiceName, err); ok {
+			return rpcruntime.ForwardUnary[StreamRequest, StreamResponse](ctx, remote, StreamService_UnaryCall_FullMethod, req)
+		}
 		return nil, err
 	}
-	svc, ok := h.(interface {
+	defer release()
+	ctx, cancel := rpcruntime.CallContext(ctx)
+	defer cancel()
+	if svc, ok := h.(interface {
 		UnaryCall(context.Context, *StreamRequest) (*StreamResponse, error)
+	}); ok {
+		return svc.UnaryCall(ctx, req)
+	}
+	svc, ok := h.(interface {
+		UnaryCall(context.Context, *connect.Request[StreamRequest]) (*connect.Response[StreamResponse], error)
 	})
 	if !ok {
 		return nil, rpcruntime.ErrHandlerTypeMismatch
 	}
-	return svc.UnaryCall(ctx, req)
+	return connectrt.CallUnary(ctx, req, svc.UnaryCall)
 }
 
 // StreamService_ClientStreamCall client-streaming adaptor functions.
@@ -58,15 +123,31 @@ func StreamService_UnaryCall(ctx context.Context, req *StreamRequest) (*StreamRe
 
 // StreamService_ClientStreamCallStart initializes a client-streaming call and returns a stream handle.
 func StreamService_ClientStreamCallStart(ctx context.Context) (uint64, error) {
-	protocol, h, err := StreamService_lookupHandler(ctx)
+	protocol, h, release, err := StreamService_lookupHandler(ctx, StreamService_ClientStreamCall_FullMethod)
 	if err != nil {
+		if remote, ok := rpcruntime.RuntimeFromContext(ctx).RemoteFallback(StreamService_ServiceName, err); ok {
+			return rpcruntime.ForwardClientStream[StreamRequest, StreamResponse](ctx, remote, StreamService_ClientStreamCall_FullMethod)
+		}
 		return 0, err
 	}
+	// release is handed over to the handler goroutine once it starts.
+	handlerStarted := false
+	defer func() {
+		if !handlerStarted {
+			release()
+		}
+	}()
 
-	svc, ok := h.(interface {
+	var svc func(context.Context, *connect.ClientStream[StreamRequest]) (*StreamResponse, error)
+	if simple, ok := h.(interface {
 		ClientStreamCall(context.Context, *connect.ClientStream[StreamRequest]) (*StreamResponse, error)
-	})
-	if !ok {
+	}); ok {
+		svc = simple.ClientStreamCall
+	} else if generic, ok := h.(interface {
+		ClientStreamCall(context.Context, *connect.ClientStream[StreamRequest]) (*connect.Response[StreamResponse], error)
+	}); ok {
+		svc = connectrt.ClientStreamHandler(generic.ClientStreamCall)
+	} else {
 		return 0, rpcruntime.ErrHandlerTypeMismatch
 	}
 
@@ -76,15 +157,15 @@ func StreamService_ClientStreamCallStart(ctx context.Context) (uint64, error) {
 		return 0, rpcruntime.ErrInvalidStreamHandle
 	}
 
-	conn := rpcruntime.NewConnectStreamConn(session)
-	connectStream := rpcruntime.NewClientStream[StreamRequest](conn)
+	handlerStarted = true
 	go func() {
+		defer release()
 		defer func() {
 			if r := recover(); r != nil {
 				rpcruntime.CompleteClientStream(handle, nil, rpcruntime.RecoverPanic(r))
 			}
 		}()
-		resp, err := svc.ClientStreamCall(childCtx, connectStream)
+		resp, err := connectrt.ServeClientStream(childCtx, session, StreamService_ClientStreamCall_FullMethod, svc)
 		rpcruntime.CompleteClientStream(handle, resp, err)
 	}()
 
@@ -115,16 +196,26 @@ func StreamService_ClientStreamCallFinish(streamHandle uint64) (*StreamResponse,
 // - onRead is called for each response message; return false to stop receiving.
 // - onDone is called exactly once when the stream ends or fails.
 func StreamService_ServerStreamCall(ctx context.Context, req *StreamRequest, onRead func(*StreamResponse) bool, onDone func(error)) error {
-	protocol, h, err := StreamService_lookupHandler(ctx)
+	protocol, h, release, err := StreamService_lookupHandler(ctx, StreamService_ServerStreamCall_FullMethod)
 	if err != nil {
+		if remote, ok := rpcruntime.RuntimeFromContext(ctx).RemoteFallback(StreamService_ServiceName, err); ok {
+			return rpcruntime.ForwardServerStream(ctx, remote, StreamService_ServerStreamCall_FullMethod, req, onRead, onDone)
+		}
 		onDone(err)
 		return err
 	}
+	defer release()
 
-	svc, ok := h.(interface {
+	var svc func(context.Context, *StreamRequest, *connect.ServerStream[StreamResponse]) error
+	if simple, ok := h.(interface {
 		ServerStreamCall(context.Context, *StreamRequest, *connect.ServerStream[StreamResponse]) error
-	})
-	if !ok {
+	}); ok {
+		svc = simple.ServerStreamCall
+	} else if generic, ok := h.(interface {
+		ServerStreamCall(context.Context, *connect.Request[StreamRequest], *connect.ServerStream[StreamResponse]) error
+	}); ok {
+		svc = connectrt.ServerStreamHandler(generic.ServerStreamCall)
+	} else {
 		onDone(rpcruntime.ErrHandlerTypeMismatch)
 		return rpcruntime.ErrHandlerTypeMismatch
 	}
@@ -137,9 +228,7 @@ func StreamService_ServerStreamCall(ctx context.Context, req *StreamRequest, onR
 	}
 	session.SetCallbacks(func(resp any) bool { return onRead(resp.(*StreamResponse)) }, onDone)
 
-	conn := rpcruntime.NewConnectStreamConn(session)
-	connectStream := rpcruntime.NewServerStream[StreamResponse](conn)
-	err = svc.ServerStreamCall(ctx, req, connectStream)
+	err = connectrt.ServeServerStream(ctx, session, StreamService_ServerStreamCall_FullMethod, req, svc)
 	rpcruntime.FinishStreamHandle(handle)
 	onDone(err)
 	return err
@@ -151,10 +240,20 @@ func StreamService_ServerStreamCall(ctx context.Context, req *StreamRequest, onR
 // StreamService_BidiStreamCallStart initializes a bidi-streaming call and returns a stream handle.
 // Provide onRead and onDone callbacks to receive response messages.
 func StreamService_BidiStreamCallStart(ctx context.Context, onRead func(*StreamResponse) bool, onDone func(error)) (uint64, error) {
-	protocol, h, err := StreamService_lookupHandler(ctx)
+	protocol, h, release, err := StreamService_lookupHandler(ctx, StreamService_BidiStreamCall_FullMethod)
 	if err != nil {
+		if remote, ok := rpcruntime.RuntimeFromContext(ctx).RemoteFallback(StreamService_ServiceName, err); ok {
+			return rpcruntime.ForwardBidiStream[StreamRequest, StreamResponse](ctx, remote, StreamService_BidiStreamCall_FullMethod, onRead, onDone)
+		}
 		return 0, err
 	}
+	// release is handed over to the handler goroutine once it starts.
+	handlerStarted := false
+	defer func() {
+		if !handlerStarted {
+			release()
+		}
+	}()
 
 	svc, ok := h.(interface {
 		BidiStreamCall(context.Context, *connect.BidiStream[StreamRequest, StreamResponse]) error
@@ -170,10 +269,9 @@ func StreamService_BidiStreamCallStart(ctx context.Context, onRead func(*StreamR
 	}
 	session.SetCallbacks(func(resp any) bool { return onRead(resp.(*StreamResponse)) }, onDone)
 
-	conn := rpcruntime.NewConnectStreamConn(session)
-	connectStream := rpcruntime.NewBidiStream[StreamRequest, StreamResponse](conn)
-	session.SetHandlerState(connectStream)
+	handlerStarted = true
 	go func() {
+		defer release()
 		defer func() {
 			if r := recover(); r != nil {
 				if cb := session.OnDone(); cb != nil {
@@ -182,7 +280,7 @@ func StreamService_BidiStreamCallStart(ctx context.Context, onRead func(*StreamR
 				rpcruntime.FinishStreamHandle(handle)
 			}
 		}()
-		err := svc.BidiStreamCall(childCtx, connectStream)
+		err := connectrt.ServeBidiStream(childCtx, session, StreamService_BidiStreamCall_FullMethod, svc.BidiStreamCall)
 		if cb := session.OnDone(); cb != nil {
 			cb(err)
 		}
@@ -202,3 +300,13 @@ func StreamService_BidiStreamCallSend(streamHandle uint64, req *StreamRequest) e
 func StreamService_BidiStreamCallCloseSend(streamHandle uint64) error {
 	return rpcruntime.CloseSendCh(rpcruntime.StreamHandle(streamHandle))
 }
+
+// Register the adaptor functions for dispatch by full method name (see grpcrt.ClientConn).
+func init() {
+	rpcruntime.RegisterMethods(
+		rpcruntime.NewUnaryMethodDesc(StreamService_UnaryCall_FullMethod, StreamService_UnaryCall),
+		rpcruntime.NewClientStreamMethodDesc(StreamService_ClientStreamCall_FullMethod, StreamService_ClientStreamCallStart, StreamService_ClientStreamCallSend, StreamService_ClientStreamCallFinish),
+		rpcruntime.NewServerStreamMethodDesc(StreamService_ServerStreamCall_FullMethod, StreamService_ServerStreamCall),
+		rpcruntime.NewBidiStreamMethodDesc(StreamService_BidiStreamCall_FullMethod, StreamService_BidiStreamCallStart, StreamService_BidiStreamCallSend, StreamService_BidiStreamCallCloseSend),
+	)
+}
@@ -6,8 +6,10 @@
 package cgotest_connect_suffix
 
 import (
+	connect "connectrpc.com/connect"
 	context "context"
 	rpcruntime "github.com/ygrpc/rpccgo/rpcruntime"
+	connectrt "github.com/ygrpc/rpccgo/rpcruntime/connectrt"
 )
 
 // TestService adaptor constants.
@@ -19,80 +21,187 @@ const (
 	TestService_NonFlat_FullMethod  = "/cgotest.TestService/NonFlat"
 )
 
-// TestService_lookupHandler selects a protocol and looks up the registered handler.
+// TestService_lookupHandler admits a call of fullMethod under the configured concurrency
+// limits, selects a protocol and acquires the registered handler.
+// The returned release func must be called once the call has finished.
+//
+// Handlers are looked up in rpcruntime.RuntimeFromContext(ctx).
+func TestService_lookupHandler(ctx context.Context, fullMethod string) (rpcruntime.Protocol, any, func(), error) {
+	rt := rpcruntime.RuntimeFromContext(ctx)
+	releaseSlot, err := rt.AcquireCallSlot(ctx, fullMethod)
+	if err != nil {
+		return "", nil, nil, err
+	}
+	protocol, h, release, err := testService_acquireHandler(ctx, rt)
+	if err != nil {
+		releaseSlot()
+		return protocol, nil, nil, err
+	}
+	return protocol, h, func() {
+		release()
+		releaseSlot()
+	}, nil
+}
+
+// testService_acquireHandler selects a protocol and acquires the registered handler from rt.
 //
 // Selection rules:
 // - Supported protocol: connectrpc
 // - If ctx explicitly carries a protocol, it must match the supported protocol.
 // - Otherwise, the supported protocol is used.
-func TestService_lookupHandler(ctx context.Context) (rpcruntime.Protocol, any, error) {
+func testService_acquireHandler(ctx context.Context, rt *rpcruntime.Runtime) (rpcruntime.Protocol, any, func(), error) {
 	protocol, hasProtocol := rpcruntime.ProtocolFromContext(ctx)
 	if hasProtocol && protocol != rpcruntime.ProtocolConnectRPC {
-		return protocol, nil, rpcruntime.ErrUnknownProtocol
+		return protocol, nil, nil, rpcruntime.ErrUnknownProtocol
 	}
-	h, ok := rpcruntime.LookupConnectHandler(TestService_ServiceName)
-	if !ok {
-		return "", nil, rpcruntime.ErrServiceNotRegistered
+	h, release, err := rt.AcquireHandler(rpcruntime.ProtocolConnectRPC, TestService_ServiceName)
+	if err != nil {
+		return "", nil, nil, err
 	}
-	return rpcruntime.ProtocolConnectRPC, h, nil
+	return rpcruntime.ProtocolConnectRPC, h, release, nil
+}
+
+// TestService_ConnectHandler is the Connect simple-API handler interface the TestService adaptor
+// dispatches to.
+type TestService_ConnectHandler interface {
+	Ping(context.Context, *PingRequest) (*PingResponse, error)
+	PingOpt1(context.Context, *PingRequestOpt1) (*PingResponse, error)
+	PingOpt2(context.Context, *PingRequestOpt2) (*PingResponse, error)
+	NonFlat(context.Context, *NonFlatRequest) (*PingResponse, error)
+}
+
+// RegisterTestServiceConnectHandler registers h as the connectrpc handler for TestService.
+// It returns true if a previous handler was replaced.
+func RegisterTestServiceConnectHandler(h TestService_ConnectHandler) (bool, error) {
+	return rpcruntime.RegisterConnectHandler(TestService_ServiceName, h)
+}
+
+// TestService_ConnectGenericHandler is the Connect generic-API handler interface the TestService
+// adaptor dispatches to; requests and responses are wrapped in connect.Request and
+// connect.Response so handlers can read headers and set trailers.
+type TestService_ConnectGenericHandler interface {
+	Ping(context.Context, *connect.Request[PingRequest]) (*connect.Response[PingResponse], error)
+	PingOpt1(context.Context, *connect.Request[PingRequestOpt1]) (*connect.Response[PingResponse], error)
+	PingOpt2(context.Context, *connect.Request[PingRequestOpt2]) (*connect.Response[PingResponse], error)
+	NonFlat(context.Context, *connect.Request[NonFlatRequest]) (*connect.Response[PingResponse], error)
+}
+
+// RegisterTestServiceConnectGenericHandler registers h as the connectrpc handler for TestService.
+// It returns true if a previous handler was replaced.
+func RegisterTestServiceConnectGenericHandler(h TestService_ConnectGenericHandler) (bool, error) {
+	return rpcruntime.RegisterConnectHandler(TestService_ServiceName, h)
 }
 
 // TestService_Ping calls cgotest.TestService.Ping via the registered handler.
 func TestService_Ping(ctx context.Context, req *PingRequest) (*PingResponse, error) {
-	_, h, err := TestService_lookupHandler(ctx)
+	_, h, release, err := TestService_lookupHandler(ctx, TestService_Ping_FullMethod)
 	if err != nil {
+		if remote, ok := rpcruntime.RuntimeFromContext(ctx).RemoteFallback(TestService_ServiceName, err); ok {
+			return rpcruntime.ForwardUnary[PingRequest, PingResponse](ctx, remote, TestService_Ping_FullMethod, req)
+		}
 		return nil, err
 	}
-	svc, ok := h.(interface {
+	defer release()
+	ctx, cancel := rpcruntime.CallContext(ctx)
+	defer cancel()
+	if svc, ok := h.(interface {
 		Ping(context.Context, *PingRequest) (*PingResponse, error)
+	}); ok {
+		return svc.Ping(ctx, req)
+	}
+	svc, ok := h.(interface {
+		Ping(context.Context, *connect.Request[PingRequest]) (*connect.Response[PingResponse], error)
 	})
 	if !ok {
 		return nil, rpcruntime.ErrHandlerTypeMismatch
 	}
-	return svc.Ping(ctx, req)
+	return connectrt.CallUnary(ctx, req, svc.Ping)
 }
 
 // TestService_PingOpt1 calls cgotest.TestService.PingOpt1 via the registered handler.
 func TestService_PingOpt1(ctx context.Context, req *PingRequestOpt1) (*PingResponse, error) {
-	_, h, err := TestService_lookupHandler(ctx)
+	_, h, release, err := TestService_lookupHandler(ctx, TestService_PingOpt1_FullMethod)
 	if err != nil {
+		if remote, ok := rpcruntime.RuntimeFromContext(ctx).RemoteFallback(TestService_ServiceName, err); ok {
+			return rpcruntime.ForwardUnary[PingRequestOpt1, PingResponse](ctx, remote, TestService_PingOpt1_FullMethod, req)
+		}
 		return nil, err
 	}
-	svc, ok := h.(interface {
+	defer release()
+	ctx, cancel := rpcruntime.CallContext(ctx)
+	defer cancel()
+	if svc, ok := h.(interface {
 		PingOpt1(context.Context, *PingRequestOpt1) (*PingResponse, error)
+	}); ok {
+		return svc.PingOpt1(ctx, req)
+	}
+	svc, ok := h.(interface {
+		PingOpt1(context.Context, *connect.Request[PingRequestOpt1]) (*connect.Response[PingResponse], error)
 	})
 	if !ok {
 		return nil, rpcruntime.ErrHandlerTypeMismatch
 	}
-	return svc.PingOpt1(ctx, req)
+	return connectrt.CallUnary(ctx, req, svc.PingOpt1)
 }
 
 // TestService_PingOpt2 calls cgotest.TestService.PingOpt2 via the registered handler.
 func TestService_PingOpt2(ctx context.Context, req *PingRequestOpt2) (*PingResponse, error) {
-	_, h, err := TestService_lookupHandler(ctx)
+	_, h, release, err := TestService_lookupHandler(ctx, TestService_PingOpt2_FullMethod)
 	if err != nil {
+		if remote, ok := rpcruntime.RuntimeFromContext(ctx).RemoteFallback(TestService_ServiceName, err); ok {
+			return rpcruntime.ForwardUnary[PingRequestOpt2, PingResponse](ctx, remote, TestService_PingOpt2_FullMethod, req)
+		}
 		return nil, err
 	}
-	svc, ok := h.(interface {
+	defer release()
+	ctx, cancel := rpcruntime.CallContext(ctx)
+	defer cancel()
+	if svc, ok := h.(interface {
 		PingOpt2(context.Context, *PingRequestOpt2) (*PingResponse, error)
+	}); ok {
+		return svc.PingOpt2(ctx, req)
+	}
+	svc, ok := h.(interface {
+		PingOpt2(context.Context, *connect.Request[PingRequestOpt2]) (*connect.Response[PingResponse], error)
 	})
 	if !ok {
 		return nil, rpcruntime.ErrHandlerTypeMismatch
 	}
-	return svc.PingOpt2(ctx, req)
+	return connectrt.CallUnary(ctx, req, svc.PingOpt2)
 }
 
 // TestService_NonFlat calls cgotest.TestService.NonFlat via the registered handler.
 func TestService_NonFlat(ctx context.Context, req *NonFlatRequest) (*PingResponse, error) {
-	_, h, err := TestService_lookupHandler(ctx)
+	_, h, release, err := TestService_lookupHandler(ctx, TestService_NonFlat_FullMethod)
 	if err != nil {
+		if remote, ok := rpcruntime.RuntimeFromContext(ctx).RemoteFallback(TestService_ServiceName, err); ok {
+			return rpcruntime.ForwardUnary[NonFlatRequest, PingResponse](ctx, remote, TestService_NonFlat_FullMethod, req)
+		}
 		return nil, err
 	}
-	svc, ok := h.(interface {
+	defer release()
+	ctx, cancel := rpcruntime.CallContext(ctx)
+	defer cancel()
+	if svc, ok := h.(interface {
 		NonFlat(context.Context, *NonFlatRequest) (*PingResponse, error)
+	}); ok {
+		return svc.NonFlat(ctx, req)
+	}
+	svc, ok := h.(interface {
+		NonFlat(context.Context, *connect.Request[NonFlatRequest]) (*connect.Response[PingResponse], error)
 	})
 	if !ok {
 		return nil, rpcruntime.ErrHandlerTypeMismatch
 	}
-	return svc.NonFlat(ctx, req)
+	return connectrt.CallUnary(ctx, req, svc.NonFlat)
+}
+
+// Register the adaptor functions for dispatch by full method name (see grpcrt.ClientConn).
+func init() {
+	rpcruntime.RegisterMethods(
+		rpcruntime.NewUnaryMethodDesc(TestService_Ping_FullMethod, TestService_Ping),
+		rpcruntime.NewUnaryMethodDesc(TestService_PingOpt1_FullMethod, TestService_PingOpt1),
+		rpcruntime.NewUnaryMethodDesc(TestService_PingOpt2_FullMethod, TestService_PingOpt2),
+		rpcruntime.NewUnaryMethodDesc(TestService_NonFlat_FullMethod, TestService_NonFlat),
+	)
 }
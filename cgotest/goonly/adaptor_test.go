@@ -0,0 +1,155 @@
+// Package cgotest_goonly tests the adaptor generated with protocol=go, which
+// must build without grpc-go and connect-go.
+package cgotest_goonly
+
+import (
+	"context"
+	"io"
+	"os/exec"
+	"strings"
+	"testing"
+
+	"github.com/ygrpc/rpccgo/cgotest/testutil"
+	"github.com/ygrpc/rpccgo/rpcruntime"
+)
+
+type mockGoTestServiceHandler struct{}
+
+func (m *mockGoTestServiceHandler) Ping(_ context.Context, req *PingRequest) (*PingResponse, error) {
+	return &PingResponse{Msg: "pong: " + req.GetMsg()}, nil
+}
+
+func (m *mockGoTestServiceHandler) PingOpt1(_ context.Context, req *PingRequestOpt1) (*PingResponse, error) {
+	return &PingResponse{Msg: "pong: " + req.GetMsg()}, nil
+}
+
+func (m *mockGoTestServiceHandler) PingOpt2(_ context.Context, req *PingRequestOpt2) (*PingResponse, error) {
+	return &PingResponse{Msg: "pong: " + req.GetMsg()}, nil
+}
+
+func (m *mockGoTestServiceHandler) NonFlat(_ context.Context, req *NonFlatRequest) (*PingResponse, error) {
+	return &PingResponse{Msg: "pong"}, nil
+}
+
+var _ TestService_GoHandler = (*mockGoTestServiceHandler)(nil)
+
+type mockGoStreamServiceHandler struct{}
+
+func (m *mockGoStreamServiceHandler) UnaryCall(_ context.Context, req *StreamRequest) (*StreamResponse, error) {
+	return &StreamResponse{Result: "go:" + req.GetData()}, nil
+}
+
+func (m *mockGoStreamServiceHandler) ClientStreamCall(
+	_ context.Context,
+	stream rpcruntime.GoClientStream[StreamRequest],
+) (*StreamResponse, error) {
+	var builder strings.Builder
+	for {
+		req, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		builder.WriteString(req.GetData())
+	}
+	return &StreamResponse{Result: "received:" + builder.String()}, nil
+}
+
+func (m *mockGoStreamServiceHandler) ServerStreamCall(
+	_ context.Context,
+	req *StreamRequest,
+	stream rpcruntime.GoServerStream[StreamResponse],
+) error {
+	for _, suffix := range []string{"a", "b", "c"} {
+		if err := stream.Send(&StreamResponse{Result: req.GetData() + "-" + suffix}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *mockGoStreamServiceHandler) BidiStreamCall(
+	_ context.Context,
+	stream rpcruntime.GoBidiStream[StreamRequest, StreamResponse],
+) error {
+	for {
+		req, err := stream.Recv()
+		if err != nil {
+			return nil
+		}
+		if err := stream.Send(&StreamResponse{Result: "echo:" + req.GetData()}); err != nil {
+			return err
+		}
+	}
+}
+
+var _ StreamService_GoHandler = (*mockGoStreamServiceHandler)(nil)
+
+func registerTestHandler(t *testing.T) testutil.RegisterFunc {
+	return func() func() {
+		_, err := RegisterTestServiceGoHandler(&mockGoTestServiceHandler{})
+		testutil.RequireNoError(t, err)
+		return func() {}
+	}
+}
+
+func registerStreamHandler(t *testing.T) testutil.RegisterFunc {
+	return func() func() {
+		_, err := RegisterStreamServiceGoHandler(&mockGoStreamServiceHandler{})
+		testutil.RequireNoError(t, err)
+		return func() {}
+	}
+}
+
+func TestGoAdaptor_Unary(t *testing.T) {
+	testutil.RunUnaryTest(t, registerTestHandler(t), func(ctx context.Context, msg string) (string, error) {
+		resp, err := TestService_Ping(ctx, &PingRequest{Msg: msg})
+		if err != nil {
+			return "", err
+		}
+		return resp.GetMsg(), nil
+	}, "hello", "pong: hello")
+}
+
+func TestGoAdaptor_ClientStream(t *testing.T) {
+	testutil.RunClientStreamTest(t, registerStreamHandler(t), func(ctx context.Context) (uint64, error) { return StreamService_ClientStreamCallStart(ctx) }, func(handle uint64, data string) error {
+		return StreamService_ClientStreamCallSend(handle, &StreamRequest{Data: data})
+	}, func(handle uint64) (string, error) {
+		resp, err := StreamService_ClientStreamCallFinish(handle)
+		if err != nil {
+			return "", err
+		}
+		return resp.GetResult(), nil
+	}, []string{"A", "B", "C"}, "received:ABC")
+}
+
+func TestGoAdaptor_ServerStream(t *testing.T) {
+	testutil.RunServerStreamTest(t, registerStreamHandler(t), func(ctx context.Context, msg string, onRead func(string) bool) error {
+		return StreamService_ServerStreamCall(ctx, &StreamRequest{Data: msg}, func(resp *StreamResponse) bool {
+			return onRead(resp.GetResult())
+		}, func(error) {})
+	}, "s", []string{"s-a", "s-b", "s-c"})
+}
+
+func TestGoAdaptor_BidiStream(t *testing.T) {
+	testutil.RunBidiStreamTest(t, registerStreamHandler(t), func(ctx context.Context, onRead func(string) bool, onDone func(error)) (uint64, error) {
+		return StreamService_BidiStreamCallStart(ctx, func(resp *StreamResponse) bool { return onRead(resp.GetResult()) }, onDone)
+	}, func(handle uint64, data string) error {
+		return StreamService_BidiStreamCallSend(handle, &StreamRequest{Data: data})
+	}, func(handle uint64) { testutil.RequireNoError(t, StreamService_BidiStreamCallCloseSend(handle)) }, []string{"X", "Y", "Z"}, []string{"echo:X", "echo:Y", "echo:Z"})
+}
+
+// TestGoAdaptor_Dependencies checks that a protocol=go adaptor links neither
+// grpc-go nor connect-go.
+func TestGoAdaptor_Dependencies(t *testing.T) {
+	out, err := exec.Command("go", "list", "-deps", ".").Output()
+	testutil.RequireNoError(t, err)
+	for _, pkg := range strings.Fields(string(out)) {
+		if pkg == "google.golang.org/grpc" || strings.HasPrefix(pkg, "google.golang.org/grpc/") ||
+			strings.HasPrefix(pkg, "connectrpc.com/") {
+			t.Errorf("protocol=go package depends on %s", pkg)
+		}
+	}
+}
@@ -0,0 +1,201 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        v6.33.1
+// source: stream.proto
+
+package cgotest_goonly
+
+import (
+	_ "github.com/ygrpc/rpccgo/proto/ygrpc/cgo"
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type StreamRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Data          string                 `protobuf:"bytes,1,opt,name=data,proto3" json:"data,omitempty"`
+	Sequence      int32                  `protobuf:"varint,2,opt,name=sequence,proto3" json:"sequence,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StreamRequest) Reset() {
+	*x = StreamRequest{}
+	mi := &file_stream_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StreamRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StreamRequest) ProtoMessage() {}
+
+func (x *StreamRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_stream_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StreamRequest.ProtoReflect.Descriptor instead.
+func (*StreamRequest) Descriptor() ([]byte, []int) {
+	return file_stream_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *StreamRequest) GetData() string {
+	if x != nil {
+		return x.Data
+	}
+	return ""
+}
+
+func (x *StreamRequest) GetSequence() int32 {
+	if x != nil {
+		return x.Sequence
+	}
+	return 0
+}
+
+type StreamResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Result        string                 `protobuf:"bytes,1,opt,name=result,proto3" json:"result,omitempty"`
+	Sequence      int32                  `protobuf:"varint,2,opt,name=sequence,proto3" json:"sequence,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StreamResponse) Reset() {
+	*x = StreamResponse{}
+	mi := &file_stream_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StreamResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StreamResponse) ProtoMessage() {}
+
+func (x *StreamResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_stream_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StreamResponse.ProtoReflect.Descriptor instead.
+func (*StreamResponse) Descriptor() ([]byte, []int) {
+	return file_stream_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *StreamResponse) GetResult() string {
+	if x != nil {
+		return x.Result
+	}
+	return ""
+}
+
+func (x *StreamResponse) GetSequence() int32 {
+	if x != nil {
+		return x.Sequence
+	}
+	return 0
+}
+
+var File_stream_proto protoreflect.FileDescriptor
+
+const file_stream_proto_rawDesc = "" +
+	"\n" +
+	"\fstream.proto\x12\acgotest\x1a\x17ygrpc/cgo/options.proto\"?\n" +
+	"\rStreamRequest\x12\x12\n" +
+	"\x04data\x18\x01 \x01(\tR\x04data\x12\x1a\n" +
+	"\bsequence\x18\x02 \x01(\x05R\bsequence\"D\n" +
+	"\x0eStreamResponse\x12\x16\n" +
+	"\x06result\x18\x01 \x01(\tR\x06result\x12\x1a\n" +
+	"\bsequence\x18\x02 \x01(\x05R\bsequence2\xa2\x02\n" +
+	"\rStreamService\x12<\n" +
+	"\tUnaryCall\x12\x16.cgotest.StreamRequest\x1a\x17.cgotest.StreamResponse\x12E\n" +
+	"\x10ClientStreamCall\x12\x16.cgotest.StreamRequest\x1a\x17.cgotest.StreamResponse(\x01\x12E\n" +
+	"\x10ServerStreamCall\x12\x16.cgotest.StreamRequest\x1a\x17.cgotest.StreamResponse0\x01\x12E\n" +
+	"\x0eBidiStreamCall\x12\x16.cgotest.StreamRequest\x1a\x17.cgotest.StreamResponse(\x010\x01B1\xa0\xbb\x18\x02\xa8\xbb\x18\x01Z'github.com/ygrpc/rpccgo/cgotest;cgotestb\x06proto3"
+
+var (
+	file_stream_proto_rawDescOnce sync.Once
+	file_stream_proto_rawDescData []byte
+)
+
+func file_stream_proto_rawDescGZIP() []byte {
+	file_stream_proto_rawDescOnce.Do(func() {
+		file_stream_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_stream_proto_rawDesc), len(file_stream_proto_rawDesc)))
+	})
+	return file_stream_proto_rawDescData
+}
+
+var file_stream_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_stream_proto_goTypes = []any{
+	(*StreamRequest)(nil),  // 0: cgotest.StreamRequest
+	(*StreamResponse)(nil), // 1: cgotest.StreamResponse
+}
+var file_stream_proto_depIdxs = []int32{
+	0, // 0: cgotest.StreamService.UnaryCall:input_type -> cgotest.StreamRequest
+	0, // 1: cgotest.StreamService.ClientStreamCall:input_type -> cgotest.StreamRequest
+	0, // 2: cgotest.StreamService.ServerStreamCall:input_type -> cgotest.StreamRequest
+	0, // 3: cgotest.StreamService.BidiStreamCall:input_type -> cgotest.StreamRequest
+	1, // 4: cgotest.StreamService.UnaryCall:output_type -> cgotest.StreamResponse
+	1, // 5: cgotest.StreamService.ClientStreamCall:output_type -> cgotest.StreamResponse
+	1, // 6: cgotest.StreamService.ServerStreamCall:output_type -> cgotest.StreamResponse
+	1, // 7: cgotest.StreamService.BidiStreamCall:output_type -> cgotest.StreamResponse
+	4, // [4:8] is the sub-list for method output_type
+	0, // [0:4] is the sub-list for method input_type
+	0, // [0:0] is the sub-list for extension type_name
+	0, // [0:0] is the sub-list for extension extendee
+	0, // [0:0] is the sub-list for field type_name
+}
+
+func init() { file_stream_proto_init() }
+func file_stream_proto_init() {
+	if File_stream_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_stream_proto_rawDesc), len(file_stream_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   2,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_stream_proto_goTypes,
+		DependencyIndexes: file_stream_proto_depIdxs,
+		MessageInfos:      file_stream_proto_msgTypes,
+	}.Build()
+	File_stream_proto = out.File
+	file_stream_proto_goTypes = nil
+	file_stream_proto_depIdxs = nil
+}
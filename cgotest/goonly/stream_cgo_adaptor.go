@@ -0,0 +1,281 @@
+// Code generated by protoc-gen-rpc-cgo-adaptor. DO NOT EDIT.
+//
+// source: stream.proto
+// protocols: go
+
+package cgotest_goonly
+
+import (
+	context "context"
+	rpcruntime "github.com/ygrpc/rpccgo/rpcruntime"
+)
+
+// StreamService adaptor constants.
+const (
+	StreamService_ServiceName                 = "cgotest.StreamService"
+	StreamService_UnaryCall_FullMethod        = "/cgotest.StreamService/UnaryCall"
+	StreamService_ClientStreamCall_FullMethod = "/cgotest.StreamService/ClientStreamCall"
+	StreamService_ServerStreamCall_FullMethod = "/cgotest.StreamService/ServerStreamCall"
+	StreamService_BidiStreamCall_FullMethod   = "/cgotest.StreamService/BidiStreamCall"
+)
+
+// StreamService_lookupHandler admits a call of fullMethod under the configured concurrency
+// limits, selects a protocol and acquires the registered handler.
+// The returned release func must be called once the call has finished.
+//
+// Handlers are looked up in rpcruntime.RuntimeFromContext(ctx).
+func StreamService_lookupHandler(ctx context.Context, fullMethod string) (rpcruntime.Protocol, any, func(), error) {
+	rt := rpcruntime.RuntimeFromContext(ctx)
+	releaseSlot, err := rt.AcquireCallSlot(ctx, fullMethod)
+	if err != nil {
+		return "", nil, nil, err
+	}
+	protocol, h, release, err := streamService_acquireHandler(ctx, rt)
+	if err != nil {
+		releaseSlot()
+		return protocol, nil, nil, err
+	}
+	return protocol, h, func() {
+		release()
+		releaseSlot()
+	}, nil
+}
+
+// streamService_acquireHandler selects a protocol and acquires the registered handler from rt.
+//
+// Selection rules:
+// - Supported protocol: go
+// - If ctx explicitly carries a protocol, it must match the supported protocol.
+// - Otherwise, the supported protocol is used.
+func streamService_acquireHandler(ctx context.Context, rt *rpcruntime.Runtime) (rpcruntime.Protocol, any, func(), error) {
+	protocol, hasProtocol := rpcruntime.ProtocolFromContext(ctx)
+	if hasProtocol && protocol != rpcruntime.ProtocolGo {
+		return protocol, nil, nil, rpcruntime.ErrUnknownProtocol
+	}
+	h, release, err := rt.AcquireHandler(rpcruntime.ProtocolGo, StreamService_ServiceName)
+	if err != nil {
+		return "", nil, nil, err
+	}
+	return rpcruntime.ProtocolGo, h, release, nil
+}
+
+// StreamService_GoHandler is the plain Go handler interface the StreamService adaptor
+// dispatches to. It depends on neither grpc-go nor connect-go.
+type StreamService_GoHandler interface {
+	UnaryCall(context.Context, *StreamRequest) (*StreamResponse, error)
+	ClientStreamCall(context.Context, rpcruntime.GoClientStream[StreamRequest]) (*StreamResponse, error)
+	ServerStreamCall(context.Context, *StreamRequest, rpcruntime.GoServerStream[StreamResponse]) error
+	BidiStreamCall(context.Context, rpcruntime.GoBidiStream[StreamRequest, StreamResponse]) error
+}
+
+// RegisterStreamServiceGoHandler registers h as the plain Go handler for StreamService.
+// It returns true if a previous handler was replaced.
+func RegisterStreamServiceGoHandler(h StreamService_GoHandler) (bool, error) {
+	return rpcruntime.RegisterHandler(rpcruntime.ProtocolGo, StreamService_ServiceName, h)
+}
+
+// StreamService_UnaryCall calls cgotest.StreamService.UnaryCall via the registered handler.
+func StreamService_UnaryCall(ctx context.Context, req *StreamRequest) (*StreamResponse, error) {
+	_, h, release, err := StreamService_lookupHandler(ctx, StreamService_UnaryCall_FullMethod)
+	if err != nil {
+		if remote, ok := rpcruntime.RuntimeFromContext(ctx).RemoteFallback(StreamService_ServiceName, err); ok {
+			return rpcruntime.ForwardUnary[StreamRequest, StreamResponse](ctx, remote, StreamService_UnaryCall_FullMethod, req)
+		}
+		return nil, err
+	}
+	defer release()
+	ctx, cancel := rpcruntime.CallContext(ctx)
+	defer cancel()
+	svc, ok := h.(interface {
+		UnaryCall(context.Context, *StreamRequest) (*StreamResponse, error)
+	})
+	if !ok {
+		return nil, rpcruntime.ErrHandlerTypeMismatch
+	}
+	return svc.UnaryCall(ctx, req)
+}
+
+// StreamService_ClientStreamCall client-streaming adaptor functions.
+// These use a staged API: Start, Send, Finish.
+
+// StreamService_ClientStreamCallStart initializes a client-streaming call and returns a stream handle.
+func StreamService_ClientStreamCallStart(ctx context.Context) (uint64, error) {
+	protocol, h, release, err := StreamService_lookupHandler(ctx, StreamService_ClientStreamCall_FullMethod)
+	if err != nil {
+		if remote, ok := rpcruntime.RuntimeFromContext(ctx).RemoteFallback(StreamService_ServiceName, err); ok {
+			return rpcruntime.ForwardClientStream[StreamRequest, StreamResponse](ctx, remote, StreamService_ClientStreamCall_FullMethod)
+		}
+		return 0, err
+	}
+	// release is handed over to the handler goroutine once it starts.
+	handlerStarted := false
+	defer func() {
+		if !handlerStarted {
+			release()
+		}
+	}()
+
+	svc, ok := h.(interface {
+		ClientStreamCall(context.Context, rpcruntime.GoClientStream[StreamRequest]) (*StreamResponse, error)
+	})
+	if !ok {
+		return 0, rpcruntime.ErrHandlerTypeMismatch
+	}
+
+	handle, childCtx, _ := rpcruntime.AllocateStreamHandle(ctx, protocol)
+	session := rpcruntime.GetStreamSession(handle)
+	if session == nil {
+		return 0, rpcruntime.ErrInvalidStreamHandle
+	}
+
+	goStream := rpcruntime.NewGoClientStream[StreamRequest](session)
+	handlerStarted = true
+	go func() {
+		defer release()
+		defer func() {
+			if r := recover(); r != nil {
+				rpcruntime.CompleteClientStream(handle, nil, rpcruntime.RecoverPanic(r))
+			}
+		}()
+		resp, err := svc.ClientStreamCall(childCtx, goStream)
+		rpcruntime.CompleteClientStream(handle, resp, err)
+	}()
+
+	_ = childCtx // Used in goroutine
+	return uint64(handle), nil
+}
+
+// StreamService_ClientStreamCallSend sends a request message to the stream.
+func StreamService_ClientStreamCallSend(streamHandle uint64, req *StreamRequest) error {
+	return rpcruntime.SendToStream(rpcruntime.StreamHandle(streamHandle), req)
+}
+
+// StreamService_ClientStreamCallFinish closes the send-side and returns the final response.
+func StreamService_ClientStreamCallFinish(streamHandle uint64) (*StreamResponse, error) {
+	resp, err := rpcruntime.FinishClientStream(rpcruntime.StreamHandle(streamHandle))
+	if err != nil {
+		return nil, err
+	}
+	if resp == nil {
+		return nil, nil
+	}
+	return resp.(*StreamResponse), nil
+}
+
+// StreamService_ServerStreamCall calls cgotest.StreamService.ServerStreamCall via the registered handler.
+//
+// This is a server-streaming method. Results are delivered via callbacks:
+// - onRead is called for each response message; return false to stop receiving.
+// - onDone is called exactly once when the stream ends or fails.
+func StreamService_ServerStreamCall(ctx context.Context, req *StreamRequest, onRead func(*StreamResponse) bool, onDone func(error)) error {
+	protocol, h, release, err := StreamService_lookupHandler(ctx, StreamService_ServerStreamCall_FullMethod)
+	if err != nil {
+		if remote, ok := rpcruntime.RuntimeFromContext(ctx).RemoteFallback(StreamService_ServiceName, err); ok {
+			return rpcruntime.ForwardServerStream(ctx, remote, StreamService_ServerStreamCall_FullMethod, req, onRead, onDone)
+		}
+		onDone(err)
+		return err
+	}
+	defer release()
+
+	svc, ok := h.(interface {
+		ServerStreamCall(context.Context, *StreamRequest, rpcruntime.GoServerStream[StreamResponse]) error
+	})
+	if !ok {
+		onDone(rpcruntime.ErrHandlerTypeMismatch)
+		return rpcruntime.ErrHandlerTypeMismatch
+	}
+
+	handle, _, _ := rpcruntime.AllocateStreamHandle(ctx, protocol)
+	session := rpcruntime.GetStreamSession(handle)
+	if session == nil {
+		onDone(rpcruntime.ErrInvalidStreamHandle)
+		return rpcruntime.ErrInvalidStreamHandle
+	}
+	session.SetCallbacks(func(resp any) bool { return onRead(resp.(*StreamResponse)) }, onDone)
+
+	goStream := rpcruntime.NewGoServerStream[StreamResponse](session)
+	err = svc.ServerStreamCall(ctx, req, goStream)
+	rpcruntime.FinishStreamHandle(handle)
+	onDone(err)
+	return err
+}
+
+// StreamService_BidiStreamCall bidi-streaming adaptor functions.
+// These use a combined staged + callback API: Start, Send, CloseSend + receive callbacks.
+
+// StreamService_BidiStreamCallStart initializes a bidi-streaming call and returns a stream handle.
+// Provide onRead and onDone callbacks to receive response messages.
+func StreamService_BidiStreamCallStart(ctx context.Context, onRead func(*StreamResponse) bool, onDone func(error)) (uint64, error) {
+	protocol, h, release, err := StreamService_lookupHandler(ctx, StreamService_BidiStreamCall_FullMethod)
+	if err != nil {
+		if remote, ok := rpcruntime.RuntimeFromContext(ctx).RemoteFallback(StreamService_ServiceName, err); ok {
+			return rpcruntime.ForwardBidiStream[StreamRequest, StreamResponse](ctx, remote, StreamService_BidiStreamCall_FullMethod, onRead, onDone)
+		}
+		return 0, err
+	}
+	// release is handed over to the handler goroutine once it starts.
+	handlerStarted := false
+	defer func() {
+		if !handlerStarted {
+			release()
+		}
+	}()
+
+	svc, ok := h.(interface {
+		BidiStreamCall(context.Context, rpcruntime.GoBidiStream[StreamRequest, StreamResponse]) error
+	})
+	if !ok {
+		return 0, rpcruntime.ErrHandlerTypeMismatch
+	}
+
+	handle, childCtx, _ := rpcruntime.AllocateStreamHandle(ctx, protocol)
+	session := rpcruntime.GetStreamSession(handle)
+	if session == nil {
+		return 0, rpcruntime.ErrInvalidStreamHandle
+	}
+	session.SetCallbacks(func(resp any) bool { return onRead(resp.(*StreamResponse)) }, onDone)
+
+	goStream := rpcruntime.NewGoBidiStream[StreamRequest, StreamResponse](session)
+	session.SetHandlerState(goStream)
+	handlerStarted = true
+	go func() {
+		defer release()
+		defer func() {
+			if r := recover(); r != nil {
+				if cb := session.OnDone(); cb != nil {
+					cb(rpcruntime.RecoverPanic(r))
+				}
+				rpcruntime.FinishStreamHandle(handle)
+			}
+		}()
+		err := svc.BidiStreamCall(childCtx, goStream)
+		if cb := session.OnDone(); cb != nil {
+			cb(err)
+		}
+		rpcruntime.FinishStreamHandle(handle)
+	}()
+
+	_ = childCtx // Used in goroutine
+	return uint64(handle), nil
+}
+
+// StreamService_BidiStreamCallSend sends a request message to the stream.
+func StreamService_BidiStreamCallSend(streamHandle uint64, req *StreamRequest) error {
+	return rpcruntime.SendToStream(rpcruntime.StreamHandle(streamHandle), req)
+}
+
+// StreamService_BidiStreamCallCloseSend closes the send-side of the stream.
+func StreamService_BidiStreamCallCloseSend(streamHandle uint64) error {
+	return rpcruntime.CloseSendCh(rpcruntime.StreamHandle(streamHandle))
+}
+
+// Register the adaptor functions for dispatch by full method name (see grpcrt.ClientConn).
+func init() {
+	rpcruntime.RegisterMethods(
+		rpcruntime.NewUnaryMethodDesc(StreamService_UnaryCall_FullMethod, StreamService_UnaryCall),
+		rpcruntime.NewClientStreamMethodDesc(StreamService_ClientStreamCall_FullMethod, StreamService_ClientStreamCallStart, StreamService_ClientStreamCallSend, StreamService_ClientStreamCallFinish),
+		rpcruntime.NewServerStreamMethodDesc(StreamService_ServerStreamCall_FullMethod, StreamService_ServerStreamCall),
+		rpcruntime.NewBidiStreamMethodDesc(StreamService_BidiStreamCall_FullMethod, StreamService_BidiStreamCallStart, StreamService_BidiStreamCallSend, StreamService_BidiStreamCallCloseSend),
+	)
+}
@@ -0,0 +1,333 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        v6.33.1
+// source: unary.proto
+
+package cgotest_goonly
+
+import (
+	_ "github.com/ygrpc/rpccgo/proto/ygrpc/cgo"
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type PingRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Msg           string                 `protobuf:"bytes,1,opt,name=msg,proto3" json:"msg,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PingRequest) Reset() {
+	*x = PingRequest{}
+	mi := &file_unary_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PingRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PingRequest) ProtoMessage() {}
+
+func (x *PingRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_unary_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PingRequest.ProtoReflect.Descriptor instead.
+func (*PingRequest) Descriptor() ([]byte, []int) {
+	return file_unary_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *PingRequest) GetMsg() string {
+	if x != nil {
+		return x.Msg
+	}
+	return ""
+}
+
+type PingRequestOpt1 struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Msg           string                 `protobuf:"bytes,1,opt,name=msg,proto3" json:"msg,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PingRequestOpt1) Reset() {
+	*x = PingRequestOpt1{}
+	mi := &file_unary_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PingRequestOpt1) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PingRequestOpt1) ProtoMessage() {}
+
+func (x *PingRequestOpt1) ProtoReflect() protoreflect.Message {
+	mi := &file_unary_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PingRequestOpt1.ProtoReflect.Descriptor instead.
+func (*PingRequestOpt1) Descriptor() ([]byte, []int) {
+	return file_unary_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *PingRequestOpt1) GetMsg() string {
+	if x != nil {
+		return x.Msg
+	}
+	return ""
+}
+
+type PingRequestOpt2 struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Msg           string                 `protobuf:"bytes,1,opt,name=msg,proto3" json:"msg,omitempty"`
+	Id            int32                  `protobuf:"varint,2,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PingRequestOpt2) Reset() {
+	*x = PingRequestOpt2{}
+	mi := &file_unary_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PingRequestOpt2) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PingRequestOpt2) ProtoMessage() {}
+
+func (x *PingRequestOpt2) ProtoReflect() protoreflect.Message {
+	mi := &file_unary_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PingRequestOpt2.ProtoReflect.Descriptor instead.
+func (*PingRequestOpt2) Descriptor() ([]byte, []int) {
+	return file_unary_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *PingRequestOpt2) GetMsg() string {
+	if x != nil {
+		return x.Msg
+	}
+	return ""
+}
+
+func (x *PingRequestOpt2) GetId() int32 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+type PingResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Msg           string                 `protobuf:"bytes,1,opt,name=msg,proto3" json:"msg,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PingResponse) Reset() {
+	*x = PingResponse{}
+	mi := &file_unary_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PingResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PingResponse) ProtoMessage() {}
+
+func (x *PingResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_unary_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PingResponse.ProtoReflect.Descriptor instead.
+func (*PingResponse) Descriptor() ([]byte, []int) {
+	return file_unary_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *PingResponse) GetMsg() string {
+	if x != nil {
+		return x.Msg
+	}
+	return ""
+}
+
+type NonFlatRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Ids           []int32                `protobuf:"varint,1,rep,packed,name=ids,proto3" json:"ids,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *NonFlatRequest) Reset() {
+	*x = NonFlatRequest{}
+	mi := &file_unary_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *NonFlatRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NonFlatRequest) ProtoMessage() {}
+
+func (x *NonFlatRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_unary_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NonFlatRequest.ProtoReflect.Descriptor instead.
+func (*NonFlatRequest) Descriptor() ([]byte, []int) {
+	return file_unary_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *NonFlatRequest) GetIds() []int32 {
+	if x != nil {
+		return x.Ids
+	}
+	return nil
+}
+
+var File_unary_proto protoreflect.FileDescriptor
+
+const file_unary_proto_rawDesc = "" +
+	"\n" +
+	"\vunary.proto\x12\acgotest\x1a\x17ygrpc/cgo/options.proto\"\x1f\n" +
+	"\vPingRequest\x12\x10\n" +
+	"\x03msg\x18\x01 \x01(\tR\x03msg\"#\n" +
+	"\x0fPingRequestOpt1\x12\x10\n" +
+	"\x03msg\x18\x01 \x01(\tR\x03msg\"3\n" +
+	"\x0fPingRequestOpt2\x12\x10\n" +
+	"\x03msg\x18\x01 \x01(\tR\x03msg\x12\x0e\n" +
+	"\x02id\x18\x02 \x01(\x05R\x02id\" \n" +
+	"\fPingResponse\x12\x10\n" +
+	"\x03msg\x18\x01 \x01(\tR\x03msg\"\"\n" +
+	"\x0eNonFlatRequest\x12\x10\n" +
+	"\x03ids\x18\x01 \x03(\x05R\x03ids2\x8d\x02\n" +
+	"\vTestService\x129\n" +
+	"\x04Ping\x12\x14.cgotest.PingRequest\x1a\x15.cgotest.PingResponse\"\x04\xb8\xbb\x18\x01\x12A\n" +
+	"\bPingOpt1\x12\x18.cgotest.PingRequestOpt1\x1a\x15.cgotest.PingResponse\"\x04\xb0\xbb\x18\x01\x12E\n" +
+	"\bPingOpt2\x12\x18.cgotest.PingRequestOpt2\x1a\x15.cgotest.PingResponse\"\b\xb0\xbb\x18\x00\xb8\xbb\x18\x00\x129\n" +
+	"\aNonFlat\x12\x17.cgotest.NonFlatRequest\x1a\x15.cgotest.PingResponseB1\xa0\xbb\x18\x02\xa8\xbb\x18\x01Z'github.com/ygrpc/rpccgo/cgotest;cgotestb\x06proto3"
+
+var (
+	file_unary_proto_rawDescOnce sync.Once
+	file_unary_proto_rawDescData []byte
+)
+
+func file_unary_proto_rawDescGZIP() []byte {
+	file_unary_proto_rawDescOnce.Do(func() {
+		file_unary_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_unary_proto_rawDesc), len(file_unary_proto_rawDesc)))
+	})
+	return file_unary_proto_rawDescData
+}
+
+var file_unary_proto_msgTypes = make([]protoimpl.MessageInfo, 5)
+var file_unary_proto_goTypes = []any{
+	(*PingRequest)(nil),     // 0: cgotest.PingRequest
+	(*PingRequestOpt1)(nil), // 1: cgotest.PingRequestOpt1
+	(*PingRequestOpt2)(nil), // 2: cgotest.PingRequestOpt2
+	(*PingResponse)(nil),    // 3: cgotest.PingResponse
+	(*NonFlatRequest)(nil),  // 4: cgotest.NonFlatRequest
+}
+var file_unary_proto_depIdxs = []int32{
+	0, // 0: cgotest.TestService.Ping:input_type -> cgotest.PingRequest
+	1, // 1: cgotest.TestService.PingOpt1:input_type -> cgotest.PingRequestOpt1
+	2, // 2: cgotest.TestService.PingOpt2:input_type -> cgotest.PingRequestOpt2
+	4, // 3: cgotest.TestService.NonFlat:input_type -> cgotest.NonFlatRequest
+	3, // 4: cgotest.TestService.Ping:output_type -> cgotest.PingResponse
+	3, // 5: cgotest.TestService.PingOpt1:output_type -> cgotest.PingResponse
+	3, // 6: cgotest.TestService.PingOpt2:output_type -> cgotest.PingResponse
+	3, // 7: cgotest.TestService.NonFlat:output_type -> cgotest.PingResponse
+	4, // [4:8] is the sub-list for method output_type
+	0, // [0:4] is the sub-list for method input_type
+	0, // [0:0] is the sub-list for extension type_name
+	0, // [0:0] is the sub-list for extension extendee
+	0, // [0:0] is the sub-list for field type_name
+}
+
+func init() { file_unary_proto_init() }
+func file_unary_proto_init() {
+	if File_unary_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_unary_proto_rawDesc), len(file_unary_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   5,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_unary_proto_goTypes,
+		DependencyIndexes: file_unary_proto_depIdxs,
+		MessageInfos:      file_unary_proto_msgTypes,
+	}.Build()
+	File_unary_proto = out.File
+	file_unary_proto_goTypes = nil
+	file_unary_proto_depIdxs = nil
+}
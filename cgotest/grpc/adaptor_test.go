@@ -2,11 +2,25 @@ package cgotest_grpc
 
 import (
 	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
 	"strings"
 	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	reflectionpb "google.golang.org/grpc/reflection/grpc_reflection_v1"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
 
 	"github.com/ygrpc/rpccgo/cgotest/testutil"
 	"github.com/ygrpc/rpccgo/rpcruntime"
+	"github.com/ygrpc/rpccgo/rpcruntime/grpcrt"
 )
 
 type mockTestServiceServer struct {
@@ -101,6 +115,76 @@ func TestGrpcAdaptor(t *testing.T) {
 			return resp.GetMsg(), nil
 		}, "hello", "pong: hello")
 	})
+	t.Run("TypedRegistration", func(t *testing.T) {
+		_, err := RegisterTestServiceGrpcHandler(&mockTestServiceServer{})
+		testutil.RequireNoError(t, err)
+		resp, err := TestService_Ping(context.Background(), &PingRequest{Msg: "typed"})
+		testutil.RequireNoError(t, err)
+		testutil.RequireStringEqual(t, resp.GetMsg(), "pong: typed")
+	})
+	t.Run("ServiceRegistrar", func(t *testing.T) {
+		RegisterTestServiceServer(grpcrt.ServiceRegistrar(), &mockTestServiceServer{})
+		resp, err := TestService_Ping(context.Background(), &PingRequest{Msg: "registrar"})
+		testutil.RequireNoError(t, err)
+		testutil.RequireStringEqual(t, resp.GetMsg(), "pong: registrar")
+	})
+	t.Run("Unregister", func(t *testing.T) {
+		_, err := RegisterTestServiceGrpcHandler(&mockTestServiceServer{})
+		testutil.RequireNoError(t, err)
+		removed, err := rpcruntime.UnregisterGrpcHandlerAndWait(context.Background(), TestService_ServiceName)
+		testutil.RequireNoError(t, err)
+		testutil.RequireEqual(t, removed, true)
+		_, callErr := TestService_Ping(context.Background(), &PingRequest{Msg: "gone"})
+		testutil.RequireEqual(t, callErr, rpcruntime.ErrServiceNotRegistered)
+	})
+	t.Run("UnregisterDrainsStream", func(t *testing.T) {
+		_, err := rpcruntime.RegisterGrpcHandler(StreamService_ServiceName, &mockStreamServiceServer{})
+		testutil.RequireNoError(t, err)
+		handle, err := StreamService_ClientStreamCallStart(context.Background())
+		testutil.RequireNoError(t, err)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+		_, err = rpcruntime.UnregisterGrpcHandlerAndWait(ctx, StreamService_ServiceName)
+		testutil.RequireEqual(t, err, context.DeadlineExceeded)
+
+		testutil.RequireNoError(t, StreamService_ClientStreamCallSend(handle, &StreamRequest{Data: "A"}))
+		resp, err := StreamService_ClientStreamCallFinish(handle)
+		testutil.RequireNoError(t, err)
+		testutil.RequireStringEqual(t, resp.GetResult(), "received:A")
+	})
+	t.Run("ConcurrencyLimit", func(t *testing.T) {
+		_, err := rpcruntime.RegisterGrpcHandler(StreamService_ServiceName, &mockStreamServiceServer{})
+		testutil.RequireNoError(t, err)
+		testutil.RequireNoError(t, rpcruntime.SetMethodConcurrencyLimit(StreamService_BidiStreamCall_FullMethod, rpcruntime.ConcurrencyLimit{MaxInFlight: 1}))
+		defer rpcruntime.SetMethodConcurrencyLimit(StreamService_BidiStreamCall_FullMethod, rpcruntime.ConcurrencyLimit{})
+
+		done := make(chan error, 1)
+		start := func() (uint64, error) {
+			return StreamService_BidiStreamCallStart(context.Background(), func(*StreamResponse) bool { return true }, func(err error) { done <- err })
+		}
+		handle, err := start()
+		testutil.RequireNoError(t, err)
+
+		_, err = start()
+		testutil.RequireEqual(t, errors.Is(err, rpcruntime.ErrResourceExhausted), true)
+
+		testutil.RequireNoError(t, StreamService_BidiStreamCallCloseSend(handle))
+		testutil.RequireNoError(t, <-done)
+
+		// The slot is released right after onDone; allow the handler goroutine to return.
+		deadline := time.Now().Add(time.Second)
+		for {
+			handle, err = start()
+			if err == nil || time.Now().After(deadline) {
+				break
+			}
+			time.Sleep(time.Millisecond)
+		}
+		testutil.RequireNoError(t, err)
+		testutil.RequireNoError(t, StreamService_BidiStreamCallCloseSend(handle))
+		testutil.RequireNoError(t, <-done)
+	})
 	t.Run("ClientStreaming", func(t *testing.T) {
 		testutil.RunClientStreamTest(t, registerGrpc(t, StreamService_ServiceName, &mockStreamServiceServer{}), func(ctx context.Context) (uint64, error) { return StreamService_ClientStreamCallStart(ctx) }, func(handle uint64, data string) error {
 			return StreamService_ClientStreamCallSend(handle, &StreamRequest{Data: data})
@@ -130,3 +214,357 @@ func TestGrpcAdaptor(t *testing.T) {
 		}, func(handle uint64) { testutil.RequireNoError(t, StreamService_BidiStreamCallCloseSend(handle)) }, []string{"A", "B", "C"}, []string{"echo:A", "echo:B", "echo:C"})
 	})
 }
+
+// mockMetadataTestServiceServer echoes the x-test request metadata back as
+// the x-echo response header.
+type mockMetadataTestServiceServer struct {
+	UnimplementedTestServiceServer
+}
+
+func (m *mockMetadataTestServiceServer) Ping(ctx context.Context, req *PingRequest) (*PingResponse, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	if err := grpc.SetHeader(ctx, metadata.Pairs("x-echo", strings.Join(md.Get("x-test"), ","))); err != nil {
+		return nil, err
+	}
+	return &PingResponse{Msg: "pong: " + req.GetMsg()}, nil
+}
+
+// TestGrpcAdaptor_ClientConn verifies generated gRPC clients reach registered
+// handlers through grpcrt.ClientConn.
+func TestGrpcAdaptor_ClientConn(t *testing.T) {
+	rt := rpcruntime.NewRuntime()
+	_, err := rt.RegisterGrpcHandler(TestService_ServiceName, &mockMetadataTestServiceServer{})
+	testutil.RequireNoError(t, err)
+	_, err = rt.RegisterGrpcHandler(StreamService_ServiceName, &mockStreamServiceServer{})
+	testutil.RequireNoError(t, err)
+	conn := grpcrt.For(rt).ClientConn()
+
+	t.Run("Unary", func(t *testing.T) {
+		ctx := metadata.AppendToOutgoingContext(context.Background(), "x-test", "v1")
+		var header metadata.MD
+		resp, err := NewTestServiceClient(conn).Ping(ctx, &PingRequest{Msg: "hello"}, grpc.Header(&header))
+		testutil.RequireNoError(t, err)
+		testutil.RequireStringEqual(t, resp.GetMsg(), "pong: hello")
+		testutil.RequireStringEqual(t, strings.Join(header.Get("x-echo"), ","), "v1")
+	})
+
+	t.Run("UnknownMethod", func(t *testing.T) {
+		err := conn.Invoke(context.Background(), "/rpc.test.Missing/Call", &PingRequest{}, &PingResponse{})
+		testutil.RequireEqual(t, status.Code(err), codes.Unimplemented)
+	})
+
+	t.Run("ServiceNotRegistered", func(t *testing.T) {
+		_, err := NewTestServiceClient(grpcrt.For(rpcruntime.NewRuntime()).ClientConn()).Ping(context.Background(), &PingRequest{})
+		testutil.RequireEqual(t, errors.Is(err, rpcruntime.ErrServiceNotRegistered), true)
+	})
+
+	client := NewStreamServiceClient(conn)
+
+	t.Run("ClientStreaming", func(t *testing.T) {
+		stream, err := client.ClientStreamCall(context.Background())
+		testutil.RequireNoError(t, err)
+		for _, data := range []string{"A", "B", "C"} {
+			testutil.RequireNoError(t, stream.Send(&StreamRequest{Data: data}))
+		}
+		resp, err := stream.CloseAndRecv()
+		testutil.RequireNoError(t, err)
+		testutil.RequireStringEqual(t, resp.GetResult(), "received:ABC")
+	})
+
+	t.Run("ServerStreaming", func(t *testing.T) {
+		stream, err := client.ServerStreamCall(context.Background(), &StreamRequest{Data: "test"})
+		testutil.RequireNoError(t, err)
+		var got []string
+		for {
+			resp, err := stream.Recv()
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			testutil.RequireNoError(t, err)
+			got = append(got, resp.GetResult())
+		}
+		testutil.RequireStringEqual(t, strings.Join(got, ","), "test-a,test-b,test-c")
+	})
+
+	t.Run("BidiStreaming", func(t *testing.T) {
+		stream, err := client.BidiStreamCall(context.Background())
+		testutil.RequireNoError(t, err)
+		var got []string
+		for _, data := range []string{"X", "Y"} {
+			testutil.RequireNoError(t, stream.Send(&StreamRequest{Data: data}))
+			resp, err := stream.Recv()
+			testutil.RequireNoError(t, err)
+			got = append(got, resp.GetResult())
+		}
+		testutil.RequireNoError(t, stream.CloseSend())
+		_, err = stream.Recv()
+		testutil.RequireEqual(t, err, io.EOF)
+		testutil.RequireStringEqual(t, strings.Join(got, ","), "echo:X,echo:Y")
+	})
+}
+
+// TestGrpcAdaptor_Serve verifies registered handlers are reachable over a
+// real gRPC connection, including server reflection.
+func TestGrpcAdaptor_Serve(t *testing.T) {
+	rt := rpcruntime.NewRuntime()
+	_, err := rt.RegisterGrpcHandler(TestService_ServiceName, &mockMetadataTestServiceServer{})
+	testutil.RequireNoError(t, err)
+	_, err = rt.RegisterGrpcHandler(StreamService_ServiceName, &mockStreamServiceServer{})
+	testutil.RequireNoError(t, err)
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	testutil.RequireNoError(t, err)
+	server := grpcrt.For(rt).NewServer()
+	go func() { _ = server.Serve(lis) }()
+	defer server.Stop()
+
+	conn, err := grpc.NewClient(lis.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	testutil.RequireNoError(t, err)
+	defer conn.Close()
+
+	t.Run("Unary", func(t *testing.T) {
+		ctx := metadata.AppendToOutgoingContext(context.Background(), "x-test", "v1")
+		var header metadata.MD
+		resp, err := NewTestServiceClient(conn).Ping(ctx, &PingRequest{Msg: "hello"}, grpc.Header(&header))
+		testutil.RequireNoError(t, err)
+		testutil.RequireStringEqual(t, resp.GetMsg(), "pong: hello")
+		testutil.RequireStringEqual(t, strings.Join(header.Get("x-echo"), ","), "v1")
+	})
+
+	t.Run("BidiStreaming", func(t *testing.T) {
+		stream, err := NewStreamServiceClient(conn).BidiStreamCall(context.Background())
+		testutil.RequireNoError(t, err)
+		testutil.RequireNoError(t, stream.Send(&StreamRequest{Data: "X"}))
+		resp, err := stream.Recv()
+		testutil.RequireNoError(t, err)
+		testutil.RequireStringEqual(t, resp.GetResult(), "echo:X")
+		testutil.RequireNoError(t, stream.CloseSend())
+		_, err = stream.Recv()
+		testutil.RequireEqual(t, err, io.EOF)
+	})
+
+	t.Run("ClientStreaming", func(t *testing.T) {
+		stream, err := NewStreamServiceClient(conn).ClientStreamCall(context.Background())
+		testutil.RequireNoError(t, err)
+		for _, data := range []string{"A", "B", "C"} {
+			testutil.RequireNoError(t, stream.Send(&StreamRequest{Data: data}))
+		}
+		resp, err := stream.CloseAndRecv()
+		testutil.RequireNoError(t, err)
+		testutil.RequireStringEqual(t, resp.GetResult(), "received:ABC")
+	})
+
+	t.Run("ServiceNotRegistered", func(t *testing.T) {
+		rt.UnregisterGrpcHandler(TestService_ServiceName)
+		_, err := NewTestServiceClient(conn).Ping(context.Background(), &PingRequest{})
+		testutil.RequireEqual(t, status.Code(err), codes.Unimplemented)
+	})
+
+	t.Run("Reflection", func(t *testing.T) {
+		stream, err := reflectionpb.NewServerReflectionClient(conn).ServerReflectionInfo(context.Background())
+		testutil.RequireNoError(t, err)
+		err = stream.Send(&reflectionpb.ServerReflectionRequest{
+			MessageRequest: &reflectionpb.ServerReflectionRequest_ListServices{},
+		})
+		testutil.RequireNoError(t, err)
+		resp, err := stream.Recv()
+		testutil.RequireNoError(t, err)
+		var names []string
+		for _, s := range resp.GetListServicesResponse().GetService() {
+			names = append(names, s.GetName())
+		}
+		listed := strings.Join(names, ",")
+		testutil.RequireEqual(t, strings.Contains(listed, TestService_ServiceName), true)
+		testutil.RequireEqual(t, strings.Contains(listed, StreamService_ServiceName), true)
+		testutil.RequireNoError(t, stream.CloseSend())
+	})
+}
+
+// TestGrpcAdaptor_RemoteForwarding verifies adaptors forward calls for a
+// service without a local handler to its registered gRPC remote.
+func TestGrpcAdaptor_RemoteForwarding(t *testing.T) {
+	server := rpcruntime.NewRuntime()
+	_, err := server.RegisterGrpcHandler(TestService_ServiceName, &mockMetadataTestServiceServer{})
+	testutil.RequireNoError(t, err)
+	_, err = server.RegisterGrpcHandler(StreamService_ServiceName, &mockStreamServiceServer{})
+	testutil.RequireNoError(t, err)
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	testutil.RequireNoError(t, err)
+	grpcServer := grpcrt.For(server).NewServer()
+	go func() { _ = grpcServer.Serve(lis) }()
+	defer grpcServer.Stop()
+
+	conn, err := grpc.NewClient(lis.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	testutil.RequireNoError(t, err)
+	defer conn.Close()
+
+	local := rpcruntime.NewRuntime()
+	remote := grpcrt.NewRemote(conn)
+	_, err = local.RegisterRemote(TestService_ServiceName, remote)
+	testutil.RequireNoError(t, err)
+	_, err = local.RegisterRemote(StreamService_ServiceName, remote)
+	testutil.RequireNoError(t, err)
+	ctx := rpcruntime.WithRuntime(context.Background(), local)
+
+	t.Run("Unary", func(t *testing.T) {
+		ctx := rpcruntime.WithRequestHeader(ctx, http.Header{"X-Test": []string{"v1"}})
+		ctx, md := rpcruntime.WithResponseMetadata(ctx)
+		resp, err := TestService_Ping(ctx, &PingRequest{Msg: "hello"})
+		testutil.RequireNoError(t, err)
+		testutil.RequireStringEqual(t, resp.GetMsg(), "pong: hello")
+		testutil.RequireStringEqual(t, md.Header.Get("X-Echo"), "v1")
+	})
+
+	t.Run("ClientStreaming", func(t *testing.T) {
+		handle, err := StreamService_ClientStreamCallStart(ctx)
+		testutil.RequireNoError(t, err)
+		for _, data := range []string{"A", "B", "C"} {
+			testutil.RequireNoError(t, StreamService_ClientStreamCallSend(handle, &StreamRequest{Data: data}))
+		}
+		resp, err := StreamService_ClientStreamCallFinish(handle)
+		testutil.RequireNoError(t, err)
+		testutil.RequireStringEqual(t, resp.GetResult(), "received:ABC")
+	})
+
+	t.Run("ServerStreaming", func(t *testing.T) {
+		var got []string
+		done := make(chan error, 1)
+		err := StreamService_ServerStreamCall(ctx, &StreamRequest{Data: "test"}, func(resp *StreamResponse) bool {
+			got = append(got, resp.GetResult())
+			return true
+		}, func(err error) { done <- err })
+		testutil.RequireNoError(t, err)
+		testutil.RequireNoError(t, <-done)
+		testutil.RequireStringEqual(t, strings.Join(got, ","), "test-a,test-b,test-c")
+	})
+
+	t.Run("BidiStreaming", func(t *testing.T) {
+		recv := make(chan string, 3)
+		done := make(chan error, 1)
+		handle, err := StreamService_BidiStreamCallStart(ctx, func(resp *StreamResponse) bool {
+			recv <- resp.GetResult()
+			return true
+		}, func(err error) { done <- err })
+		testutil.RequireNoError(t, err)
+		for _, data := range []string{"A", "B"} {
+			testutil.RequireNoError(t, StreamService_BidiStreamCallSend(handle, &StreamRequest{Data: data}))
+			testutil.RequireStringEqual(t, <-recv, "echo:"+data)
+		}
+		testutil.RequireNoError(t, StreamService_BidiStreamCallCloseSend(handle))
+		testutil.RequireNoError(t, <-done)
+	})
+
+	t.Run("RemoteError", func(t *testing.T) {
+		server.UnregisterGrpcHandler(TestService_ServiceName)
+		_, err := TestService_Ping(ctx, &PingRequest{})
+		testutil.RequireEqual(t, status.Code(err), codes.Unimplemented)
+	})
+
+	t.Run("LocalHandlerFirst", func(t *testing.T) {
+		_, err := local.RegisterGrpcHandler(TestService_ServiceName, &mockTestServiceServer{})
+		testutil.RequireNoError(t, err)
+		resp, err := TestService_Ping(ctx, &PingRequest{Msg: "local"})
+		testutil.RequireNoError(t, err)
+		testutil.RequireStringEqual(t, resp.GetMsg(), "pong: local")
+	})
+}
+
+// TestGrpcAdaptor_UnknownServiceHandler verifies adaptors hand calls for a
+// service without a handler to the unknown-service handlers.
+func TestGrpcAdaptor_UnknownServiceHandler(t *testing.T) {
+	rt := rpcruntime.NewRuntime()
+	ctx := rpcruntime.WithRuntime(context.Background(), rt)
+
+	_, err := TestService_Ping(ctx, &PingRequest{Msg: "hi"})
+	testutil.RequireEqual(t, err, rpcruntime.ErrServiceNotRegistered)
+
+	rt.SetUnknownServiceHandler(func(_ context.Context, fullMethod string, reqBytes []byte) ([]byte, error) {
+		req := &PingRequest{}
+		if err := proto.Unmarshal(reqBytes, req); err != nil {
+			return nil, err
+		}
+		return proto.Marshal(&PingResponse{Msg: fullMethod + " " + req.GetMsg()})
+	})
+	resp, err := TestService_Ping(ctx, &PingRequest{Msg: "hi"})
+	testutil.RequireNoError(t, err)
+	testutil.RequireStringEqual(t, resp.GetMsg(), TestService_Ping_FullMethod+" hi")
+
+	// Without a stream handler, streams still fail as before.
+	_, err = StreamService_BidiStreamCallStart(ctx, func(*StreamResponse) bool { return true }, func(error) {})
+	testutil.RequireEqual(t, err, rpcruntime.ErrServiceNotRegistered)
+
+	rt.SetUnknownStreamHandler(func(_ context.Context, _ string, stream rpcruntime.UnknownServiceStream) error {
+		for {
+			b, err := stream.Recv()
+			if err != nil {
+				return nil
+			}
+			req := &StreamRequest{}
+			if err := proto.Unmarshal(b, req); err != nil {
+				return err
+			}
+			out, _ := proto.Marshal(&StreamResponse{Result: "stub:" + req.GetData()})
+			if err := stream.Send(out); err != nil {
+				return err
+			}
+		}
+	})
+	recv := make(chan string, 1)
+	done := make(chan error, 1)
+	handle, err := StreamService_BidiStreamCallStart(ctx, func(resp *StreamResponse) bool {
+		recv <- resp.GetResult()
+		return true
+	}, func(err error) { done <- err })
+	testutil.RequireNoError(t, err)
+	testutil.RequireNoError(t, StreamService_BidiStreamCallSend(handle, &StreamRequest{Data: "A"}))
+	testutil.RequireStringEqual(t, <-recv, "stub:A")
+	testutil.RequireNoError(t, StreamService_BidiStreamCallCloseSend(handle))
+	testutil.RequireNoError(t, <-done)
+
+	var got []string
+	err = StreamService_ServerStreamCall(ctx, &StreamRequest{Data: "S"}, func(resp *StreamResponse) bool {
+		got = append(got, resp.GetResult())
+		return true
+	}, func(error) {})
+	testutil.RequireNoError(t, err)
+	testutil.RequireStringEqual(t, strings.Join(got, ","), "stub:S")
+}
+
+type blockingTestServiceServer struct {
+	UnimplementedTestServiceServer
+	started chan struct{}
+}
+
+func (m *blockingTestServiceServer) Ping(ctx context.Context, _ *PingRequest) (*PingResponse, error) {
+	close(m.started)
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+// TestGrpcAdaptor_ShutdownCancelsUnary verifies a unary handler still running
+// when the Shutdown deadline passes has its context cancelled.
+func TestGrpcAdaptor_ShutdownCancelsUnary(t *testing.T) {
+	rt := rpcruntime.NewRuntime()
+	h := &blockingTestServiceServer{started: make(chan struct{})}
+	_, err := rt.RegisterGrpcHandler(TestService_ServiceName, h)
+	testutil.RequireNoError(t, err)
+
+	callErr := make(chan error, 1)
+	go func() {
+		_, err := TestService_Ping(rpcruntime.WithRuntime(context.Background(), rt), &PingRequest{Msg: "block"})
+		callErr <- err
+	}()
+	<-h.started
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	testutil.RequireEqual(t, rt.Shutdown(ctx), context.DeadlineExceeded)
+
+	select {
+	case err := <-callErr:
+		testutil.RequireEqual(t, err, context.Canceled)
+	case <-time.After(time.Second):
+		t.Fatal("unary handler was not cancelled after the shutdown deadline")
+	}
+}
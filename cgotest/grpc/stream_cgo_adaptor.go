@@ -22,22 +22,50 @@ const (
 	StreamService_BidiStreamCall_FullMethod   = "/cgotest.StreamService/BidiStreamCall"
 )
 
-// StreamService_lookupHandler selects a protocol and looks up the registered handler.
+// StreamService_lookupHandler admits a call of fullMethod under the configured concurrency
+// limits, selects a protocol and acquires the registered handler.
+// The returned release func must be called once the call has finished.
+//
+// Handlers are looked up in rpcruntime.RuntimeFromContext(ctx).
+func StreamService_lookupHandler(ctx context.Context, fullMethod string) (rpcruntime.Protocol, any, func(), error) {
+	rt := rpcruntime.RuntimeFromContext(ctx)
+	releaseSlot, err := rt.AcquireCallSlot(ctx, fullMethod)
+	if err != nil {
+		return "", nil, nil, err
+	}
+	protocol, h, release, err := streamService_acquireHandler(ctx, rt)
+	if err != nil {
+		releaseSlot()
+		return protocol, nil, nil, err
+	}
+	return protocol, h, func() {
+		release()
+		releaseSlot()
+	}, nil
+}
+
+// streamService_acquireHandler selects a protocol and acquires the registered handler from rt.
 //
 // Selection rules:
 // - Supported protocol: grpc
 // - If ctx explicitly carries a protocol, it must match the supported protocol.
 // - Otherwise, the supported protocol is used.
-func StreamService_lookupHandler(ctx context.Context) (rpcruntime.Protocol, any, error) {
+func streamService_acquireHandler(ctx context.Context, rt *rpcruntime.Runtime) (rpcruntime.Protocol, any, func(), error) {
 	protocol, hasProtocol := rpcruntime.ProtocolFromContext(ctx)
 	if hasProtocol && protocol != rpcruntime.ProtocolGrpc {
-		return protocol, nil, rpcruntime.ErrUnknownProtocol
+		return protocol, nil, nil, rpcruntime.ErrUnknownProtocol
 	}
-	h, ok := rpcruntime.LookupGrpcHandler(StreamService_ServiceName)
-	if !ok {
-		return "", nil, rpcruntime.ErrServiceNotRegistered
+	h, release, err := rt.AcquireHandler(rpcruntime.ProtocolGrpc, StreamService_ServiceName)
+	if err != nil {
+		return "", nil, nil, err
 	}
-	return rpcruntime.ProtocolGrpc, h, nil
+	return rpcruntime.ProtocolGrpc, h, release, nil
+}
+
+// RegisterStreamServiceGrpcHandler registers h as the gRPC handler for StreamService.
+// It returns true if a previous handler was replaced.
+func RegisterStreamServiceGrpcHandler(h StreamServiceServer) (bool, error) {
+	return rpcruntime.RegisterGrpcHandler(StreamService_ServiceName, h)
 }
 
 // streamService_ClientStreamCallServerAdaptor adapts rpcruntime.StreamSession to StreamService_ClientStreamCallServer.
@@ -253,10 +281,16 @@ var _ StreamService_BidiStreamCallServer = (*streamService_BidiStreamCallServerA
 
 // StreamService_UnaryCall calls cgotest.StreamService.UnaryCall via the registered handler.
 func StreamService_UnaryCall(ctx context.Context, req *StreamRequest) (*StreamResponse, error) {
-	_, h, err := StreamService_lookupHandler(ctx)
+	_, h, release, err := StreamService_lookupHandler(ctx, StreamService_UnaryCall_FullMethod)
 	if err != nil {
+		if remote, ok := rpcruntime.RuntimeFromContext(ctx).RemoteFallback(StreamService_ServiceName, err); ok {
+			return rpcruntime.ForwardUnary[StreamRequest, StreamResponse](ctx, remote, StreamService_UnaryCall_FullMethod, req)
+		}
 		return nil, err
 	}
+	defer release()
+	ctx, cancel := rpcruntime.CallContext(ctx)
+	defer cancel()
 	svc, ok := h.(StreamServiceServer)
 	if !ok {
 		return nil, rpcruntime.ErrHandlerTypeMismatch
@@ -269,10 +303,20 @@ func StreamService_UnaryCall(ctx context.Context, req *StreamRequest) (*StreamRe
 
 // StreamService_ClientStreamCallStart initializes a client-streaming call and returns a stream handle.
 func StreamService_ClientStreamCallStart(ctx context.Context) (uint64, error) {
-	protocol, h, err := StreamService_lookupHandler(ctx)
+	protocol, h, release, err := StreamService_lookupHandler(ctx, StreamService_ClientStreamCall_FullMethod)
 	if err != nil {
+		if remote, ok := rpcruntime.RuntimeFromContext(ctx).RemoteFallback(StreamService_ServiceName, err); ok {
+			return rpcruntime.ForwardClientStream[StreamRequest, StreamResponse](ctx, remote, StreamService_ClientStreamCall_FullMethod)
+		}
 		return 0, err
 	}
+	// release is handed over to the handler goroutine once it starts.
+	handlerStarted := false
+	defer func() {
+		if !handlerStarted {
+			release()
+		}
+	}()
 
 	svc, ok := h.(StreamServiceServer)
 	if !ok {
@@ -287,7 +331,9 @@ func StreamService_ClientStreamCallStart(ctx context.Context) (uint64, error) {
 
 	adaptorStream := &streamService_ClientStreamCallServerAdaptor{session: session}
 	session.SetHandlerState(adaptorStream)
+	handlerStarted = true
 	go func() {
+		defer release()
 		defer func() {
 			if r := recover(); r != nil {
 				rpcruntime.CompleteClientStream(handle, nil, rpcruntime.RecoverPanic(r))
@@ -324,11 +370,15 @@ func StreamService_ClientStreamCallFinish(streamHandle uint64) (*StreamResponse,
 // - onRead is called for each response message; return false to stop receiving.
 // - onDone is called exactly once when the stream ends or fails.
 func StreamService_ServerStreamCall(ctx context.Context, req *StreamRequest, onRead func(*StreamResponse) bool, onDone func(error)) error {
-	protocol, h, err := StreamService_lookupHandler(ctx)
+	protocol, h, release, err := StreamService_lookupHandler(ctx, StreamService_ServerStreamCall_FullMethod)
 	if err != nil {
+		if remote, ok := rpcruntime.RuntimeFromContext(ctx).RemoteFallback(StreamService_ServiceName, err); ok {
+			return rpcruntime.ForwardServerStream(ctx, remote, StreamService_ServerStreamCall_FullMethod, req, onRead, onDone)
+		}
 		onDone(err)
 		return err
 	}
+	defer release()
 
 	svc, ok := h.(StreamServiceServer)
 	if !ok {
@@ -357,10 +407,20 @@ func StreamService_ServerStreamCall(ctx context.Context, req *StreamRequest, onR
 // StreamService_BidiStreamCallStart initializes a bidi-streaming call and returns a stream handle.
 // Provide onRead and onDone callbacks to receive response messages.
 func StreamService_BidiStreamCallStart(ctx context.Context, onRead func(*StreamResponse) bool, onDone func(error)) (uint64, error) {
-	protocol, h, err := StreamService_lookupHandler(ctx)
+	protocol, h, release, err := StreamService_lookupHandler(ctx, StreamService_BidiStreamCall_FullMethod)
 	if err != nil {
+		if remote, ok := rpcruntime.RuntimeFromContext(ctx).RemoteFallback(StreamService_ServiceName, err); ok {
+			return rpcruntime.ForwardBidiStream[StreamRequest, StreamResponse](ctx, remote, StreamService_BidiStreamCall_FullMethod, onRead, onDone)
+		}
 		return 0, err
 	}
+	// release is handed over to the handler goroutine once it starts.
+	handlerStarted := false
+	defer func() {
+		if !handlerStarted {
+			release()
+		}
+	}()
 
 	svc, ok := h.(StreamServiceServer)
 	if !ok {
@@ -376,7 +436,9 @@ func StreamService_BidiStreamCallStart(ctx context.Context, onRead func(*StreamR
 
 	adaptorStream := &streamService_BidiStreamCallServerAdaptor{session: session}
 	session.SetHandlerState(adaptorStream)
+	handlerStarted = true
 	go func() {
+		defer release()
 		defer func() {
 			if r := recover(); r != nil {
 				if cb := session.OnDone(); cb != nil {
@@ -405,3 +467,13 @@ func StreamService_BidiStreamCallSend(streamHandle uint64, req *StreamRequest) e
 func StreamService_BidiStreamCallCloseSend(streamHandle uint64) error {
 	return rpcruntime.CloseSendCh(rpcruntime.StreamHandle(streamHandle))
 }
+
+// Register the adaptor functions for dispatch by full method name (see grpcrt.ClientConn).
+func init() {
+	rpcruntime.RegisterMethods(
+		rpcruntime.NewUnaryMethodDesc(StreamService_UnaryCall_FullMethod, StreamService_UnaryCall),
+		rpcruntime.NewClientStreamMethodDesc(StreamService_ClientStreamCall_FullMethod, StreamService_ClientStreamCallStart, StreamService_ClientStreamCallSend, StreamService_ClientStreamCallFinish),
+		rpcruntime.NewServerStreamMethodDesc(StreamService_ServerStreamCall_FullMethod, StreamService_ServerStreamCall),
+		rpcruntime.NewBidiStreamMethodDesc(StreamService_BidiStreamCall_FullMethod, StreamService_BidiStreamCallStart, StreamService_BidiStreamCallSend, StreamService_BidiStreamCallCloseSend),
+	)
+}
@@ -19,30 +19,64 @@ const (
 	TestService_NonFlat_FullMethod  = "/cgotest.TestService/NonFlat"
 )
 
-// TestService_lookupHandler selects a protocol and looks up the registered handler.
+// TestService_lookupHandler admits a call of fullMethod under the configured concurrency
+// limits, selects a protocol and acquires the registered handler.
+// The returned release func must be called once the call has finished.
+//
+// Handlers are looked up in rpcruntime.RuntimeFromContext(ctx).
+func TestService_lookupHandler(ctx context.Context, fullMethod string) (rpcruntime.Protocol, any, func(), error) {
+	rt := rpcruntime.RuntimeFromContext(ctx)
+	releaseSlot, err := rt.AcquireCallSlot(ctx, fullMethod)
+	if err != nil {
+		return "", nil, nil, err
+	}
+	protocol, h, release, err := testService_acquireHandler(ctx, rt)
+	if err != nil {
+		releaseSlot()
+		return protocol, nil, nil, err
+	}
+	return protocol, h, func() {
+		release()
+		releaseSlot()
+	}, nil
+}
+
+// testService_acquireHandler selects a protocol and acquires the registered handler from rt.
 //
 // Selection rules:
 // - Supported protocol: grpc
 // - If ctx explicitly carries a protocol, it must match the supported protocol.
 // - Otherwise, the supported protocol is used.
-func TestService_lookupHandler(ctx context.Context) (rpcruntime.Protocol, any, error) {
+func testService_acquireHandler(ctx context.Context, rt *rpcruntime.Runtime) (rpcruntime.Protocol, any, func(), error) {
 	protocol, hasProtocol := rpcruntime.ProtocolFromContext(ctx)
 	if hasProtocol && protocol != rpcruntime.ProtocolGrpc {
-		return protocol, nil, rpcruntime.ErrUnknownProtocol
+		return protocol, nil, nil, rpcruntime.ErrUnknownProtocol
 	}
-	h, ok := rpcruntime.LookupGrpcHandler(TestService_ServiceName)
-	if !ok {
-		return "", nil, rpcruntime.ErrServiceNotRegistered
+	h, release, err := rt.AcquireHandler(rpcruntime.ProtocolGrpc, TestService_ServiceName)
+	if err != nil {
+		return "", nil, nil, err
 	}
-	return rpcruntime.ProtocolGrpc, h, nil
+	return rpcruntime.ProtocolGrpc, h, release, nil
+}
+
+// RegisterTestServiceGrpcHandler registers h as the gRPC handler for TestService.
+// It returns true if a previous handler was replaced.
+func RegisterTestServiceGrpcHandler(h TestServiceServer) (bool, error) {
+	return rpcruntime.RegisterGrpcHandler(TestService_ServiceName, h)
 }
 
 // TestService_Ping calls cgotest.TestService.Ping via the registered handler.
 func TestService_Ping(ctx context.Context, req *PingRequest) (*PingResponse, error) {
-	_, h, err := TestService_lookupHandler(ctx)
+	_, h, release, err := TestService_lookupHandler(ctx, TestService_Ping_FullMethod)
 	if err != nil {
+		if remote, ok := rpcruntime.RuntimeFromContext(ctx).RemoteFallback(TestService_ServiceName, err); ok {
+			return rpcruntime.ForwardUnary[PingRequest, PingResponse](ctx, remote, TestService_Ping_FullMethod, req)
+		}
 		return nil, err
 	}
+	defer release()
+	ctx, cancel := rpcruntime.CallContext(ctx)
+	defer cancel()
 	svc, ok := h.(TestServiceServer)
 	if !ok {
 		return nil, rpcruntime.ErrHandlerTypeMismatch
@@ -52,10 +86,16 @@ func TestService_Ping(ctx context.Context, req *PingRequest) (*PingResponse, err
 
 // TestService_PingOpt1 calls cgotest.TestService.PingOpt1 via the registered handler.
 func TestService_PingOpt1(ctx context.Context, req *PingRequestOpt1) (*PingResponse, error) {
-	_, h, err := TestService_lookupHandler(ctx)
+	_, h, release, err := TestService_lookupHandler(ctx, TestService_PingOpt1_FullMethod)
 	if err != nil {
+		if remote, ok := rpcruntime.RuntimeFromContext(ctx).RemoteFallback(TestService_ServiceName, err); ok {
+			return rpcruntime.ForwardUnary[PingRequestOpt1, PingResponse](ctx, remote, TestService_PingOpt1_FullMethod, req)
+		}
 		return nil, err
 	}
+	defer release()
+	ctx, cancel := rpcruntime.CallContext(ctx)
+	defer cancel()
 	svc, ok := h.(TestServiceServer)
 	if !ok {
 		return nil, rpcruntime.ErrHandlerTypeMismatch
@@ -65,10 +105,16 @@ func TestService_PingOpt1(ctx context.Context, req *PingRequestOpt1) (*PingRespo
 
 // TestService_PingOpt2 calls cgotest.TestService.PingOpt2 via the registered handler.
 func TestService_PingOpt2(ctx context.Context, req *PingRequestOpt2) (*PingResponse, error) {
-	_, h, err := TestService_lookupHandler(ctx)
+	_, h, release, err := TestService_lookupHandler(ctx, TestService_PingOpt2_FullMethod)
 	if err != nil {
+		if remote, ok := rpcruntime.RuntimeFromContext(ctx).RemoteFallback(TestService_ServiceName, err); ok {
+			return rpcruntime.ForwardUnary[PingRequestOpt2, PingResponse](ctx, remote, TestService_PingOpt2_FullMethod, req)
+		}
 		return nil, err
 	}
+	defer release()
+	ctx, cancel := rpcruntime.CallContext(ctx)
+	defer cancel()
 	svc, ok := h.(TestServiceServer)
 	if !ok {
 		return nil, rpcruntime.ErrHandlerTypeMismatch
@@ -78,13 +124,29 @@ func TestService_PingOpt2(ctx context.Context, req *PingRequestOpt2) (*PingRespo
 
 // TestService_NonFlat calls cgotest.TestService.NonFlat via the registered handler.
 func TestService_NonFlat(ctx context.Context, req *NonFlatRequest) (*PingResponse, error) {
-	_, h, err := TestService_lookupHandler(ctx)
+	_, h, release, err := TestService_lookupHandler(ctx, TestService_NonFlat_FullMethod)
 	if err != nil {
+		if remote, ok := rpcruntime.RuntimeFromContext(ctx).RemoteFallback(TestService_ServiceName, err); ok {
+			return rpcruntime.ForwardUnary[NonFlatRequest, PingResponse](ctx, remote, TestService_NonFlat_FullMethod, req)
+		}
 		return nil, err
 	}
+	defer release()
+	ctx, cancel := rpcruntime.CallContext(ctx)
+	defer cancel()
 	svc, ok := h.(TestServiceServer)
 	if !ok {
 		return nil, rpcruntime.ErrHandlerTypeMismatch
 	}
 	return svc.NonFlat(ctx, req)
 }
+
+// Register the adaptor functions for dispatch by full method name (see grpcrt.ClientConn).
+func init() {
+	rpcruntime.RegisterMethods(
+		rpcruntime.NewUnaryMethodDesc(TestService_Ping_FullMethod, TestService_Ping),
+		rpcruntime.NewUnaryMethodDesc(TestService_PingOpt1_FullMethod, TestService_PingOpt1),
+		rpcruntime.NewUnaryMethodDesc(TestService_PingOpt2_FullMethod, TestService_PingOpt2),
+		rpcruntime.NewUnaryMethodDesc(TestService_NonFlat_FullMethod, TestService_NonFlat),
+	)
+}
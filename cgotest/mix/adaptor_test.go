@@ -1,9 +1,11 @@
-// Package cgotest_mix tests the multi-protocol (grpc|connectrpc) adaptor with fallback.
+// Package cgotest_mix tests the multi-protocol (grpc|connectrpc|go|twirp) adaptor with fallback.
 package cgotest_mix
 
 import (
 	"connectrpc.com/connect"
 	"context"
+	"errors"
+	"io"
 	"strings"
 	"sync/atomic"
 	"testing"
@@ -222,3 +224,342 @@ func TestAllAdaptor_ContextSelection(t *testing.T) {
 		}
 	})
 }
+
+func TestAllAdaptor_RuntimeFromContext(t *testing.T) {
+	rt := rpcruntime.NewRuntime()
+	handler := &mockConnectTestServiceHandler{}
+	_, err := rt.RegisterConnectHandler(TestService_ServiceName, handler)
+	testutil.RequireNoError(t, err)
+
+	resp, err := TestService_Ping(rpcruntime.WithRuntime(context.Background(), rt), &PingRequest{Msg: "scoped"})
+	testutil.RequireNoError(t, err)
+	testutil.RequireStringEqual(t, resp.GetMsg(), "pong: scoped")
+
+	if atomic.LoadInt32(&handler.pingCalled) == 0 {
+		t.Fatalf("expected runtime-scoped handler to be called")
+	}
+}
+
+func TestAllAdaptor_RateLimit(t *testing.T) {
+	rt := rpcruntime.NewRuntime()
+	_, err := rt.RegisterGrpcHandler(TestService_ServiceName, &mockGrpcTestServiceServer{})
+	testutil.RequireNoError(t, err)
+	_, err = rt.RegisterConnectHandler(TestService_ServiceName, &mockConnectTestServiceHandler{})
+	testutil.RequireNoError(t, err)
+	_, err = rt.RegisterGrpcHandler(StreamService_ServiceName, &mockMixGrpcStreamServiceServer{})
+	testutil.RequireNoError(t, err)
+	testutil.RequireNoError(t, rt.SetMethodRateLimit(TestService_Ping_FullMethod, rpcruntime.RateLimit{Rate: 0.001, Burst: 2}))
+	testutil.RequireNoError(t, rt.SetMethodRateLimit(StreamService_BidiStreamCall_FullMethod, rpcruntime.RateLimit{Rate: 0.001, Burst: 1}))
+
+	ctx := rpcruntime.WithRuntime(context.Background(), rt)
+
+	// The bucket is shared across protocols.
+	_, err = pingCall(rpcruntime.WithProtocol(ctx, rpcruntime.ProtocolGrpc), "a")
+	testutil.RequireNoError(t, err)
+	_, err = pingCall(rpcruntime.WithProtocol(ctx, rpcruntime.ProtocolConnectRPC), "b")
+	testutil.RequireNoError(t, err)
+	_, err = pingCall(ctx, "c")
+	testutil.RequireEqual(t, errors.Is(err, rpcruntime.ErrResourceExhausted), true)
+
+	done := make(chan error, 1)
+	start := func() (uint64, error) {
+		return StreamService_BidiStreamCallStart(ctx, func(*StreamResponse) bool { return true }, func(err error) { done <- err })
+	}
+	handle, err := start()
+	testutil.RequireNoError(t, err)
+	_, err = start()
+	testutil.RequireEqual(t, errors.Is(err, rpcruntime.ErrResourceExhausted), true)
+
+	testutil.RequireNoError(t, StreamService_BidiStreamCallCloseSend(handle))
+	testutil.RequireNoError(t, <-done)
+}
+
+func TestAllAdaptor_ProtocolPreference(t *testing.T) {
+	rt := rpcruntime.NewRuntime()
+	connectHandler := &mockConnectTestServiceHandler{}
+	_, err := rt.RegisterGrpcHandler(TestService_ServiceName, &mockGrpcTestServiceServer{})
+	testutil.RequireNoError(t, err)
+	_, err = rt.RegisterConnectHandler(TestService_ServiceName, connectHandler)
+	testutil.RequireNoError(t, err)
+
+	ctx := rpcruntime.WithRuntime(context.Background(), rt)
+
+	// Generated order is grpc,connectrpc.
+	_, err = pingCall(ctx, "grpc-first")
+	testutil.RequireNoError(t, err)
+	testutil.RequireEqual(t, atomic.LoadInt32(&connectHandler.pingCalled), int32(0))
+
+	testutil.RequireNoError(t, rt.SetServiceProtocolPreference(TestService_ServiceName, rpcruntime.ProtocolConnectRPC, rpcruntime.ProtocolGrpc))
+	_, err = pingCall(ctx, "connect-first")
+	testutil.RequireNoError(t, err)
+	testutil.RequireEqual(t, atomic.LoadInt32(&connectHandler.pingCalled), int32(1))
+
+	// Only listed protocols are tried.
+	rt.UnregisterConnectHandler(TestService_ServiceName)
+	testutil.RequireNoError(t, rt.SetServiceProtocolPreference(TestService_ServiceName, rpcruntime.ProtocolConnectRPC))
+	_, err = pingCall(ctx, "connect-only")
+	testutil.RequireEqual(t, errors.Is(err, rpcruntime.ErrServiceNotRegistered), true)
+}
+
+type mockConnectPingOpt1Handler struct {
+	mockConnectTestServiceHandler
+}
+
+func (m *mockConnectPingOpt1Handler) PingOpt1(_ context.Context, req *PingRequestOpt1) (*PingResponse, error) {
+	return &PingResponse{Msg: "connect: " + req.GetMsg()}, nil
+}
+
+func TestAllAdaptor_UnimplementedFallback(t *testing.T) {
+	rt := rpcruntime.NewRuntime()
+	// The gRPC handler only implements Ping; PingOpt1 comes from UnimplementedTestServiceServer.
+	_, err := rt.RegisterGrpcHandler(TestService_ServiceName, &mockGrpcTestServiceServer{})
+	testutil.RequireNoError(t, err)
+	connectHandler := &mockConnectPingOpt1Handler{}
+	_, err = rt.RegisterConnectHandler(TestService_ServiceName, connectHandler)
+	testutil.RequireNoError(t, err)
+
+	ctx := rpcruntime.WithRuntime(context.Background(), rt)
+
+	_, err = TestService_PingOpt1(ctx, &PingRequestOpt1{Msg: "x"})
+	testutil.RequireEqual(t, rpcruntime.IsUnimplemented(err), true)
+
+	rt.SetUnimplementedFallback(true)
+	resp, err := TestService_PingOpt1(ctx, &PingRequestOpt1{Msg: "x"})
+	testutil.RequireNoError(t, err)
+	testutil.RequireStringEqual(t, resp.GetMsg(), "connect: x")
+
+	// Implemented methods stay on the first protocol.
+	resp, err = TestService_Ping(ctx, &PingRequest{Msg: "y"})
+	testutil.RequireNoError(t, err)
+	testutil.RequireStringEqual(t, resp.GetMsg(), "pong: y")
+	testutil.RequireEqual(t, atomic.LoadInt32(&connectHandler.pingCalled), int32(0))
+
+	// An explicit protocol disables the fallback.
+	_, err = TestService_PingOpt1(rpcruntime.WithProtocol(ctx, rpcruntime.ProtocolGrpc), &PingRequestOpt1{Msg: "z"})
+	testutil.RequireEqual(t, rpcruntime.IsUnimplemented(err), true)
+
+	// With no other protocol left, the Unimplemented error is returned.
+	rt.UnregisterConnectHandler(TestService_ServiceName)
+	_, err = TestService_PingOpt1(ctx, &PingRequestOpt1{Msg: "w"})
+	testutil.RequireEqual(t, rpcruntime.IsUnimplemented(err), true)
+}
+
+func TestAllAdaptor_MethodProtocolRoute(t *testing.T) {
+	rt := rpcruntime.NewRuntime()
+	_, err := rt.RegisterGrpcHandler(TestService_ServiceName, &mockGrpcTestServiceServer{})
+	testutil.RequireNoError(t, err)
+	connectHandler := &mockConnectPingOpt1Handler{}
+	_, err = rt.RegisterConnectHandler(TestService_ServiceName, connectHandler)
+	testutil.RequireNoError(t, err)
+	testutil.RequireNoError(t, rt.SetMethodProtocolPreference(TestService_PingOpt1_FullMethod, rpcruntime.ProtocolConnectRPC))
+
+	ctx := rpcruntime.WithRuntime(context.Background(), rt)
+
+	resp, err := TestService_PingOpt1(ctx, &PingRequestOpt1{Msg: "routed"})
+	testutil.RequireNoError(t, err)
+	testutil.RequireStringEqual(t, resp.GetMsg(), "connect: routed")
+
+	// Other methods keep the generated order and stay on gRPC.
+	_, err = TestService_Ping(ctx, &PingRequest{Msg: "default"})
+	testutil.RequireNoError(t, err)
+	testutil.RequireEqual(t, atomic.LoadInt32(&connectHandler.pingCalled), int32(0))
+}
+
+type mockGoStreamServiceHandler struct{}
+
+func (m *mockGoStreamServiceHandler) UnaryCall(_ context.Context, req *StreamRequest) (*StreamResponse, error) {
+	return &StreamResponse{Result: "go:" + req.GetData()}, nil
+}
+
+func (m *mockGoStreamServiceHandler) ClientStreamCall(
+	_ context.Context,
+	stream rpcruntime.GoClientStream[StreamRequest],
+) (*StreamResponse, error) {
+	var builder strings.Builder
+	for {
+		req, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		builder.WriteString(req.GetData())
+	}
+	return &StreamResponse{Result: "go received:" + builder.String()}, nil
+}
+
+func (m *mockGoStreamServiceHandler) ServerStreamCall(
+	_ context.Context,
+	req *StreamRequest,
+	stream rpcruntime.GoServerStream[StreamResponse],
+) error {
+	return sendStreamResponses("go:"+req.GetData()+"-", stream.Send)
+}
+
+func (m *mockGoStreamServiceHandler) BidiStreamCall(
+	_ context.Context,
+	stream rpcruntime.GoBidiStream[StreamRequest, StreamResponse],
+) error {
+	return echoStream(stream.Recv, stream.Send, "go echo:")
+}
+
+var _ StreamService_GoHandler = (*mockGoStreamServiceHandler)(nil)
+
+func TestAllAdaptor_GoProtocol(t *testing.T) {
+	rt := rpcruntime.NewRuntime()
+	_, err := rt.RegisterGrpcHandler(StreamService_ServiceName, &mockMixGrpcStreamServiceServer{})
+	testutil.RequireNoError(t, err)
+	_, err = rt.RegisterHandler(rpcruntime.ProtocolGo, StreamService_ServiceName, &mockGoStreamServiceHandler{})
+	testutil.RequireNoError(t, err)
+
+	ctx := rpcruntime.WithProtocol(rpcruntime.WithRuntime(context.Background(), rt), rpcruntime.ProtocolGo)
+
+	t.Run("Unary", func(t *testing.T) {
+		resp, err := StreamService_UnaryCall(ctx, &StreamRequest{Data: "u"})
+		testutil.RequireNoError(t, err)
+		testutil.RequireStringEqual(t, resp.GetResult(), "go:u")
+	})
+
+	t.Run("ClientStream", func(t *testing.T) {
+		handle, err := StreamService_ClientStreamCallStart(ctx)
+		testutil.RequireNoError(t, err)
+		for _, data := range []string{"A", "B"} {
+			testutil.RequireNoError(t, StreamService_ClientStreamCallSend(handle, &StreamRequest{Data: data}))
+		}
+		resp, err := StreamService_ClientStreamCallFinish(handle)
+		testutil.RequireNoError(t, err)
+		testutil.RequireStringEqual(t, resp.GetResult(), "go received:AB")
+	})
+
+	t.Run("ServerStream", func(t *testing.T) {
+		var got []string
+		err := StreamService_ServerStreamCall(ctx, &StreamRequest{Data: "s"}, func(resp *StreamResponse) bool {
+			got = append(got, resp.GetResult())
+			return true
+		}, func(error) {})
+		testutil.RequireNoError(t, err)
+		testutil.RequireStringEqual(t, strings.Join(got, ","), "go:s-a,go:s-b,go:s-c")
+	})
+
+	t.Run("BidiStream", func(t *testing.T) {
+		var got []string
+		done := make(chan error, 1)
+		handle, err := StreamService_BidiStreamCallStart(ctx, func(resp *StreamResponse) bool {
+			got = append(got, resp.GetResult())
+			return true
+		}, func(err error) { done <- err })
+		testutil.RequireNoError(t, err)
+		testutil.RequireNoError(t, StreamService_BidiStreamCallSend(handle, &StreamRequest{Data: "X"}))
+		testutil.RequireNoError(t, StreamService_BidiStreamCallCloseSend(handle))
+		testutil.RequireNoError(t, <-done)
+		testutil.RequireStringEqual(t, strings.Join(got, ","), "go echo:X")
+	})
+
+	t.Run("FallbackOrder", func(t *testing.T) {
+		// Without an explicit protocol the generated order tries gRPC first,
+		// whose mock leaves UnaryCall unimplemented.
+		plain := rpcruntime.WithRuntime(context.Background(), rt)
+		_, err := StreamService_UnaryCall(plain, &StreamRequest{Data: "f"})
+		testutil.RequireEqual(t, rpcruntime.IsUnimplemented(err), true)
+
+		testutil.RequireNoError(t, rt.SetServiceProtocolPreference(StreamService_ServiceName, rpcruntime.ProtocolGo))
+		resp, err := StreamService_UnaryCall(plain, &StreamRequest{Data: "f"})
+		testutil.RequireNoError(t, err)
+		testutil.RequireStringEqual(t, resp.GetResult(), "go:f")
+	})
+}
+
+type mockTwirpStreamServiceHandler struct{}
+
+func (m *mockTwirpStreamServiceHandler) UnaryCall(_ context.Context, req *StreamRequest) (*StreamResponse, error) {
+	return &StreamResponse{Result: "twirp:" + req.GetData()}, nil
+}
+
+// TestAllAdaptor_TwirpProtocol verifies Twirp handlers serve unary calls and
+// are never selected for streaming methods.
+func TestAllAdaptor_TwirpProtocol(t *testing.T) {
+	rt := rpcruntime.NewRuntime()
+	_, err := rt.RegisterHandler(rpcruntime.ProtocolTwirp, StreamService_ServiceName, &mockTwirpStreamServiceHandler{})
+	testutil.RequireNoError(t, err)
+
+	plain := rpcruntime.WithRuntime(context.Background(), rt)
+	explicit := rpcruntime.WithProtocol(plain, rpcruntime.ProtocolTwirp)
+
+	t.Run("Unary", func(t *testing.T) {
+		for _, ctx := range []context.Context{plain, explicit} {
+			resp, err := StreamService_UnaryCall(ctx, &StreamRequest{Data: "u"})
+			testutil.RequireNoError(t, err)
+			testutil.RequireStringEqual(t, resp.GetResult(), "twirp:u")
+		}
+	})
+
+	t.Run("StreamingSkipsTwirp", func(t *testing.T) {
+		_, err := StreamService_ClientStreamCallStart(plain)
+		testutil.RequireEqual(t, errors.Is(err, rpcruntime.ErrServiceNotRegistered), true)
+
+		_, err = StreamService_ClientStreamCallStart(explicit)
+		testutil.RequireEqual(t, errors.Is(err, rpcruntime.ErrUnknownProtocol), true)
+	})
+
+	t.Run("StreamingFallsBackToNextProtocol", func(t *testing.T) {
+		testutil.RequireNoError(t, rt.SetServiceProtocolPreference(
+			StreamService_ServiceName,
+			rpcruntime.ProtocolTwirp,
+			rpcruntime.ProtocolGo,
+		))
+		_, err := rt.RegisterHandler(rpcruntime.ProtocolGo, StreamService_ServiceName, &mockGoStreamServiceHandler{})
+		testutil.RequireNoError(t, err)
+
+		var got []string
+		err = StreamService_ServerStreamCall(plain, &StreamRequest{Data: "s"}, func(resp *StreamResponse) bool {
+			got = append(got, resp.GetResult())
+			return true
+		}, func(error) {})
+		testutil.RequireNoError(t, err)
+		testutil.RequireStringEqual(t, strings.Join(got, ","), "go:s-a,go:s-b,go:s-c")
+
+		resp, err := StreamService_UnaryCall(plain, &StreamRequest{Data: "u"})
+		testutil.RequireNoError(t, err)
+		testutil.RequireStringEqual(t, resp.GetResult(), "twirp:u")
+	})
+}
+
+// twirpErrorCode and twirpError mirror twirp.ErrorCode and twirp.Error.
+type twirpErrorCode string
+
+type twirpError struct{ code twirpErrorCode }
+
+func (e twirpError) Code() twirpErrorCode { return e.code }
+func (e twirpError) Msg() string          { return "not implemented" }
+func (e twirpError) Error() string        { return "twirp error " + string(e.code) + ": not implemented" }
+
+type unimplementedTwirpStreamServiceHandler struct{}
+
+func (m *unimplementedTwirpStreamServiceHandler) UnaryCall(context.Context, *StreamRequest) (*StreamResponse, error) {
+	return nil, twirpError{code: "unimplemented"}
+}
+
+// TestAllAdaptor_TwirpUnimplementedFallback verifies a Twirp "unimplemented"
+// error falls back to the next protocol like gRPC and connect ones.
+func TestAllAdaptor_TwirpUnimplementedFallback(t *testing.T) {
+	rt := rpcruntime.NewRuntime()
+	_, err := rt.RegisterHandler(rpcruntime.ProtocolTwirp, StreamService_ServiceName, &unimplementedTwirpStreamServiceHandler{})
+	testutil.RequireNoError(t, err)
+	_, err = rt.RegisterHandler(rpcruntime.ProtocolGo, StreamService_ServiceName, &mockGoStreamServiceHandler{})
+	testutil.RequireNoError(t, err)
+	testutil.RequireNoError(t, rt.SetServiceProtocolPreference(
+		StreamService_ServiceName,
+		rpcruntime.ProtocolTwirp,
+		rpcruntime.ProtocolGo,
+	))
+	ctx := rpcruntime.WithRuntime(context.Background(), rt)
+
+	_, err = StreamService_UnaryCall(ctx, &StreamRequest{Data: "u"})
+	testutil.RequireEqual(t, rpcruntime.IsUnimplemented(err), true)
+
+	rt.SetUnimplementedFallback(true)
+	resp, err := StreamService_UnaryCall(ctx, &StreamRequest{Data: "u"})
+	testutil.RequireNoError(t, err)
+	testutil.RequireStringEqual(t, resp.GetResult(), "go:u")
+}
@@ -1,7 +1,7 @@
 // Code generated by protoc-gen-rpc-cgo-adaptor. DO NOT EDIT.
 //
 // source: stream.proto
-// protocols: grpc,connectrpc
+// protocols: grpc,connectrpc,go,twirp
 
 package cgotest_mix
 
@@ -9,9 +9,11 @@ import (
 	connect "connectrpc.com/connect"
 	context "context"
 	rpcruntime "github.com/ygrpc/rpccgo/rpcruntime"
+	connectrt "github.com/ygrpc/rpccgo/rpcruntime/connectrt"
 	metadata "google.golang.org/grpc/metadata"
 	proto "google.golang.org/protobuf/proto"
 	io "io"
+	slices "slices"
 )
 
 // StreamService adaptor constants.
@@ -23,40 +25,159 @@ const (
 	StreamService_BidiStreamCall_FullMethod   = "/cgotest.StreamService/BidiStreamCall"
 )
 
-// StreamService_lookupHandler selects a protocol and looks up the registered handler.
+// StreamService_lookupHandler admits a call of fullMethod under the configured concurrency
+// limits, selects a protocol and acquires the registered handler.
+// The returned release func must be called once the call has finished.
+//
+// Handlers are looked up in rpcruntime.RuntimeFromContext(ctx).
+func StreamService_lookupHandler(ctx context.Context, fullMethod string, skip ...rpcruntime.Protocol) (rpcruntime.Protocol, any, func(), error) {
+	rt := rpcruntime.RuntimeFromContext(ctx)
+	releaseSlot, err := rt.AcquireCallSlot(ctx, fullMethod)
+	if err != nil {
+		return "", nil, nil, err
+	}
+	protocol, h, release, err := streamService_acquireHandler(ctx, rt, fullMethod, skip...)
+	if err != nil {
+		releaseSlot()
+		return protocol, nil, nil, err
+	}
+	return protocol, h, func() {
+		release()
+		releaseSlot()
+	}, nil
+}
+
+// streamService_acquireHandler selects a protocol and acquires the registered handler from rt.
 //
 // Selection rules:
 // - If ctx explicitly carries a protocol, only that protocol is attempted (no fallback).
-// - Otherwise, protocols are tried in the configured order: grpc,connectrpc
-func StreamService_lookupHandler(ctx context.Context) (rpcruntime.Protocol, any, error) {
+// - Otherwise, protocols are tried in the order from rt.ProtocolOrder(fullMethod), defaulting to: grpc,connectrpc,go,twirp
+// - Protocols in skip are not tried; an explicit protocol in skip fails with ErrUnknownProtocol.
+func streamService_acquireHandler(ctx context.Context, rt *rpcruntime.Runtime, fullMethod string, skip ...rpcruntime.Protocol) (rpcruntime.Protocol, any, func(), error) {
 	protocol, hasProtocol := rpcruntime.ProtocolFromContext(ctx)
 	if hasProtocol {
+		if slices.Contains(skip, protocol) {
+			return protocol, nil, nil, rpcruntime.ErrUnknownProtocol
+		}
 		switch protocol {
 		case rpcruntime.ProtocolGrpc:
-			h, ok := rpcruntime.LookupGrpcHandler(StreamService_ServiceName)
-			if !ok {
-				return protocol, nil, rpcruntime.ErrServiceNotRegistered
+			h, release, err := rt.AcquireHandler(rpcruntime.ProtocolGrpc, StreamService_ServiceName)
+			if err != nil {
+				return protocol, nil, nil, err
 			}
-			return protocol, h, nil
+			return protocol, h, release, nil
 		case rpcruntime.ProtocolConnectRPC:
-			h, ok := rpcruntime.LookupConnectHandler(StreamService_ServiceName)
-			if !ok {
-				return protocol, nil, rpcruntime.ErrServiceNotRegistered
+			h, release, err := rt.AcquireHandler(rpcruntime.ProtocolConnectRPC, StreamService_ServiceName)
+			if err != nil {
+				return protocol, nil, nil, err
+			}
+			return protocol, h, release, nil
+		case rpcruntime.ProtocolGo:
+			h, release, err := rt.AcquireHandler(rpcruntime.ProtocolGo, StreamService_ServiceName)
+			if err != nil {
+				return protocol, nil, nil, err
 			}
-			return protocol, h, nil
+			return protocol, h, release, nil
+		case rpcruntime.ProtocolTwirp:
+			h, release, err := rt.AcquireHandler(rpcruntime.ProtocolTwirp, StreamService_ServiceName)
+			if err != nil {
+				return protocol, nil, nil, err
+			}
+			return protocol, h, release, nil
 		default:
-			return protocol, nil, rpcruntime.ErrUnknownProtocol
+			return protocol, nil, nil, rpcruntime.ErrUnknownProtocol
 		}
 	}
 
-	// Fallback: try protocols in configured order.
-	if h, ok := rpcruntime.LookupGrpcHandler(StreamService_ServiceName); ok {
-		return rpcruntime.ProtocolGrpc, h, nil
-	}
-	if h, ok := rpcruntime.LookupConnectHandler(StreamService_ServiceName); ok {
-		return rpcruntime.ProtocolConnectRPC, h, nil
+	// Fallback: try protocols in the preferred order, skipping those without a handler.
+	for _, p := range rt.ProtocolOrder(fullMethod, rpcruntime.ProtocolGrpc, rpcruntime.ProtocolConnectRPC, rpcruntime.ProtocolGo, rpcruntime.ProtocolTwirp) {
+		if slices.Contains(skip, p) {
+			continue
+		}
+		switch p {
+		case rpcruntime.ProtocolGrpc:
+			if h, release, err := rt.AcquireHandler(rpcruntime.ProtocolGrpc, StreamService_ServiceName); err != rpcruntime.ErrServiceNotRegistered {
+				return p, h, release, err
+			}
+		case rpcruntime.ProtocolConnectRPC:
+			if h, release, err := rt.AcquireHandler(rpcruntime.ProtocolConnectRPC, StreamService_ServiceName); err != rpcruntime.ErrServiceNotRegistered {
+				return p, h, release, err
+			}
+		case rpcruntime.ProtocolGo:
+			if h, release, err := rt.AcquireHandler(rpcruntime.ProtocolGo, StreamService_ServiceName); err != rpcruntime.ErrServiceNotRegistered {
+				return p, h, release, err
+			}
+		case rpcruntime.ProtocolTwirp:
+			if h, release, err := rt.AcquireHandler(rpcruntime.ProtocolTwirp, StreamService_ServiceName); err != rpcruntime.ErrServiceNotRegistered {
+				return p, h, release, err
+			}
+		}
 	}
-	return "", nil, rpcruntime.ErrServiceNotRegistered
+	return "", nil, nil, rpcruntime.ErrServiceNotRegistered
+}
+
+// RegisterStreamServiceGrpcHandler registers h as the gRPC handler for StreamService.
+// It returns true if a previous handler was replaced.
+func RegisterStreamServiceGrpcHandler(h StreamServiceServer) (bool, error) {
+	return rpcruntime.RegisterGrpcHandler(StreamService_ServiceName, h)
+}
+
+// StreamService_ConnectHandler is the Connect simple-API handler interface the StreamService adaptor
+// dispatches to.
+type StreamService_ConnectHandler interface {
+	UnaryCall(context.Context, *StreamRequest) (*StreamResponse, error)
+	ClientStreamCall(context.Context, *connect.ClientStream[StreamRequest]) (*StreamResponse, error)
+	ServerStreamCall(context.Context, *StreamRequest, *connect.ServerStream[StreamResponse]) error
+	BidiStreamCall(context.Context, *connect.BidiStream[StreamRequest, StreamResponse]) error
+}
+
+// RegisterStreamServiceConnectHandler registers h as the connectrpc handler for StreamService.
+// It returns true if a previous handler was replaced.
+func RegisterStreamServiceConnectHandler(h StreamService_ConnectHandler) (bool, error) {
+	return rpcruntime.RegisterConnectHandler(StreamService_ServiceName, h)
+}
+
+// StreamService_ConnectGenericHandler is the Connect generic-API handler interface the StreamService
+// adaptor dispatches to; requests and responses are wrapped in connect.Request and
+// connect.Response so handlers can read headers and set trailers.
+type StreamService_ConnectGenericHandler interface {
+	UnaryCall(context.Context, *connect.Request[StreamRequest]) (*connect.Response[StreamResponse], error)
+	ClientStreamCall(context.Context, *connect.ClientStream[StreamRequest]) (*connect.Response[StreamResponse], error)
+	ServerStreamCall(context.Context, *connect.Request[StreamRequest], *connect.ServerStream[StreamResponse]) error
+	BidiStreamCall(context.Context, *connect.BidiStream[StreamRequest, StreamResponse]) error
+}
+
+// RegisterStreamServiceConnectGenericHandler registers h as the connectrpc handler for StreamService.
+// It returns true if a previous handler was replaced.
+func RegisterStreamServiceConnectGenericHandler(h StreamService_ConnectGenericHandler) (bool, error) {
+	return rpcruntime.RegisterConnectHandler(StreamService_ServiceName, h)
+}
+
+// StreamService_GoHandler is the plain Go handler interface the StreamService adaptor
+// dispatches to. It depends on neither grpc-go nor connect-go.
+type StreamService_GoHandler interface {
+	UnaryCall(context.Context, *StreamRequest) (*StreamResponse, error)
+	ClientStreamCall(context.Context, rpcruntime.GoClientStream[StreamRequest]) (*StreamResponse, error)
+	ServerStreamCall(context.Context, *StreamRequest, rpcruntime.GoServerStream[StreamResponse]) error
+	BidiStreamCall(context.Context, rpcruntime.GoBidiStream[StreamRequest, StreamResponse]) error
+}
+
+// RegisterStreamServiceGoHandler registers h as the plain Go handler for StreamService.
+// It returns true if a previous handler was replaced.
+func RegisterStreamServiceGoHandler(h StreamService_GoHandler) (bool, error) {
+	return rpcruntime.RegisterHandler(rpcruntime.ProtocolGo, StreamService_ServiceName, h)
+}
+
+// StreamService_TwirpHandler is the method set of the protoc-gen-twirp StreamService interface
+// the adaptor dispatches to. Streaming methods are not supported by Twirp.
+type StreamService_TwirpHandler interface {
+	UnaryCall(context.Context, *StreamRequest) (*StreamResponse, error)
+}
+
+// RegisterStreamServiceTwirpHandler registers h as the Twirp handler for StreamService.
+// It returns true if a previous handler was replaced.
+func RegisterStreamServiceTwirpHandler(h StreamService_TwirpHandler) (bool, error) {
+	return rpcruntime.RegisterHandler(rpcruntime.ProtocolTwirp, StreamService_ServiceName, h)
 }
 
 // streamService_ClientStreamCallServerAdaptor adapts rpcruntime.StreamSession to StreamService_ClientStreamCallServer.
@@ -272,27 +393,76 @@ var _ StreamService_BidiStreamCallServer = (*streamService_BidiStreamCallServerA
 
 // StreamService_UnaryCall calls cgotest.StreamService.UnaryCall via the registered handler.
 func StreamService_UnaryCall(ctx context.Context, req *StreamRequest) (*StreamResponse, error) {
-	protocol, h, err := StreamService_lookupHandler(ctx)
+	rt := rpcruntime.RuntimeFromContext(ctx)
+	releaseSlot, err := rt.AcquireCallSlot(ctx, StreamService_UnaryCall_FullMethod)
 	if err != nil {
 		return nil, err
 	}
-	switch protocol {
-	case rpcruntime.ProtocolGrpc:
-		svc, ok := h.(StreamServiceServer)
-		if !ok {
-			return nil, rpcruntime.ErrHandlerTypeMismatch
+	defer releaseSlot()
+	ctx, cancel := rpcruntime.CallContext(ctx)
+	defer cancel()
+
+	var tried []rpcruntime.Protocol
+	var lastErr error
+	for {
+		protocol, h, release, err := streamService_acquireHandler(ctx, rt, StreamService_UnaryCall_FullMethod, tried...)
+		if err != nil {
+			if lastErr != nil {
+				return nil, lastErr
+			}
+			if remote, ok := rt.RemoteFallback(StreamService_ServiceName, err); ok {
+				return rpcruntime.ForwardUnary[StreamRequest, StreamResponse](ctx, remote, StreamService_UnaryCall_FullMethod, req)
+			}
+			return nil, err
 		}
-		return svc.UnaryCall(ctx, req)
-	case rpcruntime.ProtocolConnectRPC:
-		svc, ok := h.(interface {
-			UnaryCall(context.Context, *StreamRequest) (*StreamResponse, error)
-		})
-		if !ok {
-			return nil, rpcruntime.ErrHandlerTypeMismatch
+
+		resp, err := func() (*StreamResponse, error) {
+			defer release()
+			switch protocol {
+			case rpcruntime.ProtocolGrpc:
+				svc, ok := h.(StreamServiceServer)
+				if !ok {
+					return nil, rpcruntime.ErrHandlerTypeMismatch
+				}
+				return svc.UnaryCall(ctx, req)
+			case rpcruntime.ProtocolConnectRPC:
+				if svc, ok := h.(interface {
+					UnaryCall(context.Context, *StreamRequest) (*StreamResponse, error)
+				}); ok {
+					return svc.UnaryCall(ctx, req)
+				}
+				svc, ok := h.(interface {
+					UnaryCall(context.Context, *connect.Request[StreamRequest]) (*connect.Response[StreamResponse], error)
+				})
+				if !ok {
+					return nil, rpcruntime.ErrHandlerTypeMismatch
+				}
+				return connectrt.CallUnary(ctx, req, svc.UnaryCall)
+			case rpcruntime.ProtocolGo:
+				svc, ok := h.(interface {
+					UnaryCall(context.Context, *StreamRequest) (*StreamResponse, error)
+				})
+				if !ok {
+					return nil, rpcruntime.ErrHandlerTypeMismatch
+				}
+				return svc.UnaryCall(ctx, req)
+			case rpcruntime.ProtocolTwirp:
+				svc, ok := h.(interface {
+					UnaryCall(context.Context, *StreamRequest) (*StreamResponse, error)
+				})
+				if !ok {
+					return nil, rpcruntime.ErrHandlerTypeMismatch
+				}
+				return svc.UnaryCall(ctx, req)
+			default:
+				return nil, rpcruntime.ErrUnknownProtocol
+			}
+		}()
+		if err == nil || !rt.FallbackOnUnimplemented(ctx, err) {
+			return resp, err
 		}
-		return svc.UnaryCall(ctx, req)
-	default:
-		return nil, rpcruntime.ErrUnknownProtocol
+		tried = append(tried, protocol)
+		lastErr = err
 	}
 }
 
@@ -301,30 +471,54 @@ func StreamService_UnaryCall(ctx context.Context, req *StreamRequest) (*StreamRe
 
 // StreamService_ClientStreamCallStart initializes a client-streaming call and returns a stream handle.
 func StreamService_ClientStreamCallStart(ctx context.Context) (uint64, error) {
-	protocol, h, err := StreamService_lookupHandler(ctx)
+	protocol, h, release, err := StreamService_lookupHandler(ctx, StreamService_ClientStreamCall_FullMethod, rpcruntime.ProtocolTwirp)
 	if err != nil {
+		if remote, ok := rpcruntime.RuntimeFromContext(ctx).RemoteFallback(StreamService_ServiceName, err); ok {
+			return rpcruntime.ForwardClientStream[StreamRequest, StreamResponse](ctx, remote, StreamService_ClientStreamCall_FullMethod)
+		}
 		return 0, err
 	}
+	// release is handed over to the handler goroutine once it starts.
+	handlerStarted := false
+	defer func() {
+		if !handlerStarted {
+			release()
+		}
+	}()
 
 	var grpcSvc StreamServiceServer
-	var connectSvc interface {
-		ClientStreamCall(context.Context, *connect.ClientStream[StreamRequest]) (*StreamResponse, error)
+	var connectSvc func(context.Context, *connect.ClientStream[StreamRequest]) (*StreamResponse, error)
+	var goSvc interface {
+		ClientStreamCall(context.Context, rpcruntime.GoClientStream[StreamRequest]) (*StreamResponse, error)
 	}
-	if protocol == rpcruntime.ProtocolGrpc {
+	switch protocol {
+	case rpcruntime.ProtocolGrpc:
 		svc, ok := h.(StreamServiceServer)
 		if !ok {
 			return 0, rpcruntime.ErrHandlerTypeMismatch
 		}
 		grpcSvc = svc
-	} else if protocol == rpcruntime.ProtocolConnectRPC {
-		svc, ok := h.(interface {
+	case rpcruntime.ProtocolConnectRPC:
+		if simple, ok := h.(interface {
 			ClientStreamCall(context.Context, *connect.ClientStream[StreamRequest]) (*StreamResponse, error)
+		}); ok {
+			connectSvc = simple.ClientStreamCall
+		} else if generic, ok := h.(interface {
+			ClientStreamCall(context.Context, *connect.ClientStream[StreamRequest]) (*connect.Response[StreamResponse], error)
+		}); ok {
+			connectSvc = connectrt.ClientStreamHandler(generic.ClientStreamCall)
+		} else {
+			return 0, rpcruntime.ErrHandlerTypeMismatch
+		}
+	case rpcruntime.ProtocolGo:
+		svc, ok := h.(interface {
+			ClientStreamCall(context.Context, rpcruntime.GoClientStream[StreamRequest]) (*StreamResponse, error)
 		})
 		if !ok {
 			return 0, rpcruntime.ErrHandlerTypeMismatch
 		}
-		connectSvc = svc
-	} else {
+		goSvc = svc
+	default:
 		return 0, rpcruntime.ErrUnknownProtocol
 	}
 
@@ -334,10 +528,13 @@ func StreamService_ClientStreamCallStart(ctx context.Context) (uint64, error) {
 		return 0, rpcruntime.ErrInvalidStreamHandle
 	}
 
-	if protocol == rpcruntime.ProtocolGrpc {
+	switch protocol {
+	case rpcruntime.ProtocolGrpc:
 		adaptorStream := &streamService_ClientStreamCallServerAdaptor{session: session}
 		session.SetHandlerState(adaptorStream)
+		handlerStarted = true
 		go func() {
+			defer release()
 			defer func() {
 				if r := recover(); r != nil {
 					rpcruntime.CompleteClientStream(handle, nil, rpcruntime.RecoverPanic(r))
@@ -346,16 +543,29 @@ func StreamService_ClientStreamCallStart(ctx context.Context) (uint64, error) {
 			err := grpcSvc.ClientStreamCall(adaptorStream)
 			rpcruntime.CompleteClientStream(handle, adaptorStream.lastResp, err)
 		}()
-	} else {
-		conn := rpcruntime.NewConnectStreamConn(session)
-		connectStream := rpcruntime.NewClientStream[StreamRequest](conn)
+	case rpcruntime.ProtocolConnectRPC:
+		handlerStarted = true
+		go func() {
+			defer release()
+			defer func() {
+				if r := recover(); r != nil {
+					rpcruntime.CompleteClientStream(handle, nil, rpcruntime.RecoverPanic(r))
+				}
+			}()
+			resp, err := connectrt.ServeClientStream(childCtx, session, StreamService_ClientStreamCall_FullMethod, connectSvc)
+			rpcruntime.CompleteClientStream(handle, resp, err)
+		}()
+	case rpcruntime.ProtocolGo:
+		goStream := rpcruntime.NewGoClientStream[StreamRequest](session)
+		handlerStarted = true
 		go func() {
+			defer release()
 			defer func() {
 				if r := recover(); r != nil {
 					rpcruntime.CompleteClientStream(handle, nil, rpcruntime.RecoverPanic(r))
 				}
 			}()
-			resp, err := connectSvc.ClientStreamCall(childCtx, connectStream)
+			resp, err := goSvc.ClientStreamCall(childCtx, goStream)
 			rpcruntime.CompleteClientStream(handle, resp, err)
 		}()
 	}
@@ -387,33 +597,52 @@ func StreamService_ClientStreamCallFinish(streamHandle uint64) (*StreamResponse,
 // - onRead is called for each response message; return false to stop receiving.
 // - onDone is called exactly once when the stream ends or fails.
 func StreamService_ServerStreamCall(ctx context.Context, req *StreamRequest, onRead func(*StreamResponse) bool, onDone func(error)) error {
-	protocol, h, err := StreamService_lookupHandler(ctx)
+	protocol, h, release, err := StreamService_lookupHandler(ctx, StreamService_ServerStreamCall_FullMethod, rpcruntime.ProtocolTwirp)
 	if err != nil {
+		if remote, ok := rpcruntime.RuntimeFromContext(ctx).RemoteFallback(StreamService_ServiceName, err); ok {
+			return rpcruntime.ForwardServerStream(ctx, remote, StreamService_ServerStreamCall_FullMethod, req, onRead, onDone)
+		}
 		onDone(err)
 		return err
 	}
+	defer release()
 
 	var grpcSvc StreamServiceServer
-	var connectSvc interface {
-		ServerStreamCall(context.Context, *StreamRequest, *connect.ServerStream[StreamResponse]) error
+	var connectSvc func(context.Context, *StreamRequest, *connect.ServerStream[StreamResponse]) error
+	var goSvc interface {
+		ServerStreamCall(context.Context, *StreamRequest, rpcruntime.GoServerStream[StreamResponse]) error
 	}
-	if protocol == rpcruntime.ProtocolGrpc {
+	switch protocol {
+	case rpcruntime.ProtocolGrpc:
 		svc, ok := h.(StreamServiceServer)
 		if !ok {
 			onDone(rpcruntime.ErrHandlerTypeMismatch)
 			return rpcruntime.ErrHandlerTypeMismatch
 		}
 		grpcSvc = svc
-	} else if protocol == rpcruntime.ProtocolConnectRPC {
-		svc, ok := h.(interface {
+	case rpcruntime.ProtocolConnectRPC:
+		if simple, ok := h.(interface {
 			ServerStreamCall(context.Context, *StreamRequest, *connect.ServerStream[StreamResponse]) error
+		}); ok {
+			connectSvc = simple.ServerStreamCall
+		} else if generic, ok := h.(interface {
+			ServerStreamCall(context.Context, *connect.Request[StreamRequest], *connect.ServerStream[StreamResponse]) error
+		}); ok {
+			connectSvc = connectrt.ServerStreamHandler(generic.ServerStreamCall)
+		} else {
+			onDone(rpcruntime.ErrHandlerTypeMismatch)
+			return rpcruntime.ErrHandlerTypeMismatch
+		}
+	case rpcruntime.ProtocolGo:
+		svc, ok := h.(interface {
+			ServerStreamCall(context.Context, *StreamRequest, rpcruntime.GoServerStream[StreamResponse]) error
 		})
 		if !ok {
 			onDone(rpcruntime.ErrHandlerTypeMismatch)
 			return rpcruntime.ErrHandlerTypeMismatch
 		}
-		connectSvc = svc
-	} else {
+		goSvc = svc
+	default:
 		onDone(rpcruntime.ErrUnknownProtocol)
 		return rpcruntime.ErrUnknownProtocol
 	}
@@ -426,20 +655,19 @@ func StreamService_ServerStreamCall(ctx context.Context, req *StreamRequest, onR
 	}
 	session.SetCallbacks(func(resp any) bool { return onRead(resp.(*StreamResponse)) }, onDone)
 
-	if protocol == rpcruntime.ProtocolGrpc {
+	switch protocol {
+	case rpcruntime.ProtocolGrpc:
 		adaptorStream := &streamService_ServerStreamCallServerAdaptor{session: session}
 		err = grpcSvc.ServerStreamCall(req, adaptorStream)
-		rpcruntime.FinishStreamHandle(handle)
-		onDone(err)
-		return err
-	} else {
-		conn := rpcruntime.NewConnectStreamConn(session)
-		connectStream := rpcruntime.NewServerStream[StreamResponse](conn)
-		err = connectSvc.ServerStreamCall(ctx, req, connectStream)
-		rpcruntime.FinishStreamHandle(handle)
-		onDone(err)
-		return err
+	case rpcruntime.ProtocolConnectRPC:
+		err = connectrt.ServeServerStream(ctx, session, StreamService_ServerStreamCall_FullMethod, req, connectSvc)
+	case rpcruntime.ProtocolGo:
+		goStream := rpcruntime.NewGoServerStream[StreamResponse](session)
+		err = goSvc.ServerStreamCall(ctx, req, goStream)
 	}
+	rpcruntime.FinishStreamHandle(handle)
+	onDone(err)
+	return err
 }
 
 // StreamService_BidiStreamCall bidi-streaming adaptor functions.
@@ -448,22 +676,36 @@ func StreamService_ServerStreamCall(ctx context.Context, req *StreamRequest, onR
 // StreamService_BidiStreamCallStart initializes a bidi-streaming call and returns a stream handle.
 // Provide onRead and onDone callbacks to receive response messages.
 func StreamService_BidiStreamCallStart(ctx context.Context, onRead func(*StreamResponse) bool, onDone func(error)) (uint64, error) {
-	protocol, h, err := StreamService_lookupHandler(ctx)
+	protocol, h, release, err := StreamService_lookupHandler(ctx, StreamService_BidiStreamCall_FullMethod, rpcruntime.ProtocolTwirp)
 	if err != nil {
+		if remote, ok := rpcruntime.RuntimeFromContext(ctx).RemoteFallback(StreamService_ServiceName, err); ok {
+			return rpcruntime.ForwardBidiStream[StreamRequest, StreamResponse](ctx, remote, StreamService_BidiStreamCall_FullMethod, onRead, onDone)
+		}
 		return 0, err
 	}
+	// release is handed over to the handler goroutine once it starts.
+	handlerStarted := false
+	defer func() {
+		if !handlerStarted {
+			release()
+		}
+	}()
 
 	var grpcSvc StreamServiceServer
 	var connectSvc interface {
 		BidiStreamCall(context.Context, *connect.BidiStream[StreamRequest, StreamResponse]) error
 	}
-	if protocol == rpcruntime.ProtocolGrpc {
+	var goSvc interface {
+		BidiStreamCall(context.Context, rpcruntime.GoBidiStream[StreamRequest, StreamResponse]) error
+	}
+	switch protocol {
+	case rpcruntime.ProtocolGrpc:
 		svc, ok := h.(StreamServiceServer)
 		if !ok {
 			return 0, rpcruntime.ErrHandlerTypeMismatch
 		}
 		grpcSvc = svc
-	} else if protocol == rpcruntime.ProtocolConnectRPC {
+	case rpcruntime.ProtocolConnectRPC:
 		svc, ok := h.(interface {
 			BidiStreamCall(context.Context, *connect.BidiStream[StreamRequest, StreamResponse]) error
 		})
@@ -471,7 +713,15 @@ func StreamService_BidiStreamCallStart(ctx context.Context, onRead func(*StreamR
 			return 0, rpcruntime.ErrHandlerTypeMismatch
 		}
 		connectSvc = svc
-	} else {
+	case rpcruntime.ProtocolGo:
+		svc, ok := h.(interface {
+			BidiStreamCall(context.Context, rpcruntime.GoBidiStream[StreamRequest, StreamResponse]) error
+		})
+		if !ok {
+			return 0, rpcruntime.ErrHandlerTypeMismatch
+		}
+		goSvc = svc
+	default:
 		return 0, rpcruntime.ErrUnknownProtocol
 	}
 
@@ -482,10 +732,13 @@ func StreamService_BidiStreamCallStart(ctx context.Context, onRead func(*StreamR
 	}
 	session.SetCallbacks(func(resp any) bool { return onRead(resp.(*StreamResponse)) }, onDone)
 
-	if protocol == rpcruntime.ProtocolGrpc {
+	switch protocol {
+	case rpcruntime.ProtocolGrpc:
 		adaptorStream := &streamService_BidiStreamCallServerAdaptor{session: session}
 		session.SetHandlerState(adaptorStream)
+		handlerStarted = true
 		go func() {
+			defer release()
 			defer func() {
 				if r := recover(); r != nil {
 					if cb := session.OnDone(); cb != nil {
@@ -500,11 +753,30 @@ func StreamService_BidiStreamCallStart(ctx context.Context, onRead func(*StreamR
 			}
 			rpcruntime.FinishStreamHandle(handle)
 		}()
-	} else {
-		conn := rpcruntime.NewConnectStreamConn(session)
-		connectStream := rpcruntime.NewBidiStream[StreamRequest, StreamResponse](conn)
-		session.SetHandlerState(connectStream)
+	case rpcruntime.ProtocolConnectRPC:
+		handlerStarted = true
+		go func() {
+			defer release()
+			defer func() {
+				if r := recover(); r != nil {
+					if cb := session.OnDone(); cb != nil {
+						cb(rpcruntime.RecoverPanic(r))
+					}
+					rpcruntime.FinishStreamHandle(handle)
+				}
+			}()
+			err := connectrt.ServeBidiStream(childCtx, session, StreamService_BidiStreamCall_FullMethod, connectSvc.BidiStreamCall)
+			if cb := session.OnDone(); cb != nil {
+				cb(err)
+			}
+			rpcruntime.FinishStreamHandle(handle)
+		}()
+	case rpcruntime.ProtocolGo:
+		goStream := rpcruntime.NewGoBidiStream[StreamRequest, StreamResponse](session)
+		session.SetHandlerState(goStream)
+		handlerStarted = true
 		go func() {
+			defer release()
 			defer func() {
 				if r := recover(); r != nil {
 					if cb := session.OnDone(); cb != nil {
@@ -513,7 +785,7 @@ func StreamService_BidiStreamCallStart(ctx context.Context, onRead func(*StreamR
 					rpcruntime.FinishStreamHandle(handle)
 				}
 			}()
-			err := connectSvc.BidiStreamCall(childCtx, connectStream)
+			err := goSvc.BidiStreamCall(childCtx, goStream)
 			if cb := session.OnDone(); cb != nil {
 				cb(err)
 			}
@@ -534,3 +806,13 @@ func StreamService_BidiStreamCallSend(streamHandle uint64, req *StreamRequest) e
 func StreamService_BidiStreamCallCloseSend(streamHandle uint64) error {
 	return rpcruntime.CloseSendCh(rpcruntime.StreamHandle(streamHandle))
 }
+
+// Register the adaptor functions for dispatch by full method name (see grpcrt.ClientConn).
+func init() {
+	rpcruntime.RegisterMethods(
+		rpcruntime.NewUnaryMethodDesc(StreamService_UnaryCall_FullMethod, StreamService_UnaryCall),
+		rpcruntime.NewClientStreamMethodDesc(StreamService_ClientStreamCall_FullMethod, StreamService_ClientStreamCallStart, StreamService_ClientStreamCallSend, StreamService_ClientStreamCallFinish),
+		rpcruntime.NewServerStreamMethodDesc(StreamService_ServerStreamCall_FullMethod, StreamService_ServerStreamCall),
+		rpcruntime.NewBidiStreamMethodDesc(StreamService_BidiStreamCall_FullMethod, StreamService_BidiStreamCallStart, StreamService_BidiStreamCallSend, StreamService_BidiStreamCallCloseSend),
+	)
+}
@@ -1,13 +1,16 @@
 // Code generated by protoc-gen-rpc-cgo-adaptor. DO NOT EDIT.
 //
 // source: unary.proto
-// protocols: grpc,connectrpc
+// protocols: grpc,connectrpc,go,twirp
 
 package cgotest_mix
 
 import (
+	connect "connectrpc.com/connect"
 	context "context"
 	rpcruntime "github.com/ygrpc/rpccgo/rpcruntime"
+	connectrt "github.com/ygrpc/rpccgo/rpcruntime/connectrt"
+	slices "slices"
 )
 
 // TestService adaptor constants.
@@ -19,142 +22,470 @@ const (
 	TestService_NonFlat_FullMethod  = "/cgotest.TestService/NonFlat"
 )
 
-// TestService_lookupHandler selects a protocol and looks up the registered handler.
+// TestService_lookupHandler admits a call of fullMethod under the configured concurrency
+// limits, selects a protocol and acquires the registered handler.
+// The returned release func must be called once the call has finished.
+//
+// Handlers are looked up in rpcruntime.RuntimeFromContext(ctx).
+func TestService_lookupHandler(ctx context.Context, fullMethod string, skip ...rpcruntime.Protocol) (rpcruntime.Protocol, any, func(), error) {
+	rt := rpcruntime.RuntimeFromContext(ctx)
+	releaseSlot, err := rt.AcquireCallSlot(ctx, fullMethod)
+	if err != nil {
+		return "", nil, nil, err
+	}
+	protocol, h, release, err := testService_acquireHandler(ctx, rt, fullMethod, skip...)
+	if err != nil {
+		releaseSlot()
+		return protocol, nil, nil, err
+	}
+	return protocol, h, func() {
+		release()
+		releaseSlot()
+	}, nil
+}
+
+// testService_acquireHandler selects a protocol and acquires the registered handler from rt.
 //
 // Selection rules:
 // - If ctx explicitly carries a protocol, only that protocol is attempted (no fallback).
-// - Otherwise, protocols are tried in the configured order: grpc,connectrpc
-func TestService_lookupHandler(ctx context.Context) (rpcruntime.Protocol, any, error) {
+// - Otherwise, protocols are tried in the order from rt.ProtocolOrder(fullMethod), defaulting to: grpc,connectrpc,go,twirp
+// - Protocols in skip are not tried; an explicit protocol in skip fails with ErrUnknownProtocol.
+func testService_acquireHandler(ctx context.Context, rt *rpcruntime.Runtime, fullMethod string, skip ...rpcruntime.Protocol) (rpcruntime.Protocol, any, func(), error) {
 	protocol, hasProtocol := rpcruntime.ProtocolFromContext(ctx)
 	if hasProtocol {
+		if slices.Contains(skip, protocol) {
+			return protocol, nil, nil, rpcruntime.ErrUnknownProtocol
+		}
 		switch protocol {
 		case rpcruntime.ProtocolGrpc:
-			h, ok := rpcruntime.LookupGrpcHandler(TestService_ServiceName)
-			if !ok {
-				return protocol, nil, rpcruntime.ErrServiceNotRegistered
+			h, release, err := rt.AcquireHandler(rpcruntime.ProtocolGrpc, TestService_ServiceName)
+			if err != nil {
+				return protocol, nil, nil, err
 			}
-			return protocol, h, nil
+			return protocol, h, release, nil
 		case rpcruntime.ProtocolConnectRPC:
-			h, ok := rpcruntime.LookupConnectHandler(TestService_ServiceName)
-			if !ok {
-				return protocol, nil, rpcruntime.ErrServiceNotRegistered
+			h, release, err := rt.AcquireHandler(rpcruntime.ProtocolConnectRPC, TestService_ServiceName)
+			if err != nil {
+				return protocol, nil, nil, err
 			}
-			return protocol, h, nil
+			return protocol, h, release, nil
+		case rpcruntime.ProtocolGo:
+			h, release, err := rt.AcquireHandler(rpcruntime.ProtocolGo, TestService_ServiceName)
+			if err != nil {
+				return protocol, nil, nil, err
+			}
+			return protocol, h, release, nil
+		case rpcruntime.ProtocolTwirp:
+			h, release, err := rt.AcquireHandler(rpcruntime.ProtocolTwirp, TestService_ServiceName)
+			if err != nil {
+				return protocol, nil, nil, err
+			}
+			return protocol, h, release, nil
 		default:
-			return protocol, nil, rpcruntime.ErrUnknownProtocol
+			return protocol, nil, nil, rpcruntime.ErrUnknownProtocol
 		}
 	}
 
-	// Fallback: try protocols in configured order.
-	if h, ok := rpcruntime.LookupGrpcHandler(TestService_ServiceName); ok {
-		return rpcruntime.ProtocolGrpc, h, nil
-	}
-	if h, ok := rpcruntime.LookupConnectHandler(TestService_ServiceName); ok {
-		return rpcruntime.ProtocolConnectRPC, h, nil
+	// Fallback: try protocols in the preferred order, skipping those without a handler.
+	for _, p := range rt.ProtocolOrder(fullMethod, rpcruntime.ProtocolGrpc, rpcruntime.ProtocolConnectRPC, rpcruntime.ProtocolGo, rpcruntime.ProtocolTwirp) {
+		if slices.Contains(skip, p) {
+			continue
+		}
+		switch p {
+		case rpcruntime.ProtocolGrpc:
+			if h, release, err := rt.AcquireHandler(rpcruntime.ProtocolGrpc, TestService_ServiceName); err != rpcruntime.ErrServiceNotRegistered {
+				return p, h, release, err
+			}
+		case rpcruntime.ProtocolConnectRPC:
+			if h, release, err := rt.AcquireHandler(rpcruntime.ProtocolConnectRPC, TestService_ServiceName); err != rpcruntime.ErrServiceNotRegistered {
+				return p, h, release, err
+			}
+		case rpcruntime.ProtocolGo:
+			if h, release, err := rt.AcquireHandler(rpcruntime.ProtocolGo, TestService_ServiceName); err != rpcruntime.ErrServiceNotRegistered {
+				return p, h, release, err
+			}
+		case rpcruntime.ProtocolTwirp:
+			if h, release, err := rt.AcquireHandler(rpcruntime.ProtocolTwirp, TestService_ServiceName); err != rpcruntime.ErrServiceNotRegistered {
+				return p, h, release, err
+			}
+		}
 	}
-	return "", nil, rpcruntime.ErrServiceNotRegistered
+	return "", nil, nil, rpcruntime.ErrServiceNotRegistered
+}
+
+// RegisterTestServiceGrpcHandler registers h as the gRPC handler for TestService.
+// It returns true if a previous handler was replaced.
+func RegisterTestServiceGrpcHandler(h TestServiceServer) (bool, error) {
+	return rpcruntime.RegisterGrpcHandler(TestService_ServiceName, h)
+}
+
+// TestService_ConnectHandler is the Connect simple-API handler interface the TestService adaptor
+// dispatches to.
+type TestService_ConnectHandler interface {
+	Ping(context.Context, *PingRequest) (*PingResponse, error)
+	PingOpt1(context.Context, *PingRequestOpt1) (*PingResponse, error)
+	PingOpt2(context.Context, *PingRequestOpt2) (*PingResponse, error)
+	NonFlat(context.Context, *NonFlatRequest) (*PingResponse, error)
+}
+
+// RegisterTestServiceConnectHandler registers h as the connectrpc handler for TestService.
+// It returns true if a previous handler was replaced.
+func RegisterTestServiceConnectHandler(h TestService_ConnectHandler) (bool, error) {
+	return rpcruntime.RegisterConnectHandler(TestService_ServiceName, h)
+}
+
+// TestService_ConnectGenericHandler is the Connect generic-API handler interface the TestService
+// adaptor dispatches to; requests and responses are wrapped in connect.Request and
+// connect.Response so handlers can read headers and set trailers.
+type TestService_ConnectGenericHandler interface {
+	Ping(context.Context, *connect.Request[PingRequest]) (*connect.Response[PingResponse], error)
+	PingOpt1(context.Context, *connect.Request[PingRequestOpt1]) (*connect.Response[PingResponse], error)
+	PingOpt2(context.Context, *connect.Request[PingRequestOpt2]) (*connect.Response[PingResponse], error)
+	NonFlat(context.Context, *connect.Request[NonFlatRequest]) (*connect.Response[PingResponse], error)
+}
+
+// RegisterTestServiceConnectGenericHandler registers h as the connectrpc handler for TestService.
+// It returns true if a previous handler was replaced.
+func RegisterTestServiceConnectGenericHandler(h TestService_ConnectGenericHandler) (bool, error) {
+	return rpcruntime.RegisterConnectHandler(TestService_ServiceName, h)
+}
+
+// TestService_GoHandler is the plain Go handler interface the TestService adaptor
+// dispatches to. It depends on neither grpc-go nor connect-go.
+type TestService_GoHandler interface {
+	Ping(context.Context, *PingRequest) (*PingResponse, error)
+	PingOpt1(context.Context, *PingRequestOpt1) (*PingResponse, error)
+	PingOpt2(context.Context, *PingRequestOpt2) (*PingResponse, error)
+	NonFlat(context.Context, *NonFlatRequest) (*PingResponse, error)
+}
+
+// RegisterTestServiceGoHandler registers h as the plain Go handler for TestService.
+// It returns true if a previous handler was replaced.
+func RegisterTestServiceGoHandler(h TestService_GoHandler) (bool, error) {
+	return rpcruntime.RegisterHandler(rpcruntime.ProtocolGo, TestService_ServiceName, h)
+}
+
+// TestService_TwirpHandler is the method set of the protoc-gen-twirp TestService interface
+// the adaptor dispatches to. Streaming methods are not supported by Twirp.
+type TestService_TwirpHandler interface {
+	Ping(context.Context, *PingRequest) (*PingResponse, error)
+	PingOpt1(context.Context, *PingRequestOpt1) (*PingResponse, error)
+	PingOpt2(context.Context, *PingRequestOpt2) (*PingResponse, error)
+	NonFlat(context.Context, *NonFlatRequest) (*PingResponse, error)
+}
+
+// RegisterTestServiceTwirpHandler registers h as the Twirp handler for TestService.
+// It returns true if a previous handler was replaced.
+func RegisterTestServiceTwirpHandler(h TestService_TwirpHandler) (bool, error) {
+	return rpcruntime.RegisterHandler(rpcruntime.ProtocolTwirp, TestService_ServiceName, h)
 }
 
 // TestService_Ping calls cgotest.TestService.Ping via the registered handler.
 func TestService_Ping(ctx context.Context, req *PingRequest) (*PingResponse, error) {
-	protocol, h, err := TestService_lookupHandler(ctx)
+	rt := rpcruntime.RuntimeFromContext(ctx)
+	releaseSlot, err := rt.AcquireCallSlot(ctx, TestService_Ping_FullMethod)
 	if err != nil {
 		return nil, err
 	}
-	switch protocol {
-	case rpcruntime.ProtocolGrpc:
-		svc, ok := h.(TestServiceServer)
-		if !ok {
-			return nil, rpcruntime.ErrHandlerTypeMismatch
+	defer releaseSlot()
+	ctx, cancel := rpcruntime.CallContext(ctx)
+	defer cancel()
+
+	var tried []rpcruntime.Protocol
+	var lastErr error
+	for {
+		protocol, h, release, err := testService_acquireHandler(ctx, rt, TestService_Ping_FullMethod, tried...)
+		if err != nil {
+			if lastErr != nil {
+				return nil, lastErr
+			}
+			if remote, ok := rt.RemoteFallback(TestService_ServiceName, err); ok {
+				return rpcruntime.ForwardUnary[PingRequest, PingResponse](ctx, remote, TestService_Ping_FullMethod, req)
+			}
+			return nil, err
 		}
-		return svc.Ping(ctx, req)
-	case rpcruntime.ProtocolConnectRPC:
-		svc, ok := h.(interface {
-			Ping(context.Context, *PingRequest) (*PingResponse, error)
-		})
-		if !ok {
-			return nil, rpcruntime.ErrHandlerTypeMismatch
+
+		resp, err := func() (*PingResponse, error) {
+			defer release()
+			switch protocol {
+			case rpcruntime.ProtocolGrpc:
+				svc, ok := h.(TestServiceServer)
+				if !ok {
+					return nil, rpcruntime.ErrHandlerTypeMismatch
+				}
+				return svc.Ping(ctx, req)
+			case rpcruntime.ProtocolConnectRPC:
+				if svc, ok := h.(interface {
+					Ping(context.Context, *PingRequest) (*PingResponse, error)
+				}); ok {
+					return svc.Ping(ctx, req)
+				}
+				svc, ok := h.(interface {
+					Ping(context.Context, *connect.Request[PingRequest]) (*connect.Response[PingResponse], error)
+				})
+				if !ok {
+					return nil, rpcruntime.ErrHandlerTypeMismatch
+				}
+				return connectrt.CallUnary(ctx, req, svc.Ping)
+			case rpcruntime.ProtocolGo:
+				svc, ok := h.(interface {
+					Ping(context.Context, *PingRequest) (*PingResponse, error)
+				})
+				if !ok {
+					return nil, rpcruntime.ErrHandlerTypeMismatch
+				}
+				return svc.Ping(ctx, req)
+			case rpcruntime.ProtocolTwirp:
+				svc, ok := h.(interface {
+					Ping(context.Context, *PingRequest) (*PingResponse, error)
+				})
+				if !ok {
+					return nil, rpcruntime.ErrHandlerTypeMismatch
+				}
+				return svc.Ping(ctx, req)
+			default:
+				return nil, rpcruntime.ErrUnknownProtocol
+			}
+		}()
+		if err == nil || !rt.FallbackOnUnimplemented(ctx, err) {
+			return resp, err
 		}
-		return svc.Ping(ctx, req)
-	default:
-		return nil, rpcruntime.ErrUnknownProtocol
+		tried = append(tried, protocol)
+		lastErr = err
 	}
 }
 
 // TestService_PingOpt1 calls cgotest.TestService.PingOpt1 via the registered handler.
 func TestService_PingOpt1(ctx context.Context, req *PingRequestOpt1) (*PingResponse, error) {
-	protocol, h, err := TestService_lookupHandler(ctx)
+	rt := rpcruntime.RuntimeFromContext(ctx)
+	releaseSlot, err := rt.AcquireCallSlot(ctx, TestService_PingOpt1_FullMethod)
 	if err != nil {
 		return nil, err
 	}
-	switch protocol {
-	case rpcruntime.ProtocolGrpc:
-		svc, ok := h.(TestServiceServer)
-		if !ok {
-			return nil, rpcruntime.ErrHandlerTypeMismatch
+	defer releaseSlot()
+	ctx, cancel := rpcruntime.CallContext(ctx)
+	defer cancel()
+
+	var tried []rpcruntime.Protocol
+	var lastErr error
+	for {
+		protocol, h, release, err := testService_acquireHandler(ctx, rt, TestService_PingOpt1_FullMethod, tried...)
+		if err != nil {
+			if lastErr != nil {
+				return nil, lastErr
+			}
+			if remote, ok := rt.RemoteFallback(TestService_ServiceName, err); ok {
+				return rpcruntime.ForwardUnary[PingRequestOpt1, PingResponse](ctx, remote, TestService_PingOpt1_FullMethod, req)
+			}
+			return nil, err
 		}
-		return svc.PingOpt1(ctx, req)
-	case rpcruntime.ProtocolConnectRPC:
-		svc, ok := h.(interface {
-			PingOpt1(context.Context, *PingRequestOpt1) (*PingResponse, error)
-		})
-		if !ok {
-			return nil, rpcruntime.ErrHandlerTypeMismatch
+
+		resp, err := func() (*PingResponse, error) {
+			defer release()
+			switch protocol {
+			case rpcruntime.ProtocolGrpc:
+				svc, ok := h.(TestServiceServer)
+				if !ok {
+					return nil, rpcruntime.ErrHandlerTypeMismatch
+				}
+				return svc.PingOpt1(ctx, req)
+			case rpcruntime.ProtocolConnectRPC:
+				if svc, ok := h.(interface {
+					PingOpt1(context.Context, *PingRequestOpt1) (*PingResponse, error)
+				}); ok {
+					return svc.PingOpt1(ctx, req)
+				}
+				svc, ok := h.(interface {
+					PingOpt1(context.Context, *connect.Request[PingRequestOpt1]) (*connect.Response[PingResponse], error)
+				})
+				if !ok {
+					return nil, rpcruntime.ErrHandlerTypeMismatch
+				}
+				return connectrt.CallUnary(ctx, req, svc.PingOpt1)
+			case rpcruntime.ProtocolGo:
+				svc, ok := h.(interface {
+					PingOpt1(context.Context, *PingRequestOpt1) (*PingResponse, error)
+				})
+				if !ok {
+					return nil, rpcruntime.ErrHandlerTypeMismatch
+				}
+				return svc.PingOpt1(ctx, req)
+			case rpcruntime.ProtocolTwirp:
+				svc, ok := h.(interface {
+					PingOpt1(context.Context, *PingRequestOpt1) (*PingResponse, error)
+				})
+				if !ok {
+					return nil, rpcruntime.ErrHandlerTypeMismatch
+				}
+				return svc.PingOpt1(ctx, req)
+			default:
+				return nil, rpcruntime.ErrUnknownProtocol
+			}
+		}()
+		if err == nil || !rt.FallbackOnUnimplemented(ctx, err) {
+			return resp, err
 		}
-		return svc.PingOpt1(ctx, req)
-	default:
-		return nil, rpcruntime.ErrUnknownProtocol
+		tried = append(tried, protocol)
+		lastErr = err
 	}
 }
 
 // TestService_PingOpt2 calls cgotest.TestService.PingOpt2 via the registered handler.
 func TestService_PingOpt2(ctx context.Context, req *PingRequestOpt2) (*PingResponse, error) {
-	protocol, h, err := TestService_lookupHandler(ctx)
+	rt := rpcruntime.RuntimeFromContext(ctx)
+	releaseSlot, err := rt.AcquireCallSlot(ctx, TestService_PingOpt2_FullMethod)
 	if err != nil {
 		return nil, err
 	}
-	switch protocol {
-	case rpcruntime.ProtocolGrpc:
-		svc, ok := h.(TestServiceServer)
-		if !ok {
-			return nil, rpcruntime.ErrHandlerTypeMismatch
+	defer releaseSlot()
+	ctx, cancel := rpcruntime.CallContext(ctx)
+	defer cancel()
+
+	var tried []rpcruntime.Protocol
+	var lastErr error
+	for {
+		protocol, h, release, err := testService_acquireHandler(ctx, rt, TestService_PingOpt2_FullMethod, tried...)
+		if err != nil {
+			if lastErr != nil {
+				return nil, lastErr
+			}
+			if remote, ok := rt.RemoteFallback(TestService_ServiceName, err); ok {
+				return rpcruntime.ForwardUnary[PingRequestOpt2, PingResponse](ctx, remote, TestService_PingOpt2_FullMethod, req)
+			}
+			return nil, err
 		}
-		return svc.PingOpt2(ctx, req)
-	case rpcruntime.ProtocolConnectRPC:
-		svc, ok := h.(interface {
-			PingOpt2(context.Context, *PingRequestOpt2) (*PingResponse, error)
-		})
-		if !ok {
-			return nil, rpcruntime.ErrHandlerTypeMismatch
+
+		resp, err := func() (*PingResponse, error) {
+			defer release()
+			switch protocol {
+			case rpcruntime.ProtocolGrpc:
+				svc, ok := h.(TestServiceServer)
+				if !ok {
+					return nil, rpcruntime.ErrHandlerTypeMismatch
+				}
+				return svc.PingOpt2(ctx, req)
+			case rpcruntime.ProtocolConnectRPC:
+				if svc, ok := h.(interface {
+					PingOpt2(context.Context, *PingRequestOpt2) (*PingResponse, error)
+				}); ok {
+					return svc.PingOpt2(ctx, req)
+				}
+				svc, ok := h.(interface {
+					PingOpt2(context.Context, *connect.Request[PingRequestOpt2]) (*connect.Response[PingResponse], error)
+				})
+				if !ok {
+					return nil, rpcruntime.ErrHandlerTypeMismatch
+				}
+				return connectrt.CallUnary(ctx, req, svc.PingOpt2)
+			case rpcruntime.ProtocolGo:
+				svc, ok := h.(interface {
+					PingOpt2(context.Context, *PingRequestOpt2) (*PingResponse, error)
+				})
+				if !ok {
+					return nil, rpcruntime.ErrHandlerTypeMismatch
+				}
+				return svc.PingOpt2(ctx, req)
+			case rpcruntime.ProtocolTwirp:
+				svc, ok := h.(interface {
+					PingOpt2(context.Context, *PingRequestOpt2) (*PingResponse, error)
+				})
+				if !ok {
+					return nil, rpcruntime.ErrHandlerTypeMismatch
+				}
+				return svc.PingOpt2(ctx, req)
+			default:
+				return nil, rpcruntime.ErrUnknownProtocol
+			}
+		}()
+		if err == nil || !rt.FallbackOnUnimplemented(ctx, err) {
+			return resp, err
 		}
-		return svc.PingOpt2(ctx, req)
-	default:
-		return nil, rpcruntime.ErrUnknownProtocol
+		tried = append(tried, protocol)
+		lastErr = err
 	}
 }
 
 // TestService_NonFlat calls cgotest.TestService.NonFlat via the registered handler.
 func TestService_NonFlat(ctx context.Context, req *NonFlatRequest) (*PingResponse, error) {
-	protocol, h, err := TestService_lookupHandler(ctx)
+	rt := rpcruntime.RuntimeFromContext(ctx)
+	releaseSlot, err := rt.AcquireCallSlot(ctx, TestService_NonFlat_FullMethod)
 	if err != nil {
 		return nil, err
 	}
-	switch protocol {
-	case rpcruntime.ProtocolGrpc:
-		svc, ok := h.(TestServiceServer)
-		if !ok {
-			return nil, rpcruntime.ErrHandlerTypeMismatch
+	defer releaseSlot()
+	ctx, cancel := rpcruntime.CallContext(ctx)
+	defer cancel()
+
+	var tried []rpcruntime.Protocol
+	var lastErr error
+	for {
+		protocol, h, release, err := testService_acquireHandler(ctx, rt, TestService_NonFlat_FullMethod, tried...)
+		if err != nil {
+			if lastErr != nil {
+				return nil, lastErr
+			}
+			if remote, ok := rt.RemoteFallback(TestService_ServiceName, err); ok {
+				return rpcruntime.ForwardUnary[NonFlatRequest, PingResponse](ctx, remote, TestService_NonFlat_FullMethod, req)
+			}
+			return nil, err
 		}
-		return svc.NonFlat(ctx, req)
-	case rpcruntime.ProtocolConnectRPC:
-		svc, ok := h.(interface {
-			NonFlat(context.Context, *NonFlatRequest) (*PingResponse, error)
-		})
-		if !ok {
-			return nil, rpcruntime.ErrHandlerTypeMismatch
+
+		resp, err := func() (*PingResponse, error) {
+			defer release()
+			switch protocol {
+			case rpcruntime.ProtocolGrpc:
+				svc, ok := h.(TestServiceServer)
+				if !ok {
+					return nil, rpcruntime.ErrHandlerTypeMismatch
+				}
+				return svc.NonFlat(ctx, req)
+			case rpcruntime.ProtocolConnectRPC:
+				if svc, ok := h.(interface {
+					NonFlat(context.Context, *NonFlatRequest) (*PingResponse, error)
+				}); ok {
+					return svc.NonFlat(ctx, req)
+				}
+				svc, ok := h.(interface {
+					NonFlat(context.Context, *connect.Request[NonFlatRequest]) (*connect.Response[PingResponse], error)
+				})
+				if !ok {
+					return nil, rpcruntime.ErrHandlerTypeMismatch
+				}
+				return connectrt.CallUnary(ctx, req, svc.NonFlat)
+			case rpcruntime.ProtocolGo:
+				svc, ok := h.(interface {
+					NonFlat(context.Context, *NonFlatRequest) (*PingResponse, error)
+				})
+				if !ok {
+					return nil, rpcruntime.ErrHandlerTypeMismatch
+				}
+				return svc.NonFlat(ctx, req)
+			case rpcruntime.ProtocolTwirp:
+				svc, ok := h.(interface {
+					NonFlat(context.Context, *NonFlatRequest) (*PingResponse, error)
+				})
+				if !ok {
+					return nil, rpcruntime.ErrHandlerTypeMismatch
+				}
+				return svc.NonFlat(ctx, req)
+			default:
+				return nil, rpcruntime.ErrUnknownProtocol
+			}
+		}()
+		if err == nil || !rt.FallbackOnUnimplemented(ctx, err) {
+			return resp, err
 		}
-		return svc.NonFlat(ctx, req)
-	default:
-		return nil, rpcruntime.ErrUnknownProtocol
+		tried = append(tried, protocol)
+		lastErr = err
 	}
 }
+
+// Register the adaptor functions for dispatch by full method name (see grpcrt.ClientConn).
+func init() {
+	rpcruntime.RegisterMethods(
+		rpcruntime.NewUnaryMethodDesc(TestService_Ping_FullMethod, TestService_Ping),
+		rpcruntime.NewUnaryMethodDesc(TestService_PingOpt1_FullMethod, TestService_PingOpt1),
+		rpcruntime.NewUnaryMethodDesc(TestService_PingOpt2_FullMethod, TestService_PingOpt2),
+		rpcruntime.NewUnaryMethodDesc(TestService_NonFlat_FullMethod, TestService_NonFlat),
+	)
+}
@@ -1,7 +1,7 @@
 #!/usr/bin/env bash
 # Run adaptor tests for specified protocol
 # Usage: adaptor-test.sh <protocol>
-# Protocols: grpc, connect, mix, connect_suffix
+# Protocols: grpc, connect, mix, connect_suffix, goonly
 
 set -euo pipefail
 
@@ -12,15 +12,15 @@ PROTOCOL="${1:-}"
 
 if [[ -z "$PROTOCOL" ]]; then
     echo "Usage: $0 <protocol>"
-    echo "Protocols: grpc, connect, mix, connect_suffix"
+    echo "Protocols: grpc, connect, mix, connect_suffix, goonly"
     exit 1
 fi
 
 case "$PROTOCOL" in
-    grpc|connect|mix|connect_suffix) ;;
+    grpc|connect|mix|connect_suffix|goonly) ;;
     *)
         echo "Invalid protocol: $PROTOCOL"
-        echo "Protocols: grpc, connect, mix, connect_suffix"
+        echo "Protocols: grpc, connect, mix, connect_suffix, goonly"
         exit 1
         ;;
 esac
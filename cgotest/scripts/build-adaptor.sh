@@ -1,7 +1,7 @@
 #!/usr/bin/env bash
 # Build adaptor code for specified protocol
 # Usage: build-adaptor.sh <protocol>
-# Protocols: grpc, connect, mix, connect_suffix
+# Protocols: grpc, connect, mix, connect_suffix, goonly
 #
 # NOTE: This script assumes protoc plugins are already installed.
 # Use 'task install-local-plugins' or 'scripts/install-local-plugins.sh' first.
@@ -15,15 +15,15 @@ PROTOCOL="${1:-}"
 
 if [[ -z "$PROTOCOL" ]]; then
     echo "Usage: $0 <protocol>"
-    echo "Protocols: grpc, connect, mix, connect_suffix"
+    echo "Protocols: grpc, connect, mix, connect_suffix, goonly"
     exit 1
 fi
 
 case "$PROTOCOL" in
-    grpc|connect|mix|connect_suffix) ;;
+    grpc|connect|mix|connect_suffix|goonly) ;;
     *)
         echo "Invalid protocol: $PROTOCOL"
-        echo "Protocols: grpc, connect, mix, connect_suffix"
+        echo "Protocols: grpc, connect, mix, connect_suffix, goonly"
         exit 1
         ;;
 esac
@@ -47,13 +47,17 @@ case "$PROTOCOL" in
         GO_PKG="Munary.proto=github.com/ygrpc/rpccgo/cgotest/connect;cgotest_connect,Mstream.proto=github.com/ygrpc/rpccgo/cgotest/connect;cgotest_connect"
         ;;
     mix)
-        PROTOCOL_OPT="grpc|connectrpc"
+        PROTOCOL_OPT="grpc|connectrpc|go|twirp"
         GO_PKG="Munary.proto=github.com/ygrpc/rpccgo/cgotest/mix;cgotest_mix,Mstream.proto=github.com/ygrpc/rpccgo/cgotest/mix;cgotest_mix"
         ;;
     connect_suffix)
         PROTOCOL_OPT="connectrpc"
         GO_PKG="Munary.proto=github.com/ygrpc/rpccgo/cgotest/connect_suffix;cgotest_connect_suffix,Mstream.proto=github.com/ygrpc/rpccgo/cgotest/connect_suffix;cgotest_connect_suffix"
         ;;
+    goonly)
+        PROTOCOL_OPT="go"
+        GO_PKG="Munary.proto=github.com/ygrpc/rpccgo/cgotest/goonly;cgotest_goonly,Mstream.proto=github.com/ygrpc/rpccgo/cgotest/goonly;cgotest_goonly"
+        ;;
 esac
 
 mkdir -p "./$PROTOCOL"
@@ -105,6 +109,15 @@ case "$PROTOCOL" in
             --rpc-cgo-adaptor_opt=paths=source_relative,protocol=connectrpc,"${GO_PKG}" \
             ./proto/unary.proto ./proto/stream.proto
         ;;
+    goonly)
+        # Only the Go handler protocol: neither grpc-go nor connect-go code is
+        # generated or linked.
+        protoc -Iproto -I../proto \
+            --go_out=./goonly --go_opt=paths=source_relative,"${GO_PKG}" \
+            --rpc-cgo-adaptor_out=./goonly \
+            --rpc-cgo-adaptor_opt=paths=source_relative,"protocol=${PROTOCOL_OPT}","${GO_PKG}" \
+            ./proto/unary.proto ./proto/stream.proto
+        ;;
 esac
 
 echo "✓ Adaptor code generated for $PROTOCOL"
@@ -11,7 +11,7 @@ cd "$CGOTEST_DIR"
 
 echo "Cleaning build artifacts..."
 
-for protocol in grpc connect mix connect_suffix; do
+for protocol in grpc connect mix connect_suffix goonly; do
     echo "  Cleaning $protocol..."
     find "./$protocol" -mindepth 1 -maxdepth 1 -type f ! -name 'adaptor_test.go' -delete 2>/dev/null || true
     find "./$protocol" -mindepth 1 -maxdepth 1 -type d -exec rm -rf '{}' + 2>/dev/null || true
@@ -10,9 +10,11 @@ import (
 const (
 	contextPackage = protogen.GoImportPath("context")
 	rpcRuntimePkg  = protogen.GoImportPath("github.com/ygrpc/rpccgo/rpcruntime")
+	connectRtPkg   = protogen.GoImportPath("github.com/ygrpc/rpccgo/rpcruntime/connectrt")
 	grpcPackage    = protogen.GoImportPath("google.golang.org/grpc")
 	connectPackage = protogen.GoImportPath("connectrpc.com/connect")
 	protoPackage   = protogen.GoImportPath("google.golang.org/protobuf/proto")
+	slicesPackage  = protogen.GoImportPath("slices")
 )
 
 func generateFile(gen *protogen.Plugin, file *protogen.File, opts GeneratorOptions) *protogen.GeneratedFile {
@@ -32,10 +34,48 @@ func generateFile(gen *protogen.Plugin, file *protogen.File, opts GeneratorOptio
 	for _, service := range file.Services {
 		generateService(g, file, service, opts)
 	}
+	generateMethodTable(g, file)
 
 	return g
 }
 
+// generateMethodTable registers every method of file with
+// rpcruntime.RegisterMethods so callers such as grpcrt.ClientConn can
+// dispatch to the adaptor functions by full method name.
+func generateMethodTable(g *protogen.GeneratedFile, file *protogen.File) {
+	if len(file.Services) == 0 {
+		return
+	}
+	g.P("// Register the adaptor functions for dispatch by full method name (see grpcrt.ClientConn).")
+	g.P("func init() {")
+	g.P(g.QualifiedGoIdent(rpcRuntimePkg.Ident("RegisterMethods")), "(")
+	for _, service := range file.Services {
+		for _, method := range service.Methods {
+			prefix := service.GoName + "_" + method.GoName
+			fullMethod := prefix + "_FullMethod"
+			switch {
+			case !method.Desc.IsStreamingClient() && !method.Desc.IsStreamingServer():
+				g.P(g.QualifiedGoIdent(rpcRuntimePkg.Ident("NewUnaryMethodDesc")), "(", fullMethod, ", ", prefix, "),")
+			case method.Desc.IsStreamingClient() && !method.Desc.IsStreamingServer():
+				g.P(
+					g.QualifiedGoIdent(rpcRuntimePkg.Ident("NewClientStreamMethodDesc")),
+					"(", fullMethod, ", ", prefix, "Start, ", prefix, "Send, ", prefix, "Finish),",
+				)
+			case !method.Desc.IsStreamingClient() && method.Desc.IsStreamingServer():
+				g.P(g.QualifiedGoIdent(rpcRuntimePkg.Ident("NewServerStreamMethodDesc")), "(", fullMethod, ", ", prefix, "),")
+			default:
+				g.P(
+					g.QualifiedGoIdent(rpcRuntimePkg.Ident("NewBidiStreamMethodDesc")),
+					"(", fullMethod, ", ", prefix, "Start, ", prefix, "Send, ", prefix, "CloseSend),",
+				)
+			}
+		}
+	}
+	g.P(")")
+	g.P("}")
+	g.P()
+}
+
 func generateService(
 	g *protogen.GeneratedFile,
 	file *protogen.File,
@@ -56,9 +96,10 @@ func generateService(
 	g.P()
 
 	generateServiceLookupHelper(g, file, service, opts)
+	generateServiceRegisterHelpers(g, service, opts)
 
 	// Generate stream adaptor types for gRPC streaming methods.
-	// Connect streaming uses rpcruntime helpers (NewClientStream, etc.) instead of adaptor types.
+	// Connect streaming uses connectrt helpers (ServeClientStream, etc.) instead of adaptor types.
 	if supportsProtocol(opts.Protocols, ProtocolOptionGrpc) {
 		for _, method := range service.Methods {
 			isClientStreaming := method.Desc.IsStreamingClient()
@@ -136,67 +177,104 @@ func generateUnaryDispatch(
 	opts GeneratorOptions,
 ) {
 	lookupFuncName := service.GoName + "_lookupHandler"
-	grpcServerIface := service.GoName + "Server"
 
 	if len(opts.Protocols) == 1 {
-		g.P("    _, h, err := ", lookupFuncName, "(ctx)")
+		g.P("    _, h, release, err := ", lookupFuncName, "(ctx, ", service.GoName, "_", method.GoName, "_FullMethod)")
 		g.P("    if err != nil {")
+		generateRemoteFallback(g, service, g.QualifiedGoIdent(rpcRuntimePkg.Ident("RuntimeFromContext"))+"(ctx)",
+			"return "+forwardCall(g, service, method, "ForwardUnary", true, "req"))
 		g.P("        return nil, err")
 		g.P("    }")
-		if opts.Protocols[0] == ProtocolOptionGrpc {
-			g.P("    svc, ok := h.(", grpcServerIface, ")")
-			g.P("    if !ok {")
-			g.P("        return nil, ", g.QualifiedGoIdent(rpcRuntimePkg.Ident("ErrHandlerTypeMismatch")))
-			g.P("    }")
-			g.P("    return svc.", method.GoName, "(ctx, req)")
-		} else {
-			connectHandlerIface := connectHandlerAssertionType(g, service, method, opts)
-			g.P("    svc, ok := h.(", connectHandlerIface, ")")
-			g.P("    if !ok {")
-			g.P("        return nil, ", g.QualifiedGoIdent(rpcRuntimePkg.Ident("ErrHandlerTypeMismatch")))
-			g.P("    }")
-			g.P("    return svc.", method.GoName, "(ctx, req)")
-		}
+		g.P("    defer release()")
+		generateCallContext(g)
+		generateUnaryHandlerCall(g, service, method, opts.Protocols[0])
 		return
 	}
 
-	connectHandlerIface := connectHandlerAssertionType(g, service, method, opts)
+	acquireFuncName := unexport(service.GoName) + "_acquireHandler"
 
-	g.P("    protocol, h, err := ", lookupFuncName, "(ctx)")
+	// Multi-protocol unary calls may be retried on the next protocol when the
+	// handler returns Unimplemented; the concurrency slot is held across retries.
+	g.P("    rt := ", g.QualifiedGoIdent(rpcRuntimePkg.Ident("RuntimeFromContext")), "(ctx)")
+	g.P("    releaseSlot, err := rt.AcquireCallSlot(ctx, ", service.GoName, "_", method.GoName, "_FullMethod)")
 	g.P("    if err != nil {")
 	g.P("        return nil, err")
 	g.P("    }")
-	g.P("    switch protocol {")
-
-	supportsGrpc := supportsProtocol(opts.Protocols, ProtocolOptionGrpc)
-	supportsConnect := supportsProtocol(opts.Protocols, ProtocolOptionConnectRPC)
-	switch {
-	case supportsGrpc:
-		g.P("    case ", g.QualifiedGoIdent(rpcRuntimePkg.Ident("ProtocolGrpc")), ":")
-		g.P("        svc, ok := h.(", grpcServerIface, ")")
-		g.P("        if !ok {")
-		g.P("            return nil, ", g.QualifiedGoIdent(rpcRuntimePkg.Ident("ErrHandlerTypeMismatch")))
-		g.P("        }")
-		g.P("        return svc.", method.GoName, "(ctx, req)")
-	default:
-		// No-op: grpc not enabled
+	g.P("    defer releaseSlot()")
+	generateCallContext(g)
+	g.P()
+	g.P("    var tried []", g.QualifiedGoIdent(rpcRuntimePkg.Ident("Protocol")))
+	g.P("    var lastErr error")
+	g.P("    for {")
+	g.P(
+		"        protocol, h, release, err := ",
+		acquireFuncName,
+		"(ctx, rt, ",
+		service.GoName,
+		"_",
+		method.GoName,
+		"_FullMethod, tried...)",
+	)
+	g.P("        if err != nil {")
+	g.P("            if lastErr != nil {")
+	g.P("                return nil, lastErr")
+	g.P("            }")
+	generateRemoteFallback(g, service, "rt", "return "+forwardCall(g, service, method, "ForwardUnary", true, "req"))
+	g.P("            return nil, err")
+	g.P("        }")
+	g.P()
+	g.P("        resp, err := func() (*", g.QualifiedGoIdent(method.Output.GoIdent), ", error) {")
+	g.P("            defer release()")
+	g.P("            switch protocol {")
+	for _, p := range opts.Protocols {
+		g.P("            case ", protocolIdent(g, p), ":")
+		generateUnaryHandlerCall(g, service, method, p)
 	}
+	g.P("            default:")
+	g.P("                return nil, ", g.QualifiedGoIdent(rpcRuntimePkg.Ident("ErrUnknownProtocol")))
+	g.P("            }")
+	g.P("        }()")
+	g.P("        if err == nil || !rt.FallbackOnUnimplemented(ctx, err) {")
+	g.P("            return resp, err")
+	g.P("        }")
+	g.P("        tried = append(tried, protocol)")
+	g.P("        lastErr = err")
+	g.P("    }")
+}
 
-	switch {
-	case supportsConnect:
-		g.P("    case ", g.QualifiedGoIdent(rpcRuntimePkg.Ident("ProtocolConnectRPC")), ":")
-		g.P("        svc, ok := h.(", connectHandlerIface, ")")
-		g.P("        if !ok {")
-		g.P("            return nil, ", g.QualifiedGoIdent(rpcRuntimePkg.Ident("ErrHandlerTypeMismatch")))
-		g.P("        }")
+// generateCallContext emits code binding ctx to the Runtime of the call, so a
+// unary handler is cancelled when Shutdown gives up waiting for it.
+func generateCallContext(g *protogen.GeneratedFile) {
+	g.P("    ctx, cancel := ", g.QualifiedGoIdent(rpcRuntimePkg.Ident("CallContext")), "(ctx)")
+	g.P("    defer cancel()")
+}
+
+// generateUnaryHandlerCall asserts the acquired handler h for protocol p and
+// returns the result of calling method on it. Connect handlers may implement
+// either the simple or the generic API.
+func generateUnaryHandlerCall(
+	g *protogen.GeneratedFile,
+	service *protogen.Service,
+	method *protogen.Method,
+	p ProtocolOption,
+) {
+	mismatch := g.QualifiedGoIdent(rpcRuntimePkg.Ident("ErrHandlerTypeMismatch"))
+	if p == ProtocolOptionConnectRPC {
+		g.P("    if svc, ok := h.(", handlerAssertionType(g, service, method, p), "); ok {")
 		g.P("        return svc.", method.GoName, "(ctx, req)")
-	default:
-		// No-op: connectrpc not enabled
+		g.P("    }")
+		g.P("    svc, ok := h.(interface{ ", connectGenericMethodSignature(g, method), " })")
+		g.P("    if !ok {")
+		g.P("        return nil, ", mismatch)
+		g.P("    }")
+		g.P("    return ", g.QualifiedGoIdent(connectRtPkg.Ident("CallUnary")), "(ctx, req, svc.", method.GoName, ")")
+		return
 	}
-
-	g.P("    default:")
-	g.P("        return nil, ", g.QualifiedGoIdent(rpcRuntimePkg.Ident("ErrUnknownProtocol")))
+	g.P("    svc, ok := h.(", handlerAssertionType(g, service, method, p), ")")
+	g.P("    if !ok {")
+	g.P("        return nil, ", mismatch)
 	g.P("    }")
+	g.P("    return svc.", method.GoName, "(ctx, req)")
 }
 
 func generateServiceLookupHelper(
@@ -206,9 +284,52 @@ func generateServiceLookupHelper(
 	opts GeneratorOptions,
 ) {
 	lookupFuncName := service.GoName + "_lookupHandler"
+	acquireFuncName := unexport(service.GoName) + "_acquireHandler"
 	serviceConstName := service.GoName + "_ServiceName"
+	runtimeType := g.QualifiedGoIdent(rpcRuntimePkg.Ident("Runtime"))
+
+	g.P("// ", lookupFuncName, " admits a call of fullMethod under the configured concurrency")
+	g.P("// limits, selects a protocol and acquires the registered handler.")
+	g.P("// The returned release func must be called once the call has finished.")
+	g.P("//")
+	g.P("// Handlers are looked up in rpcruntime.RuntimeFromContext(ctx).")
+	lookupSkipParam := ""
+	if len(opts.Protocols) > 1 {
+		lookupSkipParam = ", skip ..." + g.QualifiedGoIdent(rpcRuntimePkg.Ident("Protocol"))
+	}
+	g.P(
+		"func ",
+		lookupFuncName,
+		"(ctx ",
+		g.QualifiedGoIdent(contextPackage.Ident("Context")),
+		", fullMethod string",
+		lookupSkipParam,
+		") (",
+		g.QualifiedGoIdent(rpcRuntimePkg.Ident("Protocol")),
+		", any, func(), error) {",
+	)
+	g.P("    rt := ", g.QualifiedGoIdent(rpcRuntimePkg.Ident("RuntimeFromContext")), "(ctx)")
+	g.P("    releaseSlot, err := rt.AcquireCallSlot(ctx, fullMethod)")
+	g.P("    if err != nil {")
+	g.P("        return \"\", nil, nil, err")
+	g.P("    }")
+	if len(opts.Protocols) > 1 {
+		g.P("    protocol, h, release, err := ", acquireFuncName, "(ctx, rt, fullMethod, skip...)")
+	} else {
+		g.P("    protocol, h, release, err := ", acquireFuncName, "(ctx, rt)")
+	}
+	g.P("    if err != nil {")
+	g.P("        releaseSlot()")
+	g.P("        return protocol, nil, nil, err")
+	g.P("    }")
+	g.P("    return protocol, h, func() {")
+	g.P("        release()")
+	g.P("        releaseSlot()")
+	g.P("    }, nil")
+	g.P("}")
+	g.P()
 
-	g.P("// ", lookupFuncName, " selects a protocol and looks up the registered handler.")
+	g.P("// ", acquireFuncName, " selects a protocol and acquires the registered handler from rt.")
 	g.P("//")
 	g.P("// Selection rules:")
 	if len(opts.Protocols) == 1 {
@@ -219,115 +340,431 @@ func generateServiceLookupHelper(
 	} else {
 		g.P("// - If ctx explicitly carries a protocol, only that protocol is attempted (no fallback).")
 		g.P(
-			"// - Otherwise, protocols are tried in the configured order: ",
+			"// - Otherwise, protocols are tried in the order from rt.ProtocolOrder(fullMethod), defaulting to: ",
 			strings.Join(protocolOptionStrings(opts.Protocols), ","),
 		)
+		g.P("// - Protocols in skip are not tried; an explicit protocol in skip fails with ErrUnknownProtocol.")
+	}
+	skipParam := ""
+	if len(opts.Protocols) > 1 {
+		skipParam = ", fullMethod string, skip ..." + g.QualifiedGoIdent(rpcRuntimePkg.Ident("Protocol"))
 	}
 	g.P(
 		"func ",
-		lookupFuncName,
+		acquireFuncName,
 		"(ctx ",
 		g.QualifiedGoIdent(contextPackage.Ident("Context")),
+		", rt *",
+		runtimeType,
+		skipParam,
 		") (",
 		g.QualifiedGoIdent(rpcRuntimePkg.Ident("Protocol")),
-		", any, error) {",
+		", any, func(), error) {",
 	)
 	if len(opts.Protocols) == 1 {
 		only := opts.Protocols[0]
-		if only == ProtocolOptionGrpc {
-			g.P(
-				"    protocol, hasProtocol := ",
-				g.QualifiedGoIdent(rpcRuntimePkg.Ident("ProtocolFromContext")),
-				"(ctx)",
-			)
-			g.P("    if hasProtocol && protocol != ", g.QualifiedGoIdent(rpcRuntimePkg.Ident("ProtocolGrpc")), " {")
-			g.P("        return protocol, nil, ", g.QualifiedGoIdent(rpcRuntimePkg.Ident("ErrUnknownProtocol")))
-			g.P("    }")
-			g.P(
-				"    h, ok := ",
-				g.QualifiedGoIdent(rpcRuntimePkg.Ident("LookupGrpcHandler")),
-				"(",
-				serviceConstName,
-				")",
-			)
-			g.P("    if !ok {")
-			g.P("        return \"\", nil, ", g.QualifiedGoIdent(rpcRuntimePkg.Ident("ErrServiceNotRegistered")))
-			g.P("    }")
-			g.P("    return ", g.QualifiedGoIdent(rpcRuntimePkg.Ident("ProtocolGrpc")), ", h, nil")
-		} else {
-			g.P("    protocol, hasProtocol := ", g.QualifiedGoIdent(rpcRuntimePkg.Ident("ProtocolFromContext")), "(ctx)")
-			g.P("    if hasProtocol && protocol != ", g.QualifiedGoIdent(rpcRuntimePkg.Ident("ProtocolConnectRPC")), " {")
-			g.P("        return protocol, nil, ", g.QualifiedGoIdent(rpcRuntimePkg.Ident("ErrUnknownProtocol")))
-			g.P("    }")
-			g.P("    h, ok := ", g.QualifiedGoIdent(rpcRuntimePkg.Ident("LookupConnectHandler")), "(", serviceConstName, ")")
-			g.P("    if !ok {")
-			g.P("        return \"\", nil, ", g.QualifiedGoIdent(rpcRuntimePkg.Ident("ErrServiceNotRegistered")))
-			g.P("    }")
-			g.P("    return ", g.QualifiedGoIdent(rpcRuntimePkg.Ident("ProtocolConnectRPC")), ", h, nil")
-		}
+		g.P("    protocol, hasProtocol := ", g.QualifiedGoIdent(rpcRuntimePkg.Ident("ProtocolFromContext")), "(ctx)")
+		g.P("    if hasProtocol && protocol != ", protocolIdent(g, only), " {")
+		g.P("        return protocol, nil, nil, ", g.QualifiedGoIdent(rpcRuntimePkg.Ident("ErrUnknownProtocol")))
+		g.P("    }")
+		g.P("    h, release, err := rt.AcquireHandler(", protocolIdent(g, only), ", ", serviceConstName, ")")
+		g.P("    if err != nil {")
+		g.P("        return \"\", nil, nil, err")
+		g.P("    }")
+		g.P("    return ", protocolIdent(g, only), ", h, release, nil")
 		g.P("}")
 		g.P()
 		return
-	} else {
-		g.P("    protocol, hasProtocol := ", g.QualifiedGoIdent(rpcRuntimePkg.Ident("ProtocolFromContext")), "(ctx)")
-		g.P("    if hasProtocol {")
-		g.P("        switch protocol {")
-		if supportsProtocol(opts.Protocols, ProtocolOptionGrpc) {
-			g.P("        case ", g.QualifiedGoIdent(rpcRuntimePkg.Ident("ProtocolGrpc")), ":")
-			g.P("            h, ok := ", g.QualifiedGoIdent(rpcRuntimePkg.Ident("LookupGrpcHandler")), "(", serviceConstName, ")")
-			g.P("            if !ok {")
-			g.P("                return protocol, nil, ", g.QualifiedGoIdent(rpcRuntimePkg.Ident("ErrServiceNotRegistered")))
-			g.P("            }")
-			g.P("            return protocol, h, nil")
+	}
+
+	g.P("    protocol, hasProtocol := ", g.QualifiedGoIdent(rpcRuntimePkg.Ident("ProtocolFromContext")), "(ctx)")
+	g.P("    if hasProtocol {")
+	g.P("        if ", g.QualifiedGoIdent(slicesPackage.Ident("Contains")), "(skip, protocol) {")
+	g.P("            return protocol, nil, nil, ", g.QualifiedGoIdent(rpcRuntimePkg.Ident("ErrUnknownProtocol")))
+	g.P("        }")
+	g.P("        switch protocol {")
+	for _, p := range opts.Protocols {
+		g.P("        case ", protocolIdent(g, p), ":")
+		g.P("            h, release, err := rt.AcquireHandler(", protocolIdent(g, p), ", ", serviceConstName, ")")
+		g.P("            if err != nil {")
+		g.P("                return protocol, nil, nil, err")
+		g.P("            }")
+		g.P("            return protocol, h, release, nil")
+	}
+	g.P("        default:")
+	g.P("            return protocol, nil, nil, ", g.QualifiedGoIdent(rpcRuntimePkg.Ident("ErrUnknownProtocol")))
+	g.P("        }")
+	g.P("    }")
+	g.P()
+	g.P("    // Fallback: try protocols in the preferred order, skipping those without a handler.")
+	generated := make([]string, 0, len(opts.Protocols))
+	for _, p := range opts.Protocols {
+		generated = append(generated, protocolIdent(g, p))
+	}
+	g.P("    for _, p := range rt.ProtocolOrder(fullMethod, ", strings.Join(generated, ", "), ") {")
+	g.P("        if ", g.QualifiedGoIdent(slicesPackage.Ident("Contains")), "(skip, p) {")
+	g.P("            continue")
+	g.P("        }")
+	g.P("        switch p {")
+	for _, p := range opts.Protocols {
+		g.P("        case ", protocolIdent(g, p), ":")
+		g.P(
+			"            if h, release, err := rt.AcquireHandler(",
+			protocolIdent(g, p),
+			", ",
+			serviceConstName,
+			"); err != ",
+			g.QualifiedGoIdent(rpcRuntimePkg.Ident("ErrServiceNotRegistered")),
+			" {",
+		)
+		g.P("                return p, h, release, err")
+		g.P("            }")
+	}
+	g.P("        }")
+	g.P("    }")
+	g.P("    return \"\", nil, nil, ", g.QualifiedGoIdent(rpcRuntimePkg.Ident("ErrServiceNotRegistered")))
+	g.P("}")
+	g.P()
+}
+
+// generateRemoteFallback emits the statements forwarding a call whose handler
+// lookup failed with err to the remote registered for service, if any. rt is
+// the expression of the Runtime the lookup ran in; forward is the statement
+// making the call with the *rpcruntime.Remote variable remote.
+func generateRemoteFallback(g *protogen.GeneratedFile, service *protogen.Service, rt, forward string) {
+	g.P("        if remote, ok := ", rt, ".RemoteFallback(", service.GoName, "_ServiceName, err); ok {")
+	g.P("            ", forward)
+	g.P("        }")
+}
+
+// forwardCall returns the expression calling the rpcruntime forwarding
+// function fn for method with args following the full method name. Type
+// arguments are spelled out unless they can be inferred from args.
+func forwardCall(
+	g *protogen.GeneratedFile,
+	service *protogen.Service,
+	method *protogen.Method,
+	fn string,
+	explicitTypes bool,
+	args string,
+) string {
+	call := g.QualifiedGoIdent(rpcRuntimePkg.Ident(fn))
+	if explicitTypes {
+		call += "[" + g.QualifiedGoIdent(method.Input.GoIdent) + ", " + g.QualifiedGoIdent(method.Output.GoIdent) + "]"
+	}
+	call += "(ctx, remote, " + service.GoName + "_" + method.GoName + "_FullMethod"
+	if args != "" {
+		call += ", " + args
+	}
+	return call + ")"
+}
+
+// protocolIdent returns the qualified rpcruntime.Protocol constant for p.
+func protocolIdent(g *protogen.GeneratedFile, p ProtocolOption) string {
+	switch p {
+	case ProtocolOptionGrpc:
+		return g.QualifiedGoIdent(rpcRuntimePkg.Ident("ProtocolGrpc"))
+	case ProtocolOptionGo:
+		return g.QualifiedGoIdent(rpcRuntimePkg.Ident("ProtocolGo"))
+	case ProtocolOptionTwirp:
+		return g.QualifiedGoIdent(rpcRuntimePkg.Ident("ProtocolTwirp"))
+	default:
+		return g.QualifiedGoIdent(rpcRuntimePkg.Ident("ProtocolConnectRPC"))
+	}
+}
+
+// handlerAssertionType returns the type the handler registered for p is
+// asserted to before method is called on it.
+func handlerAssertionType(
+	g *protogen.GeneratedFile,
+	service *protogen.Service,
+	method *protogen.Method,
+	p ProtocolOption,
+) string {
+	switch p {
+	case ProtocolOptionGrpc:
+		return service.GoName + "Server"
+	case ProtocolOptionGo, ProtocolOptionTwirp:
+		// Twirp only has unary methods, whose signature matches the Go one.
+		return "interface{ " + goHandlerMethodSignature(g, method) + " }"
+	default:
+		return "interface{ " + connectHandlerMethodSignature(g, method) + " }"
+	}
+}
+
+// streamingProtocols returns the protocols in protocols that support
+// streaming methods; Twirp does not.
+func streamingProtocols(protocols []ProtocolOption) []ProtocolOption {
+	out := make([]ProtocolOption, 0, len(protocols))
+	for _, p := range protocols {
+		if p != ProtocolOptionTwirp {
+			out = append(out, p)
+		}
+	}
+	return out
+}
+
+// streamLookupSkipArgs returns the extra arguments streaming adaptors pass to
+// the lookup helper so protocols without streaming support are never selected.
+func streamLookupSkipArgs(g *protogen.GeneratedFile, opts GeneratorOptions) string {
+	if len(opts.Protocols) == 1 || !supportsProtocol(opts.Protocols, ProtocolOptionTwirp) {
+		return ""
+	}
+	return ", " + protocolIdent(g, ProtocolOptionTwirp)
+}
+
+// streamHandlerVar returns the name of the variable holding the handler
+// asserted for p in streaming adaptors.
+func streamHandlerVar(p ProtocolOption, protocols []ProtocolOption) string {
+	if len(protocols) == 1 {
+		return "svc"
+	}
+	switch p {
+	case ProtocolOptionGrpc:
+		return "grpcSvc"
+	case ProtocolOptionGo:
+		return "goSvc"
+	default:
+		return "connectSvc"
+	}
+}
+
+// generateStreamHandlerAssertion asserts the acquired handler h to the type
+// expected by the selected protocol. fail emits the statements run on failure
+// for the given error expression.
+func generateStreamHandlerAssertion(
+	g *protogen.GeneratedFile,
+	service *protogen.Service,
+	method *protogen.Method,
+	protocols []ProtocolOption,
+	fail func(errExpr string),
+) {
+	mismatch := g.QualifiedGoIdent(rpcRuntimePkg.Ident("ErrHandlerTypeMismatch"))
+	if len(protocols) == 1 {
+		if usesConnectGenericAdapter(protocols[0], method) {
+			g.P("    var svc ", streamHandlerVarType(g, service, method, protocols[0]))
+			generateConnectStreamHandlerAssertion(g, service, method, "svc", fail)
+			g.P()
+			return
 		}
-		if supportsProtocol(opts.Protocols, ProtocolOptionConnectRPC) {
-			g.P("        case ", g.QualifiedGoIdent(rpcRuntimePkg.Ident("ProtocolConnectRPC")), ":")
-			g.P("            h, ok := ", g.QualifiedGoIdent(rpcRuntimePkg.Ident("LookupConnectHandler")), "(", serviceConstName, ")")
-			g.P("            if !ok {")
-			g.P("                return protocol, nil, ", g.QualifiedGoIdent(rpcRuntimePkg.Ident("ErrServiceNotRegistered")))
-			g.P("            }")
-			g.P("            return protocol, h, nil")
+		g.P("    svc, ok := h.(", handlerAssertionType(g, service, method, protocols[0]), ")")
+		g.P("    if !ok {")
+		fail(mismatch)
+		g.P("    }")
+		g.P()
+		return
+	}
+
+	for _, p := range protocols {
+		g.P("    var ", streamHandlerVar(p, protocols), " ", streamHandlerVarType(g, service, method, p))
+	}
+	g.P("    switch protocol {")
+	for _, p := range protocols {
+		g.P("    case ", protocolIdent(g, p), ":")
+		if usesConnectGenericAdapter(p, method) {
+			generateConnectStreamHandlerAssertion(g, service, method, streamHandlerVar(p, protocols), fail)
+			continue
 		}
-		g.P("        default:")
-		g.P("            return protocol, nil, ", g.QualifiedGoIdent(rpcRuntimePkg.Ident("ErrUnknownProtocol")))
+		g.P("        svc, ok := h.(", handlerAssertionType(g, service, method, p), ")")
+		g.P("        if !ok {")
+		fail(mismatch)
 		g.P("        }")
+		g.P("        ", streamHandlerVar(p, protocols), " = svc")
 	}
+	g.P("    default:")
+	fail(g.QualifiedGoIdent(rpcRuntimePkg.Ident("ErrUnknownProtocol")))
 	g.P("    }")
 	g.P()
-	{
-		g.P("    // Fallback: try protocols in configured order.")
-		for _, p := range opts.Protocols {
-			if p == ProtocolOptionGrpc {
-				g.P(
-					"    if h, ok := ",
-					g.QualifiedGoIdent(rpcRuntimePkg.Ident("LookupGrpcHandler")),
-					"(",
-					serviceConstName,
-					"); ok {",
-				)
-				g.P("        return ", g.QualifiedGoIdent(rpcRuntimePkg.Ident("ProtocolGrpc")), ", h, nil")
-				g.P("    }")
-			} else if p == ProtocolOptionConnectRPC {
-				g.P("    if h, ok := ", g.QualifiedGoIdent(rpcRuntimePkg.Ident("LookupConnectHandler")), "(", serviceConstName, "); ok {")
-				g.P("        return ", g.QualifiedGoIdent(rpcRuntimePkg.Ident("ProtocolConnectRPC")), ", h, nil")
-				g.P("    }")
-			}
-		}
-		g.P("    return \"\", nil, ", g.QualifiedGoIdent(rpcRuntimePkg.Ident("ErrServiceNotRegistered")))
+}
+
+// usesConnectGenericAdapter reports whether the streaming method needs a
+// connectrt adapter to call a Connect generic-API handler. Bidi streams have
+// the same signature in both APIs.
+func usesConnectGenericAdapter(p ProtocolOption, method *protogen.Method) bool {
+	return p == ProtocolOptionConnectRPC && method.Desc.IsStreamingClient() != method.Desc.IsStreamingServer()
+}
+
+// streamHandlerVarType returns the type of the variable holding the handler
+// asserted for p. Connect client and server streams hold the simple-API method
+// value so generic-API handlers can be adapted to it.
+func streamHandlerVarType(
+	g *protogen.GeneratedFile,
+	service *protogen.Service,
+	method *protogen.Method,
+	p ProtocolOption,
+) string {
+	if usesConnectGenericAdapter(p, method) {
+		return "func" + strings.TrimPrefix(connectHandlerMethodSignature(g, method), method.GoName)
 	}
-	g.P("}")
-	g.P()
+	return handlerAssertionType(g, service, method, p)
+}
+
+// streamHandlerCall returns the expression calling method on the handler
+// variable svc asserted for p.
+func streamHandlerCall(p ProtocolOption, method *protogen.Method, svc string) string {
+	if usesConnectGenericAdapter(p, method) {
+		return svc
+	}
+	return svc + "." + method.GoName
 }
 
-func connectHandlerAssertionType(
+// generateConnectStreamHandlerAssertion assigns the simple-API method value of
+// h to v, adapting a generic-API handler when needed.
+func generateConnectStreamHandlerAssertion(
 	g *protogen.GeneratedFile,
 	service *protogen.Service,
 	method *protogen.Method,
+	v string,
+	fail func(errExpr string),
+) {
+	adapter := "ClientStreamHandler"
+	if method.Desc.IsStreamingServer() {
+		adapter = "ServerStreamHandler"
+	}
+	g.P("    if simple, ok := h.(", handlerAssertionType(g, service, method, ProtocolOptionConnectRPC), "); ok {")
+	g.P("        ", v, " = simple.", method.GoName)
+	g.P("    } else if generic, ok := h.(interface{ ", connectGenericMethodSignature(g, method), " }); ok {")
+	g.P("        ", v, " = ", g.QualifiedGoIdent(connectRtPkg.Ident(adapter)), "(generic.", method.GoName, ")")
+	g.P("    } else {")
+	fail(g.QualifiedGoIdent(rpcRuntimePkg.Ident("ErrHandlerTypeMismatch")))
+	g.P("    }")
+}
+
+// generateStreamProtocolSwitch emits body once per generated protocol,
+// switching on the selected protocol when there is more than one.
+func generateStreamProtocolSwitch(
+	g *protogen.GeneratedFile,
+	protocols []ProtocolOption,
+	body func(p ProtocolOption, svc string),
+) {
+	if len(protocols) == 1 {
+		body(protocols[0], streamHandlerVar(protocols[0], protocols))
+		return
+	}
+	g.P("    switch protocol {")
+	for _, p := range protocols {
+		g.P("    case ", protocolIdent(g, p), ":")
+		body(p, streamHandlerVar(p, protocols))
+	}
+	g.P("    }")
+}
+
+// generateServiceRegisterHelpers emits typed wrappers around the rpcruntime
+// registration functions so a handler of the wrong type fails to compile
+// instead of surfacing as ErrHandlerTypeMismatch at call time.
+func generateServiceRegisterHelpers(
+	g *protogen.GeneratedFile,
+	service *protogen.Service,
 	opts GeneratorOptions,
-) string {
-	_ = service
-	_ = opts
+) {
+	serviceConstName := service.GoName + "_ServiceName"
+
+	if supportsProtocol(opts.Protocols, ProtocolOptionGrpc) {
+		registerFuncName := "Register" + service.GoName + "GrpcHandler"
+		g.P("// ", registerFuncName, " registers h as the gRPC handler for ", service.GoName, ".")
+		g.P("// It returns true if a previous handler was replaced.")
+		g.P("func ", registerFuncName, "(h ", service.GoName, "Server) (bool, error) {")
+		g.P("    return ", g.QualifiedGoIdent(rpcRuntimePkg.Ident("RegisterGrpcHandler")), "(", serviceConstName, ", h)")
+		g.P("}")
+		g.P()
+	}
+
+	if supportsProtocol(opts.Protocols, ProtocolOptionConnectRPC) {
+		ifaceName := service.GoName + "_ConnectHandler"
+		g.P("// ", ifaceName, " is the Connect simple-API handler interface the ", service.GoName, " adaptor")
+		g.P("// dispatches to.")
+		g.P("type ", ifaceName, " interface {")
+		for _, method := range service.Methods {
+			g.P("    ", connectHandlerMethodSignature(g, method))
+		}
+		g.P("}")
+		g.P()
+
+		registerFuncName := "Register" + service.GoName + "ConnectHandler"
+		g.P("// ", registerFuncName, " registers h as the connectrpc handler for ", service.GoName, ".")
+		g.P("// It returns true if a previous handler was replaced.")
+		g.P("func ", registerFuncName, "(h ", ifaceName, ") (bool, error) {")
+		g.P("    return ", g.QualifiedGoIdent(rpcRuntimePkg.Ident("RegisterConnectHandler")), "(", serviceConstName, ", h)")
+		g.P("}")
+		g.P()
+
+		genericIfaceName := service.GoName + "_ConnectGenericHandler"
+		g.P("// ", genericIfaceName, " is the Connect generic-API handler interface the ", service.GoName)
+		g.P("// adaptor dispatches to; requests and responses are wrapped in connect.Request and")
+		g.P("// connect.Response so handlers can read headers and set trailers.")
+		g.P("type ", genericIfaceName, " interface {")
+		for _, method := range service.Methods {
+			g.P("    ", connectGenericMethodSignature(g, method))
+		}
+		g.P("}")
+		g.P()
+
+		genericRegisterFuncName := "Register" + service.GoName + "ConnectGenericHandler"
+		g.P("// ", genericRegisterFuncName, " registers h as the connectrpc handler for ", service.GoName, ".")
+		g.P("// It returns true if a previous handler was replaced.")
+		g.P("func ", genericRegisterFuncName, "(h ", genericIfaceName, ") (bool, error) {")
+		g.P("    return ", g.QualifiedGoIdent(rpcRuntimePkg.Ident("RegisterConnectHandler")), "(", serviceConstName, ", h)")
+		g.P("}")
+		g.P()
+	}
+
+	if supportsProtocol(opts.Protocols, ProtocolOptionGo) {
+		ifaceName := service.GoName + "_GoHandler"
+		g.P("// ", ifaceName, " is the plain Go handler interface the ", service.GoName, " adaptor")
+		g.P("// dispatches to. It depends on neither grpc-go nor connect-go.")
+		g.P("type ", ifaceName, " interface {")
+		for _, method := range service.Methods {
+			g.P("    ", goHandlerMethodSignature(g, method))
+		}
+		g.P("}")
+		g.P()
+
+		registerFuncName := "Register" + service.GoName + "GoHandler"
+		g.P("// ", registerFuncName, " registers h as the plain Go handler for ", service.GoName, ".")
+		g.P("// It returns true if a previous handler was replaced.")
+		g.P("func ", registerFuncName, "(h ", ifaceName, ") (bool, error) {")
+		g.P("    return ", g.QualifiedGoIdent(rpcRuntimePkg.Ident("RegisterHandler")), "(", protocolIdent(g, ProtocolOptionGo), ", ", serviceConstName, ", h)")
+		g.P("}")
+		g.P()
+	}
+
+	if supportsProtocol(opts.Protocols, ProtocolOptionTwirp) && hasUnaryMethod(service) {
+		ifaceName := service.GoName + "_TwirpHandler"
+		g.P("// ", ifaceName, " is the method set of the protoc-gen-twirp ", service.GoName, " interface")
+		g.P("// the adaptor dispatches to. Streaming methods are not supported by Twirp.")
+		g.P("type ", ifaceName, " interface {")
+		for _, method := range service.Methods {
+			if method.Desc.IsStreamingClient() || method.Desc.IsStreamingServer() {
+				continue
+			}
+			g.P("    ", goHandlerMethodSignature(g, method))
+		}
+		g.P("}")
+		g.P()
+
+		registerFuncName := "Register" + service.GoName + "TwirpHandler"
+		g.P("// ", registerFuncName, " registers h as the Twirp handler for ", service.GoName, ".")
+		g.P("// It returns true if a previous handler was replaced.")
+		g.P("func ", registerFuncName, "(h ", ifaceName, ") (bool, error) {")
+		g.P("    return ", g.QualifiedGoIdent(rpcRuntimePkg.Ident("RegisterHandler")), "(", protocolIdent(g, ProtocolOptionTwirp), ", ", serviceConstName, ", h)")
+		g.P("}")
+		g.P()
+	}
+}
+
+// hasUnaryMethod reports whether service has at least one unary method.
+func hasUnaryMethod(service *protogen.Service) bool {
+	for _, method := range service.Methods {
+		if !method.Desc.IsStreamingClient() && !method.Desc.IsStreamingServer() {
+			return true
+		}
+	}
+	return false
+}
 
+// connectHandlerMethodSignature returns the Connect simple-API method signature
+// for method, as it appears inside an interface type.
+func connectHandlerMethodSignature(g *protogen.GeneratedFile, method *protogen.Method) string {
 	ctxType := g.QualifiedGoIdent(contextPackage.Ident("Context"))
 	reqType := g.QualifiedGoIdent(method.Input.GoIdent)
 	respType := g.QualifiedGoIdent(method.Output.GoIdent)
@@ -337,7 +774,7 @@ func connectHandlerAssertionType(
 
 	if !isClientStreaming && !isServerStreaming {
 		return fmt.Sprintf(
-			"interface{ %s(%s, *%s) (*%s, error) }",
+			"%s(%s, *%s) (*%s, error)",
 			method.GoName,
 			ctxType,
 			reqType,
@@ -346,7 +783,7 @@ func connectHandlerAssertionType(
 	} else if isClientStreaming && !isServerStreaming {
 		clientStreamType := g.QualifiedGoIdent(connectPackage.Ident("ClientStream"))
 		return fmt.Sprintf(
-			"interface{ %s(%s, *%s[%s]) (*%s, error) }",
+			"%s(%s, *%s[%s]) (*%s, error)",
 			method.GoName,
 			ctxType,
 			clientStreamType,
@@ -356,7 +793,7 @@ func connectHandlerAssertionType(
 	} else if !isClientStreaming && isServerStreaming {
 		serverStreamType := g.QualifiedGoIdent(connectPackage.Ident("ServerStream"))
 		return fmt.Sprintf(
-			"interface{ %s(%s, *%s, *%s[%s]) error }",
+			"%s(%s, *%s, *%s[%s]) error",
 			method.GoName,
 			ctxType,
 			reqType,
@@ -366,7 +803,7 @@ func connectHandlerAssertionType(
 	} else {
 		bidiStreamType := g.QualifiedGoIdent(connectPackage.Ident("BidiStream"))
 		return fmt.Sprintf(
-			"interface{ %s(%s, *%s[%s, %s]) error }",
+			"%s(%s, *%s[%s, %s]) error",
 			method.GoName,
 			ctxType,
 			bidiStreamType,
@@ -376,30 +813,93 @@ func connectHandlerAssertionType(
 	}
 }
 
-func protocolOptionStrings(protocols []ProtocolOption) []string {
-	out := make([]string, 0, len(protocols))
-	for _, p := range protocols {
-		out = append(out, string(p))
-	}
-	return out
-}
+// connectGenericMethodSignature returns the Connect generic-API method
+// signature for method, as it appears inside an interface type.
+func connectGenericMethodSignature(g *protogen.GeneratedFile, method *protogen.Method) string {
+	ctxType := g.QualifiedGoIdent(contextPackage.Ident("Context"))
+	reqType := g.QualifiedGoIdent(method.Input.GoIdent)
+	respType := g.QualifiedGoIdent(method.Output.GoIdent)
+	requestType := g.QualifiedGoIdent(connectPackage.Ident("Request"))
+	responseType := g.QualifiedGoIdent(connectPackage.Ident("Response"))
 
-func generateClientStreamingMethod(
-	g *protogen.GeneratedFile,
-	file *protogen.File,
-	service *protogen.Service,
-	method *protogen.Method,
+	isClientStreaming := method.Desc.IsStreamingClient()
+	isServerStreaming := method.Desc.IsStreamingServer()
+
+	switch {
+	case !isClientStreaming && !isServerStreaming:
+		return fmt.Sprintf("%s(%s, *%s[%s]) (*%s[%s], error)", method.GoName, ctxType, requestType, reqType, responseType, respType)
+	case isClientStreaming && !isServerStreaming:
+		clientStreamType := g.QualifiedGoIdent(connectPackage.Ident("ClientStream"))
+		return fmt.Sprintf(
+			"%s(%s, *%s[%s]) (*%s[%s], error)",
+			method.GoName,
+			ctxType,
+			clientStreamType,
+			reqType,
+			responseType,
+			respType,
+		)
+	case !isClientStreaming && isServerStreaming:
+		serverStreamType := g.QualifiedGoIdent(connectPackage.Ident("ServerStream"))
+		return fmt.Sprintf(
+			"%s(%s, *%s[%s], *%s[%s]) error",
+			method.GoName,
+			ctxType,
+			requestType,
+			reqType,
+			serverStreamType,
+			respType,
+		)
+	default:
+		return connectHandlerMethodSignature(g, method)
+	}
+}
+
+// goHandlerMethodSignature returns the plain Go handler method signature for
+// method, as it appears inside an interface type. Streams use the rpcruntime
+// Go stream interfaces.
+func goHandlerMethodSignature(g *protogen.GeneratedFile, method *protogen.Method) string {
+	ctxType := g.QualifiedGoIdent(contextPackage.Ident("Context"))
+	reqType := g.QualifiedGoIdent(method.Input.GoIdent)
+	respType := g.QualifiedGoIdent(method.Output.GoIdent)
+
+	isClientStreaming := method.Desc.IsStreamingClient()
+	isServerStreaming := method.Desc.IsStreamingServer()
+
+	switch {
+	case !isClientStreaming && !isServerStreaming:
+		return fmt.Sprintf("%s(%s, *%s) (*%s, error)", method.GoName, ctxType, reqType, respType)
+	case isClientStreaming && !isServerStreaming:
+		clientStreamType := g.QualifiedGoIdent(rpcRuntimePkg.Ident("GoClientStream"))
+		return fmt.Sprintf("%s(%s, %s[%s]) (*%s, error)", method.GoName, ctxType, clientStreamType, reqType, respType)
+	case !isClientStreaming && isServerStreaming:
+		serverStreamType := g.QualifiedGoIdent(rpcRuntimePkg.Ident("GoServerStream"))
+		return fmt.Sprintf("%s(%s, *%s, %s[%s]) error", method.GoName, ctxType, reqType, serverStreamType, respType)
+	default:
+		bidiStreamType := g.QualifiedGoIdent(rpcRuntimePkg.Ident("GoBidiStream"))
+		return fmt.Sprintf("%s(%s, %s[%s, %s]) error", method.GoName, ctxType, bidiStreamType, reqType, respType)
+	}
+}
+
+func protocolOptionStrings(protocols []ProtocolOption) []string {
+	out := make([]string, 0, len(protocols))
+	for _, p := range protocols {
+		out = append(out, string(p))
+	}
+	return out
+}
+
+func generateClientStreamingMethod(
+	g *protogen.GeneratedFile,
+	file *protogen.File,
+	service *protogen.Service,
+	method *protogen.Method,
 	opts GeneratorOptions,
 ) {
 	funcPrefix := service.GoName + "_" + method.GoName
 	reqType := g.QualifiedGoIdent(method.Input.GoIdent)
 	respType := g.QualifiedGoIdent(method.Output.GoIdent)
 	lookupFuncName := service.GoName + "_lookupHandler"
-	grpcServerIface := service.GoName + "Server"
-	connectHandlerIface := ""
-	if supportsProtocol(opts.Protocols, ProtocolOptionConnectRPC) {
-		connectHandlerIface = connectHandlerAssertionType(g, service, method, opts)
-	}
 
 	g.P("// ", funcPrefix, " client-streaming adaptor functions.")
 	g.P("// These use a staged API: Start, Send, Finish.")
@@ -408,184 +908,96 @@ func generateClientStreamingMethod(
 	// Start function
 	g.P("// ", funcPrefix, "Start initializes a client-streaming call and returns a stream handle.")
 	g.P("func ", funcPrefix, "Start(ctx ", g.QualifiedGoIdent(contextPackage.Ident("Context")), ") (uint64, error) {")
-	g.P("    protocol, h, err := ", lookupFuncName, "(ctx)")
+	g.P(
+		"    protocol, h, release, err := ",
+		lookupFuncName,
+		"(ctx, ",
+		service.GoName,
+		"_",
+		method.GoName,
+		"_FullMethod",
+		streamLookupSkipArgs(g, opts),
+		")",
+	)
 	g.P("    if err != nil {")
+	generateRemoteFallback(g, service, g.QualifiedGoIdent(rpcRuntimePkg.Ident("RuntimeFromContext"))+"(ctx)",
+		"return "+forwardCall(g, service, method, "ForwardClientStream", true, ""))
 	g.P("        return 0, err")
 	g.P("    }")
+	g.P("    // release is handed over to the handler goroutine once it starts.")
+	g.P("    handlerStarted := false")
+	g.P("    defer func() {")
+	g.P("        if !handlerStarted {")
+	g.P("            release()")
+	g.P("        }")
+	g.P("    }()")
 	g.P()
 
-	supportsGrpc := supportsProtocol(opts.Protocols, ProtocolOptionGrpc)
-	supportsConnect := supportsProtocol(opts.Protocols, ProtocolOptionConnectRPC)
-	switch {
-	case supportsGrpc && supportsConnect:
-		g.P("    var grpcSvc ", grpcServerIface)
-		g.P("    var connectSvc ", connectHandlerIface)
-		g.P("    if protocol == ", g.QualifiedGoIdent(rpcRuntimePkg.Ident("ProtocolGrpc")), " {")
-		g.P("        svc, ok := h.(", grpcServerIface, ")")
-		g.P("        if !ok {")
-		g.P("            return 0, ", g.QualifiedGoIdent(rpcRuntimePkg.Ident("ErrHandlerTypeMismatch")))
-		g.P("        }")
-		g.P("        grpcSvc = svc")
-		g.P("    } else if protocol == ", g.QualifiedGoIdent(rpcRuntimePkg.Ident("ProtocolConnectRPC")), " {")
-		g.P("        svc, ok := h.(", connectHandlerIface, ")")
-		g.P("        if !ok {")
-		g.P("            return 0, ", g.QualifiedGoIdent(rpcRuntimePkg.Ident("ErrHandlerTypeMismatch")))
-		g.P("        }")
-		g.P("        connectSvc = svc")
-		g.P("    } else {")
-		g.P("        return 0, ", g.QualifiedGoIdent(rpcRuntimePkg.Ident("ErrUnknownProtocol")))
-		g.P("    }")
-		g.P()
-		g.P(
-			"    handle, childCtx, _ := ",
-			g.QualifiedGoIdent(rpcRuntimePkg.Ident("AllocateStreamHandle")),
-			"(ctx, protocol)",
-		)
-		g.P(
-			"    session := ",
-			g.QualifiedGoIdent(rpcRuntimePkg.Ident("GetStreamSession")),
-			"(handle)",
-		)
-		g.P("    if session == nil {")
-		g.P("        return 0, ", g.QualifiedGoIdent(rpcRuntimePkg.Ident("ErrInvalidStreamHandle")))
-		g.P("    }")
-		g.P()
-		g.P("    if protocol == ", g.QualifiedGoIdent(rpcRuntimePkg.Ident("ProtocolGrpc")), " {")
-		g.P(
-			"        adaptorStream := &",
-			unexport(service.GoName+"_"+method.GoName+"Server"),
-			"Adaptor{session: session}",
-		)
-		g.P("        session.SetHandlerState(adaptorStream)")
-		g.P("        go func() {")
-		g.P("            defer func() {")
-		g.P("                if r := recover(); r != nil {")
-		g.P(
-			"                    ",
-			g.QualifiedGoIdent(rpcRuntimePkg.Ident("CompleteClientStream")),
-			"(handle, nil, ",
-			g.QualifiedGoIdent(rpcRuntimePkg.Ident("RecoverPanic")),
-			"(r))",
-		)
-		g.P("                }")
-		g.P("            }()")
-		g.P("            err := grpcSvc.", method.GoName, "(adaptorStream)")
-		g.P(
-			"            ",
-			g.QualifiedGoIdent(rpcRuntimePkg.Ident("CompleteClientStream")),
-			"(handle, adaptorStream.lastResp, err)",
-		)
-		g.P("        }()")
-		g.P("    } else {")
-		g.P("        conn := ", g.QualifiedGoIdent(rpcRuntimePkg.Ident("NewConnectStreamConn")), "(session)")
-		g.P(
-			"        connectStream := ",
-			g.QualifiedGoIdent(rpcRuntimePkg.Ident("NewClientStream")),
-			"[",
-			reqType,
-			"](conn)",
-		)
-		g.P("        go func() {")
-		g.P("            defer func() {")
-		g.P("                if r := recover(); r != nil {")
-		g.P(
-			"                    ",
-			g.QualifiedGoIdent(rpcRuntimePkg.Ident("CompleteClientStream")),
-			"(handle, nil, ",
-			g.QualifiedGoIdent(rpcRuntimePkg.Ident("RecoverPanic")),
-			"(r))",
-		)
-		g.P("                }")
-		g.P("            }()")
-		g.P("            resp, err := connectSvc.", method.GoName, "(childCtx, connectStream)")
-		g.P("            ", g.QualifiedGoIdent(rpcRuntimePkg.Ident("CompleteClientStream")), "(handle, resp, err)")
-		g.P("        }()")
-		g.P("    }")
-	case supportsGrpc:
-		g.P("    svc, ok := h.(", grpcServerIface, ")")
-		g.P("    if !ok {")
-		g.P("        return 0, ", g.QualifiedGoIdent(rpcRuntimePkg.Ident("ErrHandlerTypeMismatch")))
-		g.P("    }")
-		g.P()
-		g.P(
-			"    handle, childCtx, _ := ",
-			g.QualifiedGoIdent(rpcRuntimePkg.Ident("AllocateStreamHandle")),
-			"(ctx, protocol)",
-		)
-		g.P(
-			"    session := ",
-			g.QualifiedGoIdent(rpcRuntimePkg.Ident("GetStreamSession")),
-			"(handle)",
-		)
-		g.P("    if session == nil {")
-		g.P("        return 0, ", g.QualifiedGoIdent(rpcRuntimePkg.Ident("ErrInvalidStreamHandle")))
-		g.P("    }")
-		g.P()
-		g.P("    adaptorStream := &", unexport(service.GoName+"_"+method.GoName+"Server"), "Adaptor{session: session}")
-		g.P("    session.SetHandlerState(adaptorStream)")
-		g.P("    go func() {")
-		g.P("        defer func() {")
-		g.P("            if r := recover(); r != nil {")
-		g.P(
-			"                ",
-			g.QualifiedGoIdent(rpcRuntimePkg.Ident("CompleteClientStream")),
-			"(handle, nil, ",
-			g.QualifiedGoIdent(rpcRuntimePkg.Ident("RecoverPanic")),
-			"(r))",
-		)
-		g.P("            }")
-		g.P("        }()")
-		g.P("        err := svc.", method.GoName, "(adaptorStream)")
-		g.P(
-			"        ",
-			g.QualifiedGoIdent(rpcRuntimePkg.Ident("CompleteClientStream")),
-			"(handle, adaptorStream.lastResp, err)",
-		)
-		g.P("    }()")
-	default:
-		g.P("    svc, ok := h.(", connectHandlerIface, ")")
-		g.P("    if !ok {")
-		g.P("        return 0, ", g.QualifiedGoIdent(rpcRuntimePkg.Ident("ErrHandlerTypeMismatch")))
-		g.P("    }")
-		g.P()
-		g.P(
-			"    handle, childCtx, _ := ",
-			g.QualifiedGoIdent(rpcRuntimePkg.Ident("AllocateStreamHandle")),
-			"(ctx, protocol)",
-		)
-		g.P(
-			"    session := ",
-			g.QualifiedGoIdent(rpcRuntimePkg.Ident("GetStreamSession")),
-			"(handle)",
-		)
-		g.P("    if session == nil {")
-		g.P("        return 0, ", g.QualifiedGoIdent(rpcRuntimePkg.Ident("ErrInvalidStreamHandle")))
-		g.P("    }")
-		g.P()
-		g.P("    conn := ", g.QualifiedGoIdent(rpcRuntimePkg.Ident("NewConnectStreamConn")), "(session)")
-		g.P(
-			"    connectStream := ",
-			g.QualifiedGoIdent(rpcRuntimePkg.Ident("NewClientStream")),
-			"[",
-			reqType,
-			"](conn)",
-		)
+	generateStreamHandlerAssertion(g, service, method, streamingProtocols(opts.Protocols), func(errExpr string) {
+		g.P("        return 0, ", errExpr)
+	})
+
+	g.P(
+		"    handle, childCtx, _ := ",
+		g.QualifiedGoIdent(rpcRuntimePkg.Ident("AllocateStreamHandle")),
+		"(ctx, protocol)",
+	)
+	g.P(
+		"    session := ",
+		g.QualifiedGoIdent(rpcRuntimePkg.Ident("GetStreamSession")),
+		"(handle)",
+	)
+	g.P("    if session == nil {")
+	g.P("        return 0, ", g.QualifiedGoIdent(rpcRuntimePkg.Ident("ErrInvalidStreamHandle")))
+	g.P("    }")
+	g.P()
+
+	completeClientStream := g.QualifiedGoIdent(rpcRuntimePkg.Ident("CompleteClientStream"))
+	generateStreamProtocolSwitch(g, streamingProtocols(opts.Protocols), func(p ProtocolOption, svc string) {
+		var call, resp string
+		switch p {
+		case ProtocolOptionGrpc:
+			g.P(
+				"    adaptorStream := &",
+				unexport(service.GoName+"_"+method.GoName+"Server"),
+				"Adaptor{session: session}",
+			)
+			g.P("    session.SetHandlerState(adaptorStream)")
+			call = "err := " + svc + "." + method.GoName + "(adaptorStream)"
+			resp = "adaptorStream.lastResp"
+		case ProtocolOptionConnectRPC:
+			call = "resp, err := " + g.QualifiedGoIdent(connectRtPkg.Ident("ServeClientStream")) +
+				"(childCtx, session, " + funcPrefix + "_FullMethod, " + streamHandlerCall(p, method, svc) + ")"
+			resp = "resp"
+		case ProtocolOptionGo:
+			g.P(
+				"    goStream := ",
+				g.QualifiedGoIdent(rpcRuntimePkg.Ident("NewGoClientStream")),
+				"[",
+				reqType,
+				"](session)",
+			)
+			call = "resp, err := " + svc + "." + method.GoName + "(childCtx, goStream)"
+			resp = "resp"
+		}
+		g.P("    handlerStarted = true")
 		g.P("    go func() {")
+		g.P("        defer release()")
 		g.P("        defer func() {")
 		g.P("            if r := recover(); r != nil {")
 		g.P(
 			"                ",
-			g.QualifiedGoIdent(rpcRuntimePkg.Ident("CompleteClientStream")),
+			completeClientStream,
 			"(handle, nil, ",
 			g.QualifiedGoIdent(rpcRuntimePkg.Ident("RecoverPanic")),
 			"(r))",
 		)
 		g.P("            }")
 		g.P("        }()")
-		g.P("        resp, err := svc.", method.GoName, "(childCtx, connectStream)")
-		g.P("        ", g.QualifiedGoIdent(rpcRuntimePkg.Ident("CompleteClientStream")), "(handle, resp, err)")
+		g.P("        ", call)
+		g.P("        ", completeClientStream, "(handle, ", resp, ", err)")
 		g.P("    }()")
-	}
+	})
 
 	g.P()
 	g.P("    _ = childCtx // Used in goroutine")
@@ -650,11 +1062,6 @@ func generateServerStreamingMethod(
 	reqType := g.QualifiedGoIdent(method.Input.GoIdent)
 	respType := g.QualifiedGoIdent(method.Output.GoIdent)
 	lookupFuncName := service.GoName + "_lookupHandler"
-	grpcServerIface := service.GoName + "Server"
-	connectHandlerIface := ""
-	if supportsProtocol(opts.Protocols, ProtocolOptionConnectRPC) {
-		connectHandlerIface = connectHandlerAssertionType(g, service, method, opts)
-	}
 
 	g.P("// ", funcName, " calls ", method.Desc.FullName(), " via the registered handler.")
 	g.P("//")
@@ -673,117 +1080,71 @@ func generateServerStreamingMethod(
 		") bool, onDone func(error)) error {",
 	)
 
-	g.P("    protocol, h, err := ", lookupFuncName, "(ctx)")
+	g.P(
+		"    protocol, h, release, err := ",
+		lookupFuncName,
+		"(ctx, ",
+		service.GoName,
+		"_",
+		method.GoName,
+		"_FullMethod",
+		streamLookupSkipArgs(g, opts),
+		")",
+	)
 	g.P("    if err != nil {")
+	generateRemoteFallback(g, service, g.QualifiedGoIdent(rpcRuntimePkg.Ident("RuntimeFromContext"))+"(ctx)",
+		"return "+forwardCall(g, service, method, "ForwardServerStream", false, "req, onRead, onDone"))
 	g.P("        onDone(err)")
 	g.P("        return err")
 	g.P("    }")
+	g.P("    defer release()")
 	g.P()
 
 	streamIface := service.GoName + "_" + method.GoName + "Server"
 
-	supportsGrpc := supportsProtocol(opts.Protocols, ProtocolOptionGrpc)
-	supportsConnect := supportsProtocol(opts.Protocols, ProtocolOptionConnectRPC)
-	switch {
-	case supportsGrpc && supportsConnect:
-		g.P("    var grpcSvc ", grpcServerIface)
-		g.P("    var connectSvc ", connectHandlerIface)
-		g.P("    if protocol == ", g.QualifiedGoIdent(rpcRuntimePkg.Ident("ProtocolGrpc")), " {")
-		g.P("        svc, ok := h.(", grpcServerIface, ")")
-		g.P("        if !ok {")
-		g.P("            onDone(", g.QualifiedGoIdent(rpcRuntimePkg.Ident("ErrHandlerTypeMismatch")), ")")
-		g.P("            return ", g.QualifiedGoIdent(rpcRuntimePkg.Ident("ErrHandlerTypeMismatch")))
-		g.P("        }")
-		g.P("        grpcSvc = svc")
-		g.P("    } else if protocol == ", g.QualifiedGoIdent(rpcRuntimePkg.Ident("ProtocolConnectRPC")), " {")
-		g.P("        svc, ok := h.(", connectHandlerIface, ")")
-		g.P("        if !ok {")
-		g.P("            onDone(", g.QualifiedGoIdent(rpcRuntimePkg.Ident("ErrHandlerTypeMismatch")), ")")
-		g.P("            return ", g.QualifiedGoIdent(rpcRuntimePkg.Ident("ErrHandlerTypeMismatch")))
-		g.P("        }")
-		g.P("        connectSvc = svc")
-		g.P("    } else {")
-		g.P("        onDone(", g.QualifiedGoIdent(rpcRuntimePkg.Ident("ErrUnknownProtocol")), ")")
-		g.P("        return ", g.QualifiedGoIdent(rpcRuntimePkg.Ident("ErrUnknownProtocol")))
-		g.P("    }")
-		g.P()
-		g.P("    handle, _, _ := ", g.QualifiedGoIdent(rpcRuntimePkg.Ident("AllocateStreamHandle")), "(ctx, protocol)")
-		g.P("    session := ", g.QualifiedGoIdent(rpcRuntimePkg.Ident("GetStreamSession")), "(handle)")
-		g.P("    if session == nil {")
-		g.P("        onDone(", g.QualifiedGoIdent(rpcRuntimePkg.Ident("ErrInvalidStreamHandle")), ")")
-		g.P("        return ", g.QualifiedGoIdent(rpcRuntimePkg.Ident("ErrInvalidStreamHandle")))
-		g.P("    }")
-		g.P("    session.SetCallbacks(func(resp any) bool { return onRead(resp.(*", respType, ")) }, onDone)")
-		g.P()
-		g.P("    if protocol == ", g.QualifiedGoIdent(rpcRuntimePkg.Ident("ProtocolGrpc")), " {")
-		g.P("        adaptorStream := &", unexport(streamIface), "Adaptor{session: session}")
-		g.P("        err = grpcSvc.", method.GoName, "(req, adaptorStream)")
-		g.P("        ", g.QualifiedGoIdent(rpcRuntimePkg.Ident("FinishStreamHandle")), "(handle)")
-		g.P("        onDone(err)")
-		g.P("        return err")
-		g.P("    } else {")
-		g.P("        conn := ", g.QualifiedGoIdent(rpcRuntimePkg.Ident("NewConnectStreamConn")), "(session)")
-		g.P(
-			"        connectStream := ",
-			g.QualifiedGoIdent(rpcRuntimePkg.Ident("NewServerStream")),
-			"[",
-			respType,
-			"](conn)",
-		)
-		g.P("        err = connectSvc.", method.GoName, "(ctx, req, connectStream)")
-		g.P("        ", g.QualifiedGoIdent(rpcRuntimePkg.Ident("FinishStreamHandle")), "(handle)")
-		g.P("        onDone(err)")
-		g.P("        return err")
-		g.P("    }")
-	case supportsGrpc:
-		g.P("    svc, ok := h.(", grpcServerIface, ")")
-		g.P("    if !ok {")
-		g.P("        onDone(", g.QualifiedGoIdent(rpcRuntimePkg.Ident("ErrHandlerTypeMismatch")), ")")
-		g.P("        return ", g.QualifiedGoIdent(rpcRuntimePkg.Ident("ErrHandlerTypeMismatch")))
-		g.P("    }")
-		g.P()
-		g.P("    handle, _, _ := ", g.QualifiedGoIdent(rpcRuntimePkg.Ident("AllocateStreamHandle")), "(ctx, protocol)")
-		g.P("    session := ", g.QualifiedGoIdent(rpcRuntimePkg.Ident("GetStreamSession")), "(handle)")
-		g.P("    if session == nil {")
-		g.P("        onDone(", g.QualifiedGoIdent(rpcRuntimePkg.Ident("ErrInvalidStreamHandle")), ")")
-		g.P("        return ", g.QualifiedGoIdent(rpcRuntimePkg.Ident("ErrInvalidStreamHandle")))
-		g.P("    }")
-		g.P("    session.SetCallbacks(func(resp any) bool { return onRead(resp.(*", respType, ")) }, onDone)")
-		g.P()
-		g.P("    adaptorStream := &", unexport(streamIface), "Adaptor{session: session}")
-		g.P("    err = svc.", method.GoName, "(req, adaptorStream)")
-		g.P("    ", g.QualifiedGoIdent(rpcRuntimePkg.Ident("FinishStreamHandle")), "(handle)")
-		g.P("    onDone(err)")
-		g.P("    return err")
-	default:
-		g.P("    svc, ok := h.(", connectHandlerIface, ")")
-		g.P("    if !ok {")
-		g.P("        onDone(", g.QualifiedGoIdent(rpcRuntimePkg.Ident("ErrHandlerTypeMismatch")), ")")
-		g.P("        return ", g.QualifiedGoIdent(rpcRuntimePkg.Ident("ErrHandlerTypeMismatch")))
-		g.P("    }")
-		g.P()
-		g.P("    handle, _, _ := ", g.QualifiedGoIdent(rpcRuntimePkg.Ident("AllocateStreamHandle")), "(ctx, protocol)")
-		g.P("    session := ", g.QualifiedGoIdent(rpcRuntimePkg.Ident("GetStreamSession")), "(handle)")
-		g.P("    if session == nil {")
-		g.P("        onDone(", g.QualifiedGoIdent(rpcRuntimePkg.Ident("ErrInvalidStreamHandle")), ")")
-		g.P("        return ", g.QualifiedGoIdent(rpcRuntimePkg.Ident("ErrInvalidStreamHandle")))
-		g.P("    }")
-		g.P("    session.SetCallbacks(func(resp any) bool { return onRead(resp.(*", respType, ")) }, onDone)")
-		g.P()
-		g.P("    conn := ", g.QualifiedGoIdent(rpcRuntimePkg.Ident("NewConnectStreamConn")), "(session)")
-		g.P(
-			"    connectStream := ",
-			g.QualifiedGoIdent(rpcRuntimePkg.Ident("NewServerStream")),
-			"[",
-			respType,
-			"](conn)",
-		)
-		g.P("    err = svc.", method.GoName, "(ctx, req, connectStream)")
-		g.P("    ", g.QualifiedGoIdent(rpcRuntimePkg.Ident("FinishStreamHandle")), "(handle)")
-		g.P("    onDone(err)")
-		g.P("    return err")
-	}
+	generateStreamHandlerAssertion(g, service, method, streamingProtocols(opts.Protocols), func(errExpr string) {
+		g.P("        onDone(", errExpr, ")")
+		g.P("        return ", errExpr)
+	})
 
+	g.P("    handle, _, _ := ", g.QualifiedGoIdent(rpcRuntimePkg.Ident("AllocateStreamHandle")), "(ctx, protocol)")
+	g.P("    session := ", g.QualifiedGoIdent(rpcRuntimePkg.Ident("GetStreamSession")), "(handle)")
+	g.P("    if session == nil {")
+	g.P("        onDone(", g.QualifiedGoIdent(rpcRuntimePkg.Ident("ErrInvalidStreamHandle")), ")")
+	g.P("        return ", g.QualifiedGoIdent(rpcRuntimePkg.Ident("ErrInvalidStreamHandle")))
+	g.P("    }")
+	g.P("    session.SetCallbacks(func(resp any) bool { return onRead(resp.(*", respType, ")) }, onDone)")
+	g.P()
+
+	generateStreamProtocolSwitch(g, streamingProtocols(opts.Protocols), func(p ProtocolOption, svc string) {
+		switch p {
+		case ProtocolOptionGrpc:
+			g.P("    adaptorStream := &", unexport(streamIface), "Adaptor{session: session}")
+			g.P("    err = ", svc, ".", method.GoName, "(req, adaptorStream)")
+		case ProtocolOptionConnectRPC:
+			g.P(
+				"    err = ",
+				g.QualifiedGoIdent(connectRtPkg.Ident("ServeServerStream")),
+				"(ctx, session, ",
+				funcName,
+				"_FullMethod, req, ",
+				streamHandlerCall(p, method, svc),
+				")",
+			)
+		case ProtocolOptionGo:
+			g.P(
+				"    goStream := ",
+				g.QualifiedGoIdent(rpcRuntimePkg.Ident("NewGoServerStream")),
+				"[",
+				respType,
+				"](session)",
+			)
+			g.P("    err = ", svc, ".", method.GoName, "(ctx, req, goStream)")
+		}
+	})
+	g.P("    ", g.QualifiedGoIdent(rpcRuntimePkg.Ident("FinishStreamHandle")), "(handle)")
+	g.P("    onDone(err)")
+	g.P("    return err")
 	g.P("}")
 	g.P()
 }
@@ -799,11 +1160,6 @@ func generateBidiStreamingMethod(
 	reqType := g.QualifiedGoIdent(method.Input.GoIdent)
 	respType := g.QualifiedGoIdent(method.Output.GoIdent)
 	lookupFuncName := service.GoName + "_lookupHandler"
-	grpcServerIface := service.GoName + "Server"
-	connectHandlerIface := ""
-	if supportsProtocol(opts.Protocols, ProtocolOptionConnectRPC) {
-		connectHandlerIface = connectHandlerAssertionType(g, service, method, opts)
-	}
 
 	g.P("// ", funcPrefix, " bidi-streaming adaptor functions.")
 	g.P("// These use a combined staged + callback API: Start, Send, CloseSend + receive callbacks.")
@@ -822,203 +1178,101 @@ func generateBidiStreamingMethod(
 		") bool, onDone func(error)) (uint64, error) {",
 	)
 
-	g.P("    protocol, h, err := ", lookupFuncName, "(ctx)")
+	g.P(
+		"    protocol, h, release, err := ",
+		lookupFuncName,
+		"(ctx, ",
+		service.GoName,
+		"_",
+		method.GoName,
+		"_FullMethod",
+		streamLookupSkipArgs(g, opts),
+		")",
+	)
 	g.P("    if err != nil {")
+	generateRemoteFallback(g, service, g.QualifiedGoIdent(rpcRuntimePkg.Ident("RuntimeFromContext"))+"(ctx)",
+		"return "+forwardCall(g, service, method, "ForwardBidiStream", true, "onRead, onDone"))
 	g.P("        return 0, err")
 	g.P("    }")
+	g.P("    // release is handed over to the handler goroutine once it starts.")
+	g.P("    handlerStarted := false")
+	g.P("    defer func() {")
+	g.P("        if !handlerStarted {")
+	g.P("            release()")
+	g.P("        }")
+	g.P("    }()")
 	g.P()
 
 	streamIface := service.GoName + "_" + method.GoName + "Server"
 
-	supportsGrpc := supportsProtocol(opts.Protocols, ProtocolOptionGrpc)
-	supportsConnect := supportsProtocol(opts.Protocols, ProtocolOptionConnectRPC)
-	switch {
-	case supportsGrpc && supportsConnect:
-		g.P("    var grpcSvc ", grpcServerIface)
-		g.P("    var connectSvc ", connectHandlerIface)
-		g.P("    if protocol == ", g.QualifiedGoIdent(rpcRuntimePkg.Ident("ProtocolGrpc")), " {")
-		g.P("        svc, ok := h.(", grpcServerIface, ")")
-		g.P("        if !ok {")
-		g.P("            return 0, ", g.QualifiedGoIdent(rpcRuntimePkg.Ident("ErrHandlerTypeMismatch")))
-		g.P("        }")
-		g.P("        grpcSvc = svc")
-		g.P("    } else if protocol == ", g.QualifiedGoIdent(rpcRuntimePkg.Ident("ProtocolConnectRPC")), " {")
-		g.P("        svc, ok := h.(", connectHandlerIface, ")")
-		g.P("        if !ok {")
-		g.P("            return 0, ", g.QualifiedGoIdent(rpcRuntimePkg.Ident("ErrHandlerTypeMismatch")))
-		g.P("        }")
-		g.P("        connectSvc = svc")
-		g.P("    } else {")
-		g.P("        return 0, ", g.QualifiedGoIdent(rpcRuntimePkg.Ident("ErrUnknownProtocol")))
-		g.P("    }")
-		g.P()
-		g.P(
-			"    handle, childCtx, _ := ",
-			g.QualifiedGoIdent(rpcRuntimePkg.Ident("AllocateStreamHandle")),
-			"(ctx, protocol)",
-		)
-		g.P(
-			"    session := ",
-			g.QualifiedGoIdent(rpcRuntimePkg.Ident("GetStreamSession")),
-			"(handle)",
-		)
-		g.P("    if session == nil {")
-		g.P("        return 0, ", g.QualifiedGoIdent(rpcRuntimePkg.Ident("ErrInvalidStreamHandle")))
-		g.P("    }")
-		g.P("    session.SetCallbacks(func(resp any) bool { return onRead(resp.(*", respType, ")) }, onDone)")
-		g.P()
-		g.P("    if protocol == ", g.QualifiedGoIdent(rpcRuntimePkg.Ident("ProtocolGrpc")), " {")
-		g.P("        adaptorStream := &", unexport(streamIface), "Adaptor{session: session}")
-		g.P("        session.SetHandlerState(adaptorStream)")
-		g.P("        go func() {")
-		g.P("            defer func() {")
-		g.P("                if r := recover(); r != nil {")
-		g.P("                    if cb := session.OnDone(); cb != nil {")
-		g.P("                        cb(", g.QualifiedGoIdent(rpcRuntimePkg.Ident("RecoverPanic")), "(r))")
-		g.P("                    }")
-		g.P("                    ", g.QualifiedGoIdent(rpcRuntimePkg.Ident("FinishStreamHandle")), "(handle)")
-		g.P("                }")
-		g.P("            }()")
-		g.P("            err := grpcSvc.", method.GoName, "(adaptorStream)")
-		g.P("            if cb := session.OnDone(); cb != nil {")
-		g.P("                cb(err)")
-		g.P("            }")
-		g.P("            ", g.QualifiedGoIdent(rpcRuntimePkg.Ident("FinishStreamHandle")), "(handle)")
-		g.P("        }()")
-		g.P("    } else {")
-		g.P("        conn := ", g.QualifiedGoIdent(rpcRuntimePkg.Ident("NewConnectStreamConn")), "(session)")
-		g.P(
-			"        connectStream := ",
-			g.QualifiedGoIdent(rpcRuntimePkg.Ident("NewBidiStream")),
-			"[",
-			reqType,
-			", ",
-			respType,
-			"](conn)",
-		)
-		g.P("        session.SetHandlerState(connectStream)")
-		g.P("        go func() {")
-		g.P("            defer func() {")
-		g.P("                if r := recover(); r != nil {")
-		g.P("                    if cb := session.OnDone(); cb != nil {")
-		g.P("                        cb(", g.QualifiedGoIdent(rpcRuntimePkg.Ident("RecoverPanic")), "(r))")
-		g.P("                    }")
-		g.P("                    ", g.QualifiedGoIdent(rpcRuntimePkg.Ident("FinishStreamHandle")), "(handle)")
-		g.P("                }")
-		g.P("            }()")
-		g.P("            err := connectSvc.", method.GoName, "(childCtx, connectStream)")
-		g.P("            if cb := session.OnDone(); cb != nil {")
-		g.P("                cb(err)")
-		g.P("            }")
-		g.P("            ", g.QualifiedGoIdent(rpcRuntimePkg.Ident("FinishStreamHandle")), "(handle)")
-		g.P("        }()")
-		g.P("    }")
-		g.P()
-		g.P("    _ = childCtx // Used in goroutine")
-		g.P("    return uint64(handle), nil")
-		g.P("}")
-		g.P()
-		goto bidiSend
-	case supportsGrpc:
-		g.P("    svc, ok := h.(", grpcServerIface, ")")
-		g.P("    if !ok {")
-		g.P("        return 0, ", g.QualifiedGoIdent(rpcRuntimePkg.Ident("ErrHandlerTypeMismatch")))
-		g.P("    }")
-		g.P()
-		g.P(
-			"    handle, childCtx, _ := ",
-			g.QualifiedGoIdent(rpcRuntimePkg.Ident("AllocateStreamHandle")),
-			"(ctx, protocol)",
-		)
-		g.P(
-			"    session := ",
-			g.QualifiedGoIdent(rpcRuntimePkg.Ident("GetStreamSession")),
-			"(handle)",
-		)
-		g.P("    if session == nil {")
-		g.P("        return 0, ", g.QualifiedGoIdent(rpcRuntimePkg.Ident("ErrInvalidStreamHandle")))
-		g.P("    }")
-		g.P("    session.SetCallbacks(func(resp any) bool { return onRead(resp.(*", respType, ")) }, onDone)")
-		g.P()
-		g.P("    adaptorStream := &", unexport(streamIface), "Adaptor{session: session}")
-		g.P("    session.SetHandlerState(adaptorStream)")
-		g.P("    go func() {")
-		g.P("        defer func() {")
-		g.P("            if r := recover(); r != nil {")
-		g.P("                if cb := session.OnDone(); cb != nil {")
-		g.P("                    cb(", g.QualifiedGoIdent(rpcRuntimePkg.Ident("RecoverPanic")), "(r))")
-		g.P("                }")
-		g.P("                ", g.QualifiedGoIdent(rpcRuntimePkg.Ident("FinishStreamHandle")), "(handle)")
-		g.P("            }")
-		g.P("        }()")
-		g.P("        err := svc.", method.GoName, "(adaptorStream)")
-		g.P("        if cb := session.OnDone(); cb != nil {")
-		g.P("            cb(err)")
-		g.P("        }")
-		g.P("        ", g.QualifiedGoIdent(rpcRuntimePkg.Ident("FinishStreamHandle")), "(handle)")
-		g.P("    }()")
-		g.P()
-		g.P("    _ = childCtx // Used in goroutine")
-		g.P("    return uint64(handle), nil")
-		g.P("}")
-		g.P()
-		goto bidiSend
-	default:
-		g.P("    svc, ok := h.(", connectHandlerIface, ")")
-		g.P("    if !ok {")
-		g.P("        return 0, ", g.QualifiedGoIdent(rpcRuntimePkg.Ident("ErrHandlerTypeMismatch")))
-		g.P("    }")
-		g.P()
-		g.P(
-			"    handle, childCtx, _ := ",
-			g.QualifiedGoIdent(rpcRuntimePkg.Ident("AllocateStreamHandle")),
-			"(ctx, protocol)",
-		)
-		g.P(
-			"    session := ",
-			g.QualifiedGoIdent(rpcRuntimePkg.Ident("GetStreamSession")),
-			"(handle)",
-		)
-		g.P("    if session == nil {")
-		g.P("        return 0, ", g.QualifiedGoIdent(rpcRuntimePkg.Ident("ErrInvalidStreamHandle")))
-		g.P("    }")
-		g.P("    session.SetCallbacks(func(resp any) bool { return onRead(resp.(*", respType, ")) }, onDone)")
-		g.P()
-		g.P("    conn := ", g.QualifiedGoIdent(rpcRuntimePkg.Ident("NewConnectStreamConn")), "(session)")
-		g.P(
-			"    connectStream := ",
-			g.QualifiedGoIdent(rpcRuntimePkg.Ident("NewBidiStream")),
-			"[",
-			reqType,
-			", ",
-			respType,
-			"](conn)",
-		)
-		g.P("    session.SetHandlerState(connectStream)")
+	generateStreamHandlerAssertion(g, service, method, streamingProtocols(opts.Protocols), func(errExpr string) {
+		g.P("        return 0, ", errExpr)
+	})
+
+	g.P(
+		"    handle, childCtx, _ := ",
+		g.QualifiedGoIdent(rpcRuntimePkg.Ident("AllocateStreamHandle")),
+		"(ctx, protocol)",
+	)
+	g.P(
+		"    session := ",
+		g.QualifiedGoIdent(rpcRuntimePkg.Ident("GetStreamSession")),
+		"(handle)",
+	)
+	g.P("    if session == nil {")
+	g.P("        return 0, ", g.QualifiedGoIdent(rpcRuntimePkg.Ident("ErrInvalidStreamHandle")))
+	g.P("    }")
+	g.P("    session.SetCallbacks(func(resp any) bool { return onRead(resp.(*", respType, ")) }, onDone)")
+	g.P()
+
+	finishStreamHandle := g.QualifiedGoIdent(rpcRuntimePkg.Ident("FinishStreamHandle"))
+	generateStreamProtocolSwitch(g, streamingProtocols(opts.Protocols), func(p ProtocolOption, svc string) {
+		var call string
+		switch p {
+		case ProtocolOptionGrpc:
+			g.P("    adaptorStream := &", unexport(streamIface), "Adaptor{session: session}")
+			g.P("    session.SetHandlerState(adaptorStream)")
+			call = svc + "." + method.GoName + "(adaptorStream)"
+		case ProtocolOptionConnectRPC:
+			call = g.QualifiedGoIdent(connectRtPkg.Ident("ServeBidiStream")) +
+				"(childCtx, session, " + funcPrefix + "_FullMethod, " + streamHandlerCall(p, method, svc) + ")"
+		case ProtocolOptionGo:
+			g.P(
+				"    goStream := ",
+				g.QualifiedGoIdent(rpcRuntimePkg.Ident("NewGoBidiStream")),
+				"[",
+				reqType,
+				", ",
+				respType,
+				"](session)",
+			)
+			g.P("    session.SetHandlerState(goStream)")
+			call = svc + "." + method.GoName + "(childCtx, goStream)"
+		}
+		g.P("    handlerStarted = true")
 		g.P("    go func() {")
+		g.P("        defer release()")
 		g.P("        defer func() {")
 		g.P("            if r := recover(); r != nil {")
 		g.P("                if cb := session.OnDone(); cb != nil {")
 		g.P("                    cb(", g.QualifiedGoIdent(rpcRuntimePkg.Ident("RecoverPanic")), "(r))")
 		g.P("                }")
-		g.P("                ", g.QualifiedGoIdent(rpcRuntimePkg.Ident("FinishStreamHandle")), "(handle)")
+		g.P("                ", finishStreamHandle, "(handle)")
 		g.P("            }")
 		g.P("        }()")
-		g.P("        err := svc.", method.GoName, "(childCtx, connectStream)")
+		g.P("        err := ", call)
 		g.P("        if cb := session.OnDone(); cb != nil {")
 		g.P("            cb(err)")
 		g.P("        }")
-		g.P("        ", g.QualifiedGoIdent(rpcRuntimePkg.Ident("FinishStreamHandle")), "(handle)")
+		g.P("        ", finishStreamHandle, "(handle)")
 		g.P("    }()")
-		g.P()
-		g.P("    _ = childCtx // Used in goroutine")
-		g.P("    return uint64(handle), nil")
-		g.P("}")
-		g.P()
-		goto bidiSend
-	}
+	})
 
-bidiSend:
+	g.P()
+	g.P("    _ = childCtx // Used in goroutine")
+	g.P("    return uint64(handle), nil")
+	g.P("}")
+	g.P()
 
 	// Send function
 	g.P("// ", funcPrefix, "Send sends a request message to the stream.")
@@ -22,6 +22,8 @@ type ProtocolOption string
 const (
 	ProtocolOptionConnectRPC ProtocolOption = "connectrpc"
 	ProtocolOptionGrpc       ProtocolOption = "grpc"
+	ProtocolOptionGo         ProtocolOption = "go"
+	ProtocolOptionTwirp      ProtocolOption = "twirp"
 )
 
 // GeneratorOptions holds all options for code generation.
@@ -40,7 +42,7 @@ func main() {
 	protocolFlag := flags.String(
 		"protocol",
 		"",
-		"protocols to generate support for; use '|' to separate multiple protocols (e.g. protocol=grpc|connectrpc); default is connectrpc",
+		"protocols to generate support for; use '|' to separate multiple protocols (e.g. protocol=grpc|connectrpc); allowed: grpc, connectrpc, go, twirp; default is connectrpc",
 	)
 
 	protogen.Options{
@@ -58,6 +60,9 @@ func main() {
 			if !f.Generate || len(f.Services) == 0 {
 				continue
 			}
+			if err := validateStreamingSupport(f, genOpts); err != nil {
+				return err
+			}
 			generateFile(gen, f, genOpts)
 		}
 
@@ -70,7 +75,7 @@ func parseProtocolToken(raw string) ([]ProtocolOption, error) {
 	if trimmedRaw == "" {
 		return []ProtocolOption{ProtocolOptionConnectRPC}, nil
 	}
-	seen := make(map[ProtocolOption]bool, 2)
+	seen := make(map[ProtocolOption]bool, 3)
 	var out []ProtocolOption
 
 	if strings.Contains(trimmedRaw, ",") {
@@ -99,8 +104,12 @@ func parseProtocolToken(raw string) ([]ProtocolOption, error) {
 			p = ProtocolOptionGrpc
 		case string(ProtocolOptionConnectRPC):
 			p = ProtocolOptionConnectRPC
+		case string(ProtocolOptionGo):
+			p = ProtocolOptionGo
+		case string(ProtocolOptionTwirp):
+			p = ProtocolOptionTwirp
 		default:
-			return nil, fmt.Errorf("invalid protocol option %q (allowed: grpc, connectrpc)", token)
+			return nil, fmt.Errorf("invalid protocol option %q (allowed: grpc, connectrpc, go, twirp)", token)
 		}
 
 		if !seen[p] {
@@ -115,6 +124,27 @@ func parseProtocolToken(raw string) ([]ProtocolOption, error) {
 	return out, nil
 }
 
+// validateStreamingSupport rejects streaming methods in f when none of the
+// configured protocols supports streaming (protocol=twirp alone).
+func validateStreamingSupport(f *protogen.File, opts GeneratorOptions) error {
+	if len(streamingProtocols(opts.Protocols)) > 0 {
+		return nil
+	}
+	for _, service := range f.Services {
+		for _, method := range service.Methods {
+			if method.Desc.IsStreamingClient() || method.Desc.IsStreamingServer() {
+				return fmt.Errorf(
+					"%s: streaming method %s is not supported by protocol=%s; add a streaming protocol (e.g. protocol=grpc|twirp)",
+					f.Desc.Path(),
+					method.Desc.FullName(),
+					strings.Join(protocolOptionStrings(opts.Protocols), "|"),
+				)
+			}
+		}
+	}
+	return nil
+}
+
 func supportsProtocol(protocols []ProtocolOption, p ProtocolOption) bool {
 	for _, got := range protocols {
 		if got == p {
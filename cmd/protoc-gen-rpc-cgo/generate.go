@@ -22,6 +22,11 @@ func generateCgoCommonHeader(gen *protogen.Plugin) *protogen.GeneratedFile {
 
 	h.P("// Code generated by protoc-gen-rpc-cgo. DO NOT EDIT.")
 	h.P()
+	h.P("// Exports that start calls or configure a Runtime take the handle of the")
+	h.P("// rpcruntime Runtime to use as their first argument (see Ygrpc_NewRuntime);")
+	h.P("// YGRPC_DEFAULT_RUNTIME selects the default Runtime. Stream handles remember")
+	h.P("// their Runtime, and error ids and callbacks are process-wide.")
+	h.P()
 	h.P("#ifndef YGRPC_CGO_COMMON_H")
 	h.P("#define YGRPC_CGO_COMMON_H")
 	h.P()
@@ -31,12 +36,36 @@ func generateCgoCommonHeader(gen *protogen.Plugin) *protogen.GeneratedFile {
 	h.P()
 	h.P("typedef void (*FreeFunc)(void*);")
 	h.P()
+	h.P("#define YGRPC_DEFAULT_RUNTIME 0")
+	h.P()
 	h.P("typedef enum {")
 	h.P("    YGRPC_PROTOCOL_UNSET = 0,")
 	h.P("    YGRPC_PROTOCOL_GRPC = 1,")
 	h.P("    YGRPC_PROTOCOL_CONNECTRPC = 2,")
+	h.P("    YGRPC_PROTOCOL_GO = 3,")
+	h.P("    YGRPC_PROTOCOL_TWIRP = 4,")
 	h.P("} YgrpcProtocol;")
 	h.P()
+	h.P("// Values match rpcruntime.ErrorKind; see Ygrpc_ErrorIs.")
+	h.P("typedef enum {")
+	h.P("    YGRPC_ERR_KIND_UNSPECIFIED = 0,")
+	h.P("    YGRPC_ERR_KIND_SERVICE_NOT_REGISTERED = 1,")
+	h.P("    YGRPC_ERR_KIND_HANDLER_TYPE_MISMATCH = 2,")
+	h.P("    YGRPC_ERR_KIND_INVALID_STREAM_HANDLE = 3,")
+	h.P("    YGRPC_ERR_KIND_UNKNOWN_PROTOCOL = 4,")
+	h.P("    YGRPC_ERR_KIND_STREAM_MESSAGE_TYPE_MISMATCH = 5,")
+	h.P("    YGRPC_ERR_KIND_EMPTY_SERVICE_NAME = 6,")
+	h.P("    YGRPC_ERR_KIND_NIL_HANDLER = 7,")
+	h.P("    YGRPC_ERR_KIND_UNAVAILABLE = 8,")
+	h.P("    YGRPC_ERR_KIND_NOT_INITIALIZED = 9,")
+	h.P("    YGRPC_ERR_KIND_RESOURCE_EXHAUSTED = 10,")
+	h.P("    YGRPC_ERR_KIND_ALREADY_INITIALIZED = 11,")
+	h.P("    YGRPC_ERR_KIND_INVALID_CONNECT_STREAM_BRIDGE = 12,")
+	h.P("    YGRPC_ERR_KIND_NIL_REMOTE = 13,")
+	h.P("    YGRPC_ERR_KIND_ALIAS_CYCLE = 14,")
+	h.P("    YGRPC_ERR_KIND_INVALID_RUNTIME_HANDLE = 15,")
+	h.P("} YgrpcErrKind;")
+	h.P()
 
 	h.P("extern void Ygrpc_Free(void* ptr);")
 	h.P()
@@ -59,6 +88,42 @@ func generateCgoCommonHeader(gen *protogen.Plugin) *protogen.GeneratedFile {
 	h.P("    if(fn) ((OnDoneFunc)fn)(call_id, error_id);")
 	h.P("}")
 	h.P()
+	h.P("// Values match rpcruntime.LogLevel; see Ygrpc_SetLogCallback.")
+	h.P("typedef enum {")
+	h.P("    YGRPC_LOG_DEBUG = 0,")
+	h.P("    YGRPC_LOG_INFO = 1,")
+	h.P("    YGRPC_LOG_WARN = 2,")
+	h.P("    YGRPC_LOG_ERROR = 3,")
+	h.P("} YgrpcLogLevel;")
+	h.P()
+	h.P("// msg is only valid for the duration of the callback.")
+	h.P("typedef void (*OnLogFunc)(int level, const char* msg, int msg_len);")
+	h.P("typedef void (*OnErrorFunc)(uint64_t error_id, const char* msg, int msg_len);")
+	h.P()
+	h.P("static inline void call_on_log(void* fn, int level, const char* msg, int msg_len) {")
+	h.P("    if(fn) ((OnLogFunc)fn)(level, msg, msg_len);")
+	h.P("}")
+	h.P()
+	h.P("static inline void call_on_error(void* fn, uint64_t error_id, const char* msg, int msg_len) {")
+	h.P("    if(fn) ((OnErrorFunc)fn)(error_id, msg, msg_len);")
+	h.P("}")
+	h.P()
+	h.P("// Values match rpcruntime.RegistryEventKind; see Ygrpc_SetRegistryWatcher.")
+	h.P("typedef enum {")
+	h.P("    YGRPC_REGISTRY_REGISTERED = 1,")
+	h.P("    YGRPC_REGISTRY_REPLACED = 2,")
+	h.P("    YGRPC_REGISTRY_UNREGISTERED = 3,")
+	h.P("} YgrpcRegistryEventKind;")
+	h.P()
+	h.P("// protocol is a YgrpcProtocol value; service_name is only valid for the duration of the callback.")
+	h.P("typedef void (*OnRegistryEventFunc)(int kind, int protocol, const char* service_name, int service_name_len);")
+	h.P()
+	h.P(
+		"static inline void call_on_registry_event(void* fn, int kind, int protocol, const char* service_name, int service_name_len) {",
+	)
+	h.P("    if(fn) ((OnRegistryEventFunc)fn)(kind, protocol, service_name, service_name_len);")
+	h.P("}")
+	h.P()
 	h.P("#endif")
 
 	return h
@@ -81,8 +146,11 @@ func generateMainFile(gen *protogen.Plugin) *protogen.GeneratedFile {
 	g.P()
 
 	g.P("import (")
+	g.P("    \"context\"")
+	g.P("    \"strings\"")
+	g.P("    \"sync\"")
+	g.P("    \"time\"")
 	g.P("    \"unsafe\"")
-	g.P("    \"github.com/ygrpc/rpccgo/rpcruntime\"")
 	g.P(")")
 	g.P()
 
@@ -92,25 +160,98 @@ func generateMainFile(gen *protogen.Plugin) *protogen.GeneratedFile {
 	g.P("}")
 	g.P()
 
+	g.P("// Ygrpc_NewRuntime creates an isolated Runtime that runs the initializers of")
+	g.P("// the default Runtime when passed to Ygrpc_Init, and stores its handle in")
+	g.P("// outRuntime. Ygrpc_Shutdown releases the handle.")
+	g.P("//export Ygrpc_NewRuntime")
+	g.P("func Ygrpc_NewRuntime(outRuntime *uint64) uint64 {")
+	g.P("    rt := ", g.QualifiedGoIdent(rpcRuntimePkg.Ident("NewRuntimeFrom")), "(rpcruntime.Default())")
+	g.P("    *outRuntime = uint64(rt.Handle())")
+	g.P("    return 0")
+	g.P("}")
+	g.P()
+
+	g.P("//export Ygrpc_Init")
+	g.P("func Ygrpc_Init(rtHandle uint64, configPtr unsafe.Pointer, configLen int) uint64 {")
+	generateRuntimeLookup(g, "uint64")
+	g.P("    var config []byte")
+	g.P("    if configPtr != nil && configLen > 0 {")
+	g.P("        config = C.GoBytes(configPtr, C.int(configLen))")
+	g.P("    }")
+	g.P("    if err := rt.Init(context.Background(), config); err != nil {")
+	g.P("        return uint64(rpcruntime.StoreLastError(err))")
+	g.P("    }")
+	g.P("    return 0")
+	g.P("}")
+	g.P()
+
 	g.P("//export Ygrpc_SetProtocol")
-	g.P("func Ygrpc_SetProtocol(protocol int) uint64 {")
+	g.P("func Ygrpc_SetProtocol(rtHandle uint64, protocol int) uint64 {")
+	generateRuntimeLookup(g, "uint64")
 	g.P("    switch protocol {")
 	g.P("    case 0:")
-	g.P("        rpcruntime.ClearDefaultProtocol()")
+	g.P("        rt.ClearDefaultProtocol()")
 	g.P("        return 0")
 	g.P("    case 1:")
-	g.P("        if err := rpcruntime.SetDefaultProtocol(rpcruntime.ProtocolGrpc); err != nil {")
-	g.P("            return uint64(rpcruntime.StoreError(err))")
+	g.P("        if err := rt.SetDefaultProtocol(rpcruntime.ProtocolGrpc); err != nil {")
+	g.P("            return uint64(rpcruntime.StoreLastError(err))")
 	g.P("        }")
 	g.P("        return 0")
 	g.P("    case 2:")
-	g.P("        if err := rpcruntime.SetDefaultProtocol(rpcruntime.ProtocolConnectRPC); err != nil {")
-	g.P("            return uint64(rpcruntime.StoreError(err))")
+	g.P("        if err := rt.SetDefaultProtocol(rpcruntime.ProtocolConnectRPC); err != nil {")
+	g.P("            return uint64(rpcruntime.StoreLastError(err))")
+	g.P("        }")
+	g.P("        return 0")
+	g.P("    case 3:")
+	g.P("        if err := rt.SetDefaultProtocol(rpcruntime.ProtocolGo); err != nil {")
+	g.P("            return uint64(rpcruntime.StoreLastError(err))")
+	g.P("        }")
+	g.P("        return 0")
+	g.P("    case 4:")
+	g.P("        if err := rt.SetDefaultProtocol(rpcruntime.ProtocolTwirp); err != nil {")
+	g.P("            return uint64(rpcruntime.StoreLastError(err))")
 	g.P("        }")
 	g.P("        return 0")
 	g.P("    default:")
-	g.P("        return uint64(rpcruntime.StoreError(rpcruntime.ErrUnknownProtocol))")
+	g.P("        return uint64(rpcruntime.StoreLastError(rpcruntime.ErrUnknownProtocol))")
+	g.P("    }")
+	g.P("}")
+	g.P()
+
+	g.P("//export Ygrpc_SetProtocolPreference")
+	g.P(
+		"func Ygrpc_SetProtocolPreference(rtHandle uint64, target *C.char, targetLen C.int, protocols *C.int, protocolsLen C.int) uint64 {",
+	)
+	generateRuntimeLookup(g, "uint64")
+	g.P("    var order []rpcruntime.Protocol")
+	g.P("    if protocols != nil && protocolsLen > 0 {")
+	g.P("        for _, p := range unsafe.Slice(protocols, int(protocolsLen)) {")
+	g.P("            switch p {")
+	g.P("            case 1:")
+	g.P("                order = append(order, rpcruntime.ProtocolGrpc)")
+	g.P("            case 2:")
+	g.P("                order = append(order, rpcruntime.ProtocolConnectRPC)")
+	g.P("            case 3:")
+	g.P("                order = append(order, rpcruntime.ProtocolGo)")
+	g.P("            case 4:")
+	g.P("                order = append(order, rpcruntime.ProtocolTwirp)")
+	g.P("            default:")
+	g.P("                return uint64(rpcruntime.StoreLastError(rpcruntime.ErrUnknownProtocol))")
+	g.P("            }")
+	g.P("        }")
+	g.P("    }")
+	g.P("    var err error")
+	g.P("    if target == nil || targetLen <= 0 {")
+	g.P("        err = rt.SetProtocolPreference(order...)")
+	g.P("    } else if name := C.GoStringN(target, targetLen); strings.HasPrefix(name, \"/\") {")
+	g.P("        err = rt.SetMethodProtocolPreference(name, order...)")
+	g.P("    } else {")
+	g.P("        err = rt.SetServiceProtocolPreference(name, order...)")
 	g.P("    }")
+	g.P("    if err != nil {")
+	g.P("        return uint64(rpcruntime.StoreLastError(err))")
+	g.P("    }")
+	g.P("    return 0")
 	g.P("}")
 	g.P()
 
@@ -136,6 +277,139 @@ func generateMainFile(gen *protogen.Plugin) *protogen.GeneratedFile {
 	g.P("}")
 	g.P()
 
+	g.P("//export Ygrpc_ErrorIs")
+	g.P("func Ygrpc_ErrorIs(errorID uint64, kind int) int {")
+	g.P("    if rpcruntime.ErrorIs(errorID, rpcruntime.ErrorKind(kind)) {")
+	g.P("        return 1")
+	g.P("    }")
+	g.P("    return 0")
+	g.P("}")
+	g.P()
+
+	g.P("//export Ygrpc_SetLogCallback")
+	g.P("func Ygrpc_SetLogCallback(level int, fn unsafe.Pointer) {")
+	g.P("    if fn == nil {")
+	g.P("        rpcruntime.SetLogSink(0, nil)")
+	g.P("        return")
+	g.P("    }")
+	g.P("    rpcruntime.SetLogSink(rpcruntime.LogLevel(level), func(level rpcruntime.LogLevel, msg string) {")
+	g.P("        cmsg := C.CString(msg)")
+	g.P("        C.call_on_log(fn, C.int(level), cmsg, C.int(len(msg)))")
+	g.P("        C.free(unsafe.Pointer(cmsg))")
+	g.P("    })")
+	g.P("}")
+	g.P()
+
+	g.P("//export Ygrpc_SetErrorHook")
+	g.P("func Ygrpc_SetErrorHook(fn unsafe.Pointer) {")
+	g.P("    if fn == nil {")
+	g.P("        rpcruntime.SetErrorHook(nil)")
+	g.P("        return")
+	g.P("    }")
+	g.P("    rpcruntime.SetErrorHook(func(errorID uint64, msg string) {")
+	g.P("        cmsg := C.CString(msg)")
+	g.P("        C.call_on_error(fn, C.uint64_t(errorID), cmsg, C.int(len(msg)))")
+	g.P("        C.free(unsafe.Pointer(cmsg))")
+	g.P("    })")
+	g.P("}")
+	g.P()
+
+	g.P("// registryWatcher is the watcher installed for one runtime handle.")
+	g.P("type registryWatcher struct {")
+	g.P("    gen    uint64")
+	g.P("    cancel func()")
+	g.P("}")
+	g.P()
+	g.P("// registryWatcherMu guards the installed watchers; it is not held while a")
+	g.P("// watcher runs, so the C callback may call back into the library.")
+	g.P("var (")
+	g.P("    registryWatcherMu  sync.Mutex")
+	g.P("    registryWatchers   = make(map[uint64]registryWatcher)")
+	g.P("    registryWatcherGen uint64")
+	g.P(")")
+	g.P()
+
+	g.P("//export Ygrpc_SetRegistryWatcher")
+	g.P("func Ygrpc_SetRegistryWatcher(rtHandle uint64, fn unsafe.Pointer) uint64 {")
+	g.P("    registryWatcherMu.Lock()")
+	g.P("    registryWatcherGen++")
+	g.P("    gen := registryWatcherGen")
+	g.P("    prev := registryWatchers[rtHandle]")
+	g.P("    if fn == nil {")
+	g.P("        delete(registryWatchers, rtHandle)")
+	g.P("    } else {")
+	g.P("        registryWatchers[rtHandle] = registryWatcher{gen: gen}")
+	g.P("    }")
+	g.P("    registryWatcherMu.Unlock()")
+	g.P("    if prev.cancel != nil {")
+	g.P("        prev.cancel()")
+	g.P("    }")
+	g.P("    if fn == nil {")
+	g.P("        return 0")
+	g.P("    }")
+	generateRuntimeLookup(g, "uint64")
+	g.P("    cancel := rt.WatchRegistry(func(event rpcruntime.RegistryEvent) {")
+	g.P("        protocol := 0")
+	g.P("        switch event.Protocol {")
+	g.P("        case rpcruntime.ProtocolGrpc:")
+	g.P("            protocol = 1")
+	g.P("        case rpcruntime.ProtocolConnectRPC:")
+	g.P("            protocol = 2")
+	g.P("        case rpcruntime.ProtocolGo:")
+	g.P("            protocol = 3")
+	g.P("        case rpcruntime.ProtocolTwirp:")
+	g.P("            protocol = 4")
+	g.P("        }")
+	g.P("        cname := C.CString(event.ServiceName)")
+	g.P("        C.call_on_registry_event(fn, C.int(event.Kind), C.int(protocol), cname, C.int(len(event.ServiceName)))")
+	g.P("        C.free(unsafe.Pointer(cname))")
+	g.P("    })")
+	g.P()
+	g.P("    // A later call may have replaced fn while its initial events were delivered.")
+	g.P("    registryWatcherMu.Lock()")
+	g.P("    if registryWatchers[rtHandle].gen != gen {")
+	g.P("        registryWatcherMu.Unlock()")
+	g.P("        cancel()")
+	g.P("        return 0")
+	g.P("    }")
+	g.P("    registryWatchers[rtHandle] = registryWatcher{gen: gen, cancel: cancel}")
+	g.P("    registryWatcherMu.Unlock()")
+	g.P("    return 0")
+	g.P("}")
+	g.P()
+
+	g.P("// Ygrpc_Shutdown rejects new calls, waits up to timeoutMs milliseconds for")
+	g.P("// in-flight calls and open streams to finish and cancels the rest.")
+	g.P("// A timeoutMs <= 0 waits without a deadline. The handle of a Runtime created")
+	g.P("// with Ygrpc_NewRuntime is released.")
+	g.P("//export Ygrpc_Shutdown")
+	g.P("func Ygrpc_Shutdown(rtHandle uint64, timeoutMs int) uint64 {")
+	generateRuntimeLookup(g, "uint64")
+	g.P("    ctx := context.Background()")
+	g.P("    if timeoutMs > 0 {")
+	g.P("        var cancel context.CancelFunc")
+	g.P("        ctx, cancel = context.WithTimeout(ctx, time.Duration(timeoutMs)*time.Millisecond)")
+	g.P("        defer cancel()")
+	g.P("    }")
+	g.P("    if err := rt.Shutdown(ctx); err != nil {")
+	g.P("        return uint64(rpcruntime.StoreLastError(err))")
+	g.P("    }")
+	g.P("    return 0")
+	g.P("}")
+	g.P()
+
+	g.P("//export Ygrpc_GetLastError")
+	g.P("func Ygrpc_GetLastError() uint64 {")
+	g.P("    return rpcruntime.LastError()")
+	g.P("}")
+	g.P()
+
+	g.P("//export Ygrpc_ClearLastError")
+	g.P("func Ygrpc_ClearLastError() {")
+	g.P("    rpcruntime.ClearLastError()")
+	g.P("}")
+	g.P()
+
 	// 2. Generate main.go (Pure Go entry point)
 	gm := gen.NewGeneratedFile("main.go", "")
 	gm.P("// Code generated by protoc-gen-rpc-cgo. DO NOT EDIT.")
@@ -147,6 +421,21 @@ func generateMainFile(gen *protogen.Plugin) *protogen.GeneratedFile {
 	return g
 }
 
+// generateRuntimeLookup emits the lookup of the Runtime selected by the
+// rtHandle parameter into rt. retType is the export's return type; onErr lines
+// run before the error is returned.
+func generateRuntimeLookup(g *protogen.GeneratedFile, retType string, onErr ...string) {
+	g.P("    rt, ok := ", g.QualifiedGoIdent(rpcRuntimePkg.Ident("RuntimeFromHandle")), "(",
+		g.QualifiedGoIdent(rpcRuntimePkg.Ident("RuntimeHandle")), "(rtHandle))")
+	g.P("    if !ok {")
+	for _, line := range onErr {
+		g.P("        ", line)
+	}
+	g.P("        return ", retType, "(", g.QualifiedGoIdent(rpcRuntimePkg.Ident("StoreLastError")), "(",
+		g.QualifiedGoIdent(rpcRuntimePkg.Ident("ErrInvalidRuntimeHandle")), "))")
+	g.P("    }")
+}
+
 func generateCgoFile(gen *protogen.Plugin, file *protogen.File) *protogen.GeneratedFile {
 	filename := file.GeneratedFilenamePrefix + "_cgo.go"
 	g := gen.NewGeneratedFile(filename, "")
@@ -70,12 +70,13 @@ func generateClientStreamingMethod(
 
 func generateClientStreamStart(g *protogen.GeneratedFile, funcName string, adaptorStart string) {
 	g.P("//export ", funcName)
-	g.P("func ", funcName, "(outHandle *uint64) uint64 {")
-	g.P("    ctx := ", g.QualifiedGoIdent(rpcRuntimePkg.Ident("BackgroundContext")), "()")
+	g.P("func ", funcName, "(rtHandle uint64, outHandle *uint64) uint64 {")
+	generateRuntimeLookup(g, "uint64", "*outHandle = 0")
+	g.P("    ctx := rt.BackgroundContext()")
 	g.P("    handle, err := ", adaptorStart, "(ctx)")
 	g.P("    if err != nil {")
 	g.P("        *outHandle = 0")
-	g.P("        return uint64(", g.QualifiedGoIdent(rpcRuntimePkg.Ident("StoreError")), "(err))")
+	g.P("        return uint64(", g.QualifiedGoIdent(rpcRuntimePkg.Ident("StoreLastError")), "(err))")
 	g.P("    }")
 	g.P("    *outHandle = uint64(handle)")
 	g.P("    return 0")
@@ -93,10 +94,10 @@ func generateClientStreamSendBinary(g *protogen.GeneratedFile, funcName string,
 	g.P("    reqBytes := C.GoBytes(reqPtr, reqLen)")
 	g.P("    req := &", reqType, "{}")
 	g.P("    if err := ", g.QualifiedGoIdent(protoPackage.Ident("Unmarshal")), "(reqBytes, req); err != nil {")
-	g.P("        return C.uint64_t(", g.QualifiedGoIdent(rpcRuntimePkg.Ident("StoreError")), "(err))")
+	g.P("        return C.uint64_t(", g.QualifiedGoIdent(rpcRuntimePkg.Ident("StoreLastError")), "(err))")
 	g.P("    }")
 	g.P("    if err := ", adaptorSend, "(uint64(streamHandle), req); err != nil {")
-	g.P("        return C.uint64_t(", g.QualifiedGoIdent(rpcRuntimePkg.Ident("StoreError")), "(err))")
+	g.P("        return C.uint64_t(", g.QualifiedGoIdent(rpcRuntimePkg.Ident("StoreLastError")), "(err))")
 	g.P("    }")
 	g.P("    return 0")
 	g.P("}")
@@ -122,10 +123,10 @@ func generateClientStreamSendBinaryTakeReq(
 	g.P("    }")
 	g.P("    req := &", reqType, "{}")
 	g.P("    if err := ", g.QualifiedGoIdent(protoPackage.Ident("Unmarshal")), "(reqBytes, req); err != nil {")
-	g.P("        return C.uint64_t(", g.QualifiedGoIdent(rpcRuntimePkg.Ident("StoreError")), "(err))")
+	g.P("        return C.uint64_t(", g.QualifiedGoIdent(rpcRuntimePkg.Ident("StoreLastError")), "(err))")
 	g.P("    }")
 	g.P("    if err := ", adaptorSend, "(uint64(streamHandle), req); err != nil {")
-	g.P("        return C.uint64_t(", g.QualifiedGoIdent(rpcRuntimePkg.Ident("StoreError")), "(err))")
+	g.P("        return C.uint64_t(", g.QualifiedGoIdent(rpcRuntimePkg.Ident("StoreLastError")), "(err))")
 	g.P("    }")
 	g.P("    return 0")
 	g.P("}")
@@ -146,7 +147,7 @@ func generateClientStreamFinishBinary(
 	g.P(") uint64 {")
 	g.P("    resp, err := ", adaptorFinish, "(uint64(streamHandle))")
 	g.P("    if err != nil {")
-	g.P("        return uint64(", g.QualifiedGoIdent(rpcRuntimePkg.Ident("StoreError")), "(err))")
+	g.P("        return uint64(", g.QualifiedGoIdent(rpcRuntimePkg.Ident("StoreLastError")), "(err))")
 	g.P("    }")
 	g.P("    if resp == nil {")
 	g.P("        *respPtr = nil")
@@ -156,7 +157,7 @@ func generateClientStreamFinishBinary(
 	g.P("    }")
 	g.P("    respBytes, err := ", g.QualifiedGoIdent(protoPackage.Ident("Marshal")), "(resp)")
 	g.P("    if err != nil {")
-	g.P("        return uint64(", g.QualifiedGoIdent(rpcRuntimePkg.Ident("StoreError")), "(err))")
+	g.P("        return uint64(", g.QualifiedGoIdent(rpcRuntimePkg.Ident("StoreLastError")), "(err))")
 	g.P("    }")
 	g.P("    buf := C.CBytes(respBytes)")
 	g.P("    *respPtr = buf")
@@ -182,7 +183,7 @@ func generateClientStreamSendNative(
 	g.P("    req := &", reqType, "{}")
 	generateNativeReqAssignments(g, reqMsg)
 	g.P("    if err := ", adaptorSend, "(uint64(streamHandle), req); err != nil {")
-	g.P("        return C.uint64_t(", g.QualifiedGoIdent(rpcRuntimePkg.Ident("StoreError")), "(err))")
+	g.P("        return C.uint64_t(", g.QualifiedGoIdent(rpcRuntimePkg.Ident("StoreLastError")), "(err))")
 	g.P("    }")
 	g.P("    return 0")
 	g.P("}")
@@ -204,7 +205,7 @@ func generateClientStreamSendNativeTakeReq(
 	g.P("    req := &", reqType, "{}")
 	generateNativeReqAssignmentsTakeReq(g, reqMsg)
 	g.P("    if err := ", adaptorSend, "(uint64(streamHandle), req); err != nil {")
-	g.P("        return C.uint64_t(", g.QualifiedGoIdent(rpcRuntimePkg.Ident("StoreError")), "(err))")
+	g.P("        return C.uint64_t(", g.QualifiedGoIdent(rpcRuntimePkg.Ident("StoreLastError")), "(err))")
 	g.P("    }")
 	g.P("    return 0")
 	g.P("}")
@@ -225,7 +226,7 @@ func generateClientStreamFinishNative(
 	g.P(") C.uint64_t {")
 	g.P("    resp, err := ", adaptorFinish, "(uint64(streamHandle))")
 	g.P("    if err != nil {")
-	g.P("        return C.uint64_t(", g.QualifiedGoIdent(rpcRuntimePkg.Ident("StoreError")), "(err))")
+	g.P("        return C.uint64_t(", g.QualifiedGoIdent(rpcRuntimePkg.Ident("StoreLastError")), "(err))")
 	g.P("    }")
 	g.P("    if resp == nil {")
 	g.P("        resp = &", respType, "{}")
@@ -300,6 +301,7 @@ func generateServerStreamBinary(
 ) {
 	g.P("//export ", funcName)
 	g.P("func ", funcName, "(")
+	g.P("    rtHandle uint64,")
 	g.P("    reqPtr ", g.QualifiedGoIdent(unsafePackage.Ident("Pointer")), ",")
 	g.P("    reqLen int,")
 	g.P("    onReadBytes unsafe.Pointer,")
@@ -309,9 +311,10 @@ func generateServerStreamBinary(
 	g.P("    reqBytes := unsafe.Slice((*byte)(reqPtr), reqLen)")
 	g.P("    req := &", reqType, "{}")
 	g.P("    if err := ", g.QualifiedGoIdent(protoPackage.Ident("Unmarshal")), "(reqBytes, req); err != nil {")
-	g.P("        return uint64(", g.QualifiedGoIdent(rpcRuntimePkg.Ident("StoreError")), "(err))")
+	g.P("        return uint64(", g.QualifiedGoIdent(rpcRuntimePkg.Ident("StoreLastError")), "(err))")
 	g.P("    }")
-	g.P("    ctx := ", g.QualifiedGoIdent(rpcRuntimePkg.Ident("BackgroundContext")), "()")
+	generateRuntimeLookup(g, "uint64")
+	g.P("    ctx := rt.BackgroundContext()")
 	g.P("    var doneErrId ", g.QualifiedGoIdent(syncAtomicPkg.Ident("Uint64")))
 	g.P("    onRead := func(resp *", respType, ") bool {")
 	g.P("        respBytes, err := ", g.QualifiedGoIdent(protoPackage.Ident("Marshal")), "(resp)")
@@ -337,6 +340,7 @@ func generateServerStreamBinary(
 	g.P("        if doneErrId.Load() == 0 {")
 	g.P("            doneErrId.Store(", g.QualifiedGoIdent(rpcRuntimePkg.Ident("StoreError")), "(err))")
 	g.P("        }")
+	g.P("        ", g.QualifiedGoIdent(rpcRuntimePkg.Ident("SetLastError")), "(doneErrId.Load())")
 	g.P("        return uint64(doneErrId.Load())")
 	g.P("    }")
 	g.P("    return 0")
@@ -353,6 +357,7 @@ func generateServerStreamBinaryTakeReq(
 ) {
 	g.P("//export ", funcName)
 	g.P("func ", funcName, "(")
+	g.P("    rtHandle uint64,")
 	g.P("    reqPtr ", g.QualifiedGoIdent(unsafePackage.Ident("Pointer")), ",")
 	g.P("    reqLen int,")
 	g.P("    reqFree unsafe.Pointer,")
@@ -366,12 +371,13 @@ func generateServerStreamBinaryTakeReq(
 	g.P("        if reqFree != nil {")
 	g.P("            C.call_free_func((C.FreeFunc)(reqFree), reqPtr)")
 	g.P("        }")
-	g.P("        return uint64(", g.QualifiedGoIdent(rpcRuntimePkg.Ident("StoreError")), "(err))")
+	g.P("        return uint64(", g.QualifiedGoIdent(rpcRuntimePkg.Ident("StoreLastError")), "(err))")
 	g.P("    }")
 	g.P("    if reqFree != nil {")
 	g.P("        C.call_free_func((C.FreeFunc)(reqFree), reqPtr)")
 	g.P("    }")
-	g.P("    ctx := ", g.QualifiedGoIdent(rpcRuntimePkg.Ident("BackgroundContext")), "()")
+	generateRuntimeLookup(g, "uint64")
+	g.P("    ctx := rt.BackgroundContext()")
 	g.P("    var doneErrId ", g.QualifiedGoIdent(syncAtomicPkg.Ident("Uint64")))
 	g.P("    onRead := func(resp *", respType, ") bool {")
 	g.P("        respBytes, err := ", g.QualifiedGoIdent(protoPackage.Ident("Marshal")), "(resp)")
@@ -397,6 +403,7 @@ func generateServerStreamBinaryTakeReq(
 	g.P("        if doneErrId.Load() == 0 {")
 	g.P("            doneErrId.Store(", g.QualifiedGoIdent(rpcRuntimePkg.Ident("StoreError")), "(err))")
 	g.P("        }")
+	g.P("        ", g.QualifiedGoIdent(rpcRuntimePkg.Ident("SetLastError")), "(doneErrId.Load())")
 	g.P("        return uint64(doneErrId.Load())")
 	g.P("    }")
 	g.P("    return 0")
@@ -417,6 +424,7 @@ func generateServerStreamNative(
 
 	g.P("//export ", funcName)
 	g.P("func ", funcName, "(")
+	g.P("    rtHandle uint64,")
 	generateNativeReqParams(g, reqMsg)
 	g.P("    onReadNative unsafe.Pointer,")
 	g.P("    onDone unsafe.Pointer,")
@@ -425,7 +433,8 @@ func generateServerStreamNative(
 	g.P("    req := &", reqType, "{}")
 	generateNativeReqAssignments(g, reqMsg)
 
-	g.P("    ctx := ", g.QualifiedGoIdent(rpcRuntimePkg.Ident("BackgroundContext")), "()")
+	generateRuntimeLookup(g, "C.uint64_t")
+	g.P("    ctx := rt.BackgroundContext()")
 	g.P("    var doneErrId ", g.QualifiedGoIdent(syncAtomicPkg.Ident("Uint64")))
 	g.P("    onRead := func(resp *", respType, ") bool {")
 	g.P("        ")
@@ -498,6 +507,7 @@ func generateServerStreamNative(
 	g.P("        if doneErrId.Load() == 0 {")
 	g.P("            doneErrId.Store(", g.QualifiedGoIdent(rpcRuntimePkg.Ident("StoreError")), "(err))")
 	g.P("        }")
+	g.P("        ", g.QualifiedGoIdent(rpcRuntimePkg.Ident("SetLastError")), "(doneErrId.Load())")
 	g.P("        return C.uint64_t(doneErrId.Load())")
 	g.P("    }")
 	g.P("    return 0")
@@ -518,6 +528,7 @@ func generateServerStreamNativeTakeReq(
 
 	g.P("//export ", funcName)
 	g.P("func ", funcName, "(")
+	g.P("    rtHandle uint64,")
 	generateNativeReqParamsTakeReq(g, reqMsg)
 	g.P("    onReadNative unsafe.Pointer,")
 	g.P("    onDone unsafe.Pointer,")
@@ -526,7 +537,8 @@ func generateServerStreamNativeTakeReq(
 	g.P("    req := &", reqType, "{}")
 	generateNativeReqAssignmentsTakeReq(g, reqMsg)
 
-	g.P("    ctx := ", g.QualifiedGoIdent(rpcRuntimePkg.Ident("BackgroundContext")), "()")
+	generateRuntimeLookup(g, "C.uint64_t")
+	g.P("    ctx := rt.BackgroundContext()")
 	g.P("    var doneErrId ", g.QualifiedGoIdent(syncAtomicPkg.Ident("Uint64")))
 	g.P("    onRead := func(resp *", respType, ") bool {")
 	fields := sortedFieldsByNumber(respMsg)
@@ -596,6 +608,7 @@ func generateServerStreamNativeTakeReq(
 	g.P("        if doneErrId.Load() == 0 {")
 	g.P("            doneErrId.Store(", g.QualifiedGoIdent(rpcRuntimePkg.Ident("StoreError")), "(err))")
 	g.P("        }")
+	g.P("        ", g.QualifiedGoIdent(rpcRuntimePkg.Ident("SetLastError")), "(doneErrId.Load())")
 	g.P("        return C.uint64_t(doneErrId.Load())")
 	g.P("    }")
 	g.P("    return 0")
@@ -647,11 +660,13 @@ func generateBidiStreamingMethod(
 func generateBidiStartBinary(g *protogen.GeneratedFile, funcName string, respType string, adaptorStart string) {
 	g.P("//export ", funcName)
 	g.P("func ", funcName, "(")
+	g.P("    rtHandle uint64,")
 	g.P("    onReadBytes unsafe.Pointer,")
 	g.P("    onDone unsafe.Pointer,")
 	g.P("    outHandle *uint64,")
 	g.P(") uint64 {")
-	g.P("    ctx := ", g.QualifiedGoIdent(rpcRuntimePkg.Ident("BackgroundContext")), "()")
+	generateRuntimeLookup(g, "uint64", "*outHandle = 0")
+	g.P("    ctx := rt.BackgroundContext()")
 	g.P("    handleReady := make(chan struct{})")
 	g.P("    var streamHandle uint64")
 	g.P("    onRead := func(resp *", respType, ") bool {")
@@ -677,7 +692,7 @@ func generateBidiStartBinary(g *protogen.GeneratedFile, funcName string, respTyp
 	g.P("    handle, err := ", adaptorStart, "(ctx, onRead, onDoneFunc)")
 	g.P("    if err != nil {")
 	g.P("        *outHandle = 0")
-	g.P("        return uint64(", g.QualifiedGoIdent(rpcRuntimePkg.Ident("StoreError")), "(err))")
+	g.P("        return uint64(", g.QualifiedGoIdent(rpcRuntimePkg.Ident("StoreLastError")), "(err))")
 	g.P("    }")
 	g.P("    streamHandle = handle")
 	g.P("    close(handleReady)")
@@ -697,10 +712,10 @@ func generateBidiSendBinary(g *protogen.GeneratedFile, funcName string, reqType
 	g.P("    reqBytes := unsafe.Slice((*byte)(reqPtr), reqLen)")
 	g.P("    req := &", reqType, "{}")
 	g.P("    if err := ", g.QualifiedGoIdent(protoPackage.Ident("Unmarshal")), "(reqBytes, req); err != nil {")
-	g.P("        return uint64(", g.QualifiedGoIdent(rpcRuntimePkg.Ident("StoreError")), "(err))")
+	g.P("        return uint64(", g.QualifiedGoIdent(rpcRuntimePkg.Ident("StoreLastError")), "(err))")
 	g.P("    }")
 	g.P("    if err := ", adaptorSend, "(uint64(streamHandle), req); err != nil {")
-	g.P("        return uint64(", g.QualifiedGoIdent(rpcRuntimePkg.Ident("StoreError")), "(err))")
+	g.P("        return uint64(", g.QualifiedGoIdent(rpcRuntimePkg.Ident("StoreLastError")), "(err))")
 	g.P("    }")
 	g.P("    return 0")
 	g.P("}")
@@ -721,13 +736,13 @@ func generateBidiSendBinaryTakeReq(g *protogen.GeneratedFile, funcName string, r
 	g.P("        if reqFree != nil {")
 	g.P("            C.call_free_func((C.FreeFunc)(reqFree), reqPtr)")
 	g.P("        }")
-	g.P("        return uint64(", g.QualifiedGoIdent(rpcRuntimePkg.Ident("StoreError")), "(err))")
+	g.P("        return uint64(", g.QualifiedGoIdent(rpcRuntimePkg.Ident("StoreLastError")), "(err))")
 	g.P("    }")
 	g.P("    if reqFree != nil {")
 	g.P("        C.call_free_func((C.FreeFunc)(reqFree), reqPtr)")
 	g.P("    }")
 	g.P("    if err := ", adaptorSend, "(uint64(streamHandle), req); err != nil {")
-	g.P("        return uint64(", g.QualifiedGoIdent(rpcRuntimePkg.Ident("StoreError")), "(err))")
+	g.P("        return uint64(", g.QualifiedGoIdent(rpcRuntimePkg.Ident("StoreLastError")), "(err))")
 	g.P("    }")
 	g.P("    return 0")
 	g.P("}")
@@ -738,7 +753,7 @@ func generateBidiCloseSend(g *protogen.GeneratedFile, funcName string, adaptorCl
 	g.P("//export ", funcName)
 	g.P("func ", funcName, "(streamHandle uint64) uint64 {")
 	g.P("    if err := ", adaptorCloseSend, "(uint64(streamHandle)); err != nil {")
-	g.P("        return uint64(", g.QualifiedGoIdent(rpcRuntimePkg.Ident("StoreError")), "(err))")
+	g.P("        return uint64(", g.QualifiedGoIdent(rpcRuntimePkg.Ident("StoreLastError")), "(err))")
 	g.P("    }")
 	g.P("    return 0")
 	g.P("}")
@@ -756,11 +771,13 @@ func generateBidiStartNative(
 
 	g.P("//export ", funcName)
 	g.P("func ", funcName, "(")
+	g.P("    rtHandle uint64,")
 	g.P("    onReadNative unsafe.Pointer,")
 	g.P("    onDone unsafe.Pointer,")
 	g.P("    outHandle *C.uint64_t,")
 	g.P(") C.uint64_t {")
-	g.P("    ctx := ", g.QualifiedGoIdent(rpcRuntimePkg.Ident("BackgroundContext")), "()")
+	generateRuntimeLookup(g, "C.uint64_t", "*outHandle = 0")
+	g.P("    ctx := rt.BackgroundContext()")
 	g.P("    handleReady := make(chan struct{})")
 	g.P("    var streamHandle uint64")
 	g.P("    onRead := func(resp *", respType, ") bool {")
@@ -830,7 +847,7 @@ func generateBidiStartNative(
 	g.P("    handle, err := ", adaptorStart, "(ctx, onRead, onDoneFunc)")
 	g.P("    if err != nil {")
 	g.P("        *outHandle = 0")
-	g.P("        return C.uint64_t(", g.QualifiedGoIdent(rpcRuntimePkg.Ident("StoreError")), "(err))")
+	g.P("        return C.uint64_t(", g.QualifiedGoIdent(rpcRuntimePkg.Ident("StoreLastError")), "(err))")
 	g.P("    }")
 	g.P("    streamHandle = handle")
 	g.P("    close(handleReady)")
@@ -855,7 +872,7 @@ func generateBidiSendNative(
 	g.P("    req := &", reqType, "{}")
 	generateNativeReqAssignments(g, reqMsg)
 	g.P("    if err := ", adaptorSend, "(uint64(streamHandle), req); err != nil {")
-	g.P("        return C.uint64_t(", g.QualifiedGoIdent(rpcRuntimePkg.Ident("StoreError")), "(err))")
+	g.P("        return C.uint64_t(", g.QualifiedGoIdent(rpcRuntimePkg.Ident("StoreLastError")), "(err))")
 	g.P("    }")
 	g.P("    return 0")
 	g.P("}")
@@ -877,7 +894,7 @@ func generateBidiSendNativeTakeReq(
 	g.P("    req := &", reqType, "{}")
 	generateNativeReqAssignmentsTakeReq(g, reqMsg)
 	g.P("    if err := ", adaptorSend, "(uint64(streamHandle), req); err != nil {")
-	g.P("        return C.uint64_t(", g.QualifiedGoIdent(rpcRuntimePkg.Ident("StoreError")), "(err))")
+	g.P("        return C.uint64_t(", g.QualifiedGoIdent(rpcRuntimePkg.Ident("StoreLastError")), "(err))")
 	g.P("    }")
 	g.P("    return 0")
 	g.P("}")
@@ -50,6 +50,7 @@ func generateUnaryBinary(
 ) {
 	g.P("//export ", abiPrefix)
 	g.P("func ", abiPrefix, "(")
+	g.P("    rtHandle uint64,")
 	g.P("    reqPtr ", g.QualifiedGoIdent(unsafePackage.Ident("Pointer")), ",")
 	g.P("    reqLen int,")
 	g.P("    respPtr *", g.QualifiedGoIdent(unsafePackage.Ident("Pointer")), ",")
@@ -59,18 +60,19 @@ func generateUnaryBinary(
 	g.P("    reqBytes := unsafe.Slice((*byte)(reqPtr), reqLen)")
 	g.P("    req := &", reqType, "{}")
 	g.P("    if err := ", g.QualifiedGoIdent(protoPackage.Ident("Unmarshal")), "(reqBytes, req); err != nil {")
-	g.P("        return uint64(", g.QualifiedGoIdent(rpcRuntimePkg.Ident("StoreError")), "(err))")
+	g.P("        return uint64(", g.QualifiedGoIdent(rpcRuntimePkg.Ident("StoreLastError")), "(err))")
 	g.P("    }")
 	g.P()
-	g.P("    ctx := ", g.QualifiedGoIdent(rpcRuntimePkg.Ident("BackgroundContext")), "()")
+	generateRuntimeLookup(g, "uint64")
+	g.P("    ctx := rt.BackgroundContext()")
 	g.P("    resp, err := ", adaptorCall, "(ctx, req)")
 	g.P("    if err != nil {")
-	g.P("        return uint64(", g.QualifiedGoIdent(rpcRuntimePkg.Ident("StoreError")), "(err))")
+	g.P("        return uint64(", g.QualifiedGoIdent(rpcRuntimePkg.Ident("StoreLastError")), "(err))")
 	g.P("    }")
 	g.P()
 	g.P("    respBytes, err := ", g.QualifiedGoIdent(protoPackage.Ident("Marshal")), "(resp)")
 	g.P("    if err != nil {")
-	g.P("        return uint64(", g.QualifiedGoIdent(rpcRuntimePkg.Ident("StoreError")), "(err))")
+	g.P("        return uint64(", g.QualifiedGoIdent(rpcRuntimePkg.Ident("StoreLastError")), "(err))")
 	g.P("    }")
 	g.P()
 	g.P("    buf := C.CBytes(respBytes)")
@@ -92,6 +94,7 @@ func generateUnaryBinaryTakeReq(
 
 	g.P("//export ", funcName)
 	g.P("func ", funcName, "(")
+	g.P("    rtHandle uint64,")
 	g.P("    reqPtr ", g.QualifiedGoIdent(unsafePackage.Ident("Pointer")), ",")
 	g.P("    reqLen int,")
 	g.P("    reqFree unsafe.Pointer,")
@@ -105,21 +108,22 @@ func generateUnaryBinaryTakeReq(
 	g.P("        if reqFree != nil {")
 	g.P("            C.call_free_func((C.FreeFunc)(reqFree), reqPtr)")
 	g.P("        }")
-	g.P("        return uint64(", g.QualifiedGoIdent(rpcRuntimePkg.Ident("StoreError")), "(err))")
+	g.P("        return uint64(", g.QualifiedGoIdent(rpcRuntimePkg.Ident("StoreLastError")), "(err))")
 	g.P("    }")
 	g.P("    if reqFree != nil {")
 	g.P("        C.call_free_func((C.FreeFunc)(reqFree), reqPtr)")
 	g.P("    }")
 	g.P()
-	g.P("    ctx := ", g.QualifiedGoIdent(rpcRuntimePkg.Ident("BackgroundContext")), "()")
+	generateRuntimeLookup(g, "uint64")
+	g.P("    ctx := rt.BackgroundContext()")
 	g.P("    resp, err := ", adaptorCall, "(ctx, req)")
 	g.P("    if err != nil {")
-	g.P("        return uint64(", g.QualifiedGoIdent(rpcRuntimePkg.Ident("StoreError")), "(err))")
+	g.P("        return uint64(", g.QualifiedGoIdent(rpcRuntimePkg.Ident("StoreLastError")), "(err))")
 	g.P("    }")
 	g.P()
 	g.P("    respBytes, err := ", g.QualifiedGoIdent(protoPackage.Ident("Marshal")), "(resp)")
 	g.P("    if err != nil {")
-	g.P("        return uint64(", g.QualifiedGoIdent(rpcRuntimePkg.Ident("StoreError")), "(err))")
+	g.P("        return uint64(", g.QualifiedGoIdent(rpcRuntimePkg.Ident("StoreLastError")), "(err))")
 	g.P("    }")
 	g.P()
 	g.P("    buf := C.CBytes(respBytes)")
@@ -143,6 +147,7 @@ func generateUnaryNative(
 
 	g.P("//export ", funcName)
 	g.P("func ", funcName, "(")
+	g.P("    rtHandle uint64,")
 
 	generateNativeReqParams(g, reqMsg)
 
@@ -154,10 +159,11 @@ func generateUnaryNative(
 	generateNativeReqAssignments(g, reqMsg)
 
 	g.P()
-	g.P("    ctx := ", g.QualifiedGoIdent(rpcRuntimePkg.Ident("BackgroundContext")), "()")
+	generateRuntimeLookup(g, "C.uint64_t")
+	g.P("    ctx := rt.BackgroundContext()")
 	g.P("    resp, err := ", adaptorCall, "(ctx, req)")
 	g.P("    if err != nil {")
-	g.P("        return C.uint64_t(", g.QualifiedGoIdent(rpcRuntimePkg.Ident("StoreError")), "(err))")
+	g.P("        return C.uint64_t(", g.QualifiedGoIdent(rpcRuntimePkg.Ident("StoreLastError")), "(err))")
 	g.P("    }")
 	g.P()
 
@@ -180,6 +186,7 @@ func generateUnaryNativeTakeReq(
 
 	g.P("//export ", funcName)
 	g.P("func ", funcName, "(")
+	g.P("    rtHandle uint64,")
 
 	generateNativeReqParamsTakeReq(g, reqMsg)
 
@@ -191,10 +198,11 @@ func generateUnaryNativeTakeReq(
 	generateNativeReqAssignmentsTakeReq(g, reqMsg)
 
 	g.P()
-	g.P("    ctx := ", g.QualifiedGoIdent(rpcRuntimePkg.Ident("BackgroundContext")), "()")
+	generateRuntimeLookup(g, "C.uint64_t")
+	g.P("    ctx := rt.BackgroundContext()")
 	g.P("    resp, err := ", adaptorCall, "(ctx, req)")
 	g.P("    if err != nil {")
-	g.P("        return C.uint64_t(", g.QualifiedGoIdent(rpcRuntimePkg.Ident("StoreError")), "(err))")
+	g.P("        return C.uint64_t(", g.QualifiedGoIdent(rpcRuntimePkg.Ident("StoreLastError")), "(err))")
 	g.P("    }")
 	g.P()
 
@@ -0,0 +1,134 @@
+package rpcruntime
+
+// RegisterAlias makes handler lookups for alias in the default Runtime answer
+// with the handlers registered for canonical. See Runtime.RegisterAlias.
+func RegisterAlias(alias, canonical string) (replaced bool, err error) {
+	return defaultRuntime.RegisterAlias(alias, canonical)
+}
+
+// RegisterAlias makes handler lookups for alias in rt answer with the handlers
+// registered for canonical, for every protocol. It lets adaptors generated
+// before and after a proto package rename, e.g. from acme.v1.Foo to
+// acme.foo.v1.Foo, share one registered handler.
+//
+// Calls through the alias share the concurrency and rate limits, protocol
+// preferences and remote (see RegisterRemote) of canonical; forwarded calls
+// are sent under the canonical name. Only a handler registered under alias
+// itself takes precedence over the alias. If canonical is itself an alias,
+// the new alias resolves to its canonical name. Aliases are not listed by
+// ListServices; see ListAliases.
+//
+// Returns replaced=true if an existing alias was overwritten.
+// Returns an error if either name is empty, ErrAliasCycle if canonical
+// resolves to alias, or ErrUnavailable once the Runtime has been shut down.
+func (rt *Runtime) RegisterAlias(alias, canonical string) (replaced bool, err error) {
+	if alias == "" || canonical == "" {
+		return false, ErrEmptyServiceName
+	}
+
+	rt.handlerMu.Lock()
+	defer rt.handlerMu.Unlock()
+	if rt.shutdown {
+		return false, ErrUnavailable
+	}
+	if target, ok := rt.aliases[canonical]; ok {
+		canonical = target
+	}
+	if canonical == alias {
+		return false, ErrAliasCycle
+	}
+	// Keep aliases one level deep so lookups resolve with a single step.
+	for name, target := range rt.aliases {
+		if target == alias {
+			rt.aliases[name] = canonical
+		}
+	}
+	_, replaced = rt.aliases[alias]
+	rt.aliases[alias] = canonical
+	if replaced {
+		logf(LogLevelWarn, "rpcruntime: replaced alias %s", alias)
+	}
+	return replaced, nil
+}
+
+// UnregisterAlias removes alias from the default Runtime.
+func UnregisterAlias(alias string) (removed bool) {
+	return defaultRuntime.UnregisterAlias(alias)
+}
+
+// UnregisterAlias removes alias from rt. Calls already dispatched through the
+// alias are not affected.
+func (rt *Runtime) UnregisterAlias(alias string) (removed bool) {
+	rt.handlerMu.Lock()
+	defer rt.handlerMu.Unlock()
+
+	_, removed = rt.aliases[alias]
+	delete(rt.aliases, alias)
+	return removed
+}
+
+// ListAliases returns the aliases of the default Runtime, mapped to their
+// canonical service names.
+func ListAliases() map[string]string {
+	return defaultRuntime.ListAliases()
+}
+
+// ListAliases returns the aliases of rt, mapped to their canonical service
+// names.
+func (rt *Runtime) ListAliases() map[string]string {
+	rt.handlerMu.RLock()
+	defer rt.handlerMu.RUnlock()
+
+	aliases := make(map[string]string, len(rt.aliases))
+	for alias, canonical := range rt.aliases {
+		aliases[alias] = canonical
+	}
+	return aliases
+}
+
+// handlerEntryLocked returns the entry registered for key, resolving an alias
+// if no handler is registered under key itself.
+// The caller must hold rt.handlerMu.
+func (rt *Runtime) handlerEntryLocked(key handlerKey) (*handlerEntry, bool) {
+	if e, ok := rt.handlers[key]; ok {
+		return e, true
+	}
+	canonical, ok := rt.aliases[key.serviceName]
+	if !ok {
+		return nil, false
+	}
+	e, ok := rt.handlers[handlerKey{protocol: key.protocol, serviceName: canonical}]
+	return e, ok
+}
+
+// resolveAlias returns the canonical name of serviceName if it is an alias in
+// rt, otherwise serviceName.
+func (rt *Runtime) resolveAlias(serviceName string) string {
+	rt.handlerMu.RLock()
+	defer rt.handlerMu.RUnlock()
+
+	if canonical, ok := rt.aliases[serviceName]; ok {
+		return canonical
+	}
+	return serviceName
+}
+
+// resolveFullMethodAlias returns fullMethod with its service name replaced by
+// the canonical one if it is an alias in rt.
+func (rt *Runtime) resolveFullMethodAlias(fullMethod string) string {
+	serviceName, _, ok := SplitFullMethod(fullMethod)
+	if !ok {
+		return fullMethod
+	}
+	canonical := rt.resolveAlias(serviceName)
+	if canonical == serviceName {
+		return fullMethod
+	}
+	return replaceFullMethodService(fullMethod, serviceName, canonical)
+}
+
+// replaceFullMethodService replaces the service name of fullMethod, known to
+// be serviceName, with newName.
+func replaceFullMethodService(fullMethod, serviceName, newName string) string {
+	return "/" + newName + fullMethod[len(serviceName)+1:]
+}
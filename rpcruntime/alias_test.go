@@ -0,0 +1,151 @@
+package rpcruntime
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+func TestRegisterAlias(t *testing.T) {
+	rt := NewRuntime()
+	const oldName, newName = "acme.v1.Foo", "acme.foo.v1.Foo"
+
+	if _, err := rt.RegisterAlias("", newName); err != ErrEmptyServiceName {
+		t.Errorf("expected ErrEmptyServiceName, got %v", err)
+	}
+	if _, err := rt.RegisterAlias(newName, newName); err != ErrAliasCycle {
+		t.Errorf("expected ErrAliasCycle, got %v", err)
+	}
+
+	_, _ = rt.RegisterGrpcHandler(newName, "grpc")
+	_, _ = rt.RegisterConnectHandler(newName, "connect")
+	replaced, err := rt.RegisterAlias(oldName, newName)
+	if err != nil || replaced {
+		t.Fatalf("RegisterAlias = %v, %v", replaced, err)
+	}
+
+	if h, ok := rt.LookupGrpcHandler(oldName); !ok || h != "grpc" {
+		t.Errorf("LookupGrpcHandler(alias) = %v, %v", h, ok)
+	}
+	h, release, err := rt.AcquireConnectHandler(oldName)
+	if err != nil || h != "connect" {
+		t.Fatalf("AcquireConnectHandler(alias) = %v, %v", h, err)
+	}
+	release()
+	if _, ok := rt.LookupHandler(ProtocolGo, oldName); ok {
+		t.Error("expected no Go handler through the alias")
+	}
+
+	if got := rt.ListGrpcServices(); !reflect.DeepEqual(got, []string{newName}) {
+		t.Errorf("ListGrpcServices = %v", got)
+	}
+	if got := rt.ListAliases(); !reflect.DeepEqual(got, map[string]string{oldName: newName}) {
+		t.Errorf("ListAliases = %v", got)
+	}
+
+	// A handler registered under the alias itself takes precedence.
+	_, _ = rt.RegisterGrpcHandler(oldName, "old")
+	if h, _ := rt.LookupGrpcHandler(oldName); h != "old" {
+		t.Errorf("expected own handler to take precedence, got %v", h)
+	}
+
+	if _, err := rt.RegisterAlias(newName, oldName); err != ErrAliasCycle {
+		t.Errorf("expected ErrAliasCycle, got %v", err)
+	}
+	if _, err := rt.RegisterAlias("acme.Foo", oldName); err != nil {
+		t.Fatalf("RegisterAlias failed: %v", err)
+	}
+	if got := rt.ListAliases()["acme.Foo"]; got != newName {
+		t.Errorf("expected alias of an alias to resolve to %s, got %s", newName, got)
+	}
+
+	if !rt.UnregisterAlias(oldName) {
+		t.Error("expected UnregisterAlias to report removal")
+	}
+	if _, ok := rt.LookupConnectHandler(oldName); ok {
+		t.Error("expected no lookup through a removed alias")
+	}
+
+	if err := rt.Shutdown(context.Background()); err != nil {
+		t.Fatalf("Shutdown failed: %v", err)
+	}
+	if _, err := rt.RegisterAlias(oldName, newName); err != ErrUnavailable {
+		t.Errorf("expected ErrUnavailable after Shutdown, got %v", err)
+	}
+}
+
+func TestAliasRemoteOnlyService(t *testing.T) {
+	rt := NewRuntime()
+	const alias, canonical = "rpc.test.OldTestService", "rpc.test.TestService"
+	transport := &echoTransport{}
+	remote := NewRemote(transport)
+
+	if _, err := rt.RegisterRemote(canonical, remote); err != nil {
+		t.Fatalf("RegisterRemote failed: %v", err)
+	}
+	if _, ok := rt.RemoteFallback(alias, ErrServiceNotRegistered); ok {
+		t.Fatal("expected no fallback before RegisterAlias")
+	}
+	if _, err := rt.RegisterAlias(alias, canonical); err != nil {
+		t.Fatalf("RegisterAlias failed: %v", err)
+	}
+
+	got, ok := rt.RemoteFallback(alias, ErrServiceNotRegistered)
+	if !ok {
+		t.Fatal("expected the alias to fall back to the canonical remote")
+	}
+	// The remote only serves the canonical name.
+	resp, err := ForwardUnary[wrapperspb.StringValue, wrapperspb.StringValue](
+		context.Background(), got, "/"+alias+"/Echo", wrapperspb.String("hi"))
+	if err != nil {
+		t.Fatalf("ForwardUnary through alias failed: %v", err)
+	}
+	if resp.GetValue() != "hi:" {
+		t.Errorf("unexpected reply %q", resp.GetValue())
+	}
+	if want := "/" + canonical + "/Echo"; transport.lastMethod != want {
+		t.Errorf("forwarded to %q, want %q", transport.lastMethod, want)
+	}
+	if remote.alias != "" {
+		t.Error("RemoteFallback must not modify the registered remote")
+	}
+}
+
+func TestAliasSharesLimitsAndPreference(t *testing.T) {
+	rt := NewRuntime()
+	const alias, canonical = "acme.v1.Foo", "acme.foo.v1.Foo"
+	if _, err := rt.RegisterAlias(alias, canonical); err != nil {
+		t.Fatalf("RegisterAlias failed: %v", err)
+	}
+
+	if err := rt.SetServiceProtocolPreference(canonical, ProtocolGo, ProtocolGrpc); err != nil {
+		t.Fatalf("SetServiceProtocolPreference failed: %v", err)
+	}
+	if err := rt.SetMethodProtocolPreference("/"+canonical+"/Routed", ProtocolConnectRPC); err != nil {
+		t.Fatalf("SetMethodProtocolPreference failed: %v", err)
+	}
+	if got := rt.ProtocolOrder("/"+alias+"/Bar", ProtocolGrpc); !reflect.DeepEqual(got, []Protocol{ProtocolGo, ProtocolGrpc}) {
+		t.Errorf("ProtocolOrder(alias) = %v", got)
+	}
+	if got := rt.ProtocolOrder("/"+alias+"/Routed", ProtocolGrpc); !reflect.DeepEqual(got, []Protocol{ProtocolConnectRPC}) {
+		t.Errorf("ProtocolOrder(alias route) = %v", got)
+	}
+	if got, _ := rt.ProtocolPreference(alias); !reflect.DeepEqual(got, []Protocol{ProtocolGo, ProtocolGrpc}) {
+		t.Errorf("ProtocolPreference(alias) = %v", got)
+	}
+
+	if err := rt.SetServiceConcurrencyLimit(canonical, ConcurrencyLimit{MaxInFlight: 1}); err != nil {
+		t.Fatalf("SetServiceConcurrencyLimit failed: %v", err)
+	}
+	release, err := rt.AcquireCallSlot(context.Background(), "/"+canonical+"/Bar")
+	if err != nil {
+		t.Fatalf("AcquireCallSlot failed: %v", err)
+	}
+	defer release()
+	if _, err := rt.AcquireCallSlot(context.Background(), "/"+alias+"/Bar"); !errors.Is(err, ErrResourceExhausted) {
+		t.Errorf("expected the alias to share the canonical limit, got %v", err)
+	}
+}
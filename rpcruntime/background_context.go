@@ -6,15 +6,38 @@ import (
 
 // BackgroundContext returns a context.Context intended to be used by generated CGO entrypoints.
 //
-// It always carries a protocol selection (see WithProtocol / ProtocolFromContext).
+// It is bound to the default Runtime, see Runtime.BackgroundContext.
+func BackgroundContext() context.Context {
+	return defaultRuntime.BackgroundContext()
+}
+
+// BackgroundContext returns a background context bound to rt (see WithRuntime).
+// It is cancelled once Shutdown of rt returns.
 //
 // Selection rules:
-//   - If a default protocol has been set via SetDefaultProtocol, uses it.
-//   - Otherwise returns context.Background() without a protocol value.
-func BackgroundContext() context.Context {
-	ctx := context.Background()
-	if p, ok := DefaultProtocol(); ok {
+//   - If a default protocol has been set via SetDefaultProtocol, it is attached (see WithProtocol).
+//   - Otherwise the context carries no protocol value.
+func (rt *Runtime) BackgroundContext() context.Context {
+	ctx := rt.baseCtx
+	if rt != defaultRuntime {
+		ctx = WithRuntime(ctx, rt)
+	}
+	if p, ok := rt.DefaultProtocol(); ok {
 		return WithProtocol(ctx, p)
 	}
 	return ctx
 }
+
+// CallContext returns a copy of ctx that is also cancelled once Shutdown of
+// RuntimeFromContext(ctx) returns, whether the call has drained or the
+// shutdown deadline has passed. Generated adaptors run unary handlers with it;
+// cancel must be called when the call has finished.
+func CallContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	rt := RuntimeFromContext(ctx)
+	ctx, cancel := context.WithCancel(ctx)
+	stop := context.AfterFunc(rt.baseCtx, cancel)
+	return ctx, func() {
+		stop()
+		cancel()
+	}
+}
@@ -0,0 +1,188 @@
+package rpcruntime
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ConcurrencyLimit bounds the number of concurrent calls of a service or method.
+//
+// Unary calls hold a slot until they return; streaming calls hold a slot until
+// the handler returns, i.e. for the whole lifetime of the stream.
+type ConcurrencyLimit struct {
+	// MaxInFlight is the maximum number of concurrent calls. Values <= 0
+	// remove the limit.
+	MaxInFlight int
+
+	// MaxWait is how long a call waits for a free slot before it is rejected
+	// with ErrResourceExhausted. Zero rejects immediately; the wait also ends
+	// when the call's context is done.
+	MaxWait time.Duration
+}
+
+// concurrencyLimiter is a counting semaphore for one service or method.
+type concurrencyLimiter struct {
+	name    string
+	slots   chan struct{}
+	maxWait time.Duration
+}
+
+// acquire takes a slot, waiting at most l.maxWait.
+func (l *concurrencyLimiter) acquire(ctx context.Context) error {
+	select {
+	case l.slots <- struct{}{}:
+		return nil
+	default:
+	}
+	if l.maxWait <= 0 {
+		return fmt.Errorf("%w: %s", ErrResourceExhausted, l.name)
+	}
+
+	timer := time.NewTimer(l.maxWait)
+	defer timer.Stop()
+	select {
+	case l.slots <- struct{}{}:
+		return nil
+	case <-timer.C:
+		return fmt.Errorf("%w: %s", ErrResourceExhausted, l.name)
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (l *concurrencyLimiter) release() {
+	<-l.slots
+}
+
+// SetServiceConcurrencyLimit sets the concurrency limit for all methods of
+// serviceName in the default Runtime. See Runtime.SetServiceConcurrencyLimit.
+func SetServiceConcurrencyLimit(serviceName string, limit ConcurrencyLimit) error {
+	return defaultRuntime.SetServiceConcurrencyLimit(serviceName, limit)
+}
+
+// SetMethodConcurrencyLimit sets the concurrency limit for a single method in
+// the default Runtime. See Runtime.SetMethodConcurrencyLimit.
+func SetMethodConcurrencyLimit(fullMethod string, limit ConcurrencyLimit) error {
+	return defaultRuntime.SetMethodConcurrencyLimit(fullMethod, limit)
+}
+
+// SetServiceConcurrencyLimit sets the concurrency limit shared by all methods
+// of serviceName in rt, across protocols.
+//
+// Calls admitted under a previous limit keep their slot and are not counted
+// against the new one. A limit with MaxInFlight <= 0 removes the limit.
+func (rt *Runtime) SetServiceConcurrencyLimit(serviceName string, limit ConcurrencyLimit) error {
+	if serviceName == "" {
+		return ErrEmptyServiceName
+	}
+	rt.setConcurrencyLimit(serviceName, limit)
+	return nil
+}
+
+// SetMethodConcurrencyLimit sets the concurrency limit of the method
+// identified by fullMethod ("/package.Service/Method", as in the generated
+// <Service>_<Method>_FullMethod constants) in rt.
+//
+// A call must fit within both its method and its service limit.
+func (rt *Runtime) SetMethodConcurrencyLimit(fullMethod string, limit ConcurrencyLimit) error {
+	if _, _, ok := SplitFullMethod(fullMethod); !ok {
+		return fmt.Errorf("rpcruntime: invalid full method name %q", fullMethod)
+	}
+	rt.setConcurrencyLimit(fullMethod, limit)
+	return nil
+}
+
+// setConcurrencyLimit installs or removes the limiter for key.
+// Service names and full method names never collide because the latter start with '/'.
+func (rt *Runtime) setConcurrencyLimit(key string, limit ConcurrencyLimit) {
+	rt.limitMu.Lock()
+	defer rt.limitMu.Unlock()
+
+	if limit.MaxInFlight <= 0 {
+		delete(rt.limits, key)
+		return
+	}
+	if rt.limits == nil {
+		rt.limits = make(map[string]*concurrencyLimiter)
+	}
+	rt.limits[key] = &concurrencyLimiter{
+		name:    key,
+		slots:   make(chan struct{}, limit.MaxInFlight),
+		maxWait: limit.MaxWait,
+	}
+}
+
+// AcquireCallSlot admits a call of fullMethod under the rate and concurrency
+// limits configured in the default Runtime. See Runtime.AcquireCallSlot.
+func AcquireCallSlot(ctx context.Context, fullMethod string) (release func(), err error) {
+	return defaultRuntime.AcquireCallSlot(ctx, fullMethod)
+}
+
+// AcquireCallSlot admits a call of fullMethod under the rate limit and the
+// service and method concurrency limits configured in rt. Calls through a
+// service alias (see RegisterAlias) are subject to the limits of the
+// canonical service.
+//
+// release must be called once the call has finished. Generated adaptors call
+// this before acquiring the handler. Returns ErrResourceExhausted if the rate
+// limit is exceeded or no slot became free in time, or ctx.Err() if ctx is
+// done while waiting.
+func (rt *Runtime) AcquireCallSlot(ctx context.Context, fullMethod string) (release func(), err error) {
+	fullMethod = rt.resolveFullMethodAlias(fullMethod)
+	if err := rt.checkRateLimit(ctx, fullMethod); err != nil {
+		return nil, err
+	}
+
+	serviceName, _, _ := SplitFullMethod(fullMethod)
+
+	rt.limitMu.RLock()
+	serviceLimiter := rt.limits[serviceName]
+	methodLimiter := rt.limits[fullMethod]
+	rt.limitMu.RUnlock()
+
+	if serviceLimiter == nil && methodLimiter == nil {
+		return func() {}, nil
+	}
+
+	if serviceLimiter != nil {
+		if err := serviceLimiter.acquire(ctx); err != nil {
+			return nil, err
+		}
+	}
+	if methodLimiter != nil {
+		if err := methodLimiter.acquire(ctx); err != nil {
+			if serviceLimiter != nil {
+				serviceLimiter.release()
+			}
+			return nil, err
+		}
+	}
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			if methodLimiter != nil {
+				methodLimiter.release()
+			}
+			if serviceLimiter != nil {
+				serviceLimiter.release()
+			}
+		})
+	}, nil
+}
+
+// SplitFullMethod splits "/package.Service/Method" into its service and
+// method names. ok is false if fullMethod is not of that form.
+func SplitFullMethod(fullMethod string) (serviceName, methodName string, ok bool) {
+	if !strings.HasPrefix(fullMethod, "/") {
+		return "", "", false
+	}
+	i := strings.LastIndex(fullMethod, "/")
+	if i == 0 || i == len(fullMethod)-1 {
+		return "", "", false
+	}
+	return fullMethod[1:i], fullMethod[i+1:], true
+}
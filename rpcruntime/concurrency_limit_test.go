@@ -0,0 +1,117 @@
+package rpcruntime
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestConcurrencyLimitRejects(t *testing.T) {
+	rt := NewRuntime()
+	fullMethod := "/rpc.test.TestService/Ping"
+
+	if err := rt.SetMethodConcurrencyLimit(fullMethod, ConcurrencyLimit{MaxInFlight: 1}); err != nil {
+		t.Fatalf("SetMethodConcurrencyLimit failed: %v", err)
+	}
+
+	release, err := rt.AcquireCallSlot(context.Background(), fullMethod)
+	if err != nil {
+		t.Fatalf("AcquireCallSlot failed: %v", err)
+	}
+	_, err = rt.AcquireCallSlot(context.Background(), fullMethod)
+	if !errors.Is(err, ErrResourceExhausted) {
+		t.Fatalf("expected ErrResourceExhausted, got %v", err)
+	}
+	if !rt.ErrorIs(rt.StoreError(err), ErrorKindResourceExhausted) {
+		t.Error("expected ErrorKindResourceExhausted")
+	}
+
+	// Other methods of the service are not affected by a method limit.
+	otherRelease, err := rt.AcquireCallSlot(context.Background(), "/rpc.test.TestService/Other")
+	if err != nil {
+		t.Fatalf("unexpected error for another method: %v", err)
+	}
+	otherRelease()
+
+	release()
+	release() // idempotent
+	release, err = rt.AcquireCallSlot(context.Background(), fullMethod)
+	if err != nil {
+		t.Fatalf("expected a free slot after release, got %v", err)
+	}
+	release()
+}
+
+func TestConcurrencyLimitService(t *testing.T) {
+	rt := NewRuntime()
+
+	if err := rt.SetServiceConcurrencyLimit("rpc.test.TestService", ConcurrencyLimit{MaxInFlight: 1}); err != nil {
+		t.Fatalf("SetServiceConcurrencyLimit failed: %v", err)
+	}
+	release, err := rt.AcquireCallSlot(context.Background(), "/rpc.test.TestService/Ping")
+	if err != nil {
+		t.Fatalf("AcquireCallSlot failed: %v", err)
+	}
+	if _, err := rt.AcquireCallSlot(context.Background(), "/rpc.test.TestService/Other"); !errors.Is(err, ErrResourceExhausted) {
+		t.Errorf("expected service limit to cover all methods, got %v", err)
+	}
+	release()
+
+	if err := rt.SetServiceConcurrencyLimit("rpc.test.TestService", ConcurrencyLimit{}); err != nil {
+		t.Fatalf("clearing the limit failed: %v", err)
+	}
+	for i := 0; i < 3; i++ {
+		if _, err := rt.AcquireCallSlot(context.Background(), "/rpc.test.TestService/Ping"); err != nil {
+			t.Fatalf("expected no limit after clearing, got %v", err)
+		}
+	}
+
+	if err := rt.SetServiceConcurrencyLimit("", ConcurrencyLimit{MaxInFlight: 1}); !errors.Is(err, ErrEmptyServiceName) {
+		t.Errorf("expected ErrEmptyServiceName, got %v", err)
+	}
+	if err := rt.SetMethodConcurrencyLimit("rpc.test.TestService", ConcurrencyLimit{MaxInFlight: 1}); err == nil {
+		t.Error("expected an error for an invalid full method name")
+	}
+}
+
+func TestConcurrencyLimitQueues(t *testing.T) {
+	rt := NewRuntime()
+	fullMethod := "/rpc.test.TestService/Ping"
+
+	if err := rt.SetMethodConcurrencyLimit(fullMethod, ConcurrencyLimit{MaxInFlight: 1, MaxWait: time.Second}); err != nil {
+		t.Fatalf("SetMethodConcurrencyLimit failed: %v", err)
+	}
+	release, err := rt.AcquireCallSlot(context.Background(), fullMethod)
+	if err != nil {
+		t.Fatalf("AcquireCallSlot failed: %v", err)
+	}
+
+	admitted := make(chan error, 1)
+	go func() {
+		r, err := rt.AcquireCallSlot(context.Background(), fullMethod)
+		if err == nil {
+			r()
+		}
+		admitted <- err
+	}()
+
+	select {
+	case err := <-admitted:
+		t.Fatalf("queued call must wait for a free slot, got %v", err)
+	case <-time.After(20 * time.Millisecond):
+	}
+	release()
+	if err := <-admitted; err != nil {
+		t.Fatalf("queued call failed: %v", err)
+	}
+
+	// A done context ends the wait early.
+	release, _ = rt.AcquireCallSlot(context.Background(), fullMethod)
+	defer release()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := rt.AcquireCallSlot(ctx, fullMethod); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected DeadlineExceeded, got %v", err)
+	}
+}
@@ -0,0 +1,62 @@
+package rpcruntime
+
+// ConnectStreamBridge selects how generated adaptors hand a stream session to
+// a Connect streaming handler. The bridges are implemented by package
+// connectrt; the selection is kept per Runtime.
+type ConnectStreamBridge int32
+
+const (
+	// ConnectStreamBridgeAuto uses ConnectStreamBridgeReflect when connect-go's
+	// stream types have the expected layout and ConnectStreamBridgeHTTP
+	// otherwise. This is the default.
+	ConnectStreamBridgeAuto ConnectStreamBridge = iota
+	// ConnectStreamBridgeReflect injects a connectrt.StreamConn into the
+	// unexported conn field of connect-go's stream types (see
+	// connectrt.NewClientStream). Messages are passed without serialization,
+	// but the bridge depends on connect-go's struct layout.
+	ConnectStreamBridgeReflect
+	// ConnectStreamBridgeHTTP serves the call with connect-go's own stream
+	// handler and client over an in-memory HTTP transport. It relies on public
+	// connect-go APIs only; messages are marshaled on the way.
+	ConnectStreamBridgeHTTP
+)
+
+// String returns the name of b.
+func (b ConnectStreamBridge) String() string {
+	switch b {
+	case ConnectStreamBridgeAuto:
+		return "auto"
+	case ConnectStreamBridgeReflect:
+		return "reflect"
+	case ConnectStreamBridgeHTTP:
+		return "http"
+	default:
+		return "unknown"
+	}
+}
+
+// SetConnectStreamBridge selects the Connect streaming bridge of the default
+// Runtime. See Runtime.SetConnectStreamBridge.
+func SetConnectStreamBridge(bridge ConnectStreamBridge) error {
+	return defaultRuntime.SetConnectStreamBridge(bridge)
+}
+
+// SetConnectStreamBridge selects how Connect streaming calls started in rt
+// reach their handler. It applies to streams started afterwards.
+//
+// Returns ErrInvalidConnectStreamBridge for values other than the
+// ConnectStreamBridge constants.
+func (rt *Runtime) SetConnectStreamBridge(bridge ConnectStreamBridge) error {
+	switch bridge {
+	case ConnectStreamBridgeAuto, ConnectStreamBridgeReflect, ConnectStreamBridgeHTTP:
+	default:
+		return ErrInvalidConnectStreamBridge
+	}
+	rt.connectStreamBridge.Store(int32(bridge))
+	return nil
+}
+
+// ConnectStreamBridge returns the Connect streaming bridge selected in rt.
+func (rt *Runtime) ConnectStreamBridge() ConnectStreamBridge {
+	return ConnectStreamBridge(rt.connectStreamBridge.Load())
+}
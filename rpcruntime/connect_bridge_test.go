@@ -0,0 +1,22 @@
+package rpcruntime
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestSetConnectStreamBridge(t *testing.T) {
+	rt := NewRuntime()
+	if got := rt.ConnectStreamBridge(); got != ConnectStreamBridgeAuto {
+		t.Fatalf("expected auto by default, got %v", got)
+	}
+	if err := rt.SetConnectStreamBridge(ConnectStreamBridgeHTTP); err != nil {
+		t.Fatalf("SetConnectStreamBridge failed: %v", err)
+	}
+	if err := rt.SetConnectStreamBridge(ConnectStreamBridge(42)); !errors.Is(err, ErrInvalidConnectStreamBridge) {
+		t.Fatalf("expected ErrInvalidConnectStreamBridge, got %v", err)
+	}
+	if got := rt.ConnectStreamBridge(); got != ConnectStreamBridgeHTTP {
+		t.Errorf("invalid value must not change the bridge, got %v", got)
+	}
+}
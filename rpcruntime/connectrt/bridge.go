@@ -0,0 +1,82 @@
+package connectrt
+
+import (
+	"context"
+
+	"connectrpc.com/connect"
+	"github.com/ygrpc/rpccgo/rpcruntime"
+)
+
+// useHTTPBridge reports whether Connect streams started with ctx use the HTTP
+// bridge.
+func useHTTPBridge(ctx context.Context) bool {
+	switch rpcruntime.RuntimeFromContext(ctx).ConnectStreamBridge() {
+	case rpcruntime.ConnectStreamBridgeReflect:
+		return false
+	case rpcruntime.ConnectStreamBridgeHTTP:
+		return true
+	default:
+		return checkConnectStreamLayout() != nil
+	}
+}
+
+// ServeClientStream runs the client-streaming handler method call for
+// procedure, feeding it the messages sent on session.
+//
+// The bridge is chosen by rpcruntime.RuntimeFromContext(ctx).ConnectStreamBridge().
+func ServeClientStream[Req, Res any](
+	ctx context.Context,
+	session rpcruntime.StreamSession,
+	procedure string,
+	call func(context.Context, *connect.ClientStream[Req]) (*Res, error),
+) (*Res, error) {
+	if useHTTPBridge(ctx) {
+		return serveClientStreamHTTP(ctx, session, procedure, call)
+	}
+	stream := &connect.ClientStream[Req]{}
+	if err := TrySetClientStreamConn(stream, NewStreamConn(session)); err != nil {
+		return nil, err
+	}
+	return call(ctx, stream)
+}
+
+// ServeServerStream runs the server-streaming handler method call for
+// procedure, delivering its messages to session's onRead callback.
+//
+// The bridge is chosen by rpcruntime.RuntimeFromContext(ctx).ConnectStreamBridge().
+func ServeServerStream[Req, Res any](
+	ctx context.Context,
+	session rpcruntime.StreamSession,
+	procedure string,
+	req *Req,
+	call func(context.Context, *Req, *connect.ServerStream[Res]) error,
+) error {
+	if useHTTPBridge(ctx) {
+		return serveServerStreamHTTP(ctx, session, procedure, req, call)
+	}
+	stream := &connect.ServerStream[Res]{}
+	if err := TrySetServerStreamConn(stream, NewStreamConn(session)); err != nil {
+		return err
+	}
+	return call(ctx, req, stream)
+}
+
+// ServeBidiStream runs the bidi-streaming handler method call for
+// procedure on session.
+//
+// The bridge is chosen by rpcruntime.RuntimeFromContext(ctx).ConnectStreamBridge().
+func ServeBidiStream[Req, Res any](
+	ctx context.Context,
+	session rpcruntime.StreamSession,
+	procedure string,
+	call func(context.Context, *connect.BidiStream[Req, Res]) error,
+) error {
+	if useHTTPBridge(ctx) {
+		return serveBidiStreamHTTP(ctx, session, procedure, call)
+	}
+	stream := &connect.BidiStream[Req, Res]{}
+	if err := TrySetBidiStreamConn(stream, NewStreamConn(session)); err != nil {
+		return err
+	}
+	return call(ctx, stream)
+}
@@ -0,0 +1,177 @@
+package connectrt
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"connectrpc.com/connect"
+	"github.com/ygrpc/rpccgo/rpcruntime"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+const bridgeTestProcedure = "/rpc.test.BridgeService/Call"
+
+func TestUseHTTPBridge(t *testing.T) {
+	rt := rpcruntime.NewRuntime()
+	ctx := rpcruntime.WithRuntime(context.Background(), rt)
+	if useHTTPBridge(ctx) {
+		t.Error("auto must use the reflect bridge while the connect-go layout is supported")
+	}
+	if err := rt.SetConnectStreamBridge(rpcruntime.ConnectStreamBridgeHTTP); err != nil {
+		t.Fatalf("SetConnectStreamBridge failed: %v", err)
+	}
+	if !useHTTPBridge(ctx) {
+		t.Error("expected the HTTP bridge to be used")
+	}
+}
+
+// newHTTPBridgeSession returns a context using a Runtime with the HTTP bridge
+// and a stream session allocated in it.
+func newHTTPBridgeSession(t *testing.T) (context.Context, rpcruntime.StreamHandle, rpcruntime.StreamSession) {
+	t.Helper()
+	rt := rpcruntime.NewRuntime()
+	if err := rt.SetConnectStreamBridge(rpcruntime.ConnectStreamBridgeHTTP); err != nil {
+		t.Fatalf("SetConnectStreamBridge failed: %v", err)
+	}
+	ctx := rpcruntime.WithRuntime(context.Background(), rt)
+	ctx = rpcruntime.WithRequestHeader(ctx, http.Header{"X-Test": []string{"in"}})
+	handle, childCtx, _ := rpcruntime.AllocateStreamHandle(ctx, rpcruntime.ProtocolConnectRPC)
+	t.Cleanup(func() { rpcruntime.FinishStreamHandle(handle) })
+	return childCtx, handle, rpcruntime.GetStreamSession(handle)
+}
+
+func TestHTTPBridgeClientStream(t *testing.T) {
+	ctx, handle, session := newHTTPBridgeSession(t)
+	ctx, md := rpcruntime.WithResponseMetadata(ctx)
+
+	go func() {
+		for _, v := range []string{"a", "b"} {
+			_ = rpcruntime.SendToStream(handle, wrapperspb.String(v))
+		}
+		_ = rpcruntime.CloseSendCh(handle)
+	}()
+
+	resp, err := ServeClientStream(
+		ctx,
+		session,
+		bridgeTestProcedure,
+		func(ctx context.Context, stream *connect.ClientStream[wrapperspb.StringValue]) (*wrapperspb.StringValue, error) {
+			var builder strings.Builder
+			for stream.Receive() {
+				builder.WriteString(stream.Msg().GetValue())
+			}
+			if err := stream.Err(); err != nil {
+				return nil, err
+			}
+			if info, ok := connect.CallInfoForHandlerContext(ctx); ok {
+				info.ResponseHeader().Set("X-Echo", stream.RequestHeader().Get("X-Test"))
+			}
+			return wrapperspb.String(builder.String()), nil
+		},
+	)
+	if err != nil {
+		t.Fatalf("ServeClientStream failed: %v", err)
+	}
+	if resp.GetValue() != "ab" {
+		t.Errorf("unexpected response %q", resp.GetValue())
+	}
+	if got := md.Header.Get("X-Echo"); got != "in" {
+		t.Errorf("unexpected X-Echo header %q", got)
+	}
+	if got := md.Header.Get("Content-Type"); got != "" {
+		t.Errorf("protocol header leaked into response metadata: %q", got)
+	}
+}
+
+func TestHTTPBridgeServerStreamStopsOnRead(t *testing.T) {
+	ctx, _, session := newHTTPBridgeSession(t)
+
+	var got []string
+	session.SetCallbacks(func(msg any) bool {
+		got = append(got, msg.(*wrapperspb.StringValue).GetValue())
+		return false
+	}, nil)
+
+	var sendErr error
+	err := ServeServerStream(
+		ctx,
+		session,
+		bridgeTestProcedure,
+		wrapperspb.String("req"),
+		func(ctx context.Context, req *wrapperspb.StringValue, stream *connect.ServerStream[wrapperspb.StringValue]) error {
+			for i := 0; i < 100; i++ {
+				if sendErr = stream.Send(wrapperspb.String(req.GetValue())); sendErr != nil {
+					return sendErr
+				}
+			}
+			return nil
+		},
+	)
+	if err == nil || sendErr == nil {
+		t.Fatalf("expected Send to fail once onRead returned false, got %v / %v", err, sendErr)
+	}
+	if len(got) != 1 || got[0] != "req" {
+		t.Errorf("unexpected messages %v", got)
+	}
+}
+
+func TestHTTPBridgeBidiStream(t *testing.T) {
+	ctx, handle, session := newHTTPBridgeSession(t)
+
+	var got []string
+	session.SetCallbacks(func(msg any) bool {
+		got = append(got, msg.(*wrapperspb.StringValue).GetValue())
+		return true
+	}, nil)
+	go func() {
+		_ = rpcruntime.SendToStream(handle, wrapperspb.String("x"))
+		_ = rpcruntime.CloseSendCh(handle)
+	}()
+
+	errDone := errors.New("done")
+	err := ServeBidiStream(
+		ctx,
+		session,
+		bridgeTestProcedure,
+		func(_ context.Context, stream *connect.BidiStream[wrapperspb.StringValue, wrapperspb.StringValue]) error {
+			if err := stream.Send(wrapperspb.String("hello")); err != nil {
+				return err
+			}
+			for {
+				req, err := stream.Receive()
+				if err != nil {
+					return errDone
+				}
+				if err := stream.Send(wrapperspb.String("echo:" + req.GetValue())); err != nil {
+					return err
+				}
+			}
+		},
+	)
+	if !errors.Is(err, errDone) {
+		t.Fatalf("expected the handler's error to be returned as is, got %v", err)
+	}
+	if strings.Join(got, ",") != "hello,echo:x" {
+		t.Errorf("unexpected messages %v", got)
+	}
+}
+
+func TestHTTPBridgeRecoversPanic(t *testing.T) {
+	ctx, _, session := newHTTPBridgeSession(t)
+
+	err := ServeServerStream(
+		ctx,
+		session,
+		bridgeTestProcedure,
+		wrapperspb.String("req"),
+		func(context.Context, *wrapperspb.StringValue, *connect.ServerStream[wrapperspb.StringValue]) error {
+			panic("boom")
+		},
+	)
+	if err == nil || !strings.Contains(err.Error(), "boom") {
+		t.Fatalf("expected the panic to surface as an error, got %v", err)
+	}
+}
@@ -0,0 +1,30 @@
+package connectrt
+
+import "net/http"
+
+// HTTPClient returns an *http.Client that serves Connect, gRPC and gRPC-Web
+// requests in process through the generated adaptors, using the handlers of
+// the default Runtime. It satisfies connect.HTTPClient:
+//
+//	client := pbconnect.NewTestServiceClient(connectrt.HTTPClient(), "inproc://")
+//	resp, err := client.Ping(ctx, connect.NewRequest(&pb.PingRequest{Msg: "hi"}))
+//
+// The host of the base URL is ignored; requests are routed by path to the
+// methods registered with rpcruntime.RegisterMethods, so the package holding
+// the generated adaptors must be linked in. Unknown methods fail with
+// CodeUnimplemented.
+//
+// Messages are marshaled as on a real connection. Request headers reach
+// handlers as with rpcruntime.WithRequestHeader; response headers and
+// trailers recorded by handlers are sent back, as are the codes of gRPC status
+// and connect errors. rpcruntime errors map to the nearest code, e.g.
+// rpcruntime.ErrServiceNotRegistered to CodeUnimplemented.
+func HTTPClient() *http.Client {
+	return defaultRuntime().HTTPClient()
+}
+
+// HTTPClient returns an *http.Client that serves requests in process with the
+// handlers registered in r.
+func (r Runtime) HTTPClient() *http.Client {
+	return &http.Client{Transport: &bridgeTransport{handler: r.HTTPHandler()}}
+}
@@ -0,0 +1,79 @@
+package connectrt
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"connectrpc.com/connect"
+	"github.com/ygrpc/rpccgo/rpcruntime"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+const clientTestMethod = "/rpc.test.ConnectClientService/Echo"
+
+func init() {
+	rpcruntime.RegisterMethods(rpcruntime.NewUnaryMethodDesc(
+		clientTestMethod,
+		func(ctx context.Context, req *wrapperspb.StringValue) (*wrapperspb.StringValue, error) {
+			if rpcruntime.RuntimeFromContext(ctx) == rpcruntime.Default() {
+				return nil, errors.New("call did not run in the client's Runtime")
+			}
+			rpcruntime.RecordResponseMetadata(ctx, nil, http.Header{"X-Done": {"yes"}})
+			return wrapperspb.String(req.GetValue() + ":" + rpcruntime.RequestHeaderFromContext(ctx).Get("X-Test")), nil
+		},
+	))
+}
+
+func TestHTTPClient(t *testing.T) {
+	httpClient := For(rpcruntime.NewRuntime()).HTTPClient()
+
+	client := connect.NewClient[wrapperspb.StringValue, wrapperspb.StringValue](
+		httpClient,
+		"inproc://"+clientTestMethod,
+	)
+	req := connect.NewRequest(wrapperspb.String("hi"))
+	req.Header().Set("X-Test", "v1")
+	resp, err := client.CallUnary(context.Background(), req)
+	if err != nil {
+		t.Fatalf("CallUnary failed: %v", err)
+	}
+	if resp.Msg.GetValue() != "hi:v1" {
+		t.Errorf("unexpected reply %q", resp.Msg.GetValue())
+	}
+	if got := resp.Trailer().Get("X-Done"); got != "yes" {
+		t.Errorf("unexpected X-Done trailer %q", got)
+	}
+
+	missing := connect.NewClient[wrapperspb.StringValue, wrapperspb.StringValue](
+		httpClient,
+		"inproc:///rpc.test.ConnectClientService/Missing",
+	)
+	_, err = missing.CallUnary(context.Background(), connect.NewRequest(wrapperspb.String("hi")))
+	if connect.CodeOf(err) != connect.CodeUnimplemented {
+		t.Errorf("expected CodeUnimplemented for an unknown method, got %v", err)
+	}
+}
+
+func TestCodeOf(t *testing.T) {
+	tests := []struct {
+		err  error
+		want connect.Code
+	}{
+		{status.Error(codes.NotFound, "missing"), connect.CodeNotFound},
+		{fmt.Errorf("wrapped: %w", rpcruntime.ErrServiceNotRegistered), connect.CodeUnimplemented},
+		{rpcruntime.ErrResourceExhausted, connect.CodeResourceExhausted},
+		{rpcruntime.ErrUnavailable, connect.CodeUnavailable},
+		{context.DeadlineExceeded, connect.CodeDeadlineExceeded},
+		{fmt.Errorf("other"), connect.CodeUnknown},
+	}
+	for _, tt := range tests {
+		if got := codeOf(tt.err); got != tt.want {
+			t.Errorf("codeOf(%v) = %v, want %v", tt.err, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,38 @@
+// Package connectrt connects an rpcruntime.Runtime to connect-go: an
+// http.Handler and an in-process HTTP client serving the method table over
+// the Connect, gRPC and gRPC-Web protocols, the stream bridges and helpers
+// used by the generated adaptors for Connect handlers, and remotes forwarding
+// calls with connect-go clients.
+//
+// It is kept out of package rpcruntime so that binaries using only plain Go
+// (protocol=go) or Twirp handlers do not link connect-go.
+package connectrt
+
+import (
+	"net/http"
+
+	"github.com/ygrpc/rpccgo/rpcruntime"
+)
+
+// Runtime serves the handlers registered in an rpcruntime.Runtime with
+// connect-go. The package-level functions use the default Runtime (see
+// rpcruntime.Default).
+type Runtime struct {
+	rt *rpcruntime.Runtime
+}
+
+// For returns the Connect side of rt.
+func For(rt *rpcruntime.Runtime) Runtime {
+	return Runtime{rt: rt}
+}
+
+// defaultRuntime returns the Connect side of the default Runtime.
+func defaultRuntime() Runtime {
+	return For(rpcruntime.Default())
+}
+
+func mergeHeader(dst, src http.Header) {
+	for k, v := range src {
+		dst[k] = append(dst[k], v...)
+	}
+}
@@ -0,0 +1,77 @@
+package connectrt
+
+import (
+	"context"
+	"errors"
+
+	"connectrpc.com/connect"
+	"github.com/ygrpc/rpccgo/rpcruntime"
+)
+
+// The helpers in this file adapt handlers written against Connect's generic
+// API (protoc-gen-connect-go without simple=true) to the message-only calls
+// made by the generated adaptors. Request headers come from
+// rpcruntime.RequestHeaderFromContext; response headers and trailers, as well
+// as the metadata of a returned *connect.Error, go to
+// rpcruntime.RecordResponseMetadata.
+
+// CallUnary calls a generic-API unary handler method with req wrapped
+// in a connect.Request and unwraps the returned connect.Response.
+func CallUnary[Req, Res any](
+	ctx context.Context,
+	req *Req,
+	call func(context.Context, *connect.Request[Req]) (*connect.Response[Res], error),
+) (*Res, error) {
+	resp, err := call(ctx, newRequest(ctx, req))
+	if err != nil {
+		recordErrorMeta(ctx, err)
+		return nil, err
+	}
+	rpcruntime.RecordResponseMetadata(ctx, resp.Header(), resp.Trailer())
+	return resp.Msg, nil
+}
+
+// ClientStreamHandler adapts a generic-API client-streaming handler
+// method to the simple-API shape.
+func ClientStreamHandler[Req, Res any](
+	call func(context.Context, *connect.ClientStream[Req]) (*connect.Response[Res], error),
+) func(context.Context, *connect.ClientStream[Req]) (*Res, error) {
+	return func(ctx context.Context, stream *connect.ClientStream[Req]) (*Res, error) {
+		resp, err := call(ctx, stream)
+		if err != nil {
+			recordErrorMeta(ctx, err)
+			return nil, err
+		}
+		rpcruntime.RecordResponseMetadata(ctx, resp.Header(), resp.Trailer())
+		return resp.Msg, nil
+	}
+}
+
+// ServerStreamHandler adapts a generic-API server-streaming handler
+// method to the simple-API shape.
+func ServerStreamHandler[Req, Res any](
+	call func(context.Context, *connect.Request[Req], *connect.ServerStream[Res]) error,
+) func(context.Context, *Req, *connect.ServerStream[Res]) error {
+	return func(ctx context.Context, req *Req, stream *connect.ServerStream[Res]) error {
+		err := call(ctx, newRequest(ctx, req), stream)
+		if err != nil {
+			recordErrorMeta(ctx, err)
+		}
+		return err
+	}
+}
+
+// newRequest wraps msg in a connect.Request carrying a copy of the
+// request headers of ctx.
+func newRequest[T any](ctx context.Context, msg *T) *connect.Request[T] {
+	req := connect.NewRequest(msg)
+	mergeHeader(req.Header(), rpcruntime.RequestHeaderFromContext(ctx))
+	return req
+}
+
+func recordErrorMeta(ctx context.Context, err error) {
+	var connectErr *connect.Error
+	if errors.As(err, &connectErr) {
+		rpcruntime.RecordResponseMetadata(ctx, connectErr.Meta(), nil)
+	}
+}
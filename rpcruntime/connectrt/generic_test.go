@@ -0,0 +1,58 @@
+package connectrt
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"connectrpc.com/connect"
+	"github.com/ygrpc/rpccgo/rpcruntime"
+)
+
+type genericTestMsg struct{ v string }
+
+func TestCallUnary(t *testing.T) {
+	ctx := rpcruntime.WithRequestHeader(context.Background(), http.Header{"X-Test": []string{"in"}})
+	ctx, md := rpcruntime.WithResponseMetadata(ctx)
+
+	resp, err := CallUnary(
+		ctx,
+		&genericTestMsg{v: "req"},
+		func(_ context.Context, req *connect.Request[genericTestMsg]) (*connect.Response[genericTestMsg], error) {
+			resp := connect.NewResponse(&genericTestMsg{v: req.Msg.v + ":" + req.Header().Get("X-Test")})
+			resp.Header().Set("X-Out", "h")
+			resp.Trailer().Set("X-Out", "t")
+			return resp, nil
+		},
+	)
+	if err != nil {
+		t.Fatalf("CallUnary failed: %v", err)
+	}
+	if resp.v != "req:in" {
+		t.Fatalf("unexpected response %q", resp.v)
+	}
+	if md.Header.Get("X-Out") != "h" || md.Trailer.Get("X-Out") != "t" {
+		t.Fatalf("unexpected response metadata %v / %v", md.Header, md.Trailer)
+	}
+}
+
+func TestCallUnaryErrorMeta(t *testing.T) {
+	ctx, md := rpcruntime.WithResponseMetadata(context.Background())
+	connectErr := connect.NewError(connect.CodeNotFound, errors.New("missing"))
+	connectErr.Meta().Set("X-Reason", "gone")
+
+	_, err := CallUnary(
+		ctx,
+		&genericTestMsg{},
+		func(context.Context, *connect.Request[genericTestMsg]) (*connect.Response[genericTestMsg], error) {
+			return nil, connectErr
+		},
+	)
+	if !errors.Is(err, connectErr) {
+		t.Fatalf("expected the handler error, got %v", err)
+	}
+	if got := md.Header.Get("X-Reason"); got != "gone" {
+		t.Fatalf("unexpected X-Reason header %q", got)
+	}
+}
@@ -0,0 +1,297 @@
+package connectrt
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"connectrpc.com/connect"
+	"github.com/ygrpc/rpccgo/rpcruntime"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// dynMessage carries a message of a type only known at run time through
+// connect-go's generic handlers and clients. Embedding the message makes
+// *dynMessage a proto.Message, so the protobuf and JSON codecs work on it.
+type dynMessage struct {
+	proto.Message
+}
+
+// wrapMessage wraps a message returned by a MethodDesc.
+func wrapMessage(msg any) (*dynMessage, error) {
+	m, ok := msg.(proto.Message)
+	if !ok {
+		return nil, fmt.Errorf("%w: %T is not a proto.Message", rpcruntime.ErrStreamMessageTypeMismatch, msg)
+	}
+	return &dynMessage{Message: m}, nil
+}
+
+// messageInitializer returns a connect initializer filling the *dynMessage
+// connect-go is about to decode into with a new message from newMessage.
+func messageInitializer(newMessage func() any) func(connect.Spec, any) error {
+	return func(_ connect.Spec, msg any) error {
+		m, err := wrapMessage(newMessage())
+		if err != nil {
+			return err
+		}
+		msg.(*dynMessage).Message = m.Message
+		return nil
+	}
+}
+
+// httpHandler serves the Connect, gRPC and gRPC-Web protocols for every
+// method in the method table, dispatching to the handlers of rt.
+type httpHandler struct {
+	rt *rpcruntime.Runtime
+}
+
+// HTTPHandler returns an http.Handler serving every method in the method
+// table over the Connect, gRPC and gRPC-Web protocols, using the handlers of
+// the default Runtime.
+//
+// Requests are routed by path ("/package.Service/Method"), so mount it at the
+// root of a server or behind a prefix-stripping mux. Streaming over the gRPC
+// protocol, and bidi streams over any protocol, need HTTP/2, e.g. through
+// http.Server.Protocols with unencrypted HTTP/2 enabled. Headers and errors
+// are handled as for HTTPClient.
+//
+// Requests for other methods go to the unknown-service handlers (see
+// rpcruntime.SetUnknownStreamHandler); their messages must use the protobuf
+// codec.
+func HTTPHandler() http.Handler {
+	return defaultRuntime().HTTPHandler()
+}
+
+// HTTPHandler returns an http.Handler serving the handlers registered in r.
+func (r Runtime) HTTPHandler() http.Handler {
+	return httpHandler{rt: r.rt}
+}
+
+func (h httpHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	r = r.WithContext(rpcruntime.WithRuntime(r.Context(), h.rt))
+	if desc, ok := rpcruntime.LookupMethod(r.URL.Path); ok {
+		methodHandler(desc).ServeHTTP(w, r)
+		return
+	}
+	kind := rpcruntime.MethodKindBidiStream
+	if isUnaryRequest(r.Header.Get("Content-Type")) {
+		kind = rpcruntime.MethodKindUnary
+	}
+	if desc, ok := h.rt.UnknownMethodDesc(r.URL.Path, kind); ok {
+		methodHandler(desc).ServeHTTP(w, r)
+		return
+	}
+	// Connect clients report 404 as CodeUnimplemented.
+	http.NotFound(w, r)
+}
+
+// isUnaryRequest reports whether contentType is the one of a unary request of
+// the Connect protocol, as opposed to a streaming Connect request or a gRPC or
+// gRPC-Web one.
+func isUnaryRequest(contentType string) bool {
+	return !strings.HasPrefix(contentType, "application/grpc") &&
+		!strings.HasPrefix(contentType, "application/connect+")
+}
+
+// methodHandler returns the Connect handler serving desc.
+func methodHandler(desc rpcruntime.MethodDesc) http.Handler {
+	initRequest := connect.WithRequestInitializer(messageInitializer(desc.NewRequest))
+	switch desc.Kind() {
+	case rpcruntime.MethodKindClientStream:
+		return newClientStreamHandler(desc, initRequest)
+	case rpcruntime.MethodKindServerStream:
+		return newServerStreamHandler(desc, initRequest)
+	case rpcruntime.MethodKindBidiStream:
+		return newBidiStreamHandler(desc, initRequest)
+	default:
+		return newUnaryHandler(desc, initRequest)
+	}
+}
+
+// serverContext prepares the context an adaptor function runs with when
+// serving a Connect request with header.
+func serverContext(ctx context.Context, header http.Header) (context.Context, *rpcruntime.ResponseMetadata) {
+	return rpcruntime.WithResponseMetadata(rpcruntime.WithRequestHeader(ctx, header.Clone()))
+}
+
+// serverError converts an error returned by an adaptor function into a
+// *connect.Error carrying md's headers and trailers.
+func serverError(err error, md *rpcruntime.ResponseMetadata) error {
+	var connectErr *connect.Error
+	if !errors.As(err, &connectErr) {
+		msg := err
+		if st, ok := status.FromError(err); ok {
+			msg = errors.New(st.Message())
+		}
+		connectErr = connect.NewError(codeOf(err), msg)
+	}
+	mergeHeader(connectErr.Meta(), md.Header)
+	mergeHeader(connectErr.Meta(), md.Trailer)
+	return connectErr
+}
+
+// codeOf returns the code a Connect client should see for err.
+func codeOf(err error) connect.Code {
+	var connectErr *connect.Error
+	if errors.As(err, &connectErr) {
+		return connectErr.Code()
+	}
+	if st, ok := status.FromError(err); ok {
+		return connect.Code(st.Code())
+	}
+	return connect.Code(rpcruntime.StatusCode(err))
+}
+
+// setResponseMetadata copies md into a response's header and trailer.
+func setResponseMetadata(header, trailer http.Header, md *rpcruntime.ResponseMetadata) {
+	mergeHeader(header, md.Header)
+	mergeHeader(trailer, md.Trailer)
+}
+
+// newResponse wraps a response message returned by an adaptor function,
+// adding the response metadata recorded in md.
+func newResponse(res any, md *rpcruntime.ResponseMetadata) (*connect.Response[dynMessage], error) {
+	msg, err := wrapMessage(res)
+	if err != nil {
+		return nil, serverError(err, md)
+	}
+	resp := connect.NewResponse(msg)
+	setResponseMetadata(resp.Header(), resp.Trailer(), md)
+	return resp, nil
+}
+
+func newUnaryHandler(desc rpcruntime.MethodDesc, opts ...connect.HandlerOption) http.Handler {
+	return connect.NewUnaryHandler(
+		desc.FullMethod(),
+		func(ctx context.Context, req *connect.Request[dynMessage]) (*connect.Response[dynMessage], error) {
+			ctx, md := serverContext(ctx, req.Header())
+			res, err := desc.Unary(ctx, req.Msg.Message)
+			if err != nil {
+				return nil, serverError(err, md)
+			}
+			return newResponse(res, md)
+		},
+		opts...,
+	)
+}
+
+func newClientStreamHandler(desc rpcruntime.MethodDesc, opts ...connect.HandlerOption) http.Handler {
+	return connect.NewClientStreamHandler(
+		desc.FullMethod(),
+		func(ctx context.Context, stream *connect.ClientStream[dynMessage]) (*connect.Response[dynMessage], error) {
+			ctx, md := serverContext(ctx, stream.RequestHeader())
+			ctx, cancel := context.WithCancel(ctx)
+			defer cancel()
+
+			handle, err := desc.StartClientStream(ctx)
+			if err != nil {
+				return nil, serverError(err, md)
+			}
+			for stream.Receive() {
+				// A failed send means the handler has finished; Finish reports why.
+				if desc.Send(handle, stream.Msg().Message) != nil {
+					break
+				}
+			}
+			if err := stream.Err(); err != nil {
+				cancel()
+			}
+			res, err := desc.FinishClientStream(handle)
+			if err == nil {
+				err = stream.Err()
+			}
+			if err != nil {
+				return nil, serverError(err, md)
+			}
+			return newResponse(res, md)
+		},
+		opts...,
+	)
+}
+
+func newServerStreamHandler(desc rpcruntime.MethodDesc, opts ...connect.HandlerOption) http.Handler {
+	return connect.NewServerStreamHandler(
+		desc.FullMethod(),
+		func(ctx context.Context, req *connect.Request[dynMessage], stream *connect.ServerStream[dynMessage]) error {
+			ctx, md := serverContext(ctx, req.Header())
+			w := &streamWriter{send: stream.Send, header: stream.ResponseHeader(), md: md}
+			err := desc.ServerStream(ctx, req.Msg.Message, w.onRead, func(error) {})
+			return w.finish(stream.ResponseTrailer(), err)
+		},
+		opts...,
+	)
+}
+
+func newBidiStreamHandler(desc rpcruntime.MethodDesc, opts ...connect.HandlerOption) http.Handler {
+	return connect.NewBidiStreamHandler(
+		desc.FullMethod(),
+		func(ctx context.Context, stream *connect.BidiStream[dynMessage, dynMessage]) error {
+			ctx, md := serverContext(ctx, stream.RequestHeader())
+			ctx, cancel := context.WithCancel(ctx)
+			defer cancel()
+
+			w := &streamWriter{send: stream.Send, header: stream.ResponseHeader(), md: md}
+			done := make(chan error, 1)
+			handle, err := desc.StartBidiStream(ctx, w.onRead, func(err error) { done <- err })
+			if err != nil {
+				return w.finish(stream.ResponseTrailer(), err)
+			}
+			go func() {
+				for {
+					msg, err := stream.Receive()
+					if err != nil {
+						// io.EOF ends the request stream; anything else aborts the call.
+						if !errors.Is(err, io.EOF) {
+							cancel()
+						}
+						_ = desc.CloseSend(handle)
+						return
+					}
+					if desc.Send(handle, msg.Message) != nil {
+						return
+					}
+				}
+			}()
+			return w.finish(stream.ResponseTrailer(), <-done)
+		},
+		opts...,
+	)
+}
+
+// streamWriter forwards the messages of a streaming adaptor call to a Connect
+// stream. Response headers recorded before the first message are sent with
+// it; the rest of the metadata is sent as trailers.
+type streamWriter struct {
+	send   func(*dynMessage) error
+	header http.Header
+	md     *rpcruntime.ResponseMetadata
+	sent   bool
+}
+
+func (w *streamWriter) onRead(msg any) bool {
+	m, err := wrapMessage(msg)
+	if err != nil {
+		return false
+	}
+	if !w.sent {
+		w.sent = true
+		mergeHeader(w.header, w.md.Header)
+	}
+	return w.send(m) == nil
+}
+
+func (w *streamWriter) finish(trailer http.Header, err error) error {
+	md := w.md
+	if w.sent {
+		md = &rpcruntime.ResponseMetadata{Trailer: w.md.Trailer}
+	}
+	if err != nil {
+		return serverError(err, md)
+	}
+	setResponseMetadata(w.header, trailer, md)
+	return nil
+}
@@ -0,0 +1,356 @@
+package connectrt
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+
+	"connectrpc.com/connect"
+	"github.com/ygrpc/rpccgo/rpcruntime"
+)
+
+// bridgeBaseURL is the base URL of the in-memory Connect server; the
+// host is never resolved.
+const bridgeBaseURL = "http://rpccgo.invalid"
+
+// bridgeTransport is an http.RoundTripper that serves every request
+// with handler in the calling process. Requests and responses are marked as
+// HTTP/2 so connect-go accepts bidi streams over it.
+type bridgeTransport struct {
+	handler http.Handler
+	wg      sync.WaitGroup
+}
+
+// RoundTrip runs the handler in its own goroutine and returns once it has
+// committed the response headers.
+func (t *bridgeTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	body, bodyWriter := io.Pipe()
+	trailer := http.Header{}
+	w := &bridgeResponseWriter{
+		header:    http.Header{},
+		body:      bodyWriter,
+		committed: make(chan struct{}),
+	}
+	serverReq := req.Clone(req.Context())
+	serverReq.Proto, serverReq.ProtoMajor, serverReq.ProtoMinor = "HTTP/2.0", 2, 0
+	serverReq.RequestURI = req.URL.RequestURI()
+	if serverReq.Body == nil {
+		serverReq.Body = http.NoBody
+	}
+
+	t.wg.Add(1)
+	go func() {
+		defer t.wg.Done()
+		// Like net/http, close the request body once the handler is done so a
+		// client still sending sees the stream end.
+		defer func() { _ = serverReq.Body.Close() }()
+		t.handler.ServeHTTP(w, serverReq)
+		w.commit(http.StatusOK)
+		// Trailers must be in place before the client reads io.EOF.
+		w.copyTrailers(trailer)
+		_ = bodyWriter.Close()
+	}()
+
+	select {
+	case <-w.committed:
+	case <-req.Context().Done():
+		_ = body.Close()
+		return nil, req.Context().Err()
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", w.status, http.StatusText(w.status)),
+		StatusCode:    w.status,
+		Proto:         "HTTP/2.0",
+		ProtoMajor:    2,
+		Header:        w.sentHeader,
+		Trailer:       trailer,
+		Body:          body,
+		ContentLength: -1,
+		Request:       req,
+	}, nil
+}
+
+// wait blocks until all handlers started by t have returned.
+func (t *bridgeTransport) wait() {
+	t.wg.Wait()
+}
+
+// bridgeResponseWriter streams the handler's response body to the
+// client through a pipe.
+type bridgeResponseWriter struct {
+	header     http.Header
+	body       *io.PipeWriter
+	once       sync.Once
+	status     int
+	sentHeader http.Header
+	committed  chan struct{}
+}
+
+func (w *bridgeResponseWriter) Header() http.Header {
+	return w.header
+}
+
+func (w *bridgeResponseWriter) WriteHeader(status int) {
+	w.commit(status)
+}
+
+func (w *bridgeResponseWriter) Write(p []byte) (int, error) {
+	w.commit(http.StatusOK)
+	return w.body.Write(p)
+}
+
+// Flush commits the headers; writes are unbuffered.
+func (w *bridgeResponseWriter) Flush() {
+	w.commit(http.StatusOK)
+}
+
+// copyTrailers copies the trailers set by the handler, either declared in the
+// Trailer header or prefixed with http.TrailerPrefix, into dst.
+func (w *bridgeResponseWriter) copyTrailers(dst http.Header) {
+	for _, declared := range w.sentHeader.Values("Trailer") {
+		for _, k := range strings.Split(declared, ",") {
+			k = http.CanonicalHeaderKey(strings.TrimSpace(k))
+			if v, ok := w.header[k]; ok {
+				dst[k] = v
+			}
+		}
+	}
+	for k, v := range w.header {
+		if name, ok := strings.CutPrefix(k, http.TrailerPrefix); ok {
+			dst[http.CanonicalHeaderKey(name)] = v
+		}
+	}
+}
+
+func (w *bridgeResponseWriter) commit(status int) {
+	w.once.Do(func() {
+		w.status = status
+		w.sentHeader = w.header.Clone()
+		close(w.committed)
+	})
+}
+
+// newBridgeClient returns a Connect client for procedure served by
+// handler over a fresh bridgeTransport.
+func newBridgeClient[Req, Res any](
+	handler http.Handler,
+	procedure string,
+) (*connect.Client[Req, Res], *bridgeTransport) {
+	transport := &bridgeTransport{handler: handler}
+	client := connect.NewClient[Req, Res](&http.Client{Transport: transport}, bridgeBaseURL+procedure)
+	return client, transport
+}
+
+// bridgeContext gives the handler its own ResponseMetadata, so that
+// metadata recorded by the generic-API adapters does not race with the bridge
+// writing what the client side observes. The caller merges it into ctx once
+// the handler has returned.
+func bridgeContext(ctx context.Context) (context.Context, *rpcruntime.ResponseMetadata) {
+	if rpcruntime.ResponseMetadataFromContext(ctx) == nil {
+		return ctx, &rpcruntime.ResponseMetadata{Header: http.Header{}, Trailer: http.Header{}}
+	}
+	return rpcruntime.WithResponseMetadata(ctx)
+}
+
+// recordBridgeMetadata records the response headers and trailers seen by the
+// bridge client, minus the ones set by the Connect protocol itself.
+func recordBridgeMetadata(ctx context.Context, header, trailer http.Header) {
+	rpcruntime.RecordReceivedResponseMetadata(ctx, header, trailer)
+}
+
+// bridgeError returns the error of a bridged call: the handler's own
+// error if it returned one, so sentinel errors survive the round trip,
+// otherwise the client's.
+func bridgeError(handlerErr, clientErr error) error {
+	if handlerErr != nil {
+		return handlerErr
+	}
+	return clientErr
+}
+
+// pumpSession sends the messages written to session to send until the caller
+// closes its send side. It stops early without error when send fails; the
+// cause is reported by the receive side.
+func pumpSession[Req any](session rpcruntime.StreamSession, send func(*Req) error) error {
+	in := rpcruntime.NewGoClientStream[Req](session)
+	for {
+		req, err := in.Recv()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if err := send(req); err != nil {
+			return nil
+		}
+	}
+}
+
+// recoverBridgeHandler converts a handler panic into *errp, since the
+// handler runs on a goroutine owned by the transport.
+func recoverBridgeHandler(errp *error) {
+	if r := recover(); r != nil {
+		*errp = rpcruntime.RecoverPanic(r)
+	}
+}
+
+func serveClientStreamHTTP[Req, Res any](
+	ctx context.Context,
+	session rpcruntime.StreamSession,
+	procedure string,
+	call func(context.Context, *connect.ClientStream[Req]) (*Res, error),
+) (*Res, error) {
+	var handlerErr error
+	handler := connect.NewClientStreamHandler(
+		procedure,
+		func(ctx context.Context, stream *connect.ClientStream[Req]) (resp *connect.Response[Res], err error) {
+			defer func() { handlerErr = err }()
+			defer recoverBridgeHandler(&err)
+			res, err := call(ctx, stream)
+			if err != nil {
+				return nil, err
+			}
+			return connect.NewResponse(res), nil
+		},
+	)
+	client, transport := newBridgeClient[Req, Res](handler, procedure)
+	bridgeCtx, handlerMD := bridgeContext(ctx)
+	bridgeCtx, cancel := context.WithCancel(bridgeCtx)
+	defer rpcruntime.RecordResponseMetadata(ctx, handlerMD.Header, handlerMD.Trailer)
+	defer transport.wait()
+	defer cancel()
+
+	stream := client.CallClientStream(bridgeCtx)
+	mergeHeader(stream.RequestHeader(), rpcruntime.RequestHeaderFromContext(ctx))
+	if err := pumpSession(session, stream.Send); err != nil {
+		cancel()
+		_, _ = stream.CloseAndReceive()
+		return nil, err
+	}
+	resp, err := stream.CloseAndReceive()
+	if err != nil {
+		cancel()
+		transport.wait()
+		return nil, bridgeError(handlerErr, err)
+	}
+	recordBridgeMetadata(ctx, resp.Header(), resp.Trailer())
+	return resp.Msg, nil
+}
+
+func serveServerStreamHTTP[Req, Res any](
+	ctx context.Context,
+	session rpcruntime.StreamSession,
+	procedure string,
+	req *Req,
+	call func(context.Context, *Req, *connect.ServerStream[Res]) error,
+) error {
+	var handlerErr error
+	handler := connect.NewServerStreamHandler(
+		procedure,
+		func(ctx context.Context, req *connect.Request[Req], stream *connect.ServerStream[Res]) (err error) {
+			defer func() { handlerErr = err }()
+			defer recoverBridgeHandler(&err)
+			return call(ctx, req.Msg, stream)
+		},
+	)
+	client, transport := newBridgeClient[Req, Res](handler, procedure)
+	bridgeCtx, handlerMD := bridgeContext(ctx)
+	bridgeCtx, cancel := context.WithCancel(bridgeCtx)
+	defer rpcruntime.RecordResponseMetadata(ctx, handlerMD.Header, handlerMD.Trailer)
+	defer transport.wait()
+	defer cancel()
+
+	request := connect.NewRequest(req)
+	mergeHeader(request.Header(), rpcruntime.RequestHeaderFromContext(ctx))
+	stream, err := client.CallServerStream(bridgeCtx, request)
+	if err != nil {
+		cancel()
+		transport.wait()
+		return bridgeError(handlerErr, err)
+	}
+	onRead := session.OnRead()
+	for stream.Receive() {
+		if onRead != nil && !onRead(stream.Msg()) {
+			// Like StreamConn.Send, the handler's next Send fails.
+			cancel()
+			break
+		}
+	}
+	err = stream.Err()
+	recordBridgeMetadata(ctx, stream.ResponseHeader(), stream.ResponseTrailer())
+	_ = stream.Close()
+	cancel()
+	transport.wait()
+	return bridgeError(handlerErr, err)
+}
+
+func serveBidiStreamHTTP[Req, Res any](
+	ctx context.Context,
+	session rpcruntime.StreamSession,
+	procedure string,
+	call func(context.Context, *connect.BidiStream[Req, Res]) error,
+) error {
+	var handlerErr error
+	handler := connect.NewBidiStreamHandler(
+		procedure,
+		func(ctx context.Context, stream *connect.BidiStream[Req, Res]) (err error) {
+			defer func() { handlerErr = err }()
+			defer recoverBridgeHandler(&err)
+			return call(ctx, stream)
+		},
+	)
+	client, transport := newBridgeClient[Req, Res](handler, procedure)
+	bridgeCtx, handlerMD := bridgeContext(ctx)
+	bridgeCtx, cancel := context.WithCancel(bridgeCtx)
+	defer rpcruntime.RecordResponseMetadata(ctx, handlerMD.Header, handlerMD.Trailer)
+	defer transport.wait()
+	defer cancel()
+
+	stream := client.CallBidiStream(bridgeCtx)
+	mergeHeader(stream.RequestHeader(), rpcruntime.RequestHeaderFromContext(ctx))
+	// Send the request headers right away so the handler starts even if the
+	// caller does not send anything first.
+	if err := stream.Send(nil); err != nil {
+		return err
+	}
+	pumpErr := make(chan error, 1)
+	go func() {
+		if err := pumpSession(session, stream.Send); err != nil {
+			pumpErr <- err
+			cancel()
+		}
+		_ = stream.CloseRequest()
+	}()
+
+	onRead := session.OnRead()
+	var recvErr error
+	for {
+		msg, err := stream.Receive()
+		if err != nil {
+			if !errors.Is(err, io.EOF) {
+				recvErr = err
+			}
+			break
+		}
+		if onRead != nil && !onRead(msg) {
+			cancel()
+			break
+		}
+	}
+	recordBridgeMetadata(ctx, stream.ResponseHeader(), stream.ResponseTrailer())
+	_ = stream.CloseResponse()
+	cancel()
+	transport.wait()
+
+	select {
+	case err := <-pumpErr:
+		return err
+	default:
+	}
+	return bridgeError(handlerErr, recvErr)
+}
@@ -0,0 +1,176 @@
+package connectrt
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+
+	"connectrpc.com/connect"
+	"github.com/ygrpc/rpccgo/rpcruntime"
+)
+
+// remoteTransport implements rpcruntime.RemoteTransport with connect-go
+// clients.
+type remoteTransport struct {
+	httpClient connect.HTTPClient
+	baseURL    string
+	opts       []connect.ClientOption
+}
+
+// NewRemote returns an rpcruntime.Remote forwarding calls with connect-go
+// clients built from httpClient, baseURL and opts, as passed to a generated
+// New<Service>Client. Use connect.WithGRPC or connect.WithGRPCWeb to select
+// another protocol than Connect.
+func NewRemote(httpClient connect.HTTPClient, baseURL string, opts ...connect.ClientOption) *rpcruntime.Remote {
+	return rpcruntime.NewRemote(remoteTransport{
+		httpClient: httpClient,
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		opts:       opts,
+	})
+}
+
+func (t remoteTransport) Protocol() rpcruntime.Protocol {
+	return rpcruntime.ProtocolConnectRPC
+}
+
+// client returns a client for fullMethod decoding responses into the messages
+// returned by newResponse.
+func (t remoteTransport) client(fullMethod string, newResponse func() any) *connect.Client[dynMessage, dynMessage] {
+	opts := append(t.opts[:len(t.opts):len(t.opts)], connect.WithResponseInitializer(messageInitializer(newResponse)))
+	return connect.NewClient[dynMessage, dynMessage](t.httpClient, t.baseURL+fullMethod, opts...)
+}
+
+// newRemoteRequest wraps req in a connect.Request carrying the request headers
+// of ctx.
+func newRemoteRequest(ctx context.Context, req any) (*connect.Request[dynMessage], error) {
+	msg, err := wrapMessage(req)
+	if err != nil {
+		return nil, err
+	}
+	request := connect.NewRequest(msg)
+	mergeHeader(request.Header(), rpcruntime.RequestHeaderFromContext(ctx))
+	return request, nil
+}
+
+func (t remoteTransport) Unary(ctx context.Context, fullMethod string, req, resp any) error {
+	request, err := newRemoteRequest(ctx, req)
+	if err != nil {
+		return err
+	}
+	response, err := t.client(fullMethod, func() any { return resp }).CallUnary(ctx, request)
+	if err != nil {
+		return err
+	}
+	rpcruntime.RecordReceivedResponseMetadata(ctx, response.Header(), response.Trailer())
+	return nil
+}
+
+func (t remoteTransport) ClientStream(ctx context.Context, fullMethod string, rs rpcruntime.RemoteStream, resp any) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	stream := t.client(fullMethod, func() any { return resp }).CallClientStream(ctx)
+	mergeHeader(stream.RequestHeader(), rpcruntime.RequestHeaderFromContext(ctx))
+	if err := pumpRemoteStream(rs, stream.Send); err != nil {
+		cancel()
+		_, _ = stream.CloseAndReceive()
+		return err
+	}
+	response, err := stream.CloseAndReceive()
+	if err != nil {
+		return err
+	}
+	rpcruntime.RecordReceivedResponseMetadata(ctx, response.Header(), response.Trailer())
+	return nil
+}
+
+func (t remoteTransport) ServerStream(ctx context.Context, fullMethod string, req any, rs rpcruntime.RemoteStream) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	request, err := newRemoteRequest(ctx, req)
+	if err != nil {
+		return err
+	}
+	stream, err := t.client(fullMethod, rs.NewResponse).CallServerStream(ctx, request)
+	if err != nil {
+		return err
+	}
+	for stream.Receive() {
+		if rs.Send(stream.Msg().Message) != nil {
+			cancel()
+			break
+		}
+	}
+	err = stream.Err()
+	rpcruntime.RecordReceivedResponseMetadata(ctx, stream.ResponseHeader(), stream.ResponseTrailer())
+	_ = stream.Close()
+	return err
+}
+
+func (t remoteTransport) BidiStream(ctx context.Context, fullMethod string, rs rpcruntime.RemoteStream) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	stream := t.client(fullMethod, rs.NewResponse).CallBidiStream(ctx)
+	mergeHeader(stream.RequestHeader(), rpcruntime.RequestHeaderFromContext(ctx))
+	// Send the request headers right away so the call starts even if the
+	// caller does not send anything first.
+	if err := stream.Send(nil); err != nil {
+		return err
+	}
+	pumpErr := make(chan error, 1)
+	go func() {
+		if err := pumpRemoteStream(rs, stream.Send); err != nil {
+			pumpErr <- err
+			cancel()
+		}
+		_ = stream.CloseRequest()
+	}()
+
+	var recvErr error
+	for {
+		msg, err := stream.Receive()
+		if err != nil {
+			if !errors.Is(err, io.EOF) {
+				recvErr = err
+			}
+			break
+		}
+		if rs.Send(msg.Message) != nil {
+			cancel()
+			break
+		}
+	}
+	rpcruntime.RecordReceivedResponseMetadata(ctx, stream.ResponseHeader(), stream.ResponseTrailer())
+	_ = stream.CloseResponse()
+	select {
+	case err := <-pumpErr:
+		return err
+	default:
+	}
+	return recvErr
+}
+
+// pumpRemoteStream sends the messages read from rs to send until the caller
+// closes its send side. It stops early without error when send fails; the
+// cause is reported by the receive side.
+func pumpRemoteStream(rs rpcruntime.RemoteStream, send func(*dynMessage) error) error {
+	for {
+		req, err := rs.Recv()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		msg, err := wrapMessage(req)
+		if err != nil {
+			return err
+		}
+		if err := send(msg); err != nil {
+			return nil
+		}
+	}
+}
@@ -0,0 +1,73 @@
+package connectrt
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	"connectrpc.com/connect"
+	"github.com/ygrpc/rpccgo/rpcruntime"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+func TestRemoteUnary(t *testing.T) {
+	httpClient := For(rpcruntime.NewRuntime()).HTTPClient()
+	remotes := map[string]*rpcruntime.Remote{
+		"connect": NewRemote(httpClient, "inproc:///"),
+		"grpc":    NewRemote(httpClient, "inproc://", connect.WithGRPC()),
+	}
+	for name, remote := range remotes {
+		t.Run(name, func(t *testing.T) {
+			ctx := rpcruntime.WithRequestHeader(context.Background(), http.Header{
+				"X-Test":       {"v1"},
+				"Content-Type": {"application/grpc"},
+			})
+			ctx, md := rpcruntime.WithResponseMetadata(ctx)
+
+			resp, err := rpcruntime.ForwardUnary[wrapperspb.StringValue, wrapperspb.StringValue](
+				ctx, remote, clientTestMethod, wrapperspb.String("hi"))
+			if err != nil {
+				t.Fatalf("ForwardUnary failed: %v", err)
+			}
+			if resp.GetValue() != "hi:v1" {
+				t.Errorf("unexpected reply %q", resp.GetValue())
+			}
+			if got := md.Trailer.Get("X-Done"); got != "yes" {
+				t.Errorf("expected trailer X-Done=yes, got %q", got)
+			}
+		})
+	}
+}
+
+func TestRemoteBidiStream(t *testing.T) {
+	remote := NewRemote(For(newUnknownTestRuntime()).HTTPClient(), "inproc://")
+
+	var got []string
+	done := make(chan error, 1)
+	handle, err := rpcruntime.ForwardBidiStream[wrapperspb.StringValue](
+		context.Background(), remote, unknownTestMethod,
+		func(resp *wrapperspb.StringValue) bool {
+			got = append(got, resp.GetValue())
+			return true
+		},
+		func(err error) { done <- err },
+	)
+	if err != nil {
+		t.Fatalf("ForwardBidiStream failed: %v", err)
+	}
+	for _, v := range []string{"a", "b"} {
+		if err := rpcruntime.SendToStream(rpcruntime.StreamHandle(handle), wrapperspb.String(v)); err != nil {
+			t.Fatalf("SendToStream failed: %v", err)
+		}
+	}
+	if err := rpcruntime.CloseSendCh(rpcruntime.StreamHandle(handle)); err != nil {
+		t.Fatalf("CloseSendCh failed: %v", err)
+	}
+	if err := <-done; err != nil {
+		t.Fatalf("stream failed: %v", err)
+	}
+	if strings.Join(got, ",") != "echo:a,echo:b" {
+		t.Errorf("unexpected replies %v", got)
+	}
+}
@@ -1,4 +1,4 @@
-package rpcruntime
+package connectrt
 
 import (
 	"fmt"
@@ -8,7 +8,7 @@ import (
 	"sync"
 
 	"connectrpc.com/connect"
-	"google.golang.org/protobuf/proto"
+	"github.com/ygrpc/rpccgo/rpcruntime"
 )
 
 var (
@@ -41,6 +41,14 @@ func checkConnFieldLayout(streamStructType reflect.Type, streamTypeName string)
 }
 
 func mustCheckConnectStreamLayout() {
+	if err := checkConnectStreamLayout(); err != nil {
+		panic(err)
+	}
+}
+
+// checkConnectStreamLayout reports whether connect-go's stream types have the
+// layout setConnField relies on. The result is computed once.
+func checkConnectStreamLayout() error {
 	checkConnectStreamLayoutOnce.Do(func() {
 		// Use reflect to check that 'conn' field exists and is settable for each stream type.
 		if err := checkConnFieldLayout(reflect.TypeOf(connect.ClientStream[any]{}), "connect.ClientStream[T]"); err != nil {
@@ -56,39 +64,58 @@ func mustCheckConnectStreamLayout() {
 			return
 		}
 	})
-	if checkConnectStreamLayoutErr != nil {
-		panic(checkConnectStreamLayoutErr)
-	}
+	return checkConnectStreamLayoutErr
 }
 
-// ConnectStreamConn implements connect.StreamingHandlerConn for CGO adaptor use.
+// StreamConn implements connect.StreamingHandlerConn for CGO adaptor use.
 // This bridges rpcruntime.StreamSession with Connect's streaming expectations.
-type ConnectStreamConn struct {
-	session StreamSession
+type StreamConn struct {
+	session         rpcruntime.StreamSession
+	requestHeader   http.Header
+	responseHeader  http.Header
+	responseTrailer http.Header
 }
 
-// NewConnectStreamConn creates a new ConnectStreamConn.
-func NewConnectStreamConn(session StreamSession) *ConnectStreamConn {
-	return &ConnectStreamConn{session: session}
+// NewStreamConn creates a new StreamConn.
+//
+// The request headers are copied from the session context (see
+// rpcruntime.WithRequestHeader). Response headers and trailers set by the
+// handler are written straight to the context's rpcruntime.ResponseMetadata,
+// if any.
+func NewStreamConn(session rpcruntime.StreamSession) *StreamConn {
+	conn := &StreamConn{
+		session:         session,
+		requestHeader:   http.Header{},
+		responseHeader:  http.Header{},
+		responseTrailer: http.Header{},
+	}
+	if ctx := session.Context(); ctx != nil {
+		mergeHeader(conn.requestHeader, rpcruntime.RequestHeaderFromContext(ctx))
+		if md := rpcruntime.ResponseMetadataFromContext(ctx); md != nil {
+			conn.responseHeader = md.Header
+			conn.responseTrailer = md.Trailer
+		}
+	}
+	return conn
 }
 
 // Spec returns the specification for the RPC.
-func (c *ConnectStreamConn) Spec() connect.Spec {
+func (c *StreamConn) Spec() connect.Spec {
 	return connect.Spec{}
 }
 
 // Peer describes the client for this RPC.
-func (c *ConnectStreamConn) Peer() connect.Peer {
+func (c *StreamConn) Peer() connect.Peer {
 	return connect.Peer{}
 }
 
 // Receive reads a message from the session's send channel.
-func (c *ConnectStreamConn) Receive(msg any) error {
+func (c *StreamConn) Receive(msg any) error {
 	select {
 	case req := <-c.session.SendCh():
 		// Copy the received message to msg.
 		// This assumes msg is a pointer to the correct type.
-		if err := copyMessage(req, msg); err != nil {
+		if err := rpcruntime.CopyMessage(req, msg); err != nil {
 			return connect.NewError(connect.CodeInternal, err)
 		}
 		return nil
@@ -97,7 +124,7 @@ func (c *ConnectStreamConn) Receive(msg any) error {
 		case req := <-c.session.SendCh():
 			// Copy the received message to msg.
 			// This assumes msg is a pointer to the correct type.
-			if err := copyMessage(req, msg); err != nil {
+			if err := rpcruntime.CopyMessage(req, msg); err != nil {
 				return connect.NewError(connect.CodeInternal, err)
 			}
 			return nil
@@ -110,12 +137,12 @@ func (c *ConnectStreamConn) Receive(msg any) error {
 }
 
 // RequestHeader returns the headers received from the client.
-func (c *ConnectStreamConn) RequestHeader() http.Header {
-	return http.Header{}
+func (c *StreamConn) RequestHeader() http.Header {
+	return c.requestHeader
 }
 
 // Send sends a message to the client via the onRead callback.
-func (c *ConnectStreamConn) Send(msg any) error {
+func (c *StreamConn) Send(msg any) error {
 	if cb := c.session.OnRead(); cb != nil {
 		if !cb(msg) {
 			return connect.NewError(connect.CodeCanceled, nil)
@@ -125,32 +152,13 @@ func (c *ConnectStreamConn) Send(msg any) error {
 }
 
 // ResponseHeader returns the response headers.
-func (c *ConnectStreamConn) ResponseHeader() http.Header {
-	return http.Header{}
+func (c *StreamConn) ResponseHeader() http.Header {
+	return c.responseHeader
 }
 
 // ResponseTrailer returns the response trailers.
-func (c *ConnectStreamConn) ResponseTrailer() http.Header {
-	return http.Header{}
-}
-
-// copyMessage copies src to dst using proto.Merge.
-// Both src and dst should be pointers to the same proto message type.
-func copyMessage(src, dst any) error {
-	srcMsg, ok := src.(proto.Message)
-	if !ok {
-		return ErrStreamMessageTypeMismatch
-	}
-	dstMsg, ok := dst.(proto.Message)
-	if !ok {
-		return ErrStreamMessageTypeMismatch
-	}
-	if srcMsg.ProtoReflect().Descriptor().FullName() != dstMsg.ProtoReflect().Descriptor().FullName() {
-		return ErrStreamMessageTypeMismatch
-	}
-	proto.Reset(dstMsg)
-	proto.Merge(dstMsg, srcMsg)
-	return nil
+func (c *StreamConn) ResponseTrailer() http.Header {
+	return c.responseTrailer
 }
 
 // setConnField uses reflect to set the 'conn' field of a stream struct.
@@ -158,15 +166,15 @@ func copyMessage(src, dst any) error {
 func setConnField(streamPtr any, conn connect.StreamingHandlerConn, streamTypeName string) {
 	rv := reflect.ValueOf(streamPtr)
 	if rv.Kind() != reflect.Ptr || rv.IsNil() {
-		panic(fmt.Sprintf("rpcruntime: %s: expected non-nil pointer", streamTypeName))
+		panic(fmt.Sprintf("connectrt: %s: expected non-nil pointer", streamTypeName))
 	}
 	elem := rv.Elem()
 	if elem.Kind() != reflect.Struct {
-		panic(fmt.Sprintf("rpcruntime: %s: expected struct, got %s", streamTypeName, elem.Kind()))
+		panic(fmt.Sprintf("connectrt: %s: expected struct, got %s", streamTypeName, elem.Kind()))
 	}
 	connField := elem.FieldByName("conn")
 	if !connField.IsValid() {
-		panic(fmt.Sprintf("rpcruntime: %s: missing 'conn' field", streamTypeName))
+		panic(fmt.Sprintf("connectrt: %s: missing 'conn' field", streamTypeName))
 	}
 	if !connField.CanSet() {
 		// Use reflect.NewAt to get a settable value for unexported field
@@ -181,7 +189,7 @@ func setConnField(streamPtr any, conn connect.StreamingHandlerConn, streamTypeNa
 // SetClientStreamConn sets the conn field of a connect.ClientStream using reflect.
 func SetClientStreamConn[Req any](stream *connect.ClientStream[Req], conn connect.StreamingHandlerConn) {
 	if stream == nil {
-		panic("rpcruntime: SetClientStreamConn called with nil stream")
+		panic("connectrt: SetClientStreamConn called with nil stream")
 	}
 	mustCheckConnectStreamLayout()
 	setConnField(stream, conn, "SetClientStreamConn")
@@ -190,7 +198,7 @@ func SetClientStreamConn[Req any](stream *connect.ClientStream[Req], conn connec
 // SetServerStreamConn sets the conn field of a connect.ServerStream using reflect.
 func SetServerStreamConn[Res any](stream *connect.ServerStream[Res], conn connect.StreamingHandlerConn) {
 	if stream == nil {
-		panic("rpcruntime: SetServerStreamConn called with nil stream")
+		panic("connectrt: SetServerStreamConn called with nil stream")
 	}
 	mustCheckConnectStreamLayout()
 	setConnField(stream, conn, "SetServerStreamConn")
@@ -199,7 +207,7 @@ func SetServerStreamConn[Res any](stream *connect.ServerStream[Res], conn connec
 // SetBidiStreamConn sets the conn field of a connect.BidiStream using reflect.
 func SetBidiStreamConn[Req, Res any](stream *connect.BidiStream[Req, Res], conn connect.StreamingHandlerConn) {
 	if stream == nil {
-		panic("rpcruntime: SetBidiStreamConn called with nil stream")
+		panic("connectrt: SetBidiStreamConn called with nil stream")
 	}
 	mustCheckConnectStreamLayout()
 	setConnField(stream, conn, "SetBidiStreamConn")
@@ -211,11 +219,11 @@ func SetBidiStreamConn[Req, Res any](stream *connect.BidiStream[Req, Res], conn
 // when the stream is nil or if the underlying reflection-based injection fails.
 func TrySetClientStreamConn[Req any](stream *connect.ClientStream[Req], conn connect.StreamingHandlerConn) (err error) {
 	if stream == nil {
-		return fmt.Errorf("rpcruntime: TrySetClientStreamConn: nil stream")
+		return fmt.Errorf("connectrt: TrySetClientStreamConn: nil stream")
 	}
 	defer func() {
 		if r := recover(); r != nil {
-			err = fmt.Errorf("rpcruntime: TrySetClientStreamConn: %w", RecoverPanic(r))
+			err = fmt.Errorf("connectrt: TrySetClientStreamConn: %w", rpcruntime.RecoverPanic(r))
 		}
 	}()
 	SetClientStreamConn(stream, conn)
@@ -228,11 +236,11 @@ func TrySetClientStreamConn[Req any](stream *connect.ClientStream[Req], conn con
 // when the stream is nil or if the underlying reflection-based injection fails.
 func TrySetServerStreamConn[Res any](stream *connect.ServerStream[Res], conn connect.StreamingHandlerConn) (err error) {
 	if stream == nil {
-		return fmt.Errorf("rpcruntime: TrySetServerStreamConn: nil stream")
+		return fmt.Errorf("connectrt: TrySetServerStreamConn: nil stream")
 	}
 	defer func() {
 		if r := recover(); r != nil {
-			err = fmt.Errorf("rpcruntime: TrySetServerStreamConn: %w", RecoverPanic(r))
+			err = fmt.Errorf("connectrt: TrySetServerStreamConn: %w", rpcruntime.RecoverPanic(r))
 		}
 	}()
 	SetServerStreamConn(stream, conn)
@@ -248,11 +256,11 @@ func TrySetBidiStreamConn[Req, Res any](
 	conn connect.StreamingHandlerConn,
 ) (err error) {
 	if stream == nil {
-		return fmt.Errorf("rpcruntime: TrySetBidiStreamConn: nil stream")
+		return fmt.Errorf("connectrt: TrySetBidiStreamConn: nil stream")
 	}
 	defer func() {
 		if r := recover(); r != nil {
-			err = fmt.Errorf("rpcruntime: TrySetBidiStreamConn: %w", RecoverPanic(r))
+			err = fmt.Errorf("connectrt: TrySetBidiStreamConn: %w", rpcruntime.RecoverPanic(r))
 		}
 	}()
 	SetBidiStreamConn(stream, conn)
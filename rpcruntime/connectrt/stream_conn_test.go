@@ -1,13 +1,22 @@
-package rpcruntime
+package connectrt
 
 import (
+	"context"
 	"reflect"
 	"testing"
 
 	"connectrpc.com/connect"
-	"google.golang.org/protobuf/types/known/wrapperspb"
+	"github.com/ygrpc/rpccgo/rpcruntime"
 )
 
+// newTestSession returns a stream session released when t ends.
+func newTestSession(t *testing.T) rpcruntime.StreamSession {
+	t.Helper()
+	handle, _, _ := rpcruntime.AllocateStreamHandle(context.Background(), rpcruntime.ProtocolConnectRPC)
+	t.Cleanup(func() { rpcruntime.FinishStreamHandle(handle) })
+	return rpcruntime.GetStreamSession(handle)
+}
+
 // getConnField uses reflect to read the 'conn' field value from a stream struct.
 func getConnField(streamPtr any) connect.StreamingHandlerConn {
 	rv := reflect.ValueOf(streamPtr)
@@ -31,7 +40,7 @@ func getConnField(streamPtr any) connect.StreamingHandlerConn {
 	return nil
 }
 
-func TestConnectStreamStructLayout(t *testing.T) {
+func TestStreamStructLayout(t *testing.T) {
 	t.Run("ReflectLayout", func(t *testing.T) {
 		if err := checkConnFieldLayout(reflect.TypeOf(connect.ClientStream[any]{}), "connect.ClientStream[T]"); err != nil {
 			t.Fatalf("client stream layout check failed: %v", err)
@@ -87,8 +96,7 @@ func TestCheckConnFieldLayoutErrors(t *testing.T) {
 
 // TestSetStreamConn verifies that SetXxxStreamConn functions work correctly.
 func TestSetStreamConn(t *testing.T) {
-	session := &streamSession{}
-	conn := NewConnectStreamConn(session)
+	conn := NewStreamConn(newTestSession(t))
 
 	t.Run("SetClientStreamConn", func(t *testing.T) {
 		stream := &connect.ClientStream[any]{}
@@ -119,8 +127,7 @@ func TestSetStreamConn(t *testing.T) {
 }
 
 func TestTrySetStreamConn(t *testing.T) {
-	session := &streamSession{}
-	conn := NewConnectStreamConn(session)
+	conn := NewStreamConn(newTestSession(t))
 
 	t.Run("NilReturnsError", func(t *testing.T) {
 		if err := TrySetClientStreamConn[any](nil, conn); err == nil {
@@ -162,8 +169,7 @@ func TestTrySetStreamConn(t *testing.T) {
 }
 
 func TestNewStreamsSetConn(t *testing.T) {
-	session := &streamSession{}
-	conn := NewConnectStreamConn(session)
+	conn := NewStreamConn(newTestSession(t))
 
 	client := NewClientStream[any](conn)
 	if getConnField(client) != conn {
@@ -180,32 +186,3 @@ func TestNewStreamsSetConn(t *testing.T) {
 		t.Fatal("NewBidiStream did not set conn correctly")
 	}
 }
-
-func TestCopyMessage(t *testing.T) {
-	t.Run("TypeMatchCopies", func(t *testing.T) {
-		src := &wrapperspb.StringValue{Value: "hello"}
-		dst := &wrapperspb.StringValue{Value: "stale"}
-
-		if err := copyMessage(src, dst); err != nil {
-			t.Fatalf("expected no error, got %v", err)
-		}
-		if dst.Value != "hello" {
-			t.Fatalf("expected dst to be updated, got %q", dst.Value)
-		}
-	})
-
-	t.Run("TypeMismatchReturnsError", func(t *testing.T) {
-		src := &wrapperspb.StringValue{Value: "hello"}
-		dst := &wrapperspb.Int32Value{Value: 1}
-
-		if err := copyMessage(src, dst); err == nil {
-			t.Fatal("expected error, got nil")
-		}
-	})
-
-	t.Run("NonProtoReturnsError", func(t *testing.T) {
-		if err := copyMessage("not proto", "also not proto"); err == nil {
-			t.Fatal("expected error, got nil")
-		}
-	})
-}
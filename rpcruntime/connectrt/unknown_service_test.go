@@ -0,0 +1,83 @@
+package connectrt
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+
+	"connectrpc.com/connect"
+	"github.com/ygrpc/rpccgo/rpcruntime"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+const unknownTestMethod = "/rpc.test.UnknownService/Echo"
+
+// newUnknownTestRuntime returns a Runtime whose unknown-service handlers
+// answer StringValue requests with "<method>:<value>" and echo stream messages
+// with an "echo:" prefix.
+func newUnknownTestRuntime() *rpcruntime.Runtime {
+	rt := rpcruntime.NewRuntime()
+	rt.SetUnknownServiceHandler(func(_ context.Context, fullMethod string, reqBytes []byte) ([]byte, error) {
+		req := &wrapperspb.StringValue{}
+		if err := proto.Unmarshal(reqBytes, req); err != nil {
+			return nil, err
+		}
+		return proto.Marshal(wrapperspb.String(fullMethod + ":" + req.GetValue()))
+	})
+	rt.SetUnknownStreamHandler(func(_ context.Context, _ string, stream rpcruntime.UnknownServiceStream) error {
+		for {
+			b, err := stream.Recv()
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+			req := &wrapperspb.StringValue{}
+			if err := proto.Unmarshal(b, req); err != nil {
+				return err
+			}
+			resp, _ := proto.Marshal(wrapperspb.String("echo:" + req.GetValue()))
+			if err := stream.Send(resp); err != nil {
+				return err
+			}
+		}
+	})
+	return rt
+}
+
+func TestUnknownServiceHTTPHandler(t *testing.T) {
+	rt := newUnknownTestRuntime()
+	httpClient := For(rt).HTTPClient()
+
+	for _, opts := range [][]connect.ClientOption{nil, {connect.WithGRPC()}} {
+		client := connect.NewClient[wrapperspb.StringValue, wrapperspb.StringValue](
+			httpClient, "inproc://"+unknownTestMethod, opts...)
+
+		stream := client.CallBidiStream(context.Background())
+		if err := stream.Send(wrapperspb.String("x")); err != nil {
+			t.Fatalf("Send failed: %v", err)
+		}
+		resp, err := stream.Receive()
+		if err != nil {
+			t.Fatalf("Receive failed: %v", err)
+		}
+		if resp.GetValue() != "echo:x" {
+			t.Errorf("unexpected stream reply %q", resp.GetValue())
+		}
+		_ = stream.CloseRequest()
+		_ = stream.CloseResponse()
+	}
+
+	client := connect.NewClient[wrapperspb.StringValue, wrapperspb.StringValue](
+		httpClient, "inproc://"+unknownTestMethod)
+	resp, err := client.CallUnary(context.Background(), connect.NewRequest(wrapperspb.String("hi")))
+	if err != nil {
+		t.Fatalf("CallUnary failed: %v", err)
+	}
+	if resp.Msg.GetValue() != unknownTestMethod+":hi" {
+		t.Errorf("unexpected reply %q", resp.Msg.GetValue())
+	}
+}
@@ -1,41 +1,48 @@
 package rpcruntime
 
-import "sync/atomic"
-
 type defaultProtocolState struct {
 	set      bool
 	protocol Protocol
 }
 
-var defaultProtocol atomic.Value
-
-func init() {
-	defaultProtocol.Store(defaultProtocolState{})
-}
-
 // SetDefaultProtocol sets the default protocol used by BackgroundContext.
 //
 // Passing Protocol(""), or calling ClearDefaultProtocol, leaves BackgroundContext without a protocol value.
 func SetDefaultProtocol(protocol Protocol) error {
+	return defaultRuntime.SetDefaultProtocol(protocol)
+}
+
+// ClearDefaultProtocol clears any default protocol selection.
+func ClearDefaultProtocol() {
+	defaultRuntime.ClearDefaultProtocol()
+}
+
+// DefaultProtocol returns the current default protocol and whether it is set.
+func DefaultProtocol() (Protocol, bool) {
+	return defaultRuntime.DefaultProtocol()
+}
+
+// SetDefaultProtocol sets the default protocol used by rt.BackgroundContext.
+func (rt *Runtime) SetDefaultProtocol(protocol Protocol) error {
 	switch protocol {
-	case ProtocolGrpc, ProtocolConnectRPC:
-		defaultProtocol.Store(defaultProtocolState{set: true, protocol: protocol})
+	case ProtocolGrpc, ProtocolConnectRPC, ProtocolGo, ProtocolTwirp:
+		rt.defaultProtocol.Store(defaultProtocolState{set: true, protocol: protocol})
 		return nil
 	case "":
-		defaultProtocol.Store(defaultProtocolState{})
+		rt.defaultProtocol.Store(defaultProtocolState{})
 		return nil
 	default:
 		return ErrUnknownProtocol
 	}
 }
 
-// ClearDefaultProtocol clears any default protocol selection.
-func ClearDefaultProtocol() {
-	defaultProtocol.Store(defaultProtocolState{})
+// ClearDefaultProtocol clears any default protocol selection of rt.
+func (rt *Runtime) ClearDefaultProtocol() {
+	rt.defaultProtocol.Store(defaultProtocolState{})
 }
 
-// DefaultProtocol returns the current default protocol and whether it is set.
-func DefaultProtocol() (Protocol, bool) {
-	st := defaultProtocol.Load().(defaultProtocolState)
+// DefaultProtocol returns the default protocol of rt and whether it is set.
+func (rt *Runtime) DefaultProtocol() (Protocol, bool) {
+	st := rt.defaultProtocol.Load().(defaultProtocolState)
 	return st.protocol, st.set
 }
@@ -1,6 +1,6 @@
 package rpcruntime
 
-import "sync"
+import "sort"
 
 // Protocol identifies the RPC protocol for handler registration.
 type Protocol string
@@ -10,6 +10,12 @@ const (
 	ProtocolGrpc Protocol = "grpc"
 	// ProtocolConnectRPC identifies connectrpc handlers.
 	ProtocolConnectRPC Protocol = "connectrpc"
+	// ProtocolGo identifies plain Go interface handlers, which depend on
+	// neither grpc-go nor connect-go (protocol=go).
+	ProtocolGo Protocol = "go"
+	// ProtocolTwirp identifies handlers implementing a Twirp service
+	// interface. Twirp only supports unary methods.
+	ProtocolTwirp Protocol = "twirp"
 )
 
 // handlerKey uniquely identifies a handler slot.
@@ -18,31 +24,28 @@ type handlerKey struct {
 	serviceName string
 }
 
-var (
-	handlerMu       sync.RWMutex
-	handlerRegistry = make(map[handlerKey]any)
-)
-
-// RegisterGrpcHandler registers a gRPC handler for the given serviceName.
+// RegisterHandler registers a handler for the given protocol and serviceName
+// in the default Runtime.
 //
-// If a handler is already registered for (grpc, serviceName), it is replaced.
-// Returns replaced=true if an existing handler was overwritten.
-// Returns an error if serviceName is empty or handler is nil.
-func RegisterGrpcHandler(serviceName string, handler any) (replaced bool, err error) {
-	return registerHandler(ProtocolGrpc, serviceName, handler)
-}
-
-// RegisterConnectHandler registers a connectrpc handler for the given serviceName.
+// For ProtocolGo, handler must implement the <Service>_GoHandler interface
+// generated with protocol=go; for ProtocolTwirp it is typically the
+// implementation of the interface generated by protoc-gen-twirp.
 //
-// If a handler is already registered for (connectrpc, serviceName), it is replaced.
+// If a handler is already registered for (protocol, serviceName), it is
+// replaced and retired as if unregistered (see UnregisterHandler).
 // Returns replaced=true if an existing handler was overwritten.
-// Returns an error if serviceName is empty or handler is nil.
-func RegisterConnectHandler(serviceName string, handler any) (replaced bool, err error) {
-	return registerHandler(ProtocolConnectRPC, serviceName, handler)
+// Returns ErrUnknownProtocol for an unknown protocol, an error if serviceName
+// is empty or handler is nil, or ErrUnavailable once the Runtime has been
+// shut down.
+func RegisterHandler(protocol Protocol, serviceName string, handler any) (replaced bool, err error) {
+	return defaultRuntime.RegisterHandler(protocol, serviceName, handler)
 }
 
-// registerHandler is the internal implementation for handler registration.
-func registerHandler(protocol Protocol, serviceName string, handler any) (replaced bool, err error) {
+// RegisterHandler registers handler for (protocol, serviceName) in rt.
+func (rt *Runtime) RegisterHandler(protocol Protocol, serviceName string, handler any) (replaced bool, err error) {
+	if !validProtocol(protocol) {
+		return false, ErrUnknownProtocol
+	}
 	if serviceName == "" {
 		return false, ErrEmptyServiceName
 	}
@@ -52,74 +55,165 @@ func registerHandler(protocol Protocol, serviceName string, handler any) (replac
 
 	key := handlerKey{protocol: protocol, serviceName: serviceName}
 
-	handlerMu.Lock()
-	defer handlerMu.Unlock()
+	rt.watchMu.Lock()
+	rt.handlerMu.Lock()
+	if rt.shutdown {
+		rt.handlerMu.Unlock()
+		rt.watchMu.Unlock()
+		return false, ErrUnavailable
+	}
+	old, existed := rt.handlers[key]
+	rt.handlers[key] = &handlerEntry{handler: handler}
+	rt.handlerMu.Unlock()
 
-	_, existed := handlerRegistry[key]
-	handlerRegistry[key] = handler
+	kind := RegistryEventRegistered
+	if existed {
+		kind = RegistryEventReplaced
+	}
+	rt.queueRegistryEventLocked(RegistryEvent{Kind: kind, Protocol: protocol, ServiceName: serviceName}, nil)
+	rt.watchMu.Unlock()
+	rt.deliverRegistryEvents()
 
+	if existed {
+		logf(LogLevelWarn, "rpcruntime: replaced %s handler for %s", protocol, serviceName)
+		rt.retireHandler(protocol, serviceName, old)
+	}
+	if h, ok := handler.(HandlerRegisteredHook); ok {
+		h.OnRegistered(protocol, serviceName)
+	}
 	return existed, nil
 }
 
-// LookupGrpcHandler looks up a gRPC handler for the given serviceName.
-//
-// Returns the handler and ok=true if found, otherwise nil and ok=false.
-func LookupGrpcHandler(serviceName string) (handler any, ok bool) {
-	return lookupHandler(ProtocolGrpc, serviceName)
+// RegisterGrpcHandler registers a gRPC handler for serviceName in the default
+// Runtime. See RegisterHandler.
+func RegisterGrpcHandler(serviceName string, handler any) (replaced bool, err error) {
+	return defaultRuntime.RegisterGrpcHandler(serviceName, handler)
+}
+
+// RegisterGrpcHandler registers a gRPC handler for serviceName in rt.
+func (rt *Runtime) RegisterGrpcHandler(serviceName string, handler any) (replaced bool, err error) {
+	return rt.RegisterHandler(ProtocolGrpc, serviceName, handler)
+}
+
+// RegisterConnectHandler registers a connectrpc handler for serviceName in the
+// default Runtime. See RegisterHandler.
+func RegisterConnectHandler(serviceName string, handler any) (replaced bool, err error) {
+	return defaultRuntime.RegisterConnectHandler(serviceName, handler)
+}
+
+// RegisterConnectHandler registers a connectrpc handler for serviceName in rt.
+func (rt *Runtime) RegisterConnectHandler(serviceName string, handler any) (replaced bool, err error) {
+	return rt.RegisterHandler(ProtocolConnectRPC, serviceName, handler)
 }
 
-// LookupConnectHandler looks up a connectrpc handler for the given serviceName.
+// LookupHandler looks up the handler for the given protocol and serviceName in
+// the default Runtime.
 //
 // Returns the handler and ok=true if found, otherwise nil and ok=false.
-func LookupConnectHandler(serviceName string) (handler any, ok bool) {
-	return lookupHandler(ProtocolConnectRPC, serviceName)
+// If serviceName is an alias (see RegisterAlias) with no handler of its own,
+// the handler of its canonical name is returned.
+func LookupHandler(protocol Protocol, serviceName string) (handler any, ok bool) {
+	return defaultRuntime.LookupHandler(protocol, serviceName)
 }
 
-// lookupHandler is the internal implementation for handler lookup.
-func lookupHandler(protocol Protocol, serviceName string) (handler any, ok bool) {
+// LookupHandler looks up the handler for (protocol, serviceName) in rt.
+func (rt *Runtime) LookupHandler(protocol Protocol, serviceName string) (handler any, ok bool) {
 	key := handlerKey{protocol: protocol, serviceName: serviceName}
 
-	handlerMu.RLock()
-	defer handlerMu.RUnlock()
+	rt.handlerMu.RLock()
+	defer rt.handlerMu.RUnlock()
+
+	e, exists := rt.handlerEntryLocked(key)
+	if !exists {
+		return nil, false
+	}
+	return e.handler, true
+}
 
-	h, exists := handlerRegistry[key]
-	return h, exists
+// LookupGrpcHandler looks up a gRPC handler for serviceName in the default
+// Runtime. See LookupHandler.
+func LookupGrpcHandler(serviceName string) (handler any, ok bool) {
+	return defaultRuntime.LookupGrpcHandler(serviceName)
 }
 
-// ListGrpcServices returns all registered gRPC service names.
-//
-// Useful for debugging and observability.
-func ListGrpcServices() []string {
-	return listServices(ProtocolGrpc)
+// LookupGrpcHandler looks up a gRPC handler for serviceName in rt.
+func (rt *Runtime) LookupGrpcHandler(serviceName string) (handler any, ok bool) {
+	return rt.LookupHandler(ProtocolGrpc, serviceName)
 }
 
-// ListConnectServices returns all registered connectrpc service names.
+// LookupConnectHandler looks up a connectrpc handler for serviceName in the
+// default Runtime. See LookupHandler.
+func LookupConnectHandler(serviceName string) (handler any, ok bool) {
+	return defaultRuntime.LookupConnectHandler(serviceName)
+}
+
+// LookupConnectHandler looks up a connectrpc handler for serviceName in rt.
+func (rt *Runtime) LookupConnectHandler(serviceName string) (handler any, ok bool) {
+	return rt.LookupHandler(ProtocolConnectRPC, serviceName)
+}
+
+// ListServices returns the service names with a handler registered for
+// protocol in the default Runtime, sorted. Aliases are not included; see
+// ListAliases.
 //
 // Useful for debugging and observability.
-func ListConnectServices() []string {
-	return listServices(ProtocolConnectRPC)
+func ListServices(protocol Protocol) []string {
+	return defaultRuntime.ListServices(protocol)
 }
 
-// listServices is the internal implementation for listing services.
-func listServices(protocol Protocol) []string {
-	handlerMu.RLock()
-	defer handlerMu.RUnlock()
+// ListServices returns the service names with a handler for protocol in rt.
+func (rt *Runtime) ListServices(protocol Protocol) []string {
+	rt.handlerMu.RLock()
+	defer rt.handlerMu.RUnlock()
 
 	var services []string
-	for key := range handlerRegistry {
+	for key := range rt.handlers {
 		if key.protocol == protocol {
 			services = append(services, key.serviceName)
 		}
 	}
+	sort.Strings(services)
 
 	return services
 }
 
-// clearHandlerRegistry clears the handler registry.
+// ListGrpcServices returns all gRPC service names registered in the default
+// Runtime, sorted. See ListServices.
+func ListGrpcServices() []string {
+	return defaultRuntime.ListGrpcServices()
+}
+
+// ListGrpcServices returns all gRPC service names registered in rt.
+func (rt *Runtime) ListGrpcServices() []string {
+	return rt.ListServices(ProtocolGrpc)
+}
+
+// ListConnectServices returns all connectrpc service names registered in the
+// default Runtime, sorted. See ListServices.
+func ListConnectServices() []string {
+	return defaultRuntime.ListConnectServices()
+}
+
+// ListConnectServices returns all connectrpc service names registered in rt.
+func (rt *Runtime) ListConnectServices() []string {
+	return rt.ListServices(ProtocolConnectRPC)
+}
+
+// validProtocol reports whether p is one of the Protocol constants.
+func validProtocol(p Protocol) bool {
+	switch p {
+	case ProtocolGrpc, ProtocolConnectRPC, ProtocolGo, ProtocolTwirp:
+		return true
+	default:
+		return false
+	}
+}
+
+// clearHandlerRegistry clears the handler registry of the default Runtime.
 // This is intended for testing only.
 func clearHandlerRegistry() {
-	handlerMu.Lock()
-	defer handlerMu.Unlock()
+	defaultRuntime.handlerMu.Lock()
+	defer defaultRuntime.handlerMu.Unlock()
 
-	handlerRegistry = make(map[handlerKey]any)
+	defaultRuntime.handlers = make(map[handlerKey]*handlerEntry)
 }
@@ -9,6 +9,12 @@ var (
 
 	// ErrNilHandler is returned when registration is attempted with a nil handler.
 	ErrNilHandler = errors.New("rpcruntime: handler cannot be nil")
+
+	// ErrNilRemote is returned when RegisterRemote is called with a nil remote.
+	ErrNilRemote = errors.New("rpcruntime: remote cannot be nil")
+
+	// ErrAliasCycle is returned when RegisterAlias would make a service name an alias of itself.
+	ErrAliasCycle = errors.New("rpcruntime: alias resolves to itself")
 )
 
 // Sentinel errors for adaptor dispatch.
@@ -27,4 +33,22 @@ var (
 
 	// ErrStreamMessageTypeMismatch is returned when stream message types do not match.
 	ErrStreamMessageTypeMismatch = errors.New("rpcruntime: stream message type mismatch")
+
+	// ErrUnavailable is returned for new calls and registrations once the runtime has been shut down.
+	ErrUnavailable = errors.New("rpcruntime: runtime unavailable")
+
+	// ErrResourceExhausted is returned when a call exceeds a configured concurrency limit.
+	ErrResourceExhausted = errors.New("rpcruntime: resource exhausted")
+
+	// ErrNotInitialized is returned for calls made before Init when initializers are registered.
+	ErrNotInitialized = errors.New("rpcruntime: runtime not initialized")
+
+	// ErrAlreadyInitialized is returned when Init is called after it has already succeeded.
+	ErrAlreadyInitialized = errors.New("rpcruntime: runtime already initialized")
+
+	// ErrInvalidConnectStreamBridge is returned by SetConnectStreamBridge for an unknown bridge.
+	ErrInvalidConnectStreamBridge = errors.New("rpcruntime: invalid connect stream bridge")
+
+	// ErrInvalidRuntimeHandle is returned by CGO exports for a RuntimeHandle that does not resolve.
+	ErrInvalidRuntimeHandle = errors.New("rpcruntime: invalid or shut down runtime handle")
 )
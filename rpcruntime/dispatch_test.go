@@ -54,6 +54,69 @@ func TestRegisterAndLookupConnectHandler(t *testing.T) {
 	}
 }
 
+func TestRegisterAndLookupGoHandler(t *testing.T) {
+	clearHandlerRegistry()
+	defer clearHandlerRegistry()
+
+	serviceName := "rpc.test.TestService"
+	handler := &struct{ name string }{name: "go-handler"}
+
+	if _, err := RegisterHandler(ProtocolGo, serviceName, handler); err != nil {
+		t.Fatalf("RegisterHandler(ProtocolGo) failed: %v", err)
+	}
+
+	got, ok := LookupHandler(ProtocolGo, serviceName)
+	if !ok || got != handler {
+		t.Fatalf("LookupHandler(ProtocolGo) returned %v, %v", got, ok)
+	}
+	if _, ok := LookupGrpcHandler(serviceName); ok {
+		t.Error("go handler must not be visible as a gRPC handler")
+	}
+	if services := ListServices(ProtocolGo); len(services) != 1 || services[0] != serviceName {
+		t.Errorf("unexpected ListServices(ProtocolGo): %v", services)
+	}
+	if !UnregisterHandler(ProtocolGo, serviceName) {
+		t.Error("expected UnregisterHandler(ProtocolGo) to remove the handler")
+	}
+}
+
+func TestRegisterAndLookupTwirpHandler(t *testing.T) {
+	clearHandlerRegistry()
+	defer clearHandlerRegistry()
+
+	serviceName := "rpc.test.TestService"
+	handler := &struct{ name string }{name: "twirp-handler"}
+
+	if _, err := RegisterHandler(ProtocolTwirp, serviceName, handler); err != nil {
+		t.Fatalf("RegisterHandler(ProtocolTwirp) failed: %v", err)
+	}
+
+	got, ok := LookupHandler(ProtocolTwirp, serviceName)
+	if !ok || got != handler {
+		t.Fatalf("LookupHandler(ProtocolTwirp) returned %v, %v", got, ok)
+	}
+	if _, ok := LookupHandler(ProtocolGo, serviceName); ok {
+		t.Error("twirp handler must not be visible as a go handler")
+	}
+	if services := ListServices(ProtocolTwirp); len(services) != 1 || services[0] != serviceName {
+		t.Errorf("unexpected ListServices(ProtocolTwirp): %v", services)
+	}
+	if !UnregisterHandler(ProtocolTwirp, serviceName) {
+		t.Error("expected UnregisterHandler(ProtocolTwirp) to remove the handler")
+	}
+}
+
+func TestRegisterHandlerUnknownProtocol(t *testing.T) {
+	rt := NewRuntime()
+
+	if _, err := rt.RegisterHandler("thrift", "rpc.test.TestService", struct{}{}); err != ErrUnknownProtocol {
+		t.Errorf("expected ErrUnknownProtocol, got %v", err)
+	}
+	if services := rt.ListServices("thrift"); len(services) != 0 {
+		t.Errorf("unexpected ListServices: %v", services)
+	}
+}
+
 func TestTwoProtocolsForSameService(t *testing.T) {
 	clearHandlerRegistry()
 	defer clearHandlerRegistry()
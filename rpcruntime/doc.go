@@ -1,7 +1,16 @@
 // Package rpcruntime provides small runtime helpers intended to be used by
 // generated CGO code.
 //
-// Currently it contains a global error message registry:
+// Handlers, stream sessions, stored errors and the default protocol are owned
+// by a Runtime. The package-level functions operate on the default Runtime;
+// WithRuntime selects another Runtime for calls through generated adaptors,
+// and a RuntimeHandle (see Runtime.Handle) selects one across the C ABI.
+//
+// The package depends on neither grpc-go nor connect-go. Serving a Runtime
+// over gRPC or Connect, calling it through their clients and forwarding to
+// remote services live in the subpackages grpcrt and connectrt.
+//
+// The error message registry works as follows:
 //   - Go code stores an error message and receives an integer errorId.
 //   - C code retrieves the message through a stable ABI (Ygrpc_GetErrorMsg)
 //     implemented in the generated CGO package main.
@@ -0,0 +1,115 @@
+package rpcruntime
+
+import (
+	"errors"
+	"time"
+)
+
+// ErrorKind classifies a stored error by the rpcruntime sentinel it wraps.
+//
+// The numeric values are part of the C ABI (YgrpcErrKind) and must not change.
+type ErrorKind int
+
+const (
+	// ErrorKindUnspecified matches no error.
+	ErrorKindUnspecified ErrorKind = 0
+	// ErrorKindServiceNotRegistered matches ErrServiceNotRegistered.
+	ErrorKindServiceNotRegistered ErrorKind = 1
+	// ErrorKindHandlerTypeMismatch matches ErrHandlerTypeMismatch.
+	ErrorKindHandlerTypeMismatch ErrorKind = 2
+	// ErrorKindInvalidStreamHandle matches ErrInvalidStreamHandle.
+	ErrorKindInvalidStreamHandle ErrorKind = 3
+	// ErrorKindUnknownProtocol matches ErrUnknownProtocol.
+	ErrorKindUnknownProtocol ErrorKind = 4
+	// ErrorKindStreamMessageTypeMismatch matches ErrStreamMessageTypeMismatch.
+	ErrorKindStreamMessageTypeMismatch ErrorKind = 5
+	// ErrorKindEmptyServiceName matches ErrEmptyServiceName.
+	ErrorKindEmptyServiceName ErrorKind = 6
+	// ErrorKindNilHandler matches ErrNilHandler.
+	ErrorKindNilHandler ErrorKind = 7
+	// ErrorKindUnavailable matches ErrUnavailable.
+	ErrorKindUnavailable ErrorKind = 8
+	// ErrorKindNotInitialized matches ErrNotInitialized.
+	ErrorKindNotInitialized ErrorKind = 9
+	// ErrorKindResourceExhausted matches ErrResourceExhausted.
+	ErrorKindResourceExhausted ErrorKind = 10
+	// ErrorKindAlreadyInitialized matches ErrAlreadyInitialized.
+	ErrorKindAlreadyInitialized ErrorKind = 11
+	// ErrorKindInvalidConnectStreamBridge matches ErrInvalidConnectStreamBridge.
+	ErrorKindInvalidConnectStreamBridge ErrorKind = 12
+	// ErrorKindNilRemote matches ErrNilRemote.
+	ErrorKindNilRemote ErrorKind = 13
+	// ErrorKindAliasCycle matches ErrAliasCycle.
+	ErrorKindAliasCycle ErrorKind = 14
+	// ErrorKindInvalidRuntimeHandle matches ErrInvalidRuntimeHandle.
+	ErrorKindInvalidRuntimeHandle ErrorKind = 15
+)
+
+// errorKindSentinels lists the sentinel checked for each ErrorKind.
+var errorKindSentinels = []struct {
+	kind ErrorKind
+	err  error
+}{
+	{ErrorKindServiceNotRegistered, ErrServiceNotRegistered},
+	{ErrorKindHandlerTypeMismatch, ErrHandlerTypeMismatch},
+	{ErrorKindInvalidStreamHandle, ErrInvalidStreamHandle},
+	{ErrorKindUnknownProtocol, ErrUnknownProtocol},
+	{ErrorKindStreamMessageTypeMismatch, ErrStreamMessageTypeMismatch},
+	{ErrorKindEmptyServiceName, ErrEmptyServiceName},
+	{ErrorKindNilHandler, ErrNilHandler},
+	{ErrorKindUnavailable, ErrUnavailable},
+	{ErrorKindNotInitialized, ErrNotInitialized},
+	{ErrorKindResourceExhausted, ErrResourceExhausted},
+	{ErrorKindAlreadyInitialized, ErrAlreadyInitialized},
+	{ErrorKindInvalidConnectStreamBridge, ErrInvalidConnectStreamBridge},
+	{ErrorKindNilRemote, ErrNilRemote},
+	{ErrorKindAliasCycle, ErrAliasCycle},
+	{ErrorKindInvalidRuntimeHandle, ErrInvalidRuntimeHandle},
+}
+
+// errorKindSet is a bitmask of ErrorKind values.
+type errorKindSet uint64
+
+func (s errorKindSet) has(kind ErrorKind) bool {
+	if kind <= ErrorKindUnspecified || kind >= 64 {
+		return false
+	}
+	return s&(1<<uint(kind)) != 0
+}
+
+// classifyError walks err's errors.Is chain and returns every matching kind.
+func classifyError(err error) errorKindSet {
+	var set errorKindSet
+	for _, s := range errorKindSentinels {
+		if errors.Is(err, s.err) {
+			set |= 1 << uint(s.kind)
+		}
+	}
+	return set
+}
+
+// ErrorIs reports whether the error stored under errorID in the default Runtime
+// wrapped the sentinel identified by kind when it was stored.
+//
+// It returns false for unknown or expired ids and for messages stored through
+// StoreErrorMsg.
+func ErrorIs(errorID uint64, kind ErrorKind) bool {
+	return defaultRuntime.ErrorIs(errorID, kind)
+}
+
+// ErrorIs reports whether the error stored under errorID in rt wrapped the
+// sentinel identified by kind.
+func (rt *Runtime) ErrorIs(errorID uint64, kind ErrorKind) bool {
+	if errorID == 0 {
+		return false
+	}
+	now := time.Now()
+
+	rt.errorMu.Lock()
+	defer rt.errorMu.Unlock()
+	record, exists := rt.errors[errorID]
+	if !exists || now.After(record.expiresAt) {
+		return false
+	}
+	return record.kinds.has(kind)
+}
@@ -1,41 +1,60 @@
 package rpcruntime
 
 import (
-	"sync"
 	"sync/atomic"
 	"time"
 )
 
 type errorRecord struct {
 	msg       []byte
+	kinds     errorKindSet
 	expiresAt time.Time
 }
 
-var (
-	registryMu sync.Mutex
-	registry   = make(map[uint64]errorRecord)
-
-	nextErrorID atomic.Uint64
-)
+// nextErrorID is shared by all runtimes so error ids are unique process-wide.
+var nextErrorID atomic.Uint64
 
 var errorTTL = 3 * time.Second
 
-// StoreError stores an error message in the global registry and returns its id.
+// StoreError stores an error message in the default Runtime and returns its id.
 //
+// The sentinel errors wrapped by err are captured at store time, see ErrorIs.
 // A returned id of 0 indicates "no error" (i.e. err is nil).
 func StoreError(err error) uint64 {
+	return defaultRuntime.StoreError(err)
+}
+
+// StoreErrorMsg stores msg in the default Runtime and returns its id.
+//
+// The stored bytes are copied.
+func StoreErrorMsg(msg []byte) uint64 {
+	return defaultRuntime.StoreErrorMsg(msg)
+}
+
+// GetErrorMsgBytes returns a copy of the message stored in the default Runtime.
+//
+// If the record is expired, it is removed and ok is false.
+func GetErrorMsgBytes(errorID uint64) (msg []byte, ok bool) {
+	return defaultRuntime.GetErrorMsgBytes(errorID)
+}
+
+// StoreError stores an error message in rt and returns its id.
+func (rt *Runtime) StoreError(err error) uint64 {
 	if err == nil {
 		return 0
 	} else {
-		return StoreErrorMsg([]byte(err.Error()))
+		return rt.storeErrorRecord([]byte(err.Error()), classifyError(err))
 	}
 }
 
-// StoreErrorMsg stores msg in the global registry and returns its id.
-//
-// The stored bytes are copied.
-func StoreErrorMsg(msg []byte) uint64 {
-	startCleanerOnce.Do(startCleaner)
+// StoreErrorMsg stores msg in rt and returns its id.
+func (rt *Runtime) StoreErrorMsg(msg []byte) uint64 {
+	return rt.storeErrorRecord(msg, 0)
+}
+
+// storeErrorRecord is the internal implementation for storing errors.
+func (rt *Runtime) storeErrorRecord(msg []byte, kinds errorKindSet) uint64 {
+	rt.startCleanerOnce.Do(rt.startCleaner)
 
 	id := nextErrorID.Add(1)
 	copied := make([]byte, len(msg))
@@ -43,34 +62,34 @@ func StoreErrorMsg(msg []byte) uint64 {
 
 	record := errorRecord{
 		msg:       copied,
+		kinds:     kinds,
 		expiresAt: time.Now().Add(errorTTL),
 	}
 
-	registryMu.Lock()
-	registry[id] = record
-	registryMu.Unlock()
+	rt.errorMu.Lock()
+	rt.errors[id] = record
+	rt.errorMu.Unlock()
 
+	notifyErrorHook(id, copied)
 	return id
 }
 
-// GetErrorMsgBytes returns a copy of the stored message bytes.
-//
-// If the record is expired, it is removed and ok is false.
-func GetErrorMsgBytes(errorID uint64) (msg []byte, ok bool) {
+// GetErrorMsgBytes returns a copy of the message stored in rt.
+func (rt *Runtime) GetErrorMsgBytes(errorID uint64) (msg []byte, ok bool) {
 	if errorID == 0 {
 		return nil, false
 	} else {
 		now := time.Now()
 
-		registryMu.Lock()
-		defer registryMu.Unlock()
-		record, exists := registry[errorID]
+		rt.errorMu.Lock()
+		defer rt.errorMu.Unlock()
+		record, exists := rt.errors[errorID]
 		if !exists {
 			return nil, false
 		}
 
 		if now.After(record.expiresAt) {
-			delete(registry, errorID)
+			delete(rt.errors, errorID)
 			return nil, false
 		} else {
 			copied := make([]byte, len(record.msg))
@@ -80,17 +99,22 @@ func GetErrorMsgBytes(errorID uint64) (msg []byte, ok bool) {
 	}
 }
 
-// cleanupExpired removes expired entries from the registry.
+// cleanupExpired removes expired entries from the default Runtime.
 //
 // It returns the number of removed records.
 func cleanupExpired(now time.Time) int {
-	registryMu.Lock()
-	defer registryMu.Unlock()
+	return defaultRuntime.cleanupExpired(now)
+}
+
+// cleanupExpired removes expired entries from rt.
+func (rt *Runtime) cleanupExpired(now time.Time) int {
+	rt.errorMu.Lock()
+	defer rt.errorMu.Unlock()
 
 	removed := 0
-	for id, record := range registry {
+	for id, record := range rt.errors {
 		if now.After(record.expiresAt) {
-			delete(registry, id)
+			delete(rt.errors, id)
 			removed++
 		}
 	}
@@ -1,17 +1,19 @@
 package rpcruntime
 
 import (
+	"errors"
+	"fmt"
 	"sync"
 	"testing"
 	"time"
 )
 
 func resetForTest() {
-	registryMu.Lock()
-	registry = make(map[uint64]errorRecord)
-	registryMu.Unlock()
+	defaultRuntime.errorMu.Lock()
+	defaultRuntime.errors = make(map[uint64]errorRecord)
+	defaultRuntime.errorMu.Unlock()
 	nextErrorID.Store(0)
-	startCleanerOnce = sync.Once{}
+	defaultRuntime.startCleanerOnce = sync.Once{}
 }
 
 func TestStoreAndLookup(t *testing.T) {
@@ -90,3 +92,67 @@ func TestConcurrencySafety(t *testing.T) {
 	}
 	wg.Wait()
 }
+
+func TestErrorIs(t *testing.T) {
+	resetForTest()
+
+	wrapped := fmt.Errorf("lookup TestService: %w", ErrServiceNotRegistered)
+	id := StoreError(wrapped)
+	if !ErrorIs(id, ErrorKindServiceNotRegistered) {
+		t.Fatal("expected ErrorKindServiceNotRegistered to match wrapped sentinel")
+	}
+	if ErrorIs(id, ErrorKindHandlerTypeMismatch) {
+		t.Fatal("expected ErrorKindHandlerTypeMismatch not to match")
+	}
+	if ErrorIs(id, ErrorKindUnspecified) {
+		t.Fatal("expected ErrorKindUnspecified never to match")
+	}
+
+	joined := StoreError(errors.Join(ErrInvalidStreamHandle, ErrUnknownProtocol))
+	if !ErrorIs(joined, ErrorKindInvalidStreamHandle) || !ErrorIs(joined, ErrorKindUnknownProtocol) {
+		t.Fatal("expected both kinds to match joined error")
+	}
+
+	plain := StoreErrorMsg([]byte(ErrServiceNotRegistered.Error()))
+	if ErrorIs(plain, ErrorKindServiceNotRegistered) {
+		t.Fatal("expected raw message not to carry a kind")
+	}
+
+	if ErrorIs(0, ErrorKindServiceNotRegistered) || ErrorIs(12345, ErrorKindServiceNotRegistered) {
+		t.Fatal("expected unknown ids not to match")
+	}
+}
+
+func TestErrorIsExpired(t *testing.T) {
+	resetForTest()
+
+	oldTTL := errorTTL
+	errorTTL = time.Millisecond
+	t.Cleanup(func() { errorTTL = oldTTL })
+
+	id := StoreError(ErrHandlerTypeMismatch)
+	time.Sleep(5 * time.Millisecond)
+	if ErrorIs(id, ErrorKindHandlerTypeMismatch) {
+		t.Fatal("expected expired record not to match")
+	}
+}
+
+func TestErrorKindSentinels(t *testing.T) {
+	resetForTest()
+
+	seen := make(map[ErrorKind]bool)
+	for _, s := range errorKindSentinels {
+		if seen[s.kind] {
+			t.Fatalf("duplicate ErrorKind %d", s.kind)
+		}
+		seen[s.kind] = true
+		if !ErrorIs(StoreError(fmt.Errorf("wrapped: %w", s.err)), s.kind) {
+			t.Errorf("expected ErrorKind %d to match %v", s.kind, s.err)
+		}
+	}
+	for _, err := range []error{ErrAlreadyInitialized, ErrInvalidConnectStreamBridge, ErrNilRemote, ErrAliasCycle, ErrInvalidRuntimeHandle} {
+		if classifyError(err) == 0 {
+			t.Errorf("expected %v to have an ErrorKind", err)
+		}
+	}
+}
@@ -1,21 +1,34 @@
 package rpcruntime
 
 import (
-	"sync"
 	"time"
+	"weak"
 )
 
-var startCleanerOnce sync.Once
-
 var cleanupInterval = 1 * time.Second
 
-func startCleaner() {
+// startCleaner periodically removes expired errors from rt.
+//
+// The goroutine only holds a weak reference, so it exits once rt is no longer
+// reachable. It also exits when rt is shut down.
+func (rt *Runtime) startCleaner() {
+	wp := weak.Make(rt)
+	stop := rt.stopCleaner
 	go func() {
 		ticker := time.NewTicker(cleanupInterval)
 		defer ticker.Stop()
 
-		for now := range ticker.C {
-			_ = cleanupExpired(now)
+		for {
+			select {
+			case <-stop:
+				return
+			case now := <-ticker.C:
+				r := wp.Value()
+				if r == nil {
+					return
+				}
+				_ = r.cleanupExpired(now)
+			}
 		}
 	}()
 }
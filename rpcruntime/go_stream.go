@@ -0,0 +1,101 @@
+package rpcruntime
+
+import (
+	"context"
+	"io"
+)
+
+// GoClientStream is the request side of a client-streaming call handled by a
+// plain Go handler (protocol=go).
+type GoClientStream[Req any] interface {
+	// Context returns the context of the stream.
+	Context() context.Context
+
+	// Recv returns the next request message, or io.EOF once the caller has
+	// finished sending.
+	Recv() (*Req, error)
+}
+
+// GoServerStream is the response side of a server-streaming call handled by a
+// plain Go handler (protocol=go).
+type GoServerStream[Res any] interface {
+	// Context returns the context of the stream.
+	Context() context.Context
+
+	// Send delivers a response message to the caller. It returns
+	// context.Canceled once the caller stops receiving.
+	Send(*Res) error
+}
+
+// GoBidiStream is a bidi-streaming call handled by a plain Go handler
+// (protocol=go).
+type GoBidiStream[Req, Res any] interface {
+	// Context returns the context of the stream.
+	Context() context.Context
+
+	// Recv returns the next request message, or io.EOF once the caller has
+	// closed its send side.
+	Recv() (*Req, error)
+
+	// Send delivers a response message to the caller. It returns
+	// context.Canceled once the caller stops receiving.
+	Send(*Res) error
+}
+
+// goStream bridges a StreamSession to the Go stream interfaces.
+type goStream[Req, Res any] struct {
+	session StreamSession
+}
+
+// NewGoClientStream returns a GoClientStream reading from session.
+func NewGoClientStream[Req any](session StreamSession) GoClientStream[Req] {
+	return &goStream[Req, struct{}]{session: session}
+}
+
+// NewGoServerStream returns a GoServerStream writing to session.
+func NewGoServerStream[Res any](session StreamSession) GoServerStream[Res] {
+	return &goStream[struct{}, Res]{session: session}
+}
+
+// NewGoBidiStream returns a GoBidiStream bound to session.
+func NewGoBidiStream[Req, Res any](session StreamSession) GoBidiStream[Req, Res] {
+	return &goStream[Req, Res]{session: session}
+}
+
+func (s *goStream[Req, Res]) Context() context.Context {
+	return s.session.Context()
+}
+
+// Recv returns the message passed to the adaptor's Send as is; no copy is made.
+func (s *goStream[Req, Res]) Recv() (*Req, error) {
+	select {
+	case msg := <-s.session.SendCh():
+		return s.recvMsg(msg)
+	case <-s.session.SendDoneCh():
+		select {
+		case msg := <-s.session.SendCh():
+			return s.recvMsg(msg)
+		default:
+			return nil, io.EOF
+		}
+	case <-s.session.Context().Done():
+		return nil, s.session.Context().Err()
+	}
+}
+
+func (s *goStream[Req, Res]) recvMsg(msg any) (*Req, error) {
+	req, ok := msg.(*Req)
+	if !ok {
+		return nil, ErrStreamMessageTypeMismatch
+	}
+	return req, nil
+}
+
+func (s *goStream[Req, Res]) Send(resp *Res) error {
+	if cb := s.session.OnRead(); cb != nil {
+		if !cb(resp) {
+			return context.Canceled
+		}
+	}
+	return nil
+}
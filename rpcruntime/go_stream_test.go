@@ -0,0 +1,63 @@
+package rpcruntime
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+)
+
+func TestGoBidiStream(t *testing.T) {
+	defer clearStreamRegistry()
+
+	handle, _, _ := AllocateStreamHandle(context.Background(), ProtocolGo)
+	session := GetStreamSession(handle)
+
+	var got []string
+	session.SetCallbacks(func(resp any) bool {
+		got = append(got, *resp.(*string))
+		return len(got) < 2
+	}, nil)
+	stream := NewGoBidiStream[string, string](session)
+
+	req := "ping"
+	if err := SendToStream(handle, &req); err != nil {
+		t.Fatalf("SendToStream failed: %v", err)
+	}
+	if err := CloseSendCh(handle); err != nil {
+		t.Fatalf("CloseSendCh failed: %v", err)
+	}
+
+	msg, err := stream.Recv()
+	if err != nil || msg != &req {
+		t.Fatalf("expected the sent message, got %v, %v", msg, err)
+	}
+	if _, err := stream.Recv(); err != io.EOF {
+		t.Fatalf("expected io.EOF after CloseSend, got %v", err)
+	}
+
+	a, b := "a", "b"
+	if err := stream.Send(&a); err != nil {
+		t.Fatalf("Send failed: %v", err)
+	}
+	if err := stream.Send(&b); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled once onRead returns false, got %v", err)
+	}
+	if len(got) != 2 || got[0] != "a" || got[1] != "b" {
+		t.Errorf("unexpected responses %v", got)
+	}
+}
+
+func TestGoClientStreamMessageTypeMismatch(t *testing.T) {
+	defer clearStreamRegistry()
+
+	handle, _, _ := AllocateStreamHandle(context.Background(), ProtocolGo)
+	stream := NewGoClientStream[string](GetStreamSession(handle))
+
+	if err := SendToStream(handle, 42); err != nil {
+		t.Fatalf("SendToStream failed: %v", err)
+	}
+	if _, err := stream.Recv(); !errors.Is(err, ErrStreamMessageTypeMismatch) {
+		t.Fatalf("expected ErrStreamMessageTypeMismatch, got %v", err)
+	}
+}
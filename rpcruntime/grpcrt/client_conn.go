@@ -0,0 +1,372 @@
+package grpcrt
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"sync"
+
+	"github.com/ygrpc/rpccgo/rpcruntime"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+// clientConn dispatches gRPC client calls to the handlers registered in rt.
+type clientConn struct {
+	rt *rpcruntime.Runtime
+}
+
+// ClientConn returns a grpc.ClientConnInterface that serves calls in process
+// through the generated adaptors, using the handlers of the default Runtime.
+//
+// Methods are found by full name in the table filled by
+// rpcruntime.RegisterMethods, so the package holding the generated adaptors
+// must be linked in. A client generated by protoc-gen-go-grpc then talks to
+// registered handlers of any protocol:
+//
+//	client := pb.NewTestServiceClient(grpcrt.ClientConn())
+//	resp, err := client.Ping(ctx, &pb.PingRequest{Msg: "hi"})
+//
+// Outgoing metadata is visible to gRPC handlers as incoming metadata and to
+// Connect handlers as request headers. Response headers and trailers, whether
+// set with grpc.SetHeader / grpc.SetTrailer or through Connect, are returned
+// through the grpc.Header and grpc.Trailer call options and the stream's
+// Header and Trailer. Other call options are ignored.
+//
+// Methods missing from the table are served by the unknown-service handlers
+// (see rpcruntime.SetUnknownServiceHandler), or fail with codes.Unimplemented.
+func ClientConn() grpc.ClientConnInterface {
+	return defaultRuntime().ClientConn()
+}
+
+// ClientConn returns a grpc.ClientConnInterface that dispatches to the
+// handlers registered in r.
+func (r Runtime) ClientConn() grpc.ClientConnInterface {
+	return clientConn{rt: r.rt}
+}
+
+// Invoke implements grpc.ClientConnInterface.
+func (cc clientConn) Invoke(ctx context.Context, method string, args, reply any, opts ...grpc.CallOption) error {
+	desc, ok := rpcruntime.LookupMethod(method)
+	raw := false
+	if !ok {
+		desc, ok = cc.rt.UnknownMethodDesc(method, rpcruntime.MethodKindUnary)
+		raw = ok
+	}
+	if !ok || desc.Kind() != rpcruntime.MethodKindUnary {
+		return unknownMethodError(method)
+	}
+	if raw {
+		rawArgs := newRawMessage()
+		if err := transcodeMessage(args, rawArgs); err != nil {
+			return err
+		}
+		args = rawArgs
+	}
+	callCtx, md := cc.callContext(ctx, method)
+	resp, err := desc.Unary(callCtx, args)
+	finishCall(ctx, md, opts)
+	if err != nil {
+		return err
+	}
+	if raw {
+		return transcodeMessage(resp, reply)
+	}
+	return rpcruntime.CopyMessage(resp, reply)
+}
+
+// NewStream implements grpc.ClientConnInterface.
+func (cc clientConn) NewStream(
+	ctx context.Context,
+	streamDesc *grpc.StreamDesc,
+	method string,
+	opts ...grpc.CallOption,
+) (grpc.ClientStream, error) {
+	desc, ok := rpcruntime.LookupMethod(method)
+	raw := false
+	if !ok {
+		desc, ok = cc.rt.UnknownMethodDesc(method, streamDescKind(streamDesc))
+		raw = ok
+	}
+	if !ok || desc.Kind() == rpcruntime.MethodKindUnary {
+		return nil, unknownMethodError(method)
+	}
+	callCtx, md := cc.callContext(ctx, method)
+	s := &clientConnStream{
+		ctx:     ctx,
+		desc:    desc,
+		raw:     raw,
+		md:      md,
+		opts:    opts,
+		msgs:    make(chan any),
+		done:    make(chan struct{}),
+		callCtx: callCtx,
+	}
+	switch desc.Kind() {
+	case rpcruntime.MethodKindClientStream:
+		handle, err := desc.StartClientStream(callCtx)
+		if err != nil {
+			return nil, err
+		}
+		s.handle = handle
+	case rpcruntime.MethodKindBidiStream:
+		handle, err := desc.StartBidiStream(callCtx, s.onRead, s.onDone)
+		if err != nil {
+			return nil, err
+		}
+		s.handle = handle
+	}
+	return s, nil
+}
+
+// streamDescKind returns the kind of a streaming method described by desc.
+func streamDescKind(desc *grpc.StreamDesc) rpcruntime.MethodKind {
+	switch {
+	case desc.ClientStreams && desc.ServerStreams:
+		return rpcruntime.MethodKindBidiStream
+	case desc.ClientStreams:
+		return rpcruntime.MethodKindClientStream
+	default:
+		return rpcruntime.MethodKindServerStream
+	}
+}
+
+func unknownMethodError(method string) error {
+	return status.Errorf(codes.Unimplemented, "grpcrt: unknown method %s", method)
+}
+
+// callContext prepares the context a call through cc runs with: it selects
+// cc.rt, turns the outgoing metadata into incoming metadata and request
+// headers, and collects response metadata into the returned ResponseMetadata.
+func (cc clientConn) callContext(ctx context.Context, method string) (context.Context, *rpcruntime.ResponseMetadata) {
+	ctx = rpcruntime.WithRuntime(ctx, cc.rt)
+	if out, ok := metadata.FromOutgoingContext(ctx); ok {
+		ctx = metadata.NewIncomingContext(ctx, out.Copy())
+		header := metadataToHeader(out)
+		mergeHeader(header, rpcruntime.RequestHeaderFromContext(ctx))
+		ctx = rpcruntime.WithRequestHeader(ctx, header)
+	}
+	ctx, md := rpcruntime.WithResponseMetadata(ctx)
+	ctx = grpc.NewContextWithServerTransportStream(ctx, &clientConnTransportStream{method: method, md: md})
+	return ctx, md
+}
+
+// finishCall hands the response metadata of a finished call to the caller's
+// ResponseMetadata, if any, and to the grpc.Header and grpc.Trailer options.
+func finishCall(ctx context.Context, md *rpcruntime.ResponseMetadata, opts []grpc.CallOption) {
+	rpcruntime.RecordResponseMetadata(ctx, md.Header, md.Trailer)
+	for _, opt := range opts {
+		switch o := opt.(type) {
+		case grpc.HeaderCallOption:
+			*o.HeaderAddr = headerToMetadata(md.Header)
+		case grpc.TrailerCallOption:
+			*o.TrailerAddr = headerToMetadata(md.Trailer)
+		}
+	}
+}
+
+// clientConnTransportStream records grpc.SetHeader, grpc.SendHeader and
+// grpc.SetTrailer calls made by gRPC handlers.
+type clientConnTransportStream struct {
+	method string
+	md     *rpcruntime.ResponseMetadata
+}
+
+func (s *clientConnTransportStream) Method() string {
+	return s.method
+}
+
+func (s *clientConnTransportStream) SetHeader(md metadata.MD) error {
+	s.record(s.md.Header, md)
+	return nil
+}
+
+func (s *clientConnTransportStream) SendHeader(md metadata.MD) error {
+	s.record(s.md.Header, md)
+	return nil
+}
+
+func (s *clientConnTransportStream) SetTrailer(md metadata.MD) error {
+	s.record(s.md.Trailer, md)
+	return nil
+}
+
+func (s *clientConnTransportStream) record(dst http.Header, md metadata.MD) {
+	for k, values := range md {
+		for _, v := range values {
+			dst.Add(k, v)
+		}
+	}
+}
+
+// clientConnStream implements grpc.ClientStream on top of the stream adaptor
+// functions described by desc.
+type clientConnStream struct {
+	// ctx is the caller's context; callCtx is the one the adaptor functions
+	// run with (see callContext).
+	ctx     context.Context
+	callCtx context.Context
+	desc    rpcruntime.MethodDesc
+	md      *rpcruntime.ResponseMetadata
+	opts    []grpc.CallOption
+	handle  uint64
+	// raw is set for methods served by the unknown-service handlers, whose
+	// messages are transcoded to and from raw messages.
+	raw bool
+
+	// msgs carries messages from the handler to RecvMsg. done is closed, and
+	// err set, once the call has finished.
+	msgs chan any
+	done chan struct{}
+	once sync.Once
+	err  error
+
+	// startOnce starts a server-streaming call on the first SendMsg.
+	startOnce sync.Once
+	// finished is set once a client-streaming call has returned its response.
+	finished bool
+}
+
+func (s *clientConnStream) onRead(msg any) bool {
+	select {
+	case s.msgs <- msg:
+		return true
+	case <-s.ctx.Done():
+		return false
+	}
+}
+
+func (s *clientConnStream) onDone(err error) {
+	s.once.Do(func() {
+		s.err = err
+		finishCall(s.ctx, s.md, s.opts)
+		close(s.done)
+	})
+}
+
+// Header returns the response headers once the call has finished, and an
+// empty MD before that.
+func (s *clientConnStream) Header() (metadata.MD, error) {
+	select {
+	case <-s.done:
+		return headerToMetadata(s.md.Header), nil
+	default:
+		return metadata.MD{}, nil
+	}
+}
+
+// Trailer returns the response trailers. It must only be called after
+// RecvMsg has returned a non-nil error.
+func (s *clientConnStream) Trailer() metadata.MD {
+	select {
+	case <-s.done:
+		return headerToMetadata(s.md.Trailer)
+	default:
+		return nil
+	}
+}
+
+func (s *clientConnStream) Context() context.Context {
+	return s.ctx
+}
+
+func (s *clientConnStream) SendMsg(m any) error {
+	if s.raw {
+		rawMsg := newRawMessage()
+		if err := transcodeMessage(m, rawMsg); err != nil {
+			return err
+		}
+		m = rawMsg
+	}
+	switch s.desc.Kind() {
+	case rpcruntime.MethodKindServerStream:
+		started := false
+		s.startOnce.Do(func() {
+			started = true
+			// Like grpc, let the caller reuse m once SendMsg returns.
+			if msg, ok := m.(proto.Message); ok {
+				m = proto.Clone(msg)
+			}
+			go func() { _ = s.desc.ServerStream(s.callCtx, m, s.onRead, s.onDone) }()
+		})
+		if !started {
+			return status.Error(codes.Internal, "rpcruntime: server-streaming call takes a single request")
+		}
+		return nil
+	default:
+		return s.desc.Send(s.handle, m)
+	}
+}
+
+func (s *clientConnStream) CloseSend() error {
+	switch s.desc.Kind() {
+	case rpcruntime.MethodKindBidiStream:
+		return s.desc.CloseSend(s.handle)
+	default:
+		// Client streams are closed by RecvMsg; a server stream's only request
+		// is sent by SendMsg.
+		return nil
+	}
+}
+
+func (s *clientConnStream) RecvMsg(m any) error {
+	if s.desc.Kind() == rpcruntime.MethodKindClientStream {
+		if s.finished {
+			return io.EOF
+		}
+		s.finished = true
+		resp, err := s.desc.FinishClientStream(s.handle)
+		s.onDone(err)
+		if err != nil {
+			return err
+		}
+		return s.copyMessage(resp, m)
+	}
+
+	select {
+	case msg := <-s.msgs:
+		return s.copyMessage(msg, m)
+	case <-s.done:
+		if s.err != nil {
+			return s.err
+		}
+		return io.EOF
+	}
+}
+
+func (s *clientConnStream) copyMessage(src, dst any) error {
+	if s.raw {
+		return transcodeMessage(src, dst)
+	}
+	return rpcruntime.CopyMessage(src, dst)
+}
+
+// newRawMessage returns a message that carries any wire-format payload as
+// unknown fields and marshals back to the same bytes, like the messages of
+// rpcruntime.Runtime.UnknownMethodDesc.
+func newRawMessage() any {
+	return &emptypb.Empty{}
+}
+
+// transcodeMessage copies src into dst through the wire format, so the two
+// may be of different types, e.g. a raw message (see newRawMessage) and a
+// generated one.
+func transcodeMessage(src, dst any) error {
+	srcMsg, ok := src.(proto.Message)
+	if !ok {
+		return rpcruntime.ErrStreamMessageTypeMismatch
+	}
+	dstMsg, ok := dst.(proto.Message)
+	if !ok {
+		return rpcruntime.ErrStreamMessageTypeMismatch
+	}
+	b, err := proto.Marshal(srcMsg)
+	if err != nil {
+		return err
+	}
+	return proto.Unmarshal(b, dstMsg)
+}
@@ -0,0 +1,66 @@
+package grpcrt
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/ygrpc/rpccgo/rpcruntime"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+const clientConnTestMethod = "/rpc.test.ClientConnService/Echo"
+
+func init() {
+	rpcruntime.RegisterMethods(rpcruntime.NewUnaryMethodDesc(
+		clientConnTestMethod,
+		func(ctx context.Context, req *wrapperspb.StringValue) (*wrapperspb.StringValue, error) {
+			if rpcruntime.RuntimeFromContext(ctx) == rpcruntime.Default() {
+				return nil, errors.New("call did not run in the ClientConn's Runtime")
+			}
+			rpcruntime.RecordResponseMetadata(ctx, nil, http.Header{"X-Done": {"yes"}})
+			return wrapperspb.String(req.GetValue() + ":" + rpcruntime.RequestHeaderFromContext(ctx).Get("X-Test")), nil
+		},
+	))
+}
+
+func TestClientConnInvoke(t *testing.T) {
+	conn := For(rpcruntime.NewRuntime()).ClientConn()
+	ctx := metadata.AppendToOutgoingContext(context.Background(), "x-test", "v1")
+	ctx, md := rpcruntime.WithResponseMetadata(ctx)
+
+	var trailer metadata.MD
+	reply := &wrapperspb.StringValue{}
+	err := conn.Invoke(ctx, clientConnTestMethod, wrapperspb.String("hi"), reply, grpc.Trailer(&trailer))
+	if err != nil {
+		t.Fatalf("Invoke failed: %v", err)
+	}
+	if reply.GetValue() != "hi:v1" {
+		t.Errorf("unexpected reply %q", reply.GetValue())
+	}
+	if got := trailer.Get("x-done"); len(got) != 1 || got[0] != "yes" {
+		t.Errorf("unexpected trailer %v", trailer)
+	}
+	if got := md.Trailer.Get("X-Done"); got != "yes" {
+		t.Errorf("caller's ResponseMetadata not filled, got %q", got)
+	}
+}
+
+func TestClientConnErrors(t *testing.T) {
+	conn := ClientConn()
+
+	err := conn.Invoke(context.Background(), clientConnTestMethod, &wrapperspb.Int32Value{}, &wrapperspb.StringValue{})
+	if !errors.Is(err, rpcruntime.ErrStreamMessageTypeMismatch) {
+		t.Errorf("expected ErrStreamMessageTypeMismatch, got %v", err)
+	}
+
+	_, err = conn.NewStream(context.Background(), &grpc.StreamDesc{ServerStreams: true}, clientConnTestMethod)
+	if status.Code(err) != codes.Unimplemented {
+		t.Errorf("expected Unimplemented for a stream on a unary method, got %v", err)
+	}
+}
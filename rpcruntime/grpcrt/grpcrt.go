@@ -0,0 +1,60 @@
+// Package grpcrt connects an rpcruntime.Runtime to gRPC: an in-process
+// grpc.ClientConnInterface and a *grpc.Server for the generated adaptors, a
+// grpc.ServiceRegistrar for gRPC handlers, and remotes forwarding calls over a
+// gRPC connection.
+//
+// It is kept out of package rpcruntime so that binaries using only plain Go
+// (protocol=go) or Twirp handlers do not link grpc-go.
+package grpcrt
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/ygrpc/rpccgo/rpcruntime"
+	"google.golang.org/grpc/metadata"
+)
+
+// Runtime serves the handlers registered in an rpcruntime.Runtime over gRPC.
+// The package-level functions use the default Runtime (see rpcruntime.Default).
+type Runtime struct {
+	rt *rpcruntime.Runtime
+}
+
+// For returns the gRPC side of rt.
+func For(rt *rpcruntime.Runtime) Runtime {
+	return Runtime{rt: rt}
+}
+
+// defaultRuntime returns the gRPC side of the default Runtime.
+func defaultRuntime() Runtime {
+	return For(rpcruntime.Default())
+}
+
+func metadataToHeader(md metadata.MD) http.Header {
+	header := http.Header{}
+	for k, values := range md {
+		if strings.HasPrefix(k, ":") {
+			continue
+		}
+		for _, v := range values {
+			header.Add(k, v)
+		}
+	}
+	return header
+}
+
+func headerToMetadata(header http.Header) metadata.MD {
+	md := metadata.MD{}
+	for k, v := range header {
+		key := strings.ToLower(k)
+		md[key] = append(md[key], v...)
+	}
+	return md
+}
+
+func mergeHeader(dst, src http.Header) {
+	for k, v := range src {
+		dst[k] = append(dst[k], v...)
+	}
+}
@@ -0,0 +1,20 @@
+package grpcrt
+
+import (
+	"context"
+
+	"google.golang.org/grpc/metadata"
+)
+
+// MetadataCallerKey returns an rpcruntime.RateLimit.CallerKey that identifies
+// callers by the first value of the gRPC metadata entry name in the incoming
+// context (see metadata.NewIncomingContext).
+func MetadataCallerKey(name string) func(ctx context.Context) string {
+	return func(ctx context.Context) string {
+		values := metadata.ValueFromIncomingContext(ctx, name)
+		if len(values) == 0 {
+			return ""
+		}
+		return values[0]
+	}
+}
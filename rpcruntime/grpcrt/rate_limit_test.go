@@ -0,0 +1,41 @@
+package grpcrt
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/ygrpc/rpccgo/rpcruntime"
+	"google.golang.org/grpc/metadata"
+)
+
+func TestMetadataCallerKey(t *testing.T) {
+	key := MetadataCallerKey("x-plugin-id")
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("x-plugin-id", "a"))
+	if got := key(ctx); got != "a" {
+		t.Errorf("key = %q, want %q", got, "a")
+	}
+	if got := key(context.Background()); got != "" {
+		t.Errorf("key without metadata = %q, want empty", got)
+	}
+
+	rt := rpcruntime.NewRuntime()
+	fullMethod := "/rpc.test.TestService/Ping"
+	if err := rt.SetMethodRateLimit(fullMethod, rpcruntime.RateLimit{Rate: 0.001, Burst: 1, CallerKey: key}); err != nil {
+		t.Fatalf("SetMethodRateLimit failed: %v", err)
+	}
+	release, err := rt.AcquireCallSlot(ctx, fullMethod)
+	if err != nil {
+		t.Fatalf("first call failed: %v", err)
+	}
+	release()
+	if _, err := rt.AcquireCallSlot(ctx, fullMethod); !errors.Is(err, rpcruntime.ErrResourceExhausted) {
+		t.Errorf("expected ErrResourceExhausted for caller a, got %v", err)
+	}
+	other := metadata.NewIncomingContext(context.Background(), metadata.Pairs("x-plugin-id", "b"))
+	if release, err := rt.AcquireCallSlot(other, fullMethod); err != nil {
+		t.Errorf("caller b must have its own bucket, got %v", err)
+	} else {
+		release()
+	}
+}
@@ -0,0 +1,68 @@
+package grpcrt
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/ygrpc/rpccgo/rpcruntime"
+	"google.golang.org/grpc"
+)
+
+// serviceRegistrar adapts the handler registry of rt to grpc.ServiceRegistrar.
+type serviceRegistrar struct {
+	rt *rpcruntime.Runtime
+}
+
+// ServiceRegistrar returns a grpc.ServiceRegistrar that registers handlers
+// into the gRPC handler registry of the default Runtime.
+//
+// This allows existing bootstrap code such as pb.RegisterFooServer(s, impl) to
+// target the CGO runtime as well as a real *grpc.Server.
+//
+// Like *grpc.Server, the registrar's RegisterService method panics if impl does
+// not implement desc.HandlerType. Use the package-level RegisterService to
+// receive the error instead.
+func ServiceRegistrar() grpc.ServiceRegistrar {
+	return defaultRuntime().ServiceRegistrar()
+}
+
+// ServiceRegistrar returns a grpc.ServiceRegistrar that registers handlers into r.
+func (r Runtime) ServiceRegistrar() grpc.ServiceRegistrar {
+	return serviceRegistrar{rt: r.rt}
+}
+
+// RegisterService implements grpc.ServiceRegistrar.
+func (s serviceRegistrar) RegisterService(desc *grpc.ServiceDesc, impl any) {
+	if _, err := For(s.rt).RegisterService(desc, impl); err != nil {
+		panic(err)
+	}
+}
+
+// RegisterService registers impl as the gRPC handler for desc.ServiceName in
+// the default Runtime.
+//
+// If desc.HandlerType is set, impl must implement the interface it points to,
+// otherwise rpcruntime.ErrHandlerTypeMismatch is returned and nothing is
+// registered. Replacement semantics and other errors match
+// rpcruntime.RegisterGrpcHandler.
+func RegisterService(desc *grpc.ServiceDesc, impl any) (replaced bool, err error) {
+	return defaultRuntime().RegisterService(desc, impl)
+}
+
+// RegisterService registers impl as the gRPC handler for desc.ServiceName in r.
+func (r Runtime) RegisterService(desc *grpc.ServiceDesc, impl any) (replaced bool, err error) {
+	if desc == nil {
+		return false, rpcruntime.ErrEmptyServiceName
+	}
+	if impl == nil {
+		return false, rpcruntime.ErrNilHandler
+	}
+	if desc.HandlerType != nil {
+		ht := reflect.TypeOf(desc.HandlerType).Elem()
+		st := reflect.TypeOf(impl)
+		if !st.Implements(ht) {
+			return false, fmt.Errorf("%w: %v does not implement %v for %s", rpcruntime.ErrHandlerTypeMismatch, st, ht, desc.ServiceName)
+		}
+	}
+	return r.rt.RegisterGrpcHandler(desc.ServiceName, impl)
+}
@@ -0,0 +1,64 @@
+package grpcrt
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ygrpc/rpccgo/rpcruntime"
+	"google.golang.org/grpc"
+)
+
+type echoServer interface {
+	Echo(string) string
+}
+
+type echoImpl struct{}
+
+func (echoImpl) Echo(s string) string { return s }
+
+var echoServiceDesc = grpc.ServiceDesc{
+	ServiceName: "rpc.test.EchoService",
+	HandlerType: (*echoServer)(nil),
+}
+
+func TestServiceRegistrar(t *testing.T) {
+	rt := rpcruntime.NewRuntime()
+	impl := echoImpl{}
+	For(rt).ServiceRegistrar().RegisterService(&echoServiceDesc, impl)
+
+	got, ok := rt.LookupGrpcHandler(echoServiceDesc.ServiceName)
+	if !ok {
+		t.Fatal("LookupGrpcHandler returned ok=false")
+	}
+	if got != impl {
+		t.Error("LookupGrpcHandler returned wrong handler")
+	}
+}
+
+func TestRegisterServiceTypeMismatch(t *testing.T) {
+	rt := rpcruntime.NewRuntime()
+	_, err := For(rt).RegisterService(&echoServiceDesc, &struct{}{})
+	if !errors.Is(err, rpcruntime.ErrHandlerTypeMismatch) {
+		t.Fatalf("expected ErrHandlerTypeMismatch, got %v", err)
+	}
+	if _, ok := rt.LookupGrpcHandler(echoServiceDesc.ServiceName); ok {
+		t.Error("mismatched handler should not be registered")
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("expected RegisterService to panic on type mismatch")
+		}
+	}()
+	For(rt).ServiceRegistrar().RegisterService(&echoServiceDesc, &struct{}{})
+}
+
+func TestRegisterServiceNil(t *testing.T) {
+	r := For(rpcruntime.NewRuntime())
+	if _, err := r.RegisterService(nil, echoImpl{}); !errors.Is(err, rpcruntime.ErrEmptyServiceName) {
+		t.Errorf("expected ErrEmptyServiceName for nil desc, got %v", err)
+	}
+	if _, err := r.RegisterService(&echoServiceDesc, nil); !errors.Is(err, rpcruntime.ErrNilHandler) {
+		t.Errorf("expected ErrNilHandler, got %v", err)
+	}
+}
@@ -0,0 +1,151 @@
+package grpcrt
+
+import (
+	"context"
+	"errors"
+	"io"
+
+	"github.com/ygrpc/rpccgo/rpcruntime"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// remoteTransport implements rpcruntime.RemoteTransport over a gRPC client
+// connection.
+type remoteTransport struct {
+	cc grpc.ClientConnInterface
+}
+
+// NewRemote returns an rpcruntime.Remote forwarding calls over cc, typically a
+// *grpc.ClientConn.
+func NewRemote(cc grpc.ClientConnInterface) *rpcruntime.Remote {
+	return rpcruntime.NewRemote(remoteTransport{cc: cc})
+}
+
+func (t remoteTransport) Protocol() rpcruntime.Protocol {
+	return rpcruntime.ProtocolGrpc
+}
+
+func (t remoteTransport) Unary(ctx context.Context, fullMethod string, req, resp any) error {
+	var header, trailer metadata.MD
+	err := t.cc.Invoke(outgoingContext(ctx), fullMethod, req, resp, grpc.Header(&header), grpc.Trailer(&trailer))
+	recordRemoteMetadata(ctx, header, trailer)
+	return err
+}
+
+func (t remoteTransport) ClientStream(ctx context.Context, fullMethod string, rs rpcruntime.RemoteStream, resp any) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	stream, err := t.cc.NewStream(outgoingContext(ctx), &grpc.StreamDesc{ClientStreams: true}, fullMethod)
+	if err != nil {
+		return err
+	}
+	if err := pumpRemoteStream(rs, stream.SendMsg); err != nil {
+		return err
+	}
+	_ = stream.CloseSend()
+	err = stream.RecvMsg(resp)
+	recordRemoteStreamMetadata(ctx, stream)
+	return err
+}
+
+func (t remoteTransport) ServerStream(ctx context.Context, fullMethod string, req any, rs rpcruntime.RemoteStream) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	stream, err := t.cc.NewStream(outgoingContext(ctx), &grpc.StreamDesc{ServerStreams: true}, fullMethod)
+	if err != nil {
+		return err
+	}
+	// io.EOF means the remote has already ended the call; RecvMsg reports why.
+	if err := stream.SendMsg(req); err != nil && !errors.Is(err, io.EOF) {
+		return err
+	}
+	_ = stream.CloseSend()
+	err = receiveRemoteStream(stream, rs, cancel)
+	recordRemoteStreamMetadata(ctx, stream)
+	return err
+}
+
+func (t remoteTransport) BidiStream(ctx context.Context, fullMethod string, rs rpcruntime.RemoteStream) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	desc := &grpc.StreamDesc{ClientStreams: true, ServerStreams: true}
+	stream, err := t.cc.NewStream(outgoingContext(ctx), desc, fullMethod)
+	if err != nil {
+		return err
+	}
+	pumpErr := make(chan error, 1)
+	go func() {
+		if err := pumpRemoteStream(rs, stream.SendMsg); err != nil {
+			pumpErr <- err
+			cancel()
+			return
+		}
+		_ = stream.CloseSend()
+	}()
+
+	err = receiveRemoteStream(stream, rs, cancel)
+	recordRemoteStreamMetadata(ctx, stream)
+	select {
+	case pErr := <-pumpErr:
+		return pErr
+	default:
+	}
+	return err
+}
+
+// outgoingContext returns ctx with its request headers as outgoing metadata.
+func outgoingContext(ctx context.Context) context.Context {
+	return metadata.NewOutgoingContext(ctx, headerToMetadata(rpcruntime.RequestHeaderFromContext(ctx)))
+}
+
+func recordRemoteMetadata(ctx context.Context, header, trailer metadata.MD) {
+	rpcruntime.RecordReceivedResponseMetadata(ctx, metadataToHeader(header), metadataToHeader(trailer))
+}
+
+// recordRemoteStreamMetadata records the response metadata of a finished
+// client stream.
+func recordRemoteStreamMetadata(ctx context.Context, stream grpc.ClientStream) {
+	header, _ := stream.Header()
+	recordRemoteMetadata(ctx, header, stream.Trailer())
+}
+
+// pumpRemoteStream sends the messages read from rs to send until the caller
+// closes its send side. It stops early without error when send fails; the
+// cause is reported by the receive side.
+func pumpRemoteStream(rs rpcruntime.RemoteStream, send func(any) error) error {
+	for {
+		req, err := rs.Recv()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if err := send(req); err != nil {
+			return nil
+		}
+	}
+}
+
+// receiveRemoteStream delivers the responses of stream to rs until the remote
+// ends it. If the caller stops receiving, the call is cancelled and nil
+// returned.
+func receiveRemoteStream(stream grpc.ClientStream, rs rpcruntime.RemoteStream, cancel context.CancelFunc) error {
+	for {
+		resp := rs.NewResponse()
+		if err := stream.RecvMsg(resp); err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return err
+		}
+		if rs.Send(resp) != nil {
+			cancel()
+			return nil
+		}
+	}
+}
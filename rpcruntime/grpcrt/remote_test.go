@@ -0,0 +1,52 @@
+package grpcrt
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/ygrpc/rpccgo/rpcruntime"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+func TestRemoteUnary(t *testing.T) {
+	remote := NewRemote(For(rpcruntime.NewRuntime()).ClientConn())
+	ctx := rpcruntime.WithRequestHeader(context.Background(), http.Header{
+		"X-Test":       {"v1"},
+		"Content-Type": {"application/grpc"},
+	})
+	ctx, md := rpcruntime.WithResponseMetadata(ctx)
+
+	resp, err := rpcruntime.ForwardUnary[wrapperspb.StringValue, wrapperspb.StringValue](
+		ctx, remote, clientConnTestMethod, wrapperspb.String("hi"))
+	if err != nil {
+		t.Fatalf("ForwardUnary failed: %v", err)
+	}
+	if resp.GetValue() != "hi:v1" {
+		t.Errorf("unexpected reply %q", resp.GetValue())
+	}
+	if got := md.Trailer.Get("X-Done"); got != "yes" {
+		t.Errorf("expected trailer X-Done=yes, got %q", got)
+	}
+}
+
+func TestRemoteServerStream(t *testing.T) {
+	remote := NewRemote(For(newUnknownTestRuntime()).ClientConn())
+
+	var got []string
+	var doneErr error
+	err := rpcruntime.ForwardServerStream(
+		context.Background(), remote, unknownTestMethod, wrapperspb.String("x"),
+		func(resp *wrapperspb.StringValue) bool {
+			got = append(got, resp.GetValue())
+			return true
+		},
+		func(err error) { doneErr = err },
+	)
+	if err != nil || doneErr != nil {
+		t.Fatalf("ForwardServerStream = %v, onDone %v", err, doneErr)
+	}
+	if len(got) != 1 || got[0] != "echo:x" {
+		t.Errorf("unexpected replies %v", got)
+	}
+}
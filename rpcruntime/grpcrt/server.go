@@ -0,0 +1,273 @@
+package grpcrt
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+
+	"connectrpc.com/connect"
+	"github.com/ygrpc/rpccgo/rpcruntime"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/reflection"
+	"google.golang.org/grpc/status"
+)
+
+// Serve serves every method in the method table over gRPC on lis, using the
+// handlers of the default Runtime. See Runtime.NewServer.
+//
+// It blocks until lis fails or is closed, like grpc.Server.Serve. Use
+// NewServer to keep control over the server, e.g. for GracefulStop.
+func Serve(lis net.Listener) error {
+	return defaultRuntime().Serve(lis)
+}
+
+// Serve serves the handlers registered in r over gRPC on lis.
+func (r Runtime) Serve(lis net.Listener) error {
+	return r.NewServer().Serve(lis)
+}
+
+// NewServer returns a *grpc.Server exposing the handlers of the default
+// Runtime. See Runtime.NewServer.
+func NewServer(opts ...grpc.ServerOption) *grpc.Server {
+	return defaultRuntime().NewServer(opts...)
+}
+
+// NewServer returns a *grpc.Server with a service for every service in the
+// method table, dispatching to the handlers registered in r through the
+// generated adaptors, and with server reflection enabled so tools such as
+// grpcurl can discover them.
+//
+// Services are taken from the methods registered when NewServer is called;
+// handlers may still be registered and unregistered afterwards.
+// Incoming metadata reaches Connect handlers as request headers, and response
+// headers and trailers recorded through ResponseMetadata are sent back.
+// Errors keep their gRPC or Connect code; rpcruntime errors map to the
+// nearest code, e.g. ErrServiceNotRegistered to Unimplemented.
+//
+// Calls of other methods go to the unknown-service handlers of r (see
+// rpcruntime.SetUnknownStreamHandler) unless opts contains a
+// grpc.UnknownServiceHandler.
+func (r Runtime) NewServer(opts ...grpc.ServerOption) *grpc.Server {
+	opts = append([]grpc.ServerOption{grpc.UnknownServiceHandler(r.unknownServiceHandler)}, opts...)
+	s := grpc.NewServer(opts...)
+	for _, sd := range r.serviceDescs() {
+		s.RegisterService(sd, nil)
+	}
+	reflection.Register(s)
+	return s
+}
+
+// serviceDescs builds a grpc.ServiceDesc per service in the method table,
+// dispatching to the handlers of r.
+func (r Runtime) serviceDescs() []*grpc.ServiceDesc {
+	services := make(map[string]*grpc.ServiceDesc)
+	var out []*grpc.ServiceDesc
+	for _, d := range rpcruntime.Methods() {
+		serviceName, methodName, ok := rpcruntime.SplitFullMethod(d.FullMethod())
+		if !ok {
+			continue
+		}
+		sd, exists := services[serviceName]
+		if !exists {
+			sd = &grpc.ServiceDesc{ServiceName: serviceName, HandlerType: (*any)(nil)}
+			services[serviceName] = sd
+			out = append(out, sd)
+		}
+		kind := d.Kind()
+		if kind == rpcruntime.MethodKindUnary {
+			sd.Methods = append(sd.Methods, grpc.MethodDesc{
+				MethodName: methodName,
+				Handler:    r.unaryHandler(d),
+			})
+			continue
+		}
+		sd.Streams = append(sd.Streams, grpc.StreamDesc{
+			StreamName:    methodName,
+			Handler:       r.streamHandler(d),
+			ClientStreams: kind == rpcruntime.MethodKindClientStream || kind == rpcruntime.MethodKindBidiStream,
+			ServerStreams: kind == rpcruntime.MethodKindServerStream || kind == rpcruntime.MethodKindBidiStream,
+		})
+	}
+	return out
+}
+
+// serverContext prepares the context an adaptor function runs with when
+// serving a gRPC call in r: the incoming metadata becomes the request headers.
+func (r Runtime) serverContext(ctx context.Context) (context.Context, *rpcruntime.ResponseMetadata) {
+	ctx = rpcruntime.WithRuntime(ctx, r.rt)
+	if in, ok := metadata.FromIncomingContext(ctx); ok {
+		ctx = rpcruntime.WithRequestHeader(ctx, metadataToHeader(in))
+	}
+	return rpcruntime.WithResponseMetadata(ctx)
+}
+
+// serverError converts an error returned by an adaptor function into a gRPC
+// status error.
+func serverError(err error) error {
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+	var connectErr *connect.Error
+	if errors.As(err, &connectErr) {
+		return status.Error(codes.Code(connectErr.Code()), connectErr.Message())
+	}
+	return status.Error(codes.Code(rpcruntime.StatusCode(err)), err.Error())
+}
+
+func (r Runtime) unaryHandler(desc rpcruntime.MethodDesc) func(any, context.Context, func(any) error, grpc.UnaryServerInterceptor) (any, error) {
+	return func(_ any, ctx context.Context, dec func(any) error, interceptor grpc.UnaryServerInterceptor) (any, error) {
+		req := desc.NewRequest()
+		if err := dec(req); err != nil {
+			return nil, err
+		}
+		handler := func(ctx context.Context, req any) (any, error) {
+			callCtx, md := r.serverContext(ctx)
+			resp, err := desc.Unary(callCtx, req)
+			if len(md.Header) > 0 {
+				_ = grpc.SetHeader(ctx, headerToMetadata(md.Header))
+			}
+			if len(md.Trailer) > 0 {
+				_ = grpc.SetTrailer(ctx, headerToMetadata(md.Trailer))
+			}
+			if err != nil {
+				return nil, serverError(err)
+			}
+			return resp, nil
+		}
+		if interceptor == nil {
+			return handler(ctx, req)
+		}
+		return interceptor(ctx, req, &grpc.UnaryServerInfo{FullMethod: desc.FullMethod()}, handler)
+	}
+}
+
+func (r Runtime) streamHandler(desc rpcruntime.MethodDesc) grpc.StreamHandler {
+	return func(_ any, stream grpc.ServerStream) error {
+		ctx, md := r.serverContext(stream.Context())
+		ctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+		w := &streamWriter{stream: stream, md: md}
+
+		switch desc.Kind() {
+		case rpcruntime.MethodKindClientStream:
+			handle, err := desc.StartClientStream(ctx)
+			if err != nil {
+				return w.finish(err)
+			}
+			var recvErr error
+			for {
+				req := desc.NewRequest()
+				if err := stream.RecvMsg(req); err != nil {
+					if !errors.Is(err, io.EOF) {
+						recvErr = err
+						cancel()
+					}
+					break
+				}
+				// A failed send means the handler has finished; Finish reports why.
+				if desc.Send(handle, req) != nil {
+					break
+				}
+			}
+			resp, err := desc.FinishClientStream(handle)
+			if err == nil {
+				err = recvErr
+			}
+			if err != nil {
+				return w.finish(err)
+			}
+			w.sendHeader()
+			if err := stream.SendMsg(resp); err != nil {
+				return err
+			}
+			return w.finish(nil)
+		case rpcruntime.MethodKindServerStream:
+			req := desc.NewRequest()
+			if err := stream.RecvMsg(req); err != nil {
+				return err
+			}
+			return w.finish(desc.ServerStream(ctx, req, w.onRead, func(error) {}))
+		default:
+			done := make(chan error, 1)
+			handle, err := desc.StartBidiStream(ctx, w.onRead, func(err error) { done <- err })
+			if err != nil {
+				return w.finish(err)
+			}
+			go func() {
+				for {
+					req := desc.NewRequest()
+					if err := stream.RecvMsg(req); err != nil {
+						// io.EOF ends the request stream; anything else aborts the call.
+						if !errors.Is(err, io.EOF) {
+							cancel()
+						}
+						_ = desc.CloseSend(handle)
+						return
+					}
+					if desc.Send(handle, req) != nil {
+						return
+					}
+				}
+			}()
+			return w.finish(<-done)
+		}
+	}
+}
+
+// unknownServiceHandler serves calls of methods without a service registered
+// on the gRPC server with the unknown-service handlers of r. The call is
+// treated as a bidi stream if r has a stream handler and as a unary call
+// otherwise.
+func (r Runtime) unknownServiceHandler(srv any, stream grpc.ServerStream) error {
+	fullMethod, _ := grpc.MethodFromServerStream(stream)
+	if desc, ok := r.rt.UnknownMethodDesc(fullMethod, rpcruntime.MethodKindBidiStream); ok {
+		return r.streamHandler(desc)(srv, stream)
+	}
+	desc, ok := r.rt.UnknownMethodDesc(fullMethod, rpcruntime.MethodKindUnary)
+	if !ok {
+		return status.Errorf(codes.Unimplemented, "unknown method %s", fullMethod)
+	}
+	resp, err := r.unaryHandler(desc)(srv, stream.Context(), stream.RecvMsg, nil)
+	if err != nil {
+		return err
+	}
+	return stream.SendMsg(resp)
+}
+
+// streamWriter forwards the messages of a streaming adaptor call to a
+// gRPC server stream. Response headers recorded before the first message are
+// sent with it; trailers are sent when the call finishes.
+type streamWriter struct {
+	stream grpc.ServerStream
+	md     *rpcruntime.ResponseMetadata
+	sent   bool
+}
+
+func (w *streamWriter) onRead(msg any) bool {
+	w.sendHeader()
+	return w.stream.SendMsg(msg) == nil
+}
+
+func (w *streamWriter) sendHeader() {
+	if w.sent {
+		return
+	}
+	w.sent = true
+	if len(w.md.Header) > 0 {
+		_ = w.stream.SetHeader(headerToMetadata(w.md.Header))
+	}
+}
+
+func (w *streamWriter) finish(err error) error {
+	w.sendHeader()
+	if len(w.md.Trailer) > 0 {
+		w.stream.SetTrailer(headerToMetadata(w.md.Trailer))
+	}
+	if err != nil {
+		return serverError(err)
+	}
+	return nil
+}
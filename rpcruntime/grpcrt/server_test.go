@@ -0,0 +1,70 @@
+package grpcrt
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"connectrpc.com/connect"
+	"github.com/ygrpc/rpccgo/rpcruntime"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+func TestServe(t *testing.T) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Listen failed: %v", err)
+	}
+	served := make(chan error, 1)
+	go func() { served <- For(rpcruntime.NewRuntime()).Serve(lis) }()
+
+	conn, err := grpc.NewClient(lis.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("NewClient failed: %v", err)
+	}
+	defer conn.Close()
+
+	ctx := metadata.AppendToOutgoingContext(context.Background(), "x-test", "v1")
+	var trailer metadata.MD
+	reply := &wrapperspb.StringValue{}
+	err = conn.Invoke(ctx, clientConnTestMethod, wrapperspb.String("hi"), reply, grpc.Trailer(&trailer))
+	if err != nil {
+		t.Fatalf("Invoke failed: %v", err)
+	}
+	if reply.GetValue() != "hi:v1" {
+		t.Errorf("unexpected reply %q", reply.GetValue())
+	}
+	if got := trailer.Get("x-done"); len(got) != 1 || got[0] != "yes" {
+		t.Errorf("unexpected trailer %v", trailer)
+	}
+
+	_ = lis.Close()
+	select {
+	case <-served:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Serve did not return after the listener was closed")
+	}
+}
+
+func TestServerError(t *testing.T) {
+	tests := []struct {
+		err  error
+		want codes.Code
+	}{
+		{status.Error(codes.NotFound, "missing"), codes.NotFound},
+		{connect.NewError(connect.CodeAlreadyExists, nil), codes.AlreadyExists},
+		{rpcruntime.ErrServiceNotRegistered, codes.Unimplemented},
+		{context.Canceled, codes.Canceled},
+	}
+	for _, tt := range tests {
+		if got := status.Code(serverError(tt.err)); got != tt.want {
+			t.Errorf("serverError(%v) code = %v, want %v", tt.err, got, tt.want)
+		}
+	}
+}
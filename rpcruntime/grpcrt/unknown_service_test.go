@@ -0,0 +1,168 @@
+package grpcrt
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/ygrpc/rpccgo/rpcruntime"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+const unknownTestMethod = "/rpc.test.UnknownService/Echo"
+
+// newUnknownTestRuntime returns a Runtime whose unknown-service handlers
+// answer StringValue requests with "<method>:<value>" and echo stream messages
+// with an "echo:" prefix.
+func newUnknownTestRuntime() *rpcruntime.Runtime {
+	rt := rpcruntime.NewRuntime()
+	rt.SetUnknownServiceHandler(func(_ context.Context, fullMethod string, reqBytes []byte) ([]byte, error) {
+		req := &wrapperspb.StringValue{}
+		if err := proto.Unmarshal(reqBytes, req); err != nil {
+			return nil, err
+		}
+		return proto.Marshal(wrapperspb.String(fullMethod + ":" + req.GetValue()))
+	})
+	rt.SetUnknownStreamHandler(func(_ context.Context, _ string, stream rpcruntime.UnknownServiceStream) error {
+		for {
+			b, err := stream.Recv()
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+			req := &wrapperspb.StringValue{}
+			if err := proto.Unmarshal(b, req); err != nil {
+				return err
+			}
+			resp, _ := proto.Marshal(wrapperspb.String("echo:" + req.GetValue()))
+			if err := stream.Send(resp); err != nil {
+				return err
+			}
+		}
+	})
+	return rt
+}
+
+func TestUnknownServiceClientConn(t *testing.T) {
+	conn := For(newUnknownTestRuntime()).ClientConn()
+
+	reply := &wrapperspb.StringValue{}
+	if err := conn.Invoke(context.Background(), unknownTestMethod, wrapperspb.String("hi"), reply); err != nil {
+		t.Fatalf("Invoke failed: %v", err)
+	}
+	if reply.GetValue() != unknownTestMethod+":hi" {
+		t.Errorf("unexpected reply %q", reply.GetValue())
+	}
+
+	desc := &grpc.StreamDesc{ClientStreams: true, ServerStreams: true}
+	stream, err := conn.NewStream(context.Background(), desc, unknownTestMethod)
+	if err != nil {
+		t.Fatalf("NewStream failed: %v", err)
+	}
+	for _, v := range []string{"a", "b"} {
+		if err := stream.SendMsg(wrapperspb.String(v)); err != nil {
+			t.Fatalf("SendMsg failed: %v", err)
+		}
+		if err := stream.RecvMsg(reply); err != nil {
+			t.Fatalf("RecvMsg failed: %v", err)
+		}
+		if reply.GetValue() != "echo:"+v {
+			t.Errorf("unexpected stream reply %q", reply.GetValue())
+		}
+	}
+	if err := stream.CloseSend(); err != nil {
+		t.Fatalf("CloseSend failed: %v", err)
+	}
+	if err := stream.RecvMsg(reply); err != io.EOF {
+		t.Errorf("expected io.EOF, got %v", err)
+	}
+
+	err = For(rpcruntime.NewRuntime()).ClientConn().Invoke(context.Background(), unknownTestMethod, wrapperspb.String("hi"), reply)
+	if status.Code(err) != codes.Unimplemented {
+		t.Errorf("expected Unimplemented without handler, got %v", err)
+	}
+}
+
+func TestUnknownServiceClientStreamResponse(t *testing.T) {
+	rt := rpcruntime.NewRuntime()
+	rt.SetUnknownStreamHandler(func(_ context.Context, _ string, stream rpcruntime.UnknownServiceStream) error {
+		var values []string
+		for {
+			b, err := stream.Recv()
+			if err != nil {
+				break
+			}
+			req := &wrapperspb.StringValue{}
+			_ = proto.Unmarshal(b, req)
+			values = append(values, req.GetValue())
+		}
+		resp, _ := proto.Marshal(wrapperspb.String(strings.Join(values, ",")))
+		if err := stream.Send(resp); err != nil {
+			return err
+		}
+		if err := stream.Send(resp); err != io.EOF {
+			return errors.New("second response was accepted")
+		}
+		return nil
+	})
+
+	stream, err := For(rt).ClientConn().NewStream(context.Background(), &grpc.StreamDesc{ClientStreams: true}, unknownTestMethod)
+	if err != nil {
+		t.Fatalf("NewStream failed: %v", err)
+	}
+	for _, v := range []string{"a", "b"} {
+		if err := stream.SendMsg(wrapperspb.String(v)); err != nil {
+			t.Fatalf("SendMsg failed: %v", err)
+		}
+	}
+	reply := &wrapperspb.StringValue{}
+	if err := stream.RecvMsg(reply); err != nil {
+		t.Fatalf("RecvMsg failed: %v", err)
+	}
+	if reply.GetValue() != "a,b" {
+		t.Errorf("unexpected reply %q", reply.GetValue())
+	}
+}
+
+func TestUnknownServiceServer(t *testing.T) {
+	rt := newUnknownTestRuntime()
+	rt.SetUnknownStreamHandler(nil)
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Listen failed: %v", err)
+	}
+	server := For(rt).NewServer()
+	go func() { _ = server.Serve(lis) }()
+	defer server.Stop()
+
+	conn, err := grpc.NewClient(lis.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("NewClient failed: %v", err)
+	}
+	defer conn.Close()
+
+	reply := &wrapperspb.StringValue{}
+	if err := conn.Invoke(context.Background(), unknownTestMethod, wrapperspb.String("hi"), reply); err != nil {
+		t.Fatalf("Invoke failed: %v", err)
+	}
+	if reply.GetValue() != unknownTestMethod+":hi" {
+		t.Errorf("unexpected reply %q", reply.GetValue())
+	}
+
+	rt.SetUnknownServiceHandler(nil)
+	err = conn.Invoke(context.Background(), unknownTestMethod, wrapperspb.String("hi"), reply)
+	if status.Code(err) != codes.Unimplemented {
+		t.Errorf("expected Unimplemented without handler, got %v", err)
+	}
+}
@@ -0,0 +1,254 @@
+package rpcruntime
+
+import (
+	"context"
+	"io"
+	"reflect"
+	"sync"
+)
+
+// HandlerRegisteredHook is an optional interface for handlers that want to be
+// notified after they are registered.
+type HandlerRegisteredHook interface {
+	OnRegistered(protocol Protocol, serviceName string)
+}
+
+// HandlerUnregisteredHook is an optional interface for handlers that want to be
+// notified once they have been unregistered or replaced.
+//
+// OnUnregistered is called as soon as the handler is removed; calls that
+// already started may still be running. Handlers implementing io.Closer are
+// additionally closed after those in-flight calls have drained.
+type HandlerUnregisteredHook interface {
+	OnUnregistered(protocol Protocol, serviceName string)
+}
+
+// handlerEntry is a registered handler with its in-flight call count.
+type handlerEntry struct {
+	handler  any
+	inflight sync.WaitGroup
+}
+
+// AcquireHandler looks up the handler for the given protocol and serviceName in
+// the default Runtime and marks a call as in flight. See
+// Runtime.AcquireHandler.
+func AcquireHandler(protocol Protocol, serviceName string) (handler any, release func(), err error) {
+	return defaultRuntime.AcquireHandler(protocol, serviceName)
+}
+
+// AcquireHandler looks up the handler for (protocol, serviceName) in rt and
+// marks a call as in flight.
+//
+// release must be called once the call has finished; unregistering the handler
+// waits for all acquired calls to be released before closing it. Generated
+// adaptors use this instead of LookupHandler.
+//
+// Returns ErrServiceNotRegistered if no handler is registered,
+// ErrNotInitialized if initializers are registered but Init has not succeeded,
+// or ErrUnavailable once rt has been shut down.
+func (rt *Runtime) AcquireHandler(protocol Protocol, serviceName string) (handler any, release func(), err error) {
+	key := handlerKey{protocol: protocol, serviceName: serviceName}
+
+	rt.handlerMu.RLock()
+	if rt.shutdown {
+		rt.handlerMu.RUnlock()
+		return nil, nil, ErrUnavailable
+	}
+	if rt.initRequired && !rt.initialized {
+		rt.handlerMu.RUnlock()
+		return nil, nil, ErrNotInitialized
+	}
+	e, exists := rt.handlerEntryLocked(key)
+	if exists {
+		// Added under the read lock so removal (under the write lock) always
+		// observes the count before waiting on it.
+		e.inflight.Add(1)
+	}
+	rt.handlerMu.RUnlock()
+
+	if !exists {
+		return nil, nil, ErrServiceNotRegistered
+	}
+	var once sync.Once
+	return e.handler, func() { once.Do(e.inflight.Done) }, nil
+}
+
+// AcquireGrpcHandler looks up a gRPC handler in the default Runtime and marks a
+// call as in flight. See Runtime.AcquireHandler.
+func AcquireGrpcHandler(serviceName string) (handler any, release func(), err error) {
+	return defaultRuntime.AcquireGrpcHandler(serviceName)
+}
+
+// AcquireGrpcHandler is AcquireHandler for ProtocolGrpc.
+func (rt *Runtime) AcquireGrpcHandler(serviceName string) (handler any, release func(), err error) {
+	return rt.AcquireHandler(ProtocolGrpc, serviceName)
+}
+
+// AcquireConnectHandler looks up a connectrpc handler in the default Runtime
+// and marks a call as in flight. See Runtime.AcquireHandler.
+func AcquireConnectHandler(serviceName string) (handler any, release func(), err error) {
+	return defaultRuntime.AcquireConnectHandler(serviceName)
+}
+
+// AcquireConnectHandler is AcquireHandler for ProtocolConnectRPC.
+func (rt *Runtime) AcquireConnectHandler(serviceName string) (handler any, release func(), err error) {
+	return rt.AcquireHandler(ProtocolConnectRPC, serviceName)
+}
+
+// UnregisterHandler removes the handler for the given protocol and serviceName
+// from the default Runtime. See Runtime.UnregisterHandler.
+func UnregisterHandler(protocol Protocol, serviceName string) (removed bool) {
+	return defaultRuntime.UnregisterHandler(protocol, serviceName)
+}
+
+// UnregisterHandler removes the handler for (protocol, serviceName) from rt.
+//
+// New calls fail with ErrServiceNotRegistered immediately. The handler's
+// OnUnregistered hook runs before returning; it is closed in the background
+// once in-flight calls have drained.
+// Returns removed=false if no handler was registered.
+func (rt *Runtime) UnregisterHandler(protocol Protocol, serviceName string) (removed bool) {
+	_, removed = rt.removeHandler(protocol, serviceName)
+	return removed
+}
+
+// UnregisterGrpcHandler removes the gRPC handler for serviceName from the
+// default Runtime. See Runtime.UnregisterHandler.
+func UnregisterGrpcHandler(serviceName string) (removed bool) {
+	return defaultRuntime.UnregisterGrpcHandler(serviceName)
+}
+
+// UnregisterGrpcHandler is UnregisterHandler for ProtocolGrpc.
+func (rt *Runtime) UnregisterGrpcHandler(serviceName string) (removed bool) {
+	return rt.UnregisterHandler(ProtocolGrpc, serviceName)
+}
+
+// UnregisterConnectHandler removes the connectrpc handler for serviceName from
+// the default Runtime. See Runtime.UnregisterHandler.
+func UnregisterConnectHandler(serviceName string) (removed bool) {
+	return defaultRuntime.UnregisterConnectHandler(serviceName)
+}
+
+// UnregisterConnectHandler is UnregisterHandler for ProtocolConnectRPC.
+func (rt *Runtime) UnregisterConnectHandler(serviceName string) (removed bool) {
+	return rt.UnregisterHandler(ProtocolConnectRPC, serviceName)
+}
+
+// UnregisterHandlerAndWait is like UnregisterHandler but waits for in-flight
+// calls to drain. See Runtime.UnregisterHandlerAndWait.
+func UnregisterHandlerAndWait(ctx context.Context, protocol Protocol, serviceName string) (removed bool, err error) {
+	return defaultRuntime.UnregisterHandlerAndWait(ctx, protocol, serviceName)
+}
+
+// UnregisterHandlerAndWait removes the handler for (protocol, serviceName)
+// from rt and waits until its in-flight calls have drained and it has been
+// closed.
+//
+// It returns the error from the handler's Close method, or ctx.Err() if ctx is
+// done first; in that case the handler is still closed once drained.
+func (rt *Runtime) UnregisterHandlerAndWait(ctx context.Context, protocol Protocol, serviceName string) (removed bool, err error) {
+	done, removed := rt.removeHandler(protocol, serviceName)
+	if !removed {
+		return false, nil
+	}
+	select {
+	case err := <-done:
+		return true, err
+	case <-ctx.Done():
+		return true, ctx.Err()
+	}
+}
+
+// UnregisterGrpcHandlerAndWait is like UnregisterGrpcHandler but waits for
+// in-flight calls to drain. See Runtime.UnregisterHandlerAndWait.
+func UnregisterGrpcHandlerAndWait(ctx context.Context, serviceName string) (removed bool, err error) {
+	return defaultRuntime.UnregisterGrpcHandlerAndWait(ctx, serviceName)
+}
+
+// UnregisterGrpcHandlerAndWait is UnregisterHandlerAndWait for ProtocolGrpc.
+func (rt *Runtime) UnregisterGrpcHandlerAndWait(ctx context.Context, serviceName string) (removed bool, err error) {
+	return rt.UnregisterHandlerAndWait(ctx, ProtocolGrpc, serviceName)
+}
+
+// UnregisterConnectHandlerAndWait is like UnregisterConnectHandler but waits
+// for in-flight calls to drain. See Runtime.UnregisterHandlerAndWait.
+func UnregisterConnectHandlerAndWait(ctx context.Context, serviceName string) (removed bool, err error) {
+	return defaultRuntime.UnregisterConnectHandlerAndWait(ctx, serviceName)
+}
+
+// UnregisterConnectHandlerAndWait is UnregisterHandlerAndWait for
+// ProtocolConnectRPC.
+func (rt *Runtime) UnregisterConnectHandlerAndWait(ctx context.Context, serviceName string) (removed bool, err error) {
+	return rt.UnregisterHandlerAndWait(ctx, ProtocolConnectRPC, serviceName)
+}
+
+// removeHandler removes the handler slot and retires the old handler.
+func (rt *Runtime) removeHandler(protocol Protocol, serviceName string) (<-chan error, bool) {
+	key := handlerKey{protocol: protocol, serviceName: serviceName}
+
+	rt.watchMu.Lock()
+	rt.handlerMu.Lock()
+	e, existed := rt.handlers[key]
+	delete(rt.handlers, key)
+	rt.handlerMu.Unlock()
+
+	if !existed {
+		rt.watchMu.Unlock()
+		return nil, false
+	}
+	rt.queueRegistryEventLocked(RegistryEvent{Kind: RegistryEventUnregistered, Protocol: protocol, ServiceName: serviceName}, nil)
+	rt.watchMu.Unlock()
+	rt.deliverRegistryEvents()
+
+	logf(LogLevelInfo, "rpcruntime: unregistered %s handler for %s", protocol, serviceName)
+	return rt.retireHandler(protocol, serviceName, e), true
+}
+
+// retireHandler notifies a removed handler and closes it once its in-flight
+// calls have drained.
+//
+// The returned channel receives the Close error (nil if the handler is not an
+// io.Closer or is still registered under another slot).
+func (rt *Runtime) retireHandler(protocol Protocol, serviceName string, e *handlerEntry) <-chan error {
+	if h, ok := e.handler.(HandlerUnregisteredHook); ok {
+		h.OnUnregistered(protocol, serviceName)
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		e.inflight.Wait()
+
+		var err error
+		if c, ok := e.handler.(io.Closer); ok && !rt.isRegistered(e.handler) {
+			err = c.Close()
+			if err != nil {
+				logf(LogLevelWarn, "rpcruntime: closing %s handler for %s: %v", protocol, serviceName, err)
+			}
+		}
+		done <- err
+	}()
+	return done
+}
+
+// isRegistered reports whether handler is still registered in any slot of rt.
+func (rt *Runtime) isRegistered(handler any) bool {
+	rt.handlerMu.RLock()
+	defer rt.handlerMu.RUnlock()
+
+	for _, e := range rt.handlers {
+		if sameHandler(e.handler, handler) {
+			return true
+		}
+	}
+	return false
+}
+
+// sameHandler reports whether a and b are the same handler value.
+// Handlers of non-comparable types are never considered the same.
+func sameHandler(a, b any) bool {
+	ta := reflect.TypeOf(a)
+	if ta != reflect.TypeOf(b) || !ta.Comparable() {
+		return false
+	}
+	return a == b
+}
@@ -0,0 +1,173 @@
+package rpcruntime
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+type lifecycleHandler struct {
+	mu       sync.Mutex
+	events   []string
+	closeErr error
+	closed   chan struct{}
+}
+
+func newLifecycleHandler() *lifecycleHandler {
+	return &lifecycleHandler{closed: make(chan struct{})}
+}
+
+func (h *lifecycleHandler) record(event string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.events = append(h.events, event)
+}
+
+func (h *lifecycleHandler) OnRegistered(protocol Protocol, serviceName string) {
+	h.record("registered:" + string(protocol) + ":" + serviceName)
+}
+
+func (h *lifecycleHandler) OnUnregistered(protocol Protocol, serviceName string) {
+	h.record("unregistered:" + string(protocol) + ":" + serviceName)
+}
+
+func (h *lifecycleHandler) Close() error {
+	h.record("closed")
+	close(h.closed)
+	return h.closeErr
+}
+
+func (h *lifecycleHandler) snapshot() []string {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return append([]string(nil), h.events...)
+}
+
+func waitClosed(t *testing.T, h *lifecycleHandler) {
+	t.Helper()
+	select {
+	case <-h.closed:
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for Close")
+	}
+}
+
+func TestUnregisterHandler(t *testing.T) {
+	rt := NewRuntime()
+	serviceName := "rpc.test.TestService"
+	h := newLifecycleHandler()
+
+	if _, err := rt.RegisterGrpcHandler(serviceName, h); err != nil {
+		t.Fatalf("RegisterGrpcHandler failed: %v", err)
+	}
+	if !rt.UnregisterGrpcHandler(serviceName) {
+		t.Fatal("expected removed=true")
+	}
+	if _, ok := rt.LookupGrpcHandler(serviceName); ok {
+		t.Error("handler must be gone after unregister")
+	}
+	if rt.UnregisterGrpcHandler(serviceName) {
+		t.Error("expected removed=false for a second unregister")
+	}
+	waitClosed(t, h)
+
+	want := []string{
+		"registered:grpc:" + serviceName,
+		"unregistered:grpc:" + serviceName,
+		"closed",
+	}
+	got := h.snapshot()
+	if len(got) != len(want) {
+		t.Fatalf("events = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("events = %v, want %v", got, want)
+		}
+	}
+}
+
+func TestReplaceHandlerRetiresOld(t *testing.T) {
+	rt := NewRuntime()
+	serviceName := "rpc.test.TestService"
+	oldHandler := newLifecycleHandler()
+	newHandler := newLifecycleHandler()
+
+	_, _ = rt.RegisterConnectHandler(serviceName, oldHandler)
+	replaced, err := rt.RegisterConnectHandler(serviceName, newHandler)
+	if err != nil || !replaced {
+		t.Fatalf("expected replaced=true, got %v, %v", replaced, err)
+	}
+	waitClosed(t, oldHandler)
+
+	select {
+	case <-newHandler.closed:
+		t.Error("new handler must not be closed")
+	default:
+	}
+}
+
+func TestUnregisterWaitsForInflightCalls(t *testing.T) {
+	rt := NewRuntime()
+	serviceName := "rpc.test.TestService"
+	h := newLifecycleHandler()
+	h.closeErr = errors.New("close failed")
+	_, _ = rt.RegisterGrpcHandler(serviceName, h)
+
+	_, release, err := rt.AcquireGrpcHandler(serviceName)
+	if err != nil {
+		t.Fatalf("AcquireGrpcHandler failed: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	removed, err := rt.UnregisterGrpcHandlerAndWait(ctx, serviceName)
+	if !removed || !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected deadline while call is in flight, got %v, %v", removed, err)
+	}
+	select {
+	case <-h.closed:
+		t.Fatal("handler closed before in-flight call was released")
+	default:
+	}
+
+	release()
+	release() // idempotent
+	waitClosed(t, h)
+}
+
+func TestUnregisterAndWaitReturnsCloseError(t *testing.T) {
+	rt := NewRuntime()
+	serviceName := "rpc.test.TestService"
+	h := newLifecycleHandler()
+	h.closeErr = errors.New("close failed")
+	_, _ = rt.RegisterGrpcHandler(serviceName, h)
+
+	removed, err := rt.UnregisterGrpcHandlerAndWait(context.Background(), serviceName)
+	if !removed || err != h.closeErr {
+		t.Fatalf("expected close error, got %v, %v", removed, err)
+	}
+}
+
+func TestCloseSkippedWhileStillRegistered(t *testing.T) {
+	rt := NewRuntime()
+	h := newLifecycleHandler()
+	_, _ = rt.RegisterGrpcHandler("rpc.test.A", h)
+	_, _ = rt.RegisterConnectHandler("rpc.test.A", h)
+
+	if _, err := rt.UnregisterGrpcHandlerAndWait(context.Background(), "rpc.test.A"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	select {
+	case <-h.closed:
+		t.Fatal("handler still registered for connectrpc must not be closed")
+	default:
+	}
+
+	if _, err := rt.UnregisterConnectHandlerAndWait(context.Background(), "rpc.test.A"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	waitClosed(t, h)
+}
@@ -0,0 +1,98 @@
+package rpcruntime
+
+import (
+	"context"
+	"net/http"
+	"strings"
+)
+
+type requestHeaderContextKey struct{}
+
+type responseMetadataContextKey struct{}
+
+// WithRequestHeader returns a copy of ctx carrying header as the request
+// headers of calls made with it.
+//
+// Connect handlers see them through Request.Header and the streams'
+// RequestHeader. The adaptor hands each call its own copy.
+func WithRequestHeader(ctx context.Context, header http.Header) context.Context {
+	return context.WithValue(ctx, requestHeaderContextKey{}, header)
+}
+
+// RequestHeaderFromContext returns the request headers carried by ctx, or nil
+// if none were set with WithRequestHeader.
+func RequestHeaderFromContext(ctx context.Context) http.Header {
+	header, _ := ctx.Value(requestHeaderContextKey{}).(http.Header)
+	return header
+}
+
+// ResponseMetadata collects the response headers and trailers set by the
+// handler of a call.
+//
+// The maps are written while the call runs; read them only once the call has
+// returned (or, for streams, once onDone or Finish has returned).
+type ResponseMetadata struct {
+	Header  http.Header
+	Trailer http.Header
+}
+
+// WithResponseMetadata returns a copy of ctx that collects the response
+// headers and trailers of calls made with it into the returned
+// ResponseMetadata.
+//
+// Example:
+//
+//	ctx, md := rpcruntime.WithResponseMetadata(ctx)
+//	resp, err := pb.TestService_Ping(ctx, req)
+//	traceID := md.Header.Get("Trace-Id")
+func WithResponseMetadata(ctx context.Context) (context.Context, *ResponseMetadata) {
+	md := &ResponseMetadata{Header: http.Header{}, Trailer: http.Header{}}
+	return context.WithValue(ctx, responseMetadataContextKey{}, md), md
+}
+
+// ResponseMetadataFromContext returns the ResponseMetadata installed by
+// WithResponseMetadata, or nil if there is none.
+func ResponseMetadataFromContext(ctx context.Context) *ResponseMetadata {
+	md, _ := ctx.Value(responseMetadataContextKey{}).(*ResponseMetadata)
+	return md
+}
+
+// RecordResponseMetadata appends header and trailer to the ResponseMetadata
+// carried by ctx. It does nothing if ctx carries none.
+func RecordResponseMetadata(ctx context.Context, header, trailer http.Header) {
+	md := ResponseMetadataFromContext(ctx)
+	if md == nil {
+		return
+	}
+	mergeHeader(md.Header, header)
+	mergeHeader(md.Trailer, trailer)
+}
+
+// RecordReceivedResponseMetadata is RecordResponseMetadata for response
+// metadata received over the wire, e.g. by a RemoteTransport: the headers set
+// by the Connect protocol and the content negotiation headers are dropped from
+// header first.
+func RecordReceivedResponseMetadata(ctx context.Context, header, trailer http.Header) {
+	RecordResponseMetadata(ctx, stripProtocolHeaders(header), trailer)
+}
+
+func mergeHeader(dst, src http.Header) {
+	for k, v := range src {
+		dst[k] = append(dst[k], v...)
+	}
+}
+
+// stripProtocolHeaders returns a copy of header without the headers set by the
+// Connect protocol and the content negotiation headers.
+func stripProtocolHeaders(header http.Header) http.Header {
+	out := http.Header{}
+	for k, v := range header {
+		switch {
+		case strings.HasPrefix(k, "Connect-"):
+		case k == "Content-Type", k == "Content-Encoding", k == "Accept-Encoding":
+		default:
+			out[k] = v
+		}
+	}
+	return out
+}
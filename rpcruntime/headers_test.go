@@ -0,0 +1,33 @@
+package rpcruntime
+
+import (
+	"context"
+	"net/http"
+	"testing"
+)
+
+func TestRequestHeaderFromContext(t *testing.T) {
+	if h := RequestHeaderFromContext(context.Background()); h != nil {
+		t.Fatalf("expected nil header, got %v", h)
+	}
+	ctx := WithRequestHeader(context.Background(), http.Header{"X-Test": []string{"v"}})
+	if got := RequestHeaderFromContext(ctx).Get("X-Test"); got != "v" {
+		t.Fatalf("unexpected X-Test header %q", got)
+	}
+}
+
+func TestRecordResponseMetadata(t *testing.T) {
+	// Without a collector recording is a no-op.
+	RecordResponseMetadata(context.Background(), http.Header{"X-A": []string{"1"}}, nil)
+
+	ctx, md := WithResponseMetadata(context.Background())
+	RecordResponseMetadata(ctx, http.Header{"X-A": []string{"1"}}, http.Header{"X-T": []string{"t"}})
+	RecordResponseMetadata(ctx, http.Header{"X-A": []string{"2"}}, nil)
+
+	if got := md.Header.Values("X-A"); len(got) != 2 || got[0] != "1" || got[1] != "2" {
+		t.Fatalf("unexpected X-A values %v", got)
+	}
+	if got := md.Trailer.Get("X-T"); got != "t" {
+		t.Fatalf("unexpected X-T trailer %q", got)
+	}
+}
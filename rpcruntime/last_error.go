@@ -0,0 +1,79 @@
+package rpcruntime
+
+import (
+	"runtime"
+	"sync"
+)
+
+// lastErrors maps an OS thread id to the error id of the last failing export
+// called on that thread.
+var (
+	lastErrorMu sync.Mutex
+	lastErrors  = make(map[uint64]uint64)
+)
+
+// StoreLastError stores err like StoreError and records the returned id as the
+// last error of the calling OS thread.
+//
+// A nil err returns 0 and leaves the last error untouched, mirroring errno.
+func StoreLastError(err error) uint64 {
+	id := StoreError(err)
+	SetLastError(id)
+	return id
+}
+
+// SetLastError records errorID as the last error of the calling OS thread.
+//
+// An errorID of 0 is ignored; use ClearLastError to reset the value.
+func SetLastError(errorID uint64) {
+	if errorID == 0 {
+		return
+	}
+	tid := lockedThreadID()
+
+	lastErrorMu.Lock()
+	lastErrors[tid] = errorID
+	lastErrorMu.Unlock()
+}
+
+// LastError returns the error id of the last failing export on the calling OS
+// thread, or 0 if none has been recorded since the last ClearLastError.
+//
+// The id follows the normal registry TTL, so the message may already have
+// expired when it is looked up.
+func LastError() uint64 {
+	tid := lockedThreadID()
+
+	lastErrorMu.Lock()
+	defer lastErrorMu.Unlock()
+	return lastErrors[tid]
+}
+
+// ClearLastError resets the last error of the calling OS thread.
+func ClearLastError() {
+	tid := lockedThreadID()
+
+	lastErrorMu.Lock()
+	delete(lastErrors, tid)
+	lastErrorMu.Unlock()
+}
+
+// lockedThreadID returns the id of the OS thread running the caller.
+//
+// The goroutine is locked to its thread while the id is read. Exports invoked
+// from C already run locked to the calling C thread for their whole duration,
+// so the id identifies the C caller.
+func lockedThreadID() uint64 {
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+	return currentThreadID()
+}
+
+// clearLastErrors clears all recorded last errors.
+// This is intended for testing only.
+func clearLastErrors() {
+	lastErrorMu.Lock()
+	defer lastErrorMu.Unlock()
+
+	lastErrors = make(map[uint64]uint64)
+}
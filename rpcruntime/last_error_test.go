@@ -0,0 +1,67 @@
+package rpcruntime
+
+import (
+	"errors"
+	"runtime"
+	"testing"
+)
+
+func TestStoreLastError(t *testing.T) {
+	clearLastErrors()
+	defer clearLastErrors()
+
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+
+	if got := StoreLastError(nil); got != 0 {
+		t.Fatalf("expected 0 for nil error, got %d", got)
+	}
+	if got := LastError(); got != 0 {
+		t.Fatalf("expected no last error, got %d", got)
+	}
+
+	id := StoreLastError(errors.New("boom"))
+	if id == 0 {
+		t.Fatal("expected non-zero error id")
+	}
+	if got := LastError(); got != id {
+		t.Fatalf("expected last error %d, got %d", id, got)
+	}
+
+	// A successful call does not reset the last error.
+	_ = StoreLastError(nil)
+	if got := LastError(); got != id {
+		t.Fatalf("expected last error %d to survive nil store, got %d", id, got)
+	}
+
+	ClearLastError()
+	if got := LastError(); got != 0 {
+		t.Fatalf("expected 0 after ClearLastError, got %d", got)
+	}
+}
+
+func TestLastErrorIsPerThread(t *testing.T) {
+	clearLastErrors()
+	defer clearLastErrors()
+
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+
+	id := StoreLastError(errors.New("main thread"))
+
+	other := make(chan uint64)
+	go func() {
+		// A locked goroutine owns its thread exclusively, so it cannot share
+		// the test goroutine's thread.
+		runtime.LockOSThread()
+		defer runtime.UnlockOSThread()
+		other <- LastError()
+	}()
+
+	if got := <-other; got != 0 {
+		t.Fatalf("expected other thread to have no last error, got %d", got)
+	}
+	if got := LastError(); got != id {
+		t.Fatalf("expected last error %d, got %d", id, got)
+	}
+}
@@ -0,0 +1,189 @@
+package rpcruntime
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync/atomic"
+)
+
+// LogLevel is the severity of a diagnostic forwarded to the log sink.
+//
+// The numeric values are part of the C ABI (YgrpcLogLevel) and must not change.
+type LogLevel int
+
+const (
+	LogLevelDebug LogLevel = 0
+	LogLevelInfo  LogLevel = 1
+	LogLevelWarn  LogLevel = 2
+	LogLevelError LogLevel = 3
+)
+
+// String returns the upper-case level name.
+func (l LogLevel) String() string {
+	switch l {
+	case LogLevelDebug:
+		return "DEBUG"
+	case LogLevelInfo:
+		return "INFO"
+	case LogLevelWarn:
+		return "WARN"
+	case LogLevelError:
+		return "ERROR"
+	default:
+		return fmt.Sprintf("LEVEL(%d)", int(l))
+	}
+}
+
+// LogSink receives runtime diagnostics.
+//
+// It may be called concurrently from any goroutine.
+type LogSink func(level LogLevel, msg string)
+
+// ErrorHook is called with every error stored in the error registry.
+//
+// It may be called concurrently from any goroutine and must not block.
+type ErrorHook func(errorID uint64, msg string)
+
+type logSinkState struct {
+	minLevel LogLevel
+	sink     LogSink
+}
+
+var (
+	logSink   atomic.Pointer[logSinkState]
+	errorHook atomic.Pointer[ErrorHook]
+)
+
+// SetLogSink installs sink to receive runtime diagnostics at minLevel or above.
+//
+// Diagnostics include recovered handler panics, finished stream sessions,
+// dropped client-stream results and handler replacements. Passing a nil sink
+// disables forwarding.
+func SetLogSink(minLevel LogLevel, sink LogSink) {
+	if sink == nil {
+		logSink.Store(nil)
+		return
+	}
+	logSink.Store(&logSinkState{minLevel: minLevel, sink: sink})
+}
+
+// SetErrorHook installs hook to observe every stored error.
+//
+// Passing nil removes the hook.
+func SetErrorHook(hook ErrorHook) {
+	if hook == nil {
+		errorHook.Store(nil)
+		return
+	}
+	errorHook.Store(&hook)
+}
+
+// logEnabled reports whether a diagnostic at level would be forwarded.
+func logEnabled(level LogLevel) bool {
+	st := logSink.Load()
+	return st != nil && level >= st.minLevel
+}
+
+// logf formats and forwards a diagnostic to the installed sink, if any.
+func logf(level LogLevel, format string, args ...any) {
+	st := logSink.Load()
+	if st == nil || level < st.minLevel {
+		return
+	}
+	st.sink(level, fmt.Sprintf(format, args...))
+}
+
+// notifyErrorHook forwards a stored error to the installed hook, if any.
+func notifyErrorHook(errorID uint64, msg []byte) {
+	if hook := errorHook.Load(); hook != nil {
+		(*hook)(errorID, string(msg))
+	}
+}
+
+// NewSlogHandler returns a slog.Handler that forwards records to the log sink
+// installed with SetLogSink, so Go handlers can log into the host's logging
+// system.
+//
+// Records are rendered as the message followed by space-separated key=value
+// attributes; group names are joined to keys with '.'.
+func NewSlogHandler() slog.Handler {
+	return &slogHandler{}
+}
+
+type slogHandler struct {
+	attrs  string
+	prefix string
+}
+
+func (h *slogHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return logEnabled(logLevelFromSlog(level))
+}
+
+func (h *slogHandler) Handle(_ context.Context, r slog.Record) error {
+	level := logLevelFromSlog(r.Level)
+	if !logEnabled(level) {
+		return nil
+	}
+	var b strings.Builder
+	b.WriteString(r.Message)
+	b.WriteString(h.attrs)
+	r.Attrs(func(a slog.Attr) bool {
+		appendSlogAttr(&b, h.prefix, a)
+		return true
+	})
+	logf(level, "%s", b.String())
+	return nil
+}
+
+func (h *slogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	var b strings.Builder
+	b.WriteString(h.attrs)
+	for _, a := range attrs {
+		appendSlogAttr(&b, h.prefix, a)
+	}
+	return &slogHandler{attrs: b.String(), prefix: h.prefix}
+}
+
+func (h *slogHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	return &slogHandler{attrs: h.attrs, prefix: h.prefix + name + "."}
+}
+
+func appendSlogAttr(b *strings.Builder, prefix string, a slog.Attr) {
+	a.Value = a.Value.Resolve()
+	if a.Equal(slog.Attr{}) {
+		return
+	}
+	if a.Value.Kind() == slog.KindGroup {
+		groupPrefix := prefix
+		if a.Key != "" {
+			groupPrefix = prefix + a.Key + "."
+		}
+		for _, ga := range a.Value.Group() {
+			appendSlogAttr(b, groupPrefix, ga)
+		}
+		return
+	}
+	b.WriteByte(' ')
+	b.WriteString(prefix)
+	b.WriteString(a.Key)
+	b.WriteByte('=')
+	b.WriteString(a.Value.String())
+}
+
+func logLevelFromSlog(level slog.Level) LogLevel {
+	switch {
+	case level < slog.LevelInfo:
+		return LogLevelDebug
+	case level < slog.LevelWarn:
+		return LogLevelInfo
+	case level < slog.LevelError:
+		return LogLevelWarn
+	default:
+		return LogLevelError
+	}
+}
@@ -0,0 +1,113 @@
+package rpcruntime
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"sync"
+	"testing"
+)
+
+type logEntry struct {
+	level LogLevel
+	msg   string
+}
+
+type logCollector struct {
+	mu      sync.Mutex
+	entries []logEntry
+}
+
+func (c *logCollector) sink(level LogLevel, msg string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries = append(c.entries, logEntry{level: level, msg: msg})
+}
+
+func (c *logCollector) find(level LogLevel, substr string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, e := range c.entries {
+		if e.level == level && strings.Contains(e.msg, substr) {
+			return true
+		}
+	}
+	return false
+}
+
+func installLogCollector(t *testing.T, minLevel LogLevel) *logCollector {
+	t.Helper()
+	c := &logCollector{}
+	SetLogSink(minLevel, c.sink)
+	t.Cleanup(func() { SetLogSink(0, nil) })
+	return c
+}
+
+func TestLogSinkReceivesDiagnostics(t *testing.T) {
+	clearHandlerRegistry()
+	defer clearHandlerRegistry()
+	c := installLogCollector(t, LogLevelDebug)
+
+	_, _ = RegisterGrpcHandler("rpc.log.Service", &struct{}{})
+	_, _ = RegisterGrpcHandler("rpc.log.Service", &struct{}{})
+	if !c.find(LogLevelWarn, "replaced grpc handler for rpc.log.Service") {
+		t.Error("expected replacement to be logged")
+	}
+
+	_ = RecoverPanic("boom")
+	if !c.find(LogLevelError, "recovered panic: boom") {
+		t.Error("expected recovered panic to be logged")
+	}
+
+	handle, _, _ := AllocateStreamHandle(context.Background(), ProtocolGrpc)
+	FinishStreamHandle(handle)
+	if !c.find(LogLevelDebug, "finished grpc stream") {
+		t.Error("expected finished stream to be logged")
+	}
+
+	CompleteClientStream(handle, nil, nil)
+	if !c.find(LogLevelWarn, "dropped client-stream result") {
+		t.Error("expected dropped result to be logged")
+	}
+}
+
+func TestLogSinkMinLevel(t *testing.T) {
+	c := installLogCollector(t, LogLevelWarn)
+
+	handle, _, _ := AllocateStreamHandle(context.Background(), ProtocolGrpc)
+	FinishStreamHandle(handle)
+	if c.find(LogLevelDebug, "finished") {
+		t.Error("expected debug diagnostics to be filtered")
+	}
+}
+
+func TestErrorHook(t *testing.T) {
+	var gotID uint64
+	var gotMsg string
+	SetErrorHook(func(errorID uint64, msg string) {
+		gotID = errorID
+		gotMsg = msg
+	})
+	t.Cleanup(func() { SetErrorHook(nil) })
+
+	id := StoreError(errors.New("hooked"))
+	if gotID != id || gotMsg != "hooked" {
+		t.Fatalf("expected hook(%d, %q), got hook(%d, %q)", id, "hooked", gotID, gotMsg)
+	}
+}
+
+func TestSlogHandler(t *testing.T) {
+	c := installLogCollector(t, LogLevelInfo)
+
+	logger := slog.New(NewSlogHandler()).With("svc", "demo").WithGroup("req")
+	logger.Debug("filtered")
+	logger.Warn("hello", "id", 7, slog.Group("peer", "addr", "local"))
+
+	if c.find(LogLevelDebug, "filtered") {
+		t.Error("expected debug record to be filtered")
+	}
+	if !c.find(LogLevelWarn, "hello svc=demo req.id=7 req.peer.addr=local") {
+		t.Errorf("unexpected entries: %+v", c.entries)
+	}
+}
@@ -0,0 +1,38 @@
+package rpcruntime
+
+import "google.golang.org/protobuf/proto"
+
+// CopyMessage copies src into dst. Both must be protobuf messages of the same
+// type; ErrStreamMessageTypeMismatch is returned otherwise.
+func CopyMessage(src, dst any) error {
+	srcMsg, ok := src.(proto.Message)
+	if !ok {
+		return ErrStreamMessageTypeMismatch
+	}
+	dstMsg, ok := dst.(proto.Message)
+	if !ok {
+		return ErrStreamMessageTypeMismatch
+	}
+	if srcMsg.ProtoReflect().Descriptor().FullName() != dstMsg.ProtoReflect().Descriptor().FullName() {
+		return ErrStreamMessageTypeMismatch
+	}
+	proto.Reset(dstMsg)
+	proto.Merge(dstMsg, srcMsg)
+	return nil
+}
+
+func marshalMessage(msg any) ([]byte, error) {
+	m, ok := msg.(proto.Message)
+	if !ok {
+		return nil, ErrStreamMessageTypeMismatch
+	}
+	return proto.Marshal(m)
+}
+
+func unmarshalMessage(b []byte, msg any) error {
+	m, ok := msg.(proto.Message)
+	if !ok {
+		return ErrStreamMessageTypeMismatch
+	}
+	return proto.Unmarshal(b, m)
+}
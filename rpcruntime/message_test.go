@@ -0,0 +1,36 @@
+package rpcruntime
+
+import (
+	"testing"
+
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+func TestCopyMessage(t *testing.T) {
+	t.Run("TypeMatchCopies", func(t *testing.T) {
+		src := &wrapperspb.StringValue{Value: "hello"}
+		dst := &wrapperspb.StringValue{Value: "stale"}
+
+		if err := CopyMessage(src, dst); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+		if dst.Value != "hello" {
+			t.Fatalf("expected dst to be updated, got %q", dst.Value)
+		}
+	})
+
+	t.Run("TypeMismatchReturnsError", func(t *testing.T) {
+		src := &wrapperspb.StringValue{Value: "hello"}
+		dst := &wrapperspb.Int32Value{Value: 1}
+
+		if err := CopyMessage(src, dst); err == nil {
+			t.Fatal("expected error, got nil")
+		}
+	})
+
+	t.Run("NonProtoReturnsError", func(t *testing.T) {
+		if err := CopyMessage("not proto", "also not proto"); err == nil {
+			t.Fatal("expected error, got nil")
+		}
+	})
+}
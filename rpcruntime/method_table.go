@@ -0,0 +1,248 @@
+package rpcruntime
+
+import (
+	"context"
+	"sort"
+	"sync"
+)
+
+// MethodKind is the streaming shape of a method.
+type MethodKind int
+
+const (
+	// MethodKindUnary is a unary method.
+	MethodKindUnary MethodKind = iota
+	// MethodKindClientStream is a client-streaming method.
+	MethodKindClientStream
+	// MethodKindServerStream is a server-streaming method.
+	MethodKindServerStream
+	// MethodKindBidiStream is a bidi-streaming method.
+	MethodKindBidiStream
+)
+
+// MethodDesc describes how to call one method through its generated adaptor
+// functions without knowing its message types.
+//
+// Generated adaptor files register a MethodDesc for every method at init time
+// with RegisterMethods; callers that dispatch by full method name, such as
+// grpcrt.ClientConn, look them up there. Build one with NewUnaryMethodDesc,
+// NewClientStreamMethodDesc, NewServerStreamMethodDesc or
+// NewBidiStreamMethodDesc.
+//
+// The call methods take and return messages as any; they are used by the
+// protocol packages serving the method table, such as grpcrt and connectrt.
+type MethodDesc struct {
+	fullMethod string
+	kind       MethodKind
+	newRequest func() any
+
+	unary        func(ctx context.Context, req any) (any, error)
+	startClient  func(ctx context.Context) (uint64, error)
+	finishClient func(handle uint64) (any, error)
+	serverStream func(ctx context.Context, req any, onRead func(any) bool, onDone func(error)) error
+	startBidi    func(ctx context.Context, onRead func(any) bool, onDone func(error)) (uint64, error)
+	send         func(handle uint64, req any) error
+	closeSend    func(handle uint64) error
+}
+
+// FullMethod returns the method name in the "/package.Service/Method" form.
+func (d MethodDesc) FullMethod() string {
+	return d.fullMethod
+}
+
+// Kind returns the streaming shape of the method.
+func (d MethodDesc) Kind() MethodKind {
+	return d.kind
+}
+
+// NewRequest returns a new, empty request message of the method.
+func (d MethodDesc) NewRequest() any {
+	return d.newRequest()
+}
+
+// Unary calls a unary method. req must be of the method's request type.
+func (d MethodDesc) Unary(ctx context.Context, req any) (any, error) {
+	return d.unary(ctx, req)
+}
+
+// StartClientStream starts a client-streaming call and returns its stream
+// handle, for use with Send and FinishClientStream.
+func (d MethodDesc) StartClientStream(ctx context.Context) (uint64, error) {
+	return d.startClient(ctx)
+}
+
+// FinishClientStream closes the send side of a client-streaming call and
+// returns its response.
+func (d MethodDesc) FinishClientStream(handle uint64) (any, error) {
+	return d.finishClient(handle)
+}
+
+// ServerStream runs a server-streaming call, delivering its responses to
+// onRead. onDone is called exactly once when the stream ends or fails.
+func (d MethodDesc) ServerStream(ctx context.Context, req any, onRead func(any) bool, onDone func(error)) error {
+	return d.serverStream(ctx, req, onRead, onDone)
+}
+
+// StartBidiStream starts a bidi-streaming call and returns its stream handle,
+// for use with Send and CloseSend. Responses are delivered to onRead and
+// onDone is called exactly once when the stream ends or fails.
+func (d MethodDesc) StartBidiStream(ctx context.Context, onRead func(any) bool, onDone func(error)) (uint64, error) {
+	return d.startBidi(ctx, onRead, onDone)
+}
+
+// Send sends a request message on a client- or bidi-streaming call.
+func (d MethodDesc) Send(handle uint64, req any) error {
+	return d.send(handle, req)
+}
+
+// CloseSend closes the send side of a bidi-streaming call.
+func (d MethodDesc) CloseSend(handle uint64) error {
+	return d.closeSend(handle)
+}
+
+// NewUnaryMethodDesc describes a unary method served by call, typically the
+// generated <Service>_<Method> adaptor function.
+func NewUnaryMethodDesc[Req, Res any](
+	fullMethod string,
+	call func(context.Context, *Req) (*Res, error),
+) MethodDesc {
+	return MethodDesc{
+		fullMethod: fullMethod,
+		kind:       MethodKindUnary,
+		newRequest: newMessage[Req],
+		unary: func(ctx context.Context, req any) (any, error) {
+			r, ok := req.(*Req)
+			if !ok {
+				return nil, ErrStreamMessageTypeMismatch
+			}
+			resp, err := call(ctx, r)
+			if err != nil {
+				return nil, err
+			}
+			return resp, nil
+		},
+	}
+}
+
+// NewClientStreamMethodDesc describes a client-streaming method served by the
+// generated Start, Send and Finish adaptor functions.
+func NewClientStreamMethodDesc[Req, Res any](
+	fullMethod string,
+	start func(context.Context) (uint64, error),
+	send func(uint64, *Req) error,
+	finish func(uint64) (*Res, error),
+) MethodDesc {
+	return MethodDesc{
+		fullMethod:  fullMethod,
+		kind:        MethodKindClientStream,
+		newRequest:  newMessage[Req],
+		startClient: start,
+		send:        typedSend(send),
+		finishClient: func(handle uint64) (any, error) {
+			resp, err := finish(handle)
+			if err != nil {
+				return nil, err
+			}
+			return resp, nil
+		},
+	}
+}
+
+// NewServerStreamMethodDesc describes a server-streaming method served by the
+// generated adaptor function.
+func NewServerStreamMethodDesc[Req, Res any](
+	fullMethod string,
+	call func(context.Context, *Req, func(*Res) bool, func(error)) error,
+) MethodDesc {
+	return MethodDesc{
+		fullMethod: fullMethod,
+		kind:       MethodKindServerStream,
+		newRequest: newMessage[Req],
+		serverStream: func(ctx context.Context, req any, onRead func(any) bool, onDone func(error)) error {
+			r, ok := req.(*Req)
+			if !ok {
+				onDone(ErrStreamMessageTypeMismatch)
+				return ErrStreamMessageTypeMismatch
+			}
+			return call(ctx, r, func(resp *Res) bool { return onRead(resp) }, onDone)
+		},
+	}
+}
+
+// NewBidiStreamMethodDesc describes a bidi-streaming method served by the
+// generated Start, Send and CloseSend adaptor functions.
+func NewBidiStreamMethodDesc[Req, Res any](
+	fullMethod string,
+	start func(context.Context, func(*Res) bool, func(error)) (uint64, error),
+	send func(uint64, *Req) error,
+	closeSend func(uint64) error,
+) MethodDesc {
+	return MethodDesc{
+		fullMethod: fullMethod,
+		kind:       MethodKindBidiStream,
+		newRequest: newMessage[Req],
+		startBidi: func(ctx context.Context, onRead func(any) bool, onDone func(error)) (uint64, error) {
+			return start(ctx, func(resp *Res) bool { return onRead(resp) }, onDone)
+		},
+		send:      typedSend(send),
+		closeSend: closeSend,
+	}
+}
+
+func newMessage[T any]() any {
+	return new(T)
+}
+
+func typedSend[Req any](send func(uint64, *Req) error) func(uint64, any) error {
+	return func(handle uint64, req any) error {
+		r, ok := req.(*Req)
+		if !ok {
+			return ErrStreamMessageTypeMismatch
+		}
+		return send(handle, r)
+	}
+}
+
+var (
+	methodTableMu sync.RWMutex
+	methodTable   = make(map[string]MethodDesc)
+)
+
+// RegisterMethods adds descs to the process-wide method table. Generated
+// adaptor files call it from init.
+//
+// A later registration for the same full method replaces the earlier one.
+func RegisterMethods(descs ...MethodDesc) {
+	methodTableMu.Lock()
+	defer methodTableMu.Unlock()
+
+	for _, d := range descs {
+		if _, exists := methodTable[d.fullMethod]; exists {
+			logf(LogLevelWarn, "rpcruntime: replaced method descriptor for %s", d.fullMethod)
+		}
+		methodTable[d.fullMethod] = d
+	}
+}
+
+// LookupMethod returns the MethodDesc registered for fullMethod.
+func LookupMethod(fullMethod string) (desc MethodDesc, ok bool) {
+	methodTableMu.RLock()
+	defer methodTableMu.RUnlock()
+
+	desc, ok = methodTable[fullMethod]
+	return desc, ok
+}
+
+// Methods returns the MethodDescs in the process-wide method table, sorted by
+// full method name.
+func Methods() []MethodDesc {
+	methodTableMu.RLock()
+	descs := make([]MethodDesc, 0, len(methodTable))
+	for _, d := range methodTable {
+		descs = append(descs, d)
+	}
+	methodTableMu.RUnlock()
+
+	sort.Slice(descs, func(i, j int) bool { return descs[i].fullMethod < descs[j].fullMethod })
+	return descs
+}
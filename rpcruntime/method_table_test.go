@@ -0,0 +1,48 @@
+package rpcruntime
+
+import (
+	"context"
+	"testing"
+
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+const methodTableTestMethod = "/rpc.test.MethodTableService/Echo"
+
+func init() {
+	RegisterMethods(NewUnaryMethodDesc(
+		methodTableTestMethod,
+		func(_ context.Context, req *wrapperspb.StringValue) (*wrapperspb.StringValue, error) {
+			return req, nil
+		},
+	))
+}
+
+func TestLookupMethod(t *testing.T) {
+	desc, ok := LookupMethod(methodTableTestMethod)
+	if !ok || desc.FullMethod() != methodTableTestMethod || desc.Kind() != MethodKindUnary {
+		t.Fatalf("LookupMethod(%q) = %v, %v", methodTableTestMethod, desc.FullMethod(), ok)
+	}
+	if _, ok := LookupMethod("/rpc.test.MethodTableService/Missing"); ok {
+		t.Error("expected unknown method lookup to fail")
+	}
+
+	if _, ok := desc.NewRequest().(*wrapperspb.StringValue); !ok {
+		t.Errorf("NewRequest returned %T", desc.NewRequest())
+	}
+	resp, err := desc.Unary(context.Background(), wrapperspb.String("hi"))
+	if err != nil || resp.(*wrapperspb.StringValue).GetValue() != "hi" {
+		t.Errorf("Unary = %v, %v", resp, err)
+	}
+	if _, err := desc.Unary(context.Background(), &wrapperspb.Int32Value{}); err != ErrStreamMessageTypeMismatch {
+		t.Errorf("expected ErrStreamMessageTypeMismatch, got %v", err)
+	}
+
+	found := false
+	for _, d := range Methods() {
+		found = found || d.FullMethod() == methodTableTestMethod
+	}
+	if !found {
+		t.Error("expected Methods to list the registered method")
+	}
+}
@@ -0,0 +1,170 @@
+package rpcruntime
+
+import "fmt"
+
+// SetProtocolPreference sets the global protocol fallback order of the default
+// Runtime. See Runtime.SetProtocolPreference.
+func SetProtocolPreference(protocols ...Protocol) error {
+	return defaultRuntime.SetProtocolPreference(protocols...)
+}
+
+// SetServiceProtocolPreference sets the protocol fallback order for
+// serviceName in the default Runtime. See Runtime.SetServiceProtocolPreference.
+func SetServiceProtocolPreference(serviceName string, protocols ...Protocol) error {
+	return defaultRuntime.SetServiceProtocolPreference(serviceName, protocols...)
+}
+
+// SetMethodProtocolPreference routes a single method of the default Runtime to
+// protocols. See Runtime.SetMethodProtocolPreference.
+func SetMethodProtocolPreference(fullMethod string, protocols ...Protocol) error {
+	return defaultRuntime.SetMethodProtocolPreference(fullMethod, protocols...)
+}
+
+// SetProtocolPreference sets the order in which multi-protocol adaptors try
+// protocols when ctx carries no explicit protocol, for all services of rt.
+//
+// It overrides the order baked in by the protocol=... plugin option. Only the
+// listed protocols are tried; protocols not generated for a service are
+// skipped. Calling it without protocols restores the generated order.
+// Returns ErrUnknownProtocol for unknown protocols and an error for duplicates.
+func (rt *Runtime) SetProtocolPreference(protocols ...Protocol) error {
+	order, err := validateProtocolOrder(protocols)
+	if err != nil {
+		return err
+	}
+
+	rt.prefMu.Lock()
+	defer rt.prefMu.Unlock()
+	rt.protocolPreference = order
+	return nil
+}
+
+// SetServiceProtocolPreference sets the protocol fallback order for
+// serviceName in rt, taking precedence over SetProtocolPreference.
+// Calling it without protocols removes the per-service order.
+func (rt *Runtime) SetServiceProtocolPreference(serviceName string, protocols ...Protocol) error {
+	if serviceName == "" {
+		return ErrEmptyServiceName
+	}
+	order, err := validateProtocolOrder(protocols)
+	if err != nil {
+		return err
+	}
+
+	rt.prefMu.Lock()
+	defer rt.prefMu.Unlock()
+	if order == nil {
+		delete(rt.servicePreference, serviceName)
+		return nil
+	}
+	if rt.servicePreference == nil {
+		rt.servicePreference = make(map[string][]Protocol)
+	}
+	rt.servicePreference[serviceName] = order
+	return nil
+}
+
+// SetMethodProtocolPreference sets the protocol fallback order for the method
+// identified by fullMethod ("/package.Service/Method") in rt, taking precedence
+// over the service and global order.
+//
+// This routes individual methods to a different protocol handler, e.g. only
+// "/pkg.Svc/Upload" to the connectrpc handler while the rest of the service
+// stays on gRPC. Calling it without protocols removes the route.
+func (rt *Runtime) SetMethodProtocolPreference(fullMethod string, protocols ...Protocol) error {
+	if _, _, ok := SplitFullMethod(fullMethod); !ok {
+		return fmt.Errorf("rpcruntime: invalid full method name %q", fullMethod)
+	}
+	order, err := validateProtocolOrder(protocols)
+	if err != nil {
+		return err
+	}
+
+	rt.prefMu.Lock()
+	defer rt.prefMu.Unlock()
+	if order == nil {
+		delete(rt.methodPreference, fullMethod)
+		return nil
+	}
+	if rt.methodPreference == nil {
+		rt.methodPreference = make(map[string][]Protocol)
+	}
+	rt.methodPreference[fullMethod] = order
+	return nil
+}
+
+// MethodProtocolRoutes returns a copy of the per-method routes configured in rt,
+// keyed by full method name.
+func (rt *Runtime) MethodProtocolRoutes() map[string][]Protocol {
+	rt.prefMu.RLock()
+	defer rt.prefMu.RUnlock()
+
+	routes := make(map[string][]Protocol, len(rt.methodPreference))
+	for fullMethod, order := range rt.methodPreference {
+		routes[fullMethod] = append([]Protocol(nil), order...)
+	}
+	return routes
+}
+
+// ProtocolPreference returns the protocol fallback order configured for
+// serviceName, or its canonical name if it is an alias, in rt, falling back to
+// the global order. ok is false if neither is set.
+func (rt *Runtime) ProtocolPreference(serviceName string) (protocols []Protocol, ok bool) {
+	serviceName = rt.resolveAlias(serviceName)
+
+	rt.prefMu.RLock()
+	defer rt.prefMu.RUnlock()
+
+	if order, ok := rt.servicePreference[serviceName]; ok {
+		return append([]Protocol(nil), order...), true
+	}
+	if rt.protocolPreference != nil {
+		return append([]Protocol(nil), rt.protocolPreference...), true
+	}
+	return nil, false
+}
+
+// ProtocolOrder returns the order in which a generated adaptor tries protocols
+// for a call of fullMethod: the method route, the service order or the global
+// order, whichever is set first, otherwise generated. For a service alias
+// (see RegisterAlias) the routes and order of the canonical service apply.
+// The returned slice must not be modified.
+func (rt *Runtime) ProtocolOrder(fullMethod string, generated ...Protocol) []Protocol {
+	fullMethod = rt.resolveFullMethodAlias(fullMethod)
+	serviceName, _, _ := SplitFullMethod(fullMethod)
+
+	rt.prefMu.RLock()
+	defer rt.prefMu.RUnlock()
+
+	if order, ok := rt.methodPreference[fullMethod]; ok {
+		return order
+	}
+	if order, ok := rt.servicePreference[serviceName]; ok {
+		return order
+	}
+	if rt.protocolPreference != nil {
+		return rt.protocolPreference
+	}
+	return generated
+}
+
+// validateProtocolOrder copies protocols, rejecting unknown and duplicate
+// entries. It returns nil for an empty list.
+func validateProtocolOrder(protocols []Protocol) ([]Protocol, error) {
+	if len(protocols) == 0 {
+		return nil, nil
+	}
+	order := make([]Protocol, 0, len(protocols))
+	for _, p := range protocols {
+		if !validProtocol(p) {
+			return nil, ErrUnknownProtocol
+		}
+		for _, seen := range order {
+			if seen == p {
+				return nil, fmt.Errorf("rpcruntime: duplicate protocol %q in preference", p)
+			}
+		}
+		order = append(order, p)
+	}
+	return order, nil
+}
@@ -0,0 +1,99 @@
+package rpcruntime
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestProtocolPreference(t *testing.T) {
+	rt := NewRuntime()
+	serviceName := "rpc.test.TestService"
+	fullMethod := "/rpc.test.TestService/Ping"
+	generated := []Protocol{ProtocolGrpc, ProtocolConnectRPC}
+
+	if _, ok := rt.ProtocolPreference(serviceName); ok {
+		t.Error("expected no preference on a new runtime")
+	}
+	if got := rt.ProtocolOrder(fullMethod, generated...); len(got) != 2 || got[0] != ProtocolGrpc {
+		t.Errorf("expected generated order, got %v", got)
+	}
+
+	if err := rt.SetProtocolPreference(ProtocolConnectRPC, ProtocolGrpc); err != nil {
+		t.Fatalf("SetProtocolPreference failed: %v", err)
+	}
+	if got := rt.ProtocolOrder(fullMethod, generated...); len(got) != 2 || got[0] != ProtocolConnectRPC {
+		t.Errorf("expected global preference, got %v", got)
+	}
+
+	if err := rt.SetServiceProtocolPreference(serviceName, ProtocolGrpc); err != nil {
+		t.Fatalf("SetServiceProtocolPreference failed: %v", err)
+	}
+	if got, ok := rt.ProtocolPreference(serviceName); !ok || len(got) != 1 || got[0] != ProtocolGrpc {
+		t.Errorf("expected per-service preference, got %v ok=%v", got, ok)
+	}
+	if got := rt.ProtocolOrder("/rpc.test.Other/Ping", generated...); got[0] != ProtocolConnectRPC {
+		t.Errorf("other services must use the global preference, got %v", got)
+	}
+
+	if err := rt.SetServiceProtocolPreference(serviceName); err != nil {
+		t.Fatalf("clearing the service preference failed: %v", err)
+	}
+	if err := rt.SetProtocolPreference(); err != nil {
+		t.Fatalf("clearing the global preference failed: %v", err)
+	}
+	if got := rt.ProtocolOrder(fullMethod, generated...); got[0] != ProtocolGrpc {
+		t.Errorf("expected generated order after clearing, got %v", got)
+	}
+}
+
+func TestProtocolPreferenceValidation(t *testing.T) {
+	rt := NewRuntime()
+
+	if err := rt.SetProtocolPreference("http"); !errors.Is(err, ErrUnknownProtocol) {
+		t.Errorf("expected ErrUnknownProtocol, got %v", err)
+	}
+	if err := rt.SetProtocolPreference(ProtocolGrpc, ProtocolGrpc); err == nil {
+		t.Error("expected an error for duplicate protocols")
+	}
+	if err := rt.SetServiceProtocolPreference("", ProtocolGrpc); !errors.Is(err, ErrEmptyServiceName) {
+		t.Errorf("expected ErrEmptyServiceName, got %v", err)
+	}
+	if _, ok := rt.ProtocolPreference("rpc.test.TestService"); ok {
+		t.Error("invalid preferences must not be stored")
+	}
+}
+
+func TestMethodProtocolRoute(t *testing.T) {
+	rt := NewRuntime()
+	upload := "/rpc.test.TestService/Upload"
+	ping := "/rpc.test.TestService/Ping"
+	generated := []Protocol{ProtocolGrpc, ProtocolConnectRPC}
+
+	if err := rt.SetServiceProtocolPreference("rpc.test.TestService", ProtocolGrpc); err != nil {
+		t.Fatalf("SetServiceProtocolPreference failed: %v", err)
+	}
+	if err := rt.SetMethodProtocolPreference(upload, ProtocolConnectRPC); err != nil {
+		t.Fatalf("SetMethodProtocolPreference failed: %v", err)
+	}
+
+	if got := rt.ProtocolOrder(upload, generated...); len(got) != 1 || got[0] != ProtocolConnectRPC {
+		t.Errorf("expected method route for Upload, got %v", got)
+	}
+	if got := rt.ProtocolOrder(ping, generated...); len(got) != 1 || got[0] != ProtocolGrpc {
+		t.Errorf("expected service order for Ping, got %v", got)
+	}
+	if routes := rt.MethodProtocolRoutes(); len(routes) != 1 || routes[upload][0] != ProtocolConnectRPC {
+		t.Errorf("unexpected routes %v", routes)
+	}
+
+	if err := rt.SetMethodProtocolPreference(upload); err != nil {
+		t.Fatalf("removing the route failed: %v", err)
+	}
+	if got := rt.ProtocolOrder(upload, generated...); got[0] != ProtocolGrpc {
+		t.Errorf("expected service order after removing the route, got %v", got)
+	}
+
+	if err := rt.SetMethodProtocolPreference("Upload", ProtocolGrpc); err == nil {
+		t.Error("expected an error for an invalid full method name")
+	}
+}
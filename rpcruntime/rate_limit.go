@@ -0,0 +1,148 @@
+package rpcruntime
+
+import (
+	"container/list"
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// maxIdleRateBuckets is the maximum number of per-caller buckets of a method.
+// Beyond it the least recently used bucket is dropped and recreated full on
+// the caller's next call, so callers rotating keys cannot grow the limiter.
+const maxIdleRateBuckets = 1024
+
+// RateLimit configures a token bucket for a method.
+//
+// Every call, including stream starts, takes one token. Calls arriving while
+// the bucket is empty are rejected with ErrResourceExhausted.
+type RateLimit struct {
+	// Rate is the number of tokens added per second. Values <= 0 remove the limit.
+	Rate float64
+
+	// Burst is the bucket capacity, i.e. the number of calls admitted at once
+	// after a quiet period. Values < 1 are treated as 1.
+	Burst int
+
+	// CallerKey optionally derives a caller identity from the call context.
+	// Each caller gets its own bucket; calls with an empty key share one
+	// bucket. Nil applies a single bucket to all callers. See
+	// grpcrt.MetadataCallerKey to identify callers by gRPC metadata.
+	CallerKey func(ctx context.Context) string
+}
+
+// tokenBucket is the state of one bucket.
+type tokenBucket struct {
+	tokens float64
+	last   time.Time
+
+	// elem is the bucket's entry in rateLimiter.lru; its Value is the caller.
+	elem *list.Element
+}
+
+// rateLimiter holds the buckets of one method.
+type rateLimiter struct {
+	name      string
+	rate      float64
+	burst     float64
+	callerKey func(ctx context.Context) string
+
+	mu      sync.Mutex
+	buckets map[string]*tokenBucket
+	lru     list.List // callers, most recently used first
+}
+
+// allow takes a token from the bucket for caller, refilling it up to now.
+func (l *rateLimiter) allow(caller string, now time.Time) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	b, ok := l.buckets[caller]
+	if ok {
+		l.lru.MoveToFront(b.elem)
+	} else {
+		if len(l.buckets) >= maxIdleRateBuckets {
+			l.evictLocked()
+		}
+		b = &tokenBucket{tokens: l.burst, last: now, elem: l.lru.PushFront(caller)}
+		l.buckets[caller] = b
+	}
+
+	if elapsed := now.Sub(b.last); elapsed > 0 {
+		b.tokens = min(l.burst, b.tokens+elapsed.Seconds()*l.rate)
+		b.last = now
+	}
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
+
+// evictLocked drops the least recently used bucket.
+// The caller must hold l.mu.
+func (l *rateLimiter) evictLocked() {
+	if elem := l.lru.Back(); elem != nil {
+		delete(l.buckets, l.lru.Remove(elem).(string))
+	}
+}
+
+// SetMethodRateLimit sets the rate limit of fullMethod in the default Runtime.
+// See Runtime.SetMethodRateLimit.
+func SetMethodRateLimit(fullMethod string, limit RateLimit) error {
+	return defaultRuntime.SetMethodRateLimit(fullMethod, limit)
+}
+
+// SetMethodRateLimit sets the token-bucket rate limit of the method identified
+// by fullMethod ("/package.Service/Method", as in the generated
+// <Service>_<Method>_FullMethod constants) in rt.
+//
+// The limit applies to calls through every protocol. Setting a new limit
+// starts with full buckets; a limit with Rate <= 0 removes the limit.
+func (rt *Runtime) SetMethodRateLimit(fullMethod string, limit RateLimit) error {
+	if _, _, ok := SplitFullMethod(fullMethod); !ok {
+		return fmt.Errorf("rpcruntime: invalid full method name %q", fullMethod)
+	}
+
+	rt.limitMu.Lock()
+	defer rt.limitMu.Unlock()
+
+	if limit.Rate <= 0 {
+		delete(rt.rateLimits, fullMethod)
+		return nil
+	}
+	if rt.rateLimits == nil {
+		rt.rateLimits = make(map[string]*rateLimiter)
+	}
+	rt.rateLimits[fullMethod] = &rateLimiter{
+		name:      fullMethod,
+		rate:      limit.Rate,
+		burst:     float64(max(limit.Burst, 1)),
+		callerKey: limit.CallerKey,
+		buckets:   make(map[string]*tokenBucket),
+	}
+	return nil
+}
+
+// checkRateLimit takes a token for a call of fullMethod, if it is rate limited.
+func (rt *Runtime) checkRateLimit(ctx context.Context, fullMethod string) error {
+	rt.limitMu.RLock()
+	l := rt.rateLimits[fullMethod]
+	rt.limitMu.RUnlock()
+	if l == nil {
+		return nil
+	}
+
+	var caller string
+	if l.callerKey != nil {
+		caller = l.callerKey(ctx)
+	}
+	if !l.allow(caller, time.Now()) {
+		if caller != "" {
+			return fmt.Errorf("%w: rate limit exceeded for %s by %q", ErrResourceExhausted, fullMethod, caller)
+		}
+		return fmt.Errorf("%w: rate limit exceeded for %s", ErrResourceExhausted, fullMethod)
+	}
+	return nil
+}
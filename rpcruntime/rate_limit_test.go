@@ -0,0 +1,100 @@
+package rpcruntime
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestRateLimiterRefill(t *testing.T) {
+	l := &rateLimiter{rate: 10, burst: 2, buckets: make(map[string]*tokenBucket)}
+	now := time.Unix(1000, 0)
+
+	if !l.allow("", now) || !l.allow("", now) {
+		t.Fatal("expected burst of 2 to be admitted")
+	}
+	if l.allow("", now) {
+		t.Fatal("expected empty bucket to reject")
+	}
+	if l.allow("", now.Add(50*time.Millisecond)) {
+		t.Error("half a token must not admit a call")
+	}
+	if !l.allow("", now.Add(100*time.Millisecond)) {
+		t.Error("expected one token after 100ms at 10/s")
+	}
+	if !l.allow("", now.Add(time.Hour)) || !l.allow("", now.Add(time.Hour)) || l.allow("", now.Add(time.Hour)) {
+		t.Error("refill must be capped at burst")
+	}
+}
+
+func TestRateLimiterEvictsLeastRecentlyUsedBuckets(t *testing.T) {
+	l := &rateLimiter{rate: 1, burst: 1, buckets: make(map[string]*tokenBucket)}
+	now := time.Unix(1000, 0)
+
+	if !l.allow("first", now) {
+		t.Fatal("expected a fresh bucket for a new caller")
+	}
+	// Callers rotating keys within one refill period must not grow the map.
+	for i := 0; i < 2*maxIdleRateBuckets; i++ {
+		l.allow(fmt.Sprintf("caller-%d", i), now)
+	}
+	if len(l.buckets) > maxIdleRateBuckets || l.lru.Len() != len(l.buckets) {
+		t.Fatalf("expected at most %d buckets, got %d (lru %d)", maxIdleRateBuckets, len(l.buckets), l.lru.Len())
+	}
+	if _, ok := l.buckets["first"]; ok {
+		t.Error("expected the least recently used bucket to be evicted")
+	}
+	if !l.allow("first", now) {
+		t.Error("expected an evicted caller to get a fresh bucket")
+	}
+	if l.allow(fmt.Sprintf("caller-%d", 2*maxIdleRateBuckets-1), now) {
+		t.Error("expected a recently used bucket to be kept")
+	}
+}
+
+func TestMethodRateLimitPerCaller(t *testing.T) {
+	rt := NewRuntime()
+	fullMethod := "/rpc.test.TestService/Ping"
+
+	callerKey := func(ctx context.Context) string { return RequestHeaderFromContext(ctx).Get("X-Plugin-Id") }
+	err := rt.SetMethodRateLimit(fullMethod, RateLimit{Rate: 0.001, Burst: 1, CallerKey: callerKey})
+	if err != nil {
+		t.Fatalf("SetMethodRateLimit failed: %v", err)
+	}
+
+	pluginA := WithRequestHeader(context.Background(), http.Header{"X-Plugin-Id": {"a"}})
+	pluginB := WithRequestHeader(context.Background(), http.Header{"X-Plugin-Id": {"b"}})
+
+	release, err := rt.AcquireCallSlot(pluginA, fullMethod)
+	if err != nil {
+		t.Fatalf("first call failed: %v", err)
+	}
+	release()
+	if _, err := rt.AcquireCallSlot(pluginA, fullMethod); !errors.Is(err, ErrResourceExhausted) {
+		t.Errorf("expected ErrResourceExhausted for caller a, got %v", err)
+	}
+	if release, err := rt.AcquireCallSlot(pluginB, fullMethod); err != nil {
+		t.Errorf("caller b must have its own bucket, got %v", err)
+	} else {
+		release()
+	}
+	if release, err := rt.AcquireCallSlot(pluginA, "/rpc.test.TestService/Other"); err != nil {
+		t.Errorf("other methods must not be limited, got %v", err)
+	} else {
+		release()
+	}
+
+	if err := rt.SetMethodRateLimit(fullMethod, RateLimit{}); err != nil {
+		t.Fatalf("clearing the limit failed: %v", err)
+	}
+	if _, err := rt.AcquireCallSlot(pluginA, fullMethod); err != nil {
+		t.Errorf("expected no limit after clearing, got %v", err)
+	}
+
+	if err := rt.SetMethodRateLimit("Ping", RateLimit{Rate: 1}); err == nil {
+		t.Error("expected an error for an invalid full method name")
+	}
+}
@@ -0,0 +1,165 @@
+package rpcruntime
+
+import (
+	"sort"
+)
+
+// RegistryEventKind describes a change to the handler registry.
+//
+// The numeric values are part of the C ABI (YgrpcRegistryEventKind) and must not change.
+type RegistryEventKind int
+
+const (
+	// RegistryEventRegistered is sent when a handler is registered for an empty slot.
+	RegistryEventRegistered RegistryEventKind = 1
+	// RegistryEventReplaced is sent when a handler replaces an existing one.
+	RegistryEventReplaced RegistryEventKind = 2
+	// RegistryEventUnregistered is sent when a handler is unregistered.
+	RegistryEventUnregistered RegistryEventKind = 3
+)
+
+// String returns a lower-case name for the event kind.
+func (k RegistryEventKind) String() string {
+	switch k {
+	case RegistryEventRegistered:
+		return "registered"
+	case RegistryEventReplaced:
+		return "replaced"
+	case RegistryEventUnregistered:
+		return "unregistered"
+	default:
+		return "unknown"
+	}
+}
+
+// RegistryEvent is delivered to registry watchers.
+type RegistryEvent struct {
+	Kind        RegistryEventKind
+	Protocol    Protocol
+	ServiceName string
+}
+
+// WatchRegistry subscribes fn to handler registry changes of the default
+// Runtime. See Runtime.WatchRegistry.
+func WatchRegistry(fn func(event RegistryEvent)) (cancel func()) {
+	return defaultRuntime.WatchRegistry(fn)
+}
+
+// WatchRegistry subscribes fn to handler registry changes of rt and returns a
+// function that cancels the subscription.
+//
+// fn first receives a RegistryEventRegistered event for every handler that is
+// already registered, sorted by protocol and service name, and then the
+// changes in the order they were applied. Events are delivered one at a time
+// and without any lock held, so fn may register or unregister handlers and
+// watch or cancel watchers; the events of such changes are delivered after fn
+// returns.
+//
+// Delivery is synchronous: the goroutine making a change calls the watchers,
+// unless another goroutine is already delivering events, in which case that
+// goroutine delivers them too. In particular WatchRegistry returns after the
+// initial events have been delivered unless it is called from a watcher or
+// while another goroutine is delivering.
+func (rt *Runtime) WatchRegistry(fn func(event RegistryEvent)) (cancel func()) {
+	if fn == nil {
+		return func() {}
+	}
+
+	rt.watchMu.Lock()
+	rt.nextWatcherID++
+	id := rt.nextWatcherID
+	if rt.watchers == nil {
+		rt.watchers = make(map[uint64]func(RegistryEvent))
+	}
+	rt.watchers[id] = fn
+	for _, key := range rt.registeredKeys() {
+		rt.queueRegistryEventLocked(RegistryEvent{Kind: RegistryEventRegistered, Protocol: key.protocol, ServiceName: key.serviceName}, []uint64{id})
+	}
+	rt.watchMu.Unlock()
+	rt.deliverRegistryEvents()
+
+	return func() {
+		rt.watchMu.Lock()
+		delete(rt.watchers, id)
+		rt.watchMu.Unlock()
+	}
+}
+
+// registeredKeys returns all registered handler slots sorted by protocol and
+// service name.
+func (rt *Runtime) registeredKeys() []handlerKey {
+	rt.handlerMu.RLock()
+	keys := make([]handlerKey, 0, len(rt.handlers))
+	for key := range rt.handlers {
+		keys = append(keys, key)
+	}
+	rt.handlerMu.RUnlock()
+
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].protocol != keys[j].protocol {
+			return keys[i].protocol < keys[j].protocol
+		}
+		return keys[i].serviceName < keys[j].serviceName
+	})
+	return keys
+}
+
+// queuedRegistryEvent is an event waiting to be delivered to the watchers
+// subscribed when it was queued.
+type queuedRegistryEvent struct {
+	event    RegistryEvent
+	watchers []uint64
+}
+
+// queueRegistryEventLocked queues event for the watchers with the given ids,
+// or for all current watchers if ids is nil. The caller must hold rt.watchMu
+// and call deliverRegistryEvents after releasing it.
+func (rt *Runtime) queueRegistryEventLocked(event RegistryEvent, ids []uint64) {
+	if ids == nil {
+		if len(rt.watchers) == 0 {
+			return
+		}
+		ids = make([]uint64, 0, len(rt.watchers))
+		for id := range rt.watchers {
+			ids = append(ids, id)
+		}
+	}
+	rt.watchQueue = append(rt.watchQueue, queuedRegistryEvent{event: event, watchers: ids})
+}
+
+// deliverRegistryEvents delivers queued events in order, unless another
+// goroutine (or a watcher further up the stack) is already doing so.
+// Watchers cancelled since an event was queued do not receive it.
+func (rt *Runtime) deliverRegistryEvents() {
+	rt.watchMu.Lock()
+	if rt.delivering {
+		rt.watchMu.Unlock()
+		return
+	}
+	rt.delivering = true
+	defer func() {
+		rt.delivering = false
+		rt.watchMu.Unlock()
+	}()
+
+	for len(rt.watchQueue) > 0 {
+		queued := rt.watchQueue[0]
+		rt.watchQueue[0] = queuedRegistryEvent{}
+		rt.watchQueue = rt.watchQueue[1:]
+
+		for _, id := range queued.watchers {
+			if fn, ok := rt.watchers[id]; ok {
+				rt.callWatcherLocked(fn, queued.event)
+			}
+		}
+	}
+	rt.watchQueue = nil
+}
+
+// callWatcherLocked calls fn with rt.watchMu released. The caller must hold
+// rt.watchMu; it is held again when callWatcherLocked returns or panics.
+func (rt *Runtime) callWatcherLocked(fn func(RegistryEvent), event RegistryEvent) {
+	rt.watchMu.Unlock()
+	defer rt.watchMu.Lock()
+	fn(event)
+}
@@ -0,0 +1,131 @@
+package rpcruntime
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestWatchRegistry(t *testing.T) {
+	rt := NewRuntime()
+	_, _ = rt.RegisterConnectHandler("rpc.test.B", "b")
+	_, _ = rt.RegisterGrpcHandler("rpc.test.A", "a")
+	_, _ = rt.RegisterConnectHandler("rpc.test.A", "a")
+
+	var events []RegistryEvent
+	cancel := rt.WatchRegistry(func(event RegistryEvent) {
+		events = append(events, event)
+	})
+
+	_, _ = rt.RegisterGrpcHandler("rpc.test.A", "a2")
+	_, _ = rt.RegisterGrpcHandler("rpc.test.C", "c")
+	rt.UnregisterConnectHandler("rpc.test.B")
+	rt.UnregisterConnectHandler("rpc.test.Missing")
+
+	cancel()
+	_, _ = rt.RegisterGrpcHandler("rpc.test.D", "d")
+
+	want := []RegistryEvent{
+		{Kind: RegistryEventRegistered, Protocol: ProtocolConnectRPC, ServiceName: "rpc.test.A"},
+		{Kind: RegistryEventRegistered, Protocol: ProtocolConnectRPC, ServiceName: "rpc.test.B"},
+		{Kind: RegistryEventRegistered, Protocol: ProtocolGrpc, ServiceName: "rpc.test.A"},
+		{Kind: RegistryEventReplaced, Protocol: ProtocolGrpc, ServiceName: "rpc.test.A"},
+		{Kind: RegistryEventRegistered, Protocol: ProtocolGrpc, ServiceName: "rpc.test.C"},
+		{Kind: RegistryEventUnregistered, Protocol: ProtocolConnectRPC, ServiceName: "rpc.test.B"},
+	}
+	if !reflect.DeepEqual(events, want) {
+		t.Fatalf("events = %v\nwant %v", events, want)
+	}
+}
+
+func TestListServicesSorted(t *testing.T) {
+	rt := NewRuntime()
+	for _, name := range []string{"rpc.test.C", "rpc.test.A", "rpc.test.B"} {
+		_, _ = rt.RegisterGrpcHandler(name, name)
+	}
+
+	want := []string{"rpc.test.A", "rpc.test.B", "rpc.test.C"}
+	if got := rt.ListGrpcServices(); !reflect.DeepEqual(got, want) {
+		t.Fatalf("ListGrpcServices = %v, want %v", got, want)
+	}
+}
+
+func TestWatchRegistryReentrant(t *testing.T) {
+	rt := NewRuntime()
+	_, _ = rt.RegisterGrpcHandler("rpc.test.A", "a")
+
+	var events []RegistryEvent
+	var nested []RegistryEvent
+	var cancelNested func()
+	cancel := rt.WatchRegistry(func(event RegistryEvent) {
+		events = append(events, event)
+		switch {
+		case event.ServiceName == "rpc.test.A" && event.Kind == RegistryEventRegistered:
+			// Registering from a watcher must not deadlock.
+			_, _ = rt.RegisterGrpcHandler("rpc.test.B", "b")
+		case event.ServiceName == "rpc.test.B" && cancelNested == nil:
+			cancelNested = rt.WatchRegistry(func(event RegistryEvent) {
+				nested = append(nested, event)
+			})
+			rt.UnregisterGrpcHandler("rpc.test.A")
+		}
+	})
+	defer cancel()
+	defer cancelNested()
+
+	want := []RegistryEvent{
+		{Kind: RegistryEventRegistered, Protocol: ProtocolGrpc, ServiceName: "rpc.test.A"},
+		{Kind: RegistryEventRegistered, Protocol: ProtocolGrpc, ServiceName: "rpc.test.B"},
+		{Kind: RegistryEventUnregistered, Protocol: ProtocolGrpc, ServiceName: "rpc.test.A"},
+	}
+	if !reflect.DeepEqual(events, want) {
+		t.Fatalf("events = %v\nwant %v", events, want)
+	}
+	if !reflect.DeepEqual(nested, want) {
+		t.Fatalf("nested events = %v\nwant %v", nested, want)
+	}
+}
+
+func TestWatchRegistrySlowWatcher(t *testing.T) {
+	rt := NewRuntime()
+	entered := make(chan struct{})
+	unblock := make(chan struct{})
+	var events []RegistryEvent
+	cancel := rt.WatchRegistry(func(event RegistryEvent) {
+		if event.ServiceName == "rpc.test.A" {
+			close(entered)
+			<-unblock
+		}
+		events = append(events, event)
+	})
+	defer cancel()
+
+	done := make(chan struct{})
+	go func() {
+		_, _ = rt.RegisterGrpcHandler("rpc.test.A", "a")
+		close(done)
+	}()
+	<-entered
+
+	// The blocked watcher must not stall registrations from other goroutines.
+	registered := make(chan struct{})
+	go func() {
+		_, _ = rt.RegisterGrpcHandler("rpc.test.B", "b")
+		close(registered)
+	}()
+	select {
+	case <-registered:
+	case <-time.After(time.Second):
+		t.Fatal("RegisterGrpcHandler blocked on a slow watcher")
+	}
+
+	close(unblock)
+	<-done
+	want := []RegistryEvent{
+		{Kind: RegistryEventRegistered, Protocol: ProtocolGrpc, ServiceName: "rpc.test.A"},
+		{Kind: RegistryEventRegistered, Protocol: ProtocolGrpc, ServiceName: "rpc.test.B"},
+	}
+	if !reflect.DeepEqual(events, want) {
+		t.Fatalf("events = %v\nwant %v", events, want)
+	}
+}
@@ -0,0 +1,157 @@
+package rpcruntime
+
+import "sort"
+
+// Remote is a server that calls for a service are forwarded to when no local
+// handler is registered for it. Create one with grpcrt.NewRemote or
+// connectrt.NewRemote, or with NewRemote for another RemoteTransport, and
+// register it with RegisterRemote.
+type Remote struct {
+	transport RemoteTransport
+
+	// unknown marks unknownServiceRemote.
+	unknown bool
+
+	// alias and canonical are set on the copy RemoteFallback returns for a
+	// service alias; calls are forwarded under the canonical service name.
+	alias, canonical string
+}
+
+// NewRemote returns a Remote forwarding calls over transport.
+func NewRemote(transport RemoteTransport) *Remote {
+	return &Remote{transport: transport}
+}
+
+// protocol returns the protocol stream sessions forwarded to r are tagged with.
+func (r *Remote) protocol() Protocol {
+	if r.unknown {
+		return ""
+	}
+	return r.transport.Protocol()
+}
+
+// remoteMethod returns the full method name to call on r for fullMethod.
+func (r *Remote) remoteMethod(fullMethod string) string {
+	if r.alias == "" {
+		return fullMethod
+	}
+	return replaceFullMethodService(fullMethod, r.alias, r.canonical)
+}
+
+// RegisterRemote registers remote as the forwarding target for serviceName in
+// the default Runtime. See Runtime.RegisterRemote.
+func RegisterRemote(serviceName string, remote *Remote) (replaced bool, err error) {
+	return defaultRuntime.RegisterRemote(serviceName, remote)
+}
+
+// RegisterRemote registers remote as the forwarding target for serviceName
+// in rt.
+//
+// When a generated adaptor finds no handler for serviceName under any of its
+// protocols, it forwards the call, streams included, to remote instead of
+// failing with ErrServiceNotRegistered. Locally registered handlers always
+// take precedence, and remotes over the unknown-service handlers (see
+// SetUnknownServiceHandler). Forwarded calls count against the rate limits of rt, but
+// concurrency limits are left to the remote.
+//
+// Returns replaced=true if an existing remote was overwritten.
+// Returns an error if serviceName is empty, ErrNilRemote if remote or its
+// transport is nil, or ErrUnavailable once the Runtime has been shut down.
+func (rt *Runtime) RegisterRemote(serviceName string, remote *Remote) (replaced bool, err error) {
+	if serviceName == "" {
+		return false, ErrEmptyServiceName
+	}
+	if remote == nil || remote.transport == nil {
+		return false, ErrNilRemote
+	}
+
+	rt.handlerMu.Lock()
+	defer rt.handlerMu.Unlock()
+	if rt.shutdown {
+		return false, ErrUnavailable
+	}
+	_, replaced = rt.remotes[serviceName]
+	rt.remotes[serviceName] = remote
+	if replaced {
+		logf(LogLevelWarn, "rpcruntime: replaced remote for %s", serviceName)
+	}
+	return replaced, nil
+}
+
+// UnregisterRemote removes the forwarding target for serviceName from the
+// default Runtime.
+func UnregisterRemote(serviceName string) (removed bool) {
+	return defaultRuntime.UnregisterRemote(serviceName)
+}
+
+// UnregisterRemote removes the forwarding target for serviceName from rt.
+// Calls already forwarded are not affected.
+func (rt *Runtime) UnregisterRemote(serviceName string) (removed bool) {
+	rt.handlerMu.Lock()
+	defer rt.handlerMu.Unlock()
+
+	_, removed = rt.remotes[serviceName]
+	delete(rt.remotes, serviceName)
+	return removed
+}
+
+// LookupRemote looks up the forwarding target for serviceName in the default
+// Runtime.
+func LookupRemote(serviceName string) (remote *Remote, ok bool) {
+	return defaultRuntime.LookupRemote(serviceName)
+}
+
+// LookupRemote looks up the forwarding target for serviceName in rt.
+func (rt *Runtime) LookupRemote(serviceName string) (remote *Remote, ok bool) {
+	rt.handlerMu.RLock()
+	defer rt.handlerMu.RUnlock()
+
+	remote, ok = rt.remotes[serviceName]
+	return remote, ok
+}
+
+// ListRemoteServices returns the service names with a forwarding target in
+// the default Runtime, sorted.
+func ListRemoteServices() []string {
+	return defaultRuntime.ListRemoteServices()
+}
+
+// ListRemoteServices returns the service names with a forwarding target in
+// rt, sorted.
+func (rt *Runtime) ListRemoteServices() []string {
+	rt.handlerMu.RLock()
+	defer rt.handlerMu.RUnlock()
+
+	services := make([]string, 0, len(rt.remotes))
+	for serviceName := range rt.remotes {
+		services = append(services, serviceName)
+	}
+	sort.Strings(services)
+	return services
+}
+
+// RemoteFallback reports whether a generated adaptor whose handler lookup for
+// serviceName failed with err should forward the call, and to which remote:
+// the one registered for serviceName, the one registered for its canonical
+// name if serviceName is an alias (see RegisterAlias) or, failing that, one
+// standing for the unknown-service handlers of rt. Calls forwarded through an
+// alias are sent under the canonical service name.
+func (rt *Runtime) RemoteFallback(serviceName string, err error) (*Remote, bool) {
+	if err != ErrServiceNotRegistered {
+		return nil, false
+	}
+	if remote, ok := rt.LookupRemote(serviceName); ok {
+		return remote, true
+	}
+	if canonical := rt.resolveAlias(serviceName); canonical != serviceName {
+		if remote, ok := rt.LookupRemote(canonical); ok {
+			aliased := *remote
+			aliased.alias, aliased.canonical = serviceName, canonical
+			return &aliased, true
+		}
+	}
+	if unary, stream := rt.unknownHandlers(); unary != nil || stream != nil {
+		return unknownServiceRemote, true
+	}
+	return nil, false
+}
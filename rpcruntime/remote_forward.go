@@ -0,0 +1,237 @@
+package rpcruntime
+
+import (
+	"context"
+	"strings"
+)
+
+// RemoteTransport carries the calls forwarded to a Remote. Package grpcrt
+// implements it over a gRPC client connection and package connectrt with
+// connect-go clients, so that rpcruntime itself depends on neither.
+//
+// Messages are passed as any and are of the generated message types of the
+// method; resp is a new, empty message to receive the response into. The
+// request headers to send are those of ctx (see RequestHeaderFromContext),
+// with the ones set by the RPC protocols themselves already removed. The
+// response headers and trailers go to RecordReceivedResponseMetadata.
+type RemoteTransport interface {
+	// Protocol returns the protocol stream sessions forwarded over the
+	// transport are tagged with.
+	Protocol() Protocol
+
+	// Unary makes a unary call of fullMethod.
+	Unary(ctx context.Context, fullMethod string, req, resp any) error
+
+	// ClientStream makes a client-streaming call of fullMethod, sending the
+	// messages read from stream.
+	ClientStream(ctx context.Context, fullMethod string, stream RemoteStream, resp any) error
+
+	// ServerStream makes a server-streaming call of fullMethod, delivering its
+	// responses to stream.
+	ServerStream(ctx context.Context, fullMethod string, req any, stream RemoteStream) error
+
+	// BidiStream makes a bidi-streaming call of fullMethod, sending the
+	// messages read from stream and delivering its responses to it.
+	BidiStream(ctx context.Context, fullMethod string, stream RemoteStream) error
+}
+
+// RemoteStream is the caller's side of a streaming call forwarded to a
+// RemoteTransport.
+type RemoteStream interface {
+	// Recv returns the next request message sent by the caller, or io.EOF
+	// once the caller has finished sending.
+	Recv() (any, error)
+
+	// NewResponse returns a new, empty response message.
+	NewResponse() any
+
+	// Send delivers a response message to the caller. It returns
+	// context.Canceled once the caller stops receiving; the transport should
+	// then cancel the call and return nil.
+	Send(resp any) error
+}
+
+// ForwardUnary forwards a unary call of fullMethod to remote. Generated
+// adaptors call it when RemoteFallback selects a remote.
+//
+// The request headers of ctx are sent along and the response headers and
+// trailers are recorded in its ResponseMetadata. Errors are returned as
+// received from the remote, e.g. as gRPC status errors or *connect.Error.
+// For the remote standing for the unknown-service handlers, messages are
+// passed to them in wire format.
+func ForwardUnary[Req, Res any](ctx context.Context, remote *Remote, fullMethod string, req *Req) (*Res, error) {
+	fullMethod = remote.remoteMethod(fullMethod)
+	if remote.unknown {
+		return forwardUnknownUnary[Req, Res](ctx, fullMethod, req)
+	}
+	resp := new(Res)
+	if err := remote.transport.Unary(remoteContext(ctx), fullMethod, req, resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// ForwardClientStream starts a client-streaming call of fullMethod on remote
+// and returns its stream handle, to be used with the generated Send and
+// Finish adaptor functions. See ForwardUnary for metadata and errors.
+func ForwardClientStream[Req, Res any](ctx context.Context, remote *Remote, fullMethod string) (uint64, error) {
+	fullMethod = remote.remoteMethod(fullMethod)
+	if remote.unknown {
+		if _, err := unknownStreamHandlerFor(ctx); err != nil {
+			return 0, err
+		}
+	}
+	handle, childCtx, _ := AllocateStreamHandle(ctx, remote.protocol())
+	session := getStreamSessionInternal(handle)
+	if session == nil {
+		return 0, ErrInvalidStreamHandle
+	}
+
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				CompleteClientStream(handle, nil, RecoverPanic(r))
+			}
+		}()
+		var resp *Res
+		var err error
+		if remote.unknown {
+			resp, err = forwardUnknownClientStream[Req, Res](childCtx, session, fullMethod)
+		} else {
+			resp = new(Res)
+			err = remote.transport.ClientStream(remoteContext(childCtx), fullMethod, newRemoteStream[Req, Res](session), resp)
+		}
+		if err != nil {
+			resp = nil
+		}
+		CompleteClientStream(handle, resp, err)
+	}()
+	return uint64(handle), nil
+}
+
+// ForwardServerStream forwards a server-streaming call of fullMethod to
+// remote, delivering its responses to onRead. onDone is called exactly once
+// when the stream ends or fails. See ForwardUnary for metadata and errors.
+func ForwardServerStream[Req, Res any](
+	ctx context.Context,
+	remote *Remote,
+	fullMethod string,
+	req *Req,
+	onRead func(*Res) bool,
+	onDone func(error),
+) error {
+	fullMethod = remote.remoteMethod(fullMethod)
+	if remote.unknown {
+		if _, err := unknownStreamHandlerFor(ctx); err != nil {
+			onDone(err)
+			return err
+		}
+	}
+	handle, childCtx, _ := AllocateStreamHandle(ctx, remote.protocol())
+	session := getStreamSessionInternal(handle)
+	if session == nil {
+		onDone(ErrInvalidStreamHandle)
+		return ErrInvalidStreamHandle
+	}
+	session.SetCallbacks(func(resp any) bool { return onRead(resp.(*Res)) }, onDone)
+
+	var err error
+	if remote.unknown {
+		err = forwardUnknownServerStream[Req, Res](childCtx, session, fullMethod, req)
+	} else {
+		err = remote.transport.ServerStream(remoteContext(childCtx), fullMethod, req, newRemoteStream[Req, Res](session))
+	}
+	FinishStreamHandle(handle)
+	onDone(err)
+	return err
+}
+
+// ForwardBidiStream starts a bidi-streaming call of fullMethod on remote and
+// returns its stream handle, to be used with the generated Send and CloseSend
+// adaptor functions. Responses are delivered to onRead and onDone is called
+// exactly once when the stream ends or fails. See ForwardUnary for metadata
+// and errors.
+func ForwardBidiStream[Req, Res any](
+	ctx context.Context,
+	remote *Remote,
+	fullMethod string,
+	onRead func(*Res) bool,
+	onDone func(error),
+) (uint64, error) {
+	fullMethod = remote.remoteMethod(fullMethod)
+	if remote.unknown {
+		if _, err := unknownStreamHandlerFor(ctx); err != nil {
+			return 0, err
+		}
+	}
+	handle, childCtx, _ := AllocateStreamHandle(ctx, remote.protocol())
+	session := getStreamSessionInternal(handle)
+	if session == nil {
+		return 0, ErrInvalidStreamHandle
+	}
+	session.SetCallbacks(func(resp any) bool { return onRead(resp.(*Res)) }, onDone)
+
+	go func() {
+		var err error
+		defer func() {
+			if r := recover(); r != nil {
+				err = RecoverPanic(r)
+			}
+			if cb := session.OnDone(); cb != nil {
+				cb(err)
+			}
+			FinishStreamHandle(handle)
+		}()
+		if remote.unknown {
+			err = forwardUnknownBidiStream[Req, Res](childCtx, session, fullMethod)
+		} else {
+			err = remote.transport.BidiStream(remoteContext(childCtx), fullMethod, newRemoteStream[Req, Res](session))
+		}
+	}()
+	return uint64(handle), nil
+}
+
+// remoteStream implements RemoteStream on top of a stream session.
+type remoteStream[Req, Res any] struct {
+	stream *goStream[Req, Res]
+}
+
+func newRemoteStream[Req, Res any](session StreamSession) RemoteStream {
+	return remoteStream[Req, Res]{stream: &goStream[Req, Res]{session: session}}
+}
+
+func (s remoteStream[Req, Res]) Recv() (any, error) {
+	req, err := s.stream.Recv()
+	if err != nil {
+		return nil, err
+	}
+	return req, nil
+}
+
+func (s remoteStream[Req, Res]) NewResponse() any {
+	return new(Res)
+}
+
+func (s remoteStream[Req, Res]) Send(resp any) error {
+	r, ok := resp.(*Res)
+	if !ok {
+		return ErrStreamMessageTypeMismatch
+	}
+	return s.stream.Send(r)
+}
+
+// remoteContext returns ctx carrying the request headers to send to a remote:
+// those of ctx minus the ones set by the RPC protocols themselves.
+func remoteContext(ctx context.Context) context.Context {
+	header := stripProtocolHeaders(RequestHeaderFromContext(ctx))
+	for k := range header {
+		switch {
+		case strings.HasPrefix(k, "Grpc-"):
+		case k == "Te", k == "User-Agent", k == "Content-Length":
+		default:
+			continue
+		}
+		delete(header, k)
+	}
+	return WithRequestHeader(ctx, header)
+}
@@ -0,0 +1,110 @@
+package rpcruntime
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+func TestRegisterRemote(t *testing.T) {
+	rt := NewRuntime()
+	remote := NewRemote(&echoTransport{})
+
+	if _, err := rt.RegisterRemote("", remote); err != ErrEmptyServiceName {
+		t.Errorf("expected ErrEmptyServiceName, got %v", err)
+	}
+	if _, err := rt.RegisterRemote("svc", nil); err != ErrNilRemote {
+		t.Errorf("expected ErrNilRemote, got %v", err)
+	}
+
+	replaced, err := rt.RegisterRemote("svc", remote)
+	if err != nil || replaced {
+		t.Fatalf("RegisterRemote = %v, %v", replaced, err)
+	}
+	if replaced, _ := rt.RegisterRemote("svc", remote); !replaced {
+		t.Error("expected second registration to replace the first")
+	}
+	if got, ok := rt.LookupRemote("svc"); !ok || got != remote {
+		t.Errorf("LookupRemote = %v, %v", got, ok)
+	}
+	if got := rt.ListRemoteServices(); len(got) != 1 || got[0] != "svc" {
+		t.Errorf("ListRemoteServices = %v", got)
+	}
+
+	if _, ok := rt.RemoteFallback("svc", ErrHandlerTypeMismatch); ok {
+		t.Error("expected no fallback for errors other than ErrServiceNotRegistered")
+	}
+	if got, ok := rt.RemoteFallback("svc", ErrServiceNotRegistered); !ok || got != remote {
+		t.Errorf("RemoteFallback = %v, %v", got, ok)
+	}
+
+	if !rt.UnregisterRemote("svc") {
+		t.Error("expected UnregisterRemote to report removal")
+	}
+	if _, ok := rt.RemoteFallback("svc", ErrServiceNotRegistered); ok {
+		t.Error("expected no fallback after UnregisterRemote")
+	}
+
+	if err := rt.Shutdown(context.Background()); err != nil {
+		t.Fatalf("Shutdown failed: %v", err)
+	}
+	if _, err := rt.RegisterRemote("svc", remote); err != ErrUnavailable {
+		t.Errorf("expected ErrUnavailable after Shutdown, got %v", err)
+	}
+}
+
+// echoTransport is a RemoteTransport answering unary calls with the request
+// value suffixed by the X-Test request header. It remembers the method of the
+// last call.
+type echoTransport struct {
+	lastMethod string
+}
+
+func (*echoTransport) Protocol() Protocol { return ProtocolGrpc }
+
+func (tr *echoTransport) Unary(ctx context.Context, fullMethod string, req, resp any) error {
+	tr.lastMethod = fullMethod
+	header := RequestHeaderFromContext(ctx)
+	if header.Get("Content-Type") != "" {
+		return errors.New("protocol headers were not stripped")
+	}
+	value := req.(*wrapperspb.StringValue).GetValue() + ":" + header.Get("X-Test")
+	resp.(*wrapperspb.StringValue).Value = value
+	RecordReceivedResponseMetadata(ctx, nil, http.Header{"X-Done": {"yes"}})
+	return nil
+}
+
+func (*echoTransport) ClientStream(context.Context, string, RemoteStream, any) error {
+	return errors.ErrUnsupported
+}
+
+func (*echoTransport) ServerStream(context.Context, string, any, RemoteStream) error {
+	return errors.ErrUnsupported
+}
+
+func (*echoTransport) BidiStream(context.Context, string, RemoteStream) error {
+	return errors.ErrUnsupported
+}
+
+func TestForwardUnary(t *testing.T) {
+	ctx := WithRequestHeader(context.Background(), http.Header{
+		"X-Test":       {"v1"},
+		"Content-Type": {"application/grpc"},
+	})
+	ctx, md := WithResponseMetadata(ctx)
+
+	resp, err := ForwardUnary[wrapperspb.StringValue, wrapperspb.StringValue](
+		ctx, NewRemote(&echoTransport{}), "/rpc.test.TestService/Echo", wrapperspb.String("hi"))
+	if err != nil {
+		t.Fatalf("ForwardUnary failed: %v", err)
+	}
+	if resp.GetValue() != "hi:v1" {
+		t.Errorf("unexpected reply %q", resp.GetValue())
+	}
+	if got := md.Trailer.Get("X-Done"); got != "yes" {
+		t.Errorf("expected trailer X-Done=yes, got %q", got)
+	}
+}
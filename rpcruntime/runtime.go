@@ -0,0 +1,121 @@
+package rpcruntime
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+)
+
+// Runtime owns an isolated set of registered handlers, in-flight stream
+// sessions, stored errors and the default protocol.
+//
+// The package-level functions operate on the default Runtime returned by
+// Default. Additional runtimes created with NewRuntime are selected per call
+// by attaching them to the context with WithRuntime; generated adaptors look
+// up handlers and allocate streams in RuntimeFromContext(ctx).
+//
+// Stream handles and error ids are allocated from process-wide counters, so
+// they never collide between runtimes.
+//
+// The C ABI selects a Runtime by its RuntimeHandle (see Runtime.Handle); the
+// zero handle selects the default Runtime.
+type Runtime struct {
+	handlerMu sync.RWMutex
+	handlers  map[handlerKey]*handlerEntry
+	remotes   map[string]*Remote
+	aliases   map[string]string
+	shutdown  bool
+
+	// baseCtx is the root of every call context of rt (see CallContext); it
+	// is cancelled by Shutdown.
+	baseCtx    context.Context
+	cancelBase context.CancelFunc
+
+	// initMu serializes initializer registration with Init.
+	// initRequired and initialized are guarded by handlerMu.
+	initMu       sync.Mutex
+	initializers []InitFunc
+	initRequired bool
+	initialized  bool
+
+	// watchMu serializes registry changes with queueing their events.
+	// Watchers are called without it held (see deliverRegistryEvents).
+	watchMu       sync.Mutex
+	watchers      map[uint64]func(RegistryEvent)
+	nextWatcherID uint64
+	watchQueue    []queuedRegistryEvent
+	delivering    bool
+
+	limitMu    sync.RWMutex
+	limits     map[string]*concurrencyLimiter
+	rateLimits map[string]*rateLimiter
+
+	streamMu sync.RWMutex
+	streams  map[StreamHandle]*streamSession
+
+	errorMu          sync.Mutex
+	errors           map[uint64]errorRecord
+	startCleanerOnce sync.Once
+	stopCleaner      chan struct{}
+
+	defaultProtocol atomic.Value
+
+	prefMu             sync.RWMutex
+	protocolPreference []Protocol
+	servicePreference  map[string][]Protocol
+	methodPreference   map[string][]Protocol
+
+	unimplementedFallback atomic.Bool
+
+	connectStreamBridge atomic.Int32
+
+	unknownMu      sync.RWMutex
+	unknownService UnknownServiceHandler
+	unknownStream  UnknownStreamHandler
+
+	handleOnce sync.Once
+	handle     RuntimeHandle
+}
+
+// defaultRuntime backs the package-level API.
+var defaultRuntime = NewRuntime()
+
+// NewRuntime returns an empty Runtime with no handlers and no default protocol.
+func NewRuntime() *Runtime {
+	rt := &Runtime{
+		handlers: make(map[handlerKey]*handlerEntry),
+		remotes:  make(map[string]*Remote),
+		aliases:  make(map[string]string),
+		streams:  make(map[StreamHandle]*streamSession),
+		errors:   make(map[uint64]errorRecord),
+
+		stopCleaner: make(chan struct{}),
+	}
+	rt.baseCtx, rt.cancelBase = context.WithCancel(context.Background())
+	rt.defaultProtocol.Store(defaultProtocolState{})
+	return rt
+}
+
+// Default returns the process-wide Runtime used by the package-level API and
+// by generated CGO exports called with DefaultRuntimeHandle.
+func Default() *Runtime {
+	return defaultRuntime
+}
+
+// runtimeContextKey is the context key carrying the selected Runtime.
+type runtimeContextKey struct{}
+
+// WithRuntime returns a copy of ctx that makes generated adaptors dispatch
+// through rt instead of the default Runtime.
+func WithRuntime(ctx context.Context, rt *Runtime) context.Context {
+	return context.WithValue(ctx, runtimeContextKey{}, rt)
+}
+
+// RuntimeFromContext returns the Runtime attached to ctx by WithRuntime, or the
+// default Runtime if none is attached.
+func RuntimeFromContext(ctx context.Context) *Runtime {
+	if rt, ok := ctx.Value(runtimeContextKey{}).(*Runtime); ok && rt != nil {
+		return rt
+	}
+	return defaultRuntime
+}
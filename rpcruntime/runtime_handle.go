@@ -0,0 +1,80 @@
+package rpcruntime
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// RuntimeHandle identifies a Runtime across the C ABI, where *Runtime cannot be
+// passed. The zero handle always refers to the default Runtime.
+type RuntimeHandle uint64
+
+// DefaultRuntimeHandle is the handle of the default Runtime.
+const DefaultRuntimeHandle RuntimeHandle = 0
+
+var (
+	// nextRuntimeHandle is never reset, so a released handle is never reused.
+	nextRuntimeHandle atomic.Uint64
+
+	// runtimeHandles maps each live RuntimeHandle to its Runtime.
+	runtimeHandles sync.Map
+)
+
+// Handle returns the handle of rt, allocating it on first use.
+//
+// The handle stays valid until Shutdown of rt is called; afterwards it no
+// longer resolves and calls made through it fail with ErrInvalidRuntimeHandle.
+// The default Runtime always has DefaultRuntimeHandle.
+func (rt *Runtime) Handle() RuntimeHandle {
+	if rt == defaultRuntime {
+		return DefaultRuntimeHandle
+	}
+	rt.handleOnce.Do(func() {
+		rt.handle = RuntimeHandle(nextRuntimeHandle.Add(1))
+		runtimeHandles.Store(rt.handle, rt)
+	})
+	return rt.handle
+}
+
+// RuntimeFromHandle returns the Runtime identified by h. ok is false if h was
+// never allocated or its Runtime has been shut down.
+func RuntimeFromHandle(h RuntimeHandle) (rt *Runtime, ok bool) {
+	if h == DefaultRuntimeHandle {
+		return defaultRuntime, true
+	}
+	v, ok := runtimeHandles.Load(h)
+	if !ok {
+		return nil, false
+	}
+	return v.(*Runtime), true
+}
+
+// releaseHandle stops the handle of rt from resolving. A handle allocated
+// afterwards by Handle never resolves.
+func (rt *Runtime) releaseHandle() {
+	if rt == defaultRuntime {
+		return
+	}
+	rt.handleOnce.Do(func() {
+		rt.handle = RuntimeHandle(nextRuntimeHandle.Add(1))
+	})
+	runtimeHandles.Delete(rt.handle)
+}
+
+// NewRuntimeFrom returns an empty Runtime that runs the initializers registered
+// with base (see OnInit) when it is initialized.
+//
+// Handlers, limits and other settings of base are not copied. Initializers
+// find the Runtime being initialized with RuntimeFromContext, so a host can
+// create several isolated runtimes from the initializers of the default one
+// and initialize each with its own config.
+func NewRuntimeFrom(base *Runtime) *Runtime {
+	base.initMu.Lock()
+	initializers := append([]InitFunc(nil), base.initializers...)
+	base.initMu.Unlock()
+
+	rt := NewRuntime()
+	rt.initializers = initializers
+	rt.initRequired = len(initializers) > 0
+	return rt
+}
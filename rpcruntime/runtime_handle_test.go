@@ -0,0 +1,77 @@
+package rpcruntime
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestRuntimeHandle(t *testing.T) {
+	if h := Default().Handle(); h != DefaultRuntimeHandle {
+		t.Errorf("expected the default Runtime to have handle 0, got %d", h)
+	}
+	if rt, ok := RuntimeFromHandle(DefaultRuntimeHandle); !ok || rt != Default() {
+		t.Error("expected handle 0 to resolve to the default Runtime")
+	}
+
+	rt1, rt2 := NewRuntime(), NewRuntime()
+	h1, h2 := rt1.Handle(), rt2.Handle()
+	if h1 == DefaultRuntimeHandle || h1 == h2 {
+		t.Fatalf("expected distinct non-zero handles, got %d and %d", h1, h2)
+	}
+	if rt1.Handle() != h1 {
+		t.Error("expected Handle to be stable")
+	}
+	if got, ok := RuntimeFromHandle(h1); !ok || got != rt1 {
+		t.Errorf("RuntimeFromHandle(%d) = %p, %v", h1, got, ok)
+	}
+
+	if err := rt1.Shutdown(context.Background()); err != nil {
+		t.Fatalf("Shutdown failed: %v", err)
+	}
+	if _, ok := RuntimeFromHandle(h1); ok {
+		t.Error("expected the handle to be released by Shutdown")
+	}
+	if _, ok := RuntimeFromHandle(h2); !ok {
+		t.Error("expected the other Runtime to keep its handle")
+	}
+	if _, ok := RuntimeFromHandle(RuntimeHandle(1 << 62)); ok {
+		t.Error("expected an unknown handle not to resolve")
+	}
+
+	rt3 := NewRuntime()
+	_ = rt3.Shutdown(context.Background())
+	if _, ok := RuntimeFromHandle(rt3.Handle()); ok || rt3.Handle() == DefaultRuntimeHandle {
+		t.Error("expected a handle allocated after Shutdown never to resolve")
+	}
+}
+
+func TestNewRuntimeFrom(t *testing.T) {
+	base := NewRuntime()
+	serviceName := "rpc.test.TestService"
+	base.OnInit(func(ctx context.Context, config []byte) error {
+		_, err := RuntimeFromContext(ctx).RegisterGrpcHandler(serviceName, string(config))
+		return err
+	})
+
+	rt1, rt2 := NewRuntimeFrom(base), NewRuntimeFrom(base)
+	if _, _, err := rt1.AcquireGrpcHandler(serviceName); !errors.Is(err, ErrNotInitialized) {
+		t.Fatalf("expected ErrNotInitialized before Init, got %v", err)
+	}
+	if err := rt1.Init(context.Background(), []byte("one")); err != nil {
+		t.Fatalf("Init failed: %v", err)
+	}
+	if err := rt2.Init(context.Background(), []byte("two")); err != nil {
+		t.Fatalf("Init failed: %v", err)
+	}
+
+	if h, ok := rt1.LookupGrpcHandler(serviceName); !ok || h != "one" {
+		t.Errorf("rt1 handler = %v, %v", h, ok)
+	}
+	if h, ok := rt2.LookupGrpcHandler(serviceName); !ok || h != "two" {
+		t.Errorf("rt2 handler = %v, %v", h, ok)
+	}
+	if _, ok := base.LookupGrpcHandler(serviceName); ok {
+		t.Error("expected base to stay untouched")
+	}
+}
@@ -0,0 +1,89 @@
+package rpcruntime
+
+import (
+	"context"
+	"fmt"
+)
+
+// InitFunc initializes handlers from the host-supplied configuration payload.
+//
+// config is opaque to rpcruntime; its encoding is agreed between the host and
+// the initializers. Initializers typically register handlers.
+type InitFunc func(ctx context.Context, config []byte) error
+
+// OnInit registers fn to run when the default Runtime is initialized.
+// See Runtime.OnInit.
+func OnInit(fn InitFunc) {
+	defaultRuntime.OnInit(fn)
+}
+
+// OnInit registers fn to run when rt is initialized by Init.
+//
+// Once an initializer is registered, calls dispatched through rt fail with
+// ErrNotInitialized until Init has succeeded. OnInit is usually called from
+// package init functions; initializers registered after Init has succeeded are
+// never run.
+func (rt *Runtime) OnInit(fn InitFunc) {
+	if fn == nil {
+		return
+	}
+
+	rt.initMu.Lock()
+	defer rt.initMu.Unlock()
+	rt.initializers = append(rt.initializers, fn)
+
+	rt.handlerMu.Lock()
+	initialized := rt.initialized
+	if !initialized {
+		rt.initRequired = true
+	}
+	rt.handlerMu.Unlock()
+
+	if initialized {
+		logf(LogLevelWarn, "rpcruntime: initializer registered after Init will not run")
+	}
+}
+
+// Init initializes the default Runtime. See Runtime.Init.
+func Init(ctx context.Context, config []byte) error {
+	return defaultRuntime.Init(ctx, config)
+}
+
+// Init runs the initializers registered with OnInit in registration order,
+// passing each the same config. The ctx passed to them carries rt (see
+// RuntimeFromContext), so initializers shared through NewRuntimeFrom register
+// handlers in the Runtime being initialized.
+//
+// Init stops at the first failing initializer and returns its error; rt then
+// stays uninitialized and Init may be retried. Handlers registered by earlier
+// initializers remain registered.
+// Returns ErrAlreadyInitialized if Init has already succeeded, or
+// ErrUnavailable once rt has been shut down.
+func (rt *Runtime) Init(ctx context.Context, config []byte) error {
+	rt.initMu.Lock()
+	defer rt.initMu.Unlock()
+
+	rt.handlerMu.RLock()
+	shutdown, initialized := rt.shutdown, rt.initialized
+	rt.handlerMu.RUnlock()
+	if shutdown {
+		return ErrUnavailable
+	}
+	if initialized {
+		return ErrAlreadyInitialized
+	}
+
+	for i, fn := range rt.initializers {
+		if err := fn(WithRuntime(ctx, rt), config); err != nil {
+			logf(LogLevelError, "rpcruntime: initializer %d failed: %v", i, err)
+			return fmt.Errorf("rpcruntime: initializer %d: %w", i, err)
+		}
+	}
+
+	rt.handlerMu.Lock()
+	rt.initialized = true
+	rt.handlerMu.Unlock()
+
+	logf(LogLevelInfo, "rpcruntime: initialized with %d initializers", len(rt.initializers))
+	return nil
+}
@@ -0,0 +1,99 @@
+package rpcruntime
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestInitRunsInitializers(t *testing.T) {
+	rt := NewRuntime()
+	serviceName := "rpc.test.TestService"
+
+	var order []string
+	rt.OnInit(func(ctx context.Context, config []byte) error {
+		order = append(order, "first:"+string(config))
+		_, err := rt.RegisterGrpcHandler(serviceName, "handler")
+		return err
+	})
+	rt.OnInit(func(ctx context.Context, config []byte) error {
+		order = append(order, "second:"+string(config))
+		return nil
+	})
+
+	if _, _, err := rt.AcquireGrpcHandler(serviceName); !errors.Is(err, ErrNotInitialized) {
+		t.Fatalf("expected ErrNotInitialized before Init, got %v", err)
+	}
+
+	if err := rt.Init(context.Background(), []byte("cfg")); err != nil {
+		t.Fatalf("Init failed: %v", err)
+	}
+	want := []string{"first:cfg", "second:cfg"}
+	if len(order) != len(want) || order[0] != want[0] || order[1] != want[1] {
+		t.Errorf("expected %v, got %v", want, order)
+	}
+
+	h, release, err := rt.AcquireGrpcHandler(serviceName)
+	if err != nil {
+		t.Fatalf("AcquireGrpcHandler failed: %v", err)
+	}
+	release()
+	if h != "handler" {
+		t.Errorf("unexpected handler %v", h)
+	}
+
+	if err := rt.Init(context.Background(), nil); !errors.Is(err, ErrAlreadyInitialized) {
+		t.Errorf("expected ErrAlreadyInitialized, got %v", err)
+	}
+}
+
+func TestInitFailureCanBeRetried(t *testing.T) {
+	rt := NewRuntime()
+	errBoom := errors.New("boom")
+
+	calls := 0
+	rt.OnInit(func(ctx context.Context, config []byte) error {
+		calls++
+		if calls == 1 {
+			return errBoom
+		}
+		return nil
+	})
+
+	err := rt.Init(context.Background(), nil)
+	if !errors.Is(err, errBoom) {
+		t.Fatalf("expected initializer error, got %v", err)
+	}
+	if _, _, err := rt.AcquireGrpcHandler("rpc.test.TestService"); !errors.Is(err, ErrNotInitialized) {
+		t.Errorf("expected ErrNotInitialized after failed Init, got %v", err)
+	}
+	if !rt.ErrorIs(rt.StoreError(ErrNotInitialized), ErrorKindNotInitialized) {
+		t.Error("expected ErrorKindNotInitialized")
+	}
+
+	if err := rt.Init(context.Background(), nil); err != nil {
+		t.Fatalf("retry failed: %v", err)
+	}
+	if _, _, err := rt.AcquireGrpcHandler("rpc.test.TestService"); !errors.Is(err, ErrServiceNotRegistered) {
+		t.Errorf("expected ErrServiceNotRegistered after Init, got %v", err)
+	}
+}
+
+func TestInitWithoutInitializers(t *testing.T) {
+	rt := NewRuntime()
+	if _, err := rt.RegisterGrpcHandler("rpc.test.TestService", "handler"); err != nil {
+		t.Fatalf("RegisterGrpcHandler failed: %v", err)
+	}
+	if _, release, err := rt.AcquireGrpcHandler("rpc.test.TestService"); err != nil {
+		t.Fatalf("calls must not require Init without initializers: %v", err)
+	} else {
+		release()
+	}
+
+	if err := rt.Shutdown(context.Background()); err != nil {
+		t.Fatalf("Shutdown failed: %v", err)
+	}
+	if err := rt.Init(context.Background(), nil); !errors.Is(err, ErrUnavailable) {
+		t.Errorf("expected ErrUnavailable after Shutdown, got %v", err)
+	}
+}
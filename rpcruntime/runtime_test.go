@@ -0,0 +1,94 @@
+package rpcruntime
+
+import (
+	"context"
+	"testing"
+)
+
+func TestRuntimeIsolation(t *testing.T) {
+	t.Parallel()
+
+	a := NewRuntime()
+	b := NewRuntime()
+	serviceName := "rpc.test.TestService"
+
+	if _, err := a.RegisterGrpcHandler(serviceName, "a"); err != nil {
+		t.Fatalf("RegisterGrpcHandler failed: %v", err)
+	}
+	if _, ok := b.LookupGrpcHandler(serviceName); ok {
+		t.Error("handler registered in a must not be visible in b")
+	}
+	if _, ok := Default().LookupGrpcHandler(serviceName); ok {
+		t.Error("handler registered in a must not be visible in the default runtime")
+	}
+
+	if err := a.SetDefaultProtocol(ProtocolConnectRPC); err != nil {
+		t.Fatalf("SetDefaultProtocol failed: %v", err)
+	}
+	if _, ok := b.DefaultProtocol(); ok {
+		t.Error("default protocol of a must not leak into b")
+	}
+
+	id := a.StoreErrorMsg([]byte("boom"))
+	if _, ok := b.GetErrorMsgBytes(id); ok {
+		t.Error("error stored in a must not be visible in b")
+	}
+	if msg, ok := a.GetErrorMsgBytes(id); !ok || string(msg) != "boom" {
+		t.Errorf("expected stored message, got %q ok=%v", msg, ok)
+	}
+	if other := b.StoreErrorMsg([]byte("other")); other == id {
+		t.Error("error ids must be unique across runtimes")
+	}
+}
+
+func TestRuntimeFromContext(t *testing.T) {
+	t.Parallel()
+
+	if got := RuntimeFromContext(context.Background()); got != Default() {
+		t.Error("expected default runtime for a bare context")
+	}
+
+	rt := NewRuntime()
+	if got := RuntimeFromContext(WithRuntime(context.Background(), rt)); got != rt {
+		t.Error("expected runtime attached with WithRuntime")
+	}
+
+	if err := rt.SetDefaultProtocol(ProtocolGrpc); err != nil {
+		t.Fatalf("SetDefaultProtocol failed: %v", err)
+	}
+	ctx := rt.BackgroundContext()
+	if got := RuntimeFromContext(ctx); got != rt {
+		t.Error("BackgroundContext must carry its runtime")
+	}
+	if p, ok := ProtocolFromContext(ctx); !ok || p != ProtocolGrpc {
+		t.Errorf("expected grpc protocol, got %q ok=%v", p, ok)
+	}
+}
+
+func TestRuntimeStreamRouting(t *testing.T) {
+	t.Parallel()
+
+	rt := NewRuntime()
+	handle, _, _ := AllocateStreamHandle(WithRuntime(context.Background(), rt), ProtocolGrpc)
+
+	rt.streamMu.RLock()
+	_, owned := rt.streams[handle]
+	rt.streamMu.RUnlock()
+	if !owned {
+		t.Fatal("stream must be allocated in the runtime from the context")
+	}
+	if getStreamSessionInternal(handle) == nil {
+		t.Fatal("handle-only lookup must find the session")
+	}
+	if err := SendToStream(handle, "msg"); err != nil {
+		t.Fatalf("SendToStream failed: %v", err)
+	}
+
+	FinishStreamHandle(handle)
+	if getStreamSessionInternal(handle) != nil {
+		t.Error("expected finished session to be gone")
+	}
+	if _, ok := streamOwners.Load(handle); ok {
+		t.Error("expected owner entry to be removed")
+	}
+}
@@ -0,0 +1,62 @@
+package rpcruntime
+
+import (
+	"context"
+	"errors"
+)
+
+// Shutdown gracefully shuts down the default Runtime. See Runtime.Shutdown.
+func Shutdown(ctx context.Context) error {
+	return defaultRuntime.Shutdown(ctx)
+}
+
+// Shutdown gracefully shuts down rt.
+//
+// New calls and registrations are rejected with ErrUnavailable immediately.
+// Every registered handler is then unregistered as if by UnregisterGrpcHandler
+// and Shutdown waits until in-flight unary calls and open stream sessions have
+// finished and the handlers have been closed. If ctx is done first, the
+// contexts of the remaining unary calls and stream sessions, and of
+// BackgroundContext, are cancelled and ctx.Err() is returned.
+// The background error cleaner is stopped; stored errors remain readable.
+//
+// Otherwise the errors returned by the handlers' Close methods are joined and
+// returned. Calling Shutdown again waits for nothing and returns nil.
+// The handle of rt (see Handle) is released.
+func (rt *Runtime) Shutdown(ctx context.Context) error {
+	rt.handlerMu.Lock()
+	already := rt.shutdown
+	rt.shutdown = true
+	rt.handlerMu.Unlock()
+
+	if !already {
+		logf(LogLevelInfo, "rpcruntime: shutting down")
+		close(rt.stopCleaner)
+		rt.releaseHandle()
+	}
+
+	var pending []<-chan error
+	for _, key := range rt.registeredKeys() {
+		if done, removed := rt.removeHandler(key.protocol, key.serviceName); removed {
+			pending = append(pending, done)
+		}
+	}
+
+	var errs []error
+	for _, done := range pending {
+		select {
+		case err := <-done:
+			errs = append(errs, err)
+		case <-ctx.Done():
+			logf(LogLevelWarn, "rpcruntime: shutdown deadline reached, cancelling in-flight calls")
+			rt.cancelBase()
+			rt.clearStreams()
+			return ctx.Err()
+		}
+	}
+
+	// Sessions that never reached a handler hold no in-flight count.
+	rt.cancelBase()
+	rt.clearStreams()
+	return errors.Join(errs...)
+}
@@ -0,0 +1,156 @@
+package rpcruntime
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestShutdownRejectsNewCalls(t *testing.T) {
+	rt := NewRuntime()
+	serviceName := "rpc.test.TestService"
+	h := newLifecycleHandler()
+
+	if _, err := rt.RegisterGrpcHandler(serviceName, h); err != nil {
+		t.Fatalf("RegisterGrpcHandler failed: %v", err)
+	}
+	if err := rt.Shutdown(context.Background()); err != nil {
+		t.Fatalf("Shutdown failed: %v", err)
+	}
+	waitClosed(t, h)
+
+	if _, _, err := rt.AcquireGrpcHandler(serviceName); !errors.Is(err, ErrUnavailable) {
+		t.Errorf("expected ErrUnavailable from acquire, got %v", err)
+	}
+	if _, err := rt.RegisterConnectHandler(serviceName, h); !errors.Is(err, ErrUnavailable) {
+		t.Errorf("expected ErrUnavailable from register, got %v", err)
+	}
+	if got := rt.ListGrpcServices(); len(got) != 0 {
+		t.Errorf("expected no services after shutdown, got %v", got)
+	}
+
+	id := rt.StoreError(ErrUnavailable)
+	if !rt.ErrorIs(id, ErrorKindUnavailable) {
+		t.Error("expected ErrorKindUnavailable")
+	}
+
+	if err := rt.Shutdown(context.Background()); err != nil {
+		t.Errorf("second Shutdown must be a no-op, got %v", err)
+	}
+}
+
+func TestShutdownWaitsForInflight(t *testing.T) {
+	rt := NewRuntime()
+	serviceName := "rpc.test.TestService"
+	h := newLifecycleHandler()
+
+	if _, err := rt.RegisterGrpcHandler(serviceName, h); err != nil {
+		t.Fatalf("RegisterGrpcHandler failed: %v", err)
+	}
+	_, release, err := rt.AcquireGrpcHandler(serviceName)
+	if err != nil {
+		t.Fatalf("AcquireGrpcHandler failed: %v", err)
+	}
+
+	done := make(chan error, 1)
+	go func() { done <- rt.Shutdown(context.Background()) }()
+
+	select {
+	case err := <-done:
+		t.Fatalf("Shutdown returned before the in-flight call finished: %v", err)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	release()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("Shutdown failed: %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for Shutdown")
+	}
+	waitClosed(t, h)
+}
+
+func TestShutdownCancelsStreamsOnDeadline(t *testing.T) {
+	rt := NewRuntime()
+	serviceName := "rpc.test.TestService"
+
+	if _, err := rt.RegisterGrpcHandler(serviceName, newLifecycleHandler()); err != nil {
+		t.Fatalf("RegisterGrpcHandler failed: %v", err)
+	}
+	_, release, err := rt.AcquireGrpcHandler(serviceName)
+	if err != nil {
+		t.Fatalf("AcquireGrpcHandler failed: %v", err)
+	}
+	defer release()
+
+	handle, streamCtx, _ := AllocateStreamHandle(WithRuntime(context.Background(), rt), ProtocolGrpc)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := rt.Shutdown(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected DeadlineExceeded, got %v", err)
+	}
+
+	select {
+	case <-streamCtx.Done():
+	default:
+		t.Error("open stream must be cancelled after the deadline")
+	}
+	if getStreamSessionInternal(handle) != nil {
+		t.Error("stream session must be removed after shutdown")
+	}
+}
+
+func TestShutdownCancelsUnaryCallsOnDeadline(t *testing.T) {
+	rt := NewRuntime()
+	serviceName := "rpc.test.TestService"
+
+	if _, err := rt.RegisterGrpcHandler(serviceName, newLifecycleHandler()); err != nil {
+		t.Fatalf("RegisterGrpcHandler failed: %v", err)
+	}
+	_, release, err := rt.AcquireGrpcHandler(serviceName)
+	if err != nil {
+		t.Fatalf("AcquireGrpcHandler failed: %v", err)
+	}
+
+	// Mirror a generated unary adaptor whose handler blocks on ctx.Done.
+	callCtx, cancelCall := CallContext(WithRuntime(context.Background(), rt))
+	unblocked := make(chan struct{})
+	go func() {
+		defer release()
+		defer cancelCall()
+		<-callCtx.Done()
+		close(unblocked)
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := rt.Shutdown(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected DeadlineExceeded, got %v", err)
+	}
+
+	select {
+	case <-unblocked:
+	case <-time.After(time.Second):
+		t.Fatal("unary handler must be cancelled after the deadline")
+	}
+	if rt.BackgroundContext().Err() == nil {
+		t.Error("BackgroundContext must be cancelled after shutdown")
+	}
+}
+
+func TestShutdownCancelsBackgroundContextAfterDrain(t *testing.T) {
+	rt := NewRuntime()
+	ctx := rt.BackgroundContext()
+
+	if err := rt.Shutdown(context.Background()); err != nil {
+		t.Fatalf("Shutdown failed: %v", err)
+	}
+	if ctx.Err() == nil {
+		t.Error("BackgroundContext must be cancelled after a clean shutdown")
+	}
+}
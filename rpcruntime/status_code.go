@@ -0,0 +1,46 @@
+package rpcruntime
+
+import (
+	"context"
+	"errors"
+)
+
+// Status codes shared by gRPC (codes.Code) and Connect (connect.Code).
+const (
+	statusCanceled           = 1
+	statusUnknown            = 2
+	statusInvalidArgument    = 3
+	statusDeadlineExceeded   = 4
+	statusResourceExhausted  = 8
+	statusFailedPrecondition = 9
+	statusUnimplemented      = 12
+	statusUnavailable        = 14
+)
+
+// StatusCode returns the status code a remote caller should see for err, an
+// error that is neither a gRPC status nor a connect error: the nearest code
+// for context and rpcruntime errors, e.g. Unimplemented for
+// ErrServiceNotRegistered, and Unknown otherwise.
+//
+// gRPC and Connect number their codes alike, so the result converts to both
+// codes.Code and connect.Code.
+func StatusCode(err error) uint32 {
+	switch {
+	case errors.Is(err, context.Canceled):
+		return statusCanceled
+	case errors.Is(err, context.DeadlineExceeded):
+		return statusDeadlineExceeded
+	case errors.Is(err, ErrServiceNotRegistered), errors.Is(err, ErrHandlerTypeMismatch):
+		return statusUnimplemented
+	case errors.Is(err, ErrResourceExhausted):
+		return statusResourceExhausted
+	case errors.Is(err, ErrUnavailable):
+		return statusUnavailable
+	case errors.Is(err, ErrNotInitialized):
+		return statusFailedPrecondition
+	case errors.Is(err, ErrStreamMessageTypeMismatch):
+		return statusInvalidArgument
+	default:
+		return statusUnknown
+	}
+}
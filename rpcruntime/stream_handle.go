@@ -56,15 +56,20 @@ type streamResult struct {
 }
 
 var (
-	streamMu       sync.RWMutex
-	streamRegistry = make(map[StreamHandle]*streamSession)
-	nextStreamID   atomic.Uint64
+	// nextStreamID is shared by all runtimes so handles are unique process-wide.
+	nextStreamID atomic.Uint64
+
+	// streamOwners maps each live StreamHandle to the Runtime holding its session,
+	// so handle-only calls coming from C can be routed.
+	streamOwners sync.Map
 )
 
-// allocateStreamHandle creates a new stream session and returns its handle.
+// AllocateStreamHandle creates a new stream session in RuntimeFromContext(ctx)
+// and returns its handle.
 func AllocateStreamHandle(ctx context.Context, protocol Protocol) (StreamHandle, context.Context, context.CancelFunc) {
+	rt := RuntimeFromContext(ctx)
 	id := StreamHandle(nextStreamID.Add(1))
-	childCtx, cancel := context.WithCancel(ctx)
+	childCtx, cancel := CallContext(ctx)
 
 	session := &streamSession{
 		ctx:      childCtx,
@@ -75,13 +80,23 @@ func AllocateStreamHandle(ctx context.Context, protocol Protocol) (StreamHandle,
 		respCh:   make(chan streamResult, 1),
 	}
 
-	streamMu.Lock()
-	streamRegistry[id] = session
-	streamMu.Unlock()
+	rt.streamMu.Lock()
+	rt.streams[id] = session
+	rt.streamMu.Unlock()
+	streamOwners.Store(id, rt)
 
 	return id, childCtx, cancel
 }
 
+// streamRuntime returns the Runtime owning handle, or the default Runtime if
+// the handle is unknown.
+func streamRuntime(handle StreamHandle) *Runtime {
+	if rt, ok := streamOwners.Load(handle); ok {
+		return rt.(*Runtime)
+	}
+	return defaultRuntime
+}
+
 // GetStreamSession retrieves a stream session by handle.
 // Returns nil if not found or already finished.
 func GetStreamSession(handle StreamHandle) StreamSession {
@@ -90,10 +105,11 @@ func GetStreamSession(handle StreamHandle) StreamSession {
 
 // getStreamSessionInternal returns the concrete session for internal use.
 func getStreamSessionInternal(handle StreamHandle) *streamSession {
-	streamMu.RLock()
-	defer streamMu.RUnlock()
+	rt := streamRuntime(handle)
+	rt.streamMu.RLock()
+	defer rt.streamMu.RUnlock()
 
-	session, ok := streamRegistry[handle]
+	session, ok := rt.streams[handle]
 	if !ok || session.finished {
 		return nil
 	}
@@ -102,14 +118,20 @@ func getStreamSessionInternal(handle StreamHandle) *streamSession {
 
 // FinishStreamHandle marks a stream as finished and removes it from registry.
 func FinishStreamHandle(handle StreamHandle) {
-	streamMu.Lock()
-	defer streamMu.Unlock()
-
-	if session, ok := streamRegistry[handle]; ok {
+	rt := streamRuntime(handle)
+	rt.streamMu.Lock()
+	session, ok := rt.streams[handle]
+	if ok {
 		session.finished = true
 		session.cancel()
 		session.closeSendLocked()
-		delete(streamRegistry, handle)
+		delete(rt.streams, handle)
+		streamOwners.Delete(handle)
+	}
+	rt.streamMu.Unlock()
+
+	if ok {
+		logf(LogLevelDebug, "rpcruntime: finished %s stream %d", session.protocol, handle)
 	}
 }
 
@@ -138,10 +160,11 @@ func (s *streamSession) closeSendLocked() {
 
 // CloseSendCh safely closes the send channel (called from bidi CloseSend).
 func CloseSendCh(handle StreamHandle) error {
-	streamMu.Lock()
-	defer streamMu.Unlock()
+	rt := streamRuntime(handle)
+	rt.streamMu.Lock()
+	defer rt.streamMu.Unlock()
 
-	session, ok := streamRegistry[handle]
+	session, ok := rt.streams[handle]
 	if !ok || session.finished {
 		return ErrInvalidStreamHandle
 	}
@@ -202,6 +225,7 @@ func FinishClientStream(handle StreamHandle) (any, error) {
 func CompleteClientStream(handle StreamHandle, resp any, err error) {
 	session := getStreamSessionInternal(handle)
 	if session == nil {
+		logf(LogLevelWarn, "rpcruntime: dropped client-stream result for finished stream %d (err=%v)", handle, err)
 		return
 	}
 
@@ -209,21 +233,28 @@ func CompleteClientStream(handle StreamHandle, resp any, err error) {
 	case session.respCh <- streamResult{resp: resp, err: err}:
 	default:
 		// Response channel full or closed, ignore.
+		logf(LogLevelWarn, "rpcruntime: dropped duplicate client-stream result for stream %d (err=%v)", handle, err)
 	}
 }
 
-// clearStreamRegistry clears all stream sessions.
+// clearStreamRegistry clears all stream sessions of the default Runtime.
 // This is intended for testing only.
 func clearStreamRegistry() {
-	streamMu.Lock()
-	defer streamMu.Unlock()
+	defaultRuntime.clearStreams()
+}
+
+// clearStreams cancels and removes all stream sessions of rt.
+func (rt *Runtime) clearStreams() {
+	rt.streamMu.Lock()
+	defer rt.streamMu.Unlock()
 
-	for id, session := range streamRegistry {
+	for id, session := range rt.streams {
 		session.sendMu.Lock()
 		session.closeSendLocked()
 		session.sendMu.Unlock()
 		session.cancel()
-		delete(streamRegistry, id)
+		delete(rt.streams, id)
+		streamOwners.Delete(id)
 	}
 }
 
@@ -233,6 +264,7 @@ func RecoverPanic(r any) error {
 	if r == nil {
 		return nil
 	}
+	logf(LogLevelError, "rpcruntime: recovered panic: %v", r)
 	if err, ok := r.(error); ok {
 		return fmt.Errorf("panic: %w", err)
 	}
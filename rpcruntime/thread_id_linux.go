@@ -0,0 +1,7 @@
+package rpcruntime
+
+import "syscall"
+
+func currentThreadID() uint64 {
+	return uint64(syscall.Gettid())
+}
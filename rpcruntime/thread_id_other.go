@@ -0,0 +1,9 @@
+//go:build !linux && !windows && !darwin
+
+package rpcruntime
+
+// currentThreadID has no portable implementation on this platform, so all
+// threads share a single last-error slot.
+func currentThreadID() uint64 {
+	return 0
+}
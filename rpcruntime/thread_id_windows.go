@@ -0,0 +1,10 @@
+package rpcruntime
+
+import "syscall"
+
+var procGetCurrentThreadId = syscall.NewLazyDLL("kernel32.dll").NewProc("GetCurrentThreadId")
+
+func currentThreadID() uint64 {
+	id, _, _ := procGetCurrentThreadId.Call()
+	return uint64(id)
+}
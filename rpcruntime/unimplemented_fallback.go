@@ -0,0 +1,116 @@
+package rpcruntime
+
+import (
+	"context"
+	"reflect"
+)
+
+// twirpUnimplemented is the Twirp error code of unimplemented methods
+// (twirp.Unimplemented).
+const twirpUnimplemented = "unimplemented"
+
+// IsUnimplemented reports whether err is a gRPC status or connect error with
+// code Unimplemented, as returned by the embedded UnimplementedXServer types,
+// or a Twirp error with code "unimplemented".
+//
+// The errors are recognized by their shape, so neither gRPC, connect-go nor
+// Twirp has to be linked in: a Code method returning a string (twirp.Error) or
+// an unsigned integer (*connect.Error), or a GRPCStatus method returning a
+// value with such a Code method (gRPC status errors).
+func IsUnimplemented(err error) bool {
+	unimplemented, _ := findInErrorTree(err, unimplementedCode)
+	return unimplemented
+}
+
+// unimplementedCode reports whether err, shaped like one of the errors listed
+// by IsUnimplemented, has the Unimplemented code. ok is false if err has none
+// of those shapes.
+func unimplementedCode(err error) (unimplemented, ok bool) {
+	v := reflect.ValueOf(err)
+	if code, ok := callGetter(v, "Code"); ok {
+		switch {
+		case code.Kind() == reflect.String:
+			return code.String() == twirpUnimplemented, true
+		case code.CanUint():
+			return code.Uint() == statusUnimplemented, true
+		}
+	}
+	if st, ok := callGetter(v, "GRPCStatus"); ok {
+		if code, ok := callGetter(st, "Code"); ok && code.CanUint() {
+			return code.Uint() == statusUnimplemented, true
+		}
+	}
+	return false, false
+}
+
+// callGetter calls the method name of v if it takes no arguments and returns
+// a single value.
+func callGetter(v reflect.Value, name string) (reflect.Value, bool) {
+	m := v.MethodByName(name)
+	if !m.IsValid() {
+		return reflect.Value{}, false
+	}
+	if t := m.Type(); t.NumIn() != 0 || t.NumOut() != 1 {
+		return reflect.Value{}, false
+	}
+	return m.Call(nil)[0], true
+}
+
+// findInErrorTree calls match on err and the errors it wraps, depth first,
+// and returns the result of the first call reporting ok.
+func findInErrorTree[T any](err error, match func(error) (T, bool)) (T, bool) {
+	for err != nil {
+		if v, ok := match(err); ok {
+			return v, true
+		}
+		switch u := err.(type) {
+		case interface{ Unwrap() error }:
+			err = u.Unwrap()
+		case interface{ Unwrap() []error }:
+			for _, e := range u.Unwrap() {
+				if v, ok := findInErrorTree(e, match); ok {
+					return v, true
+				}
+			}
+			err = nil
+		default:
+			err = nil
+		}
+	}
+	var zero T
+	return zero, false
+}
+
+// SetUnimplementedFallback enables or disables falling back to the next
+// protocol on Unimplemented in the default Runtime.
+// See Runtime.SetUnimplementedFallback.
+func SetUnimplementedFallback(enabled bool) {
+	defaultRuntime.SetUnimplementedFallback(enabled)
+}
+
+// SetUnimplementedFallback enables or disables falling back to the next
+// protocol when a handler returns Unimplemented.
+//
+// When enabled, a multi-protocol unary adaptor whose handler fails with an
+// error for which IsUnimplemented is true retries the call on the next
+// protocol in the fallback order that has a handler registered. If none is
+// left, the last Unimplemented error is returned. Calls whose ctx carries an
+// explicit protocol and streaming calls are never retried. Disabled by default.
+func (rt *Runtime) SetUnimplementedFallback(enabled bool) {
+	rt.unimplementedFallback.Store(enabled)
+}
+
+// UnimplementedFallback reports whether falling back on Unimplemented is enabled in rt.
+func (rt *Runtime) UnimplementedFallback() bool {
+	return rt.unimplementedFallback.Load()
+}
+
+// FallbackOnUnimplemented reports whether a generated adaptor should retry a
+// call that failed with err on the next protocol.
+func (rt *Runtime) FallbackOnUnimplemented(ctx context.Context, err error) bool {
+	if !rt.UnimplementedFallback() || !IsUnimplemented(err) {
+		return false
+	}
+	_, explicit := ProtocolFromContext(ctx)
+	return !explicit
+}
@@ -0,0 +1,70 @@
+package rpcruntime
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"connectrpc.com/connect"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// fakeTwirpCode and fakeTwirpError mirror twirp.ErrorCode and twirp.Error.
+type fakeTwirpCode string
+
+type fakeTwirpError struct{ code fakeTwirpCode }
+
+func (e fakeTwirpError) Code() fakeTwirpCode { return e.code }
+func (e fakeTwirpError) Msg() string         { return "twirp error" }
+func (e fakeTwirpError) Error() string       { return "twirp error " + string(e.code) }
+
+func TestIsUnimplemented(t *testing.T) {
+	cases := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"nil", nil, false},
+		{"grpc", status.Error(codes.Unimplemented, "method Ping not implemented"), true},
+		{"grpc wrapped", fmt.Errorf("call: %w", status.Error(codes.Unimplemented, "")), true},
+		{"grpc other code", status.Error(codes.Internal, "boom"), false},
+		{"connect", connect.NewError(connect.CodeUnimplemented, errors.New("not implemented")), true},
+		{"connect wrapped", fmt.Errorf("call: %w", connect.NewError(connect.CodeUnimplemented, nil)), true},
+		{"connect other code", connect.NewError(connect.CodeUnavailable, nil), false},
+		{"twirp", fakeTwirpError{code: "unimplemented"}, true},
+		{"twirp wrapped", fmt.Errorf("call: %w", fakeTwirpError{code: "unimplemented"}), true},
+		{"twirp joined", errors.Join(errors.New("other"), fakeTwirpError{code: "unimplemented"}), true},
+		{"twirp other code", fakeTwirpError{code: "not_found"}, false},
+		{"plain", errors.New("unimplemented"), false},
+	}
+	for _, tc := range cases {
+		if got := IsUnimplemented(tc.err); got != tc.want {
+			t.Errorf("%s: IsUnimplemented = %v, want %v", tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestFallbackOnUnimplemented(t *testing.T) {
+	rt := NewRuntime()
+	err := status.Error(codes.Unimplemented, "method Ping not implemented")
+
+	if rt.FallbackOnUnimplemented(context.Background(), err) {
+		t.Error("fallback must be disabled by default")
+	}
+
+	rt.SetUnimplementedFallback(true)
+	if !rt.UnimplementedFallback() {
+		t.Fatal("expected fallback to be enabled")
+	}
+	if !rt.FallbackOnUnimplemented(context.Background(), err) {
+		t.Error("expected fallback for Unimplemented")
+	}
+	if rt.FallbackOnUnimplemented(context.Background(), errors.New("boom")) {
+		t.Error("other errors must not fall back")
+	}
+	if rt.FallbackOnUnimplemented(WithProtocol(context.Background(), ProtocolGrpc), err) {
+		t.Error("calls with an explicit protocol must not fall back")
+	}
+}
@@ -0,0 +1,275 @@
+package rpcruntime
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+// UnknownServiceHandler serves unary calls of methods that have no handler,
+// e.g. to proxy or stub services the binary was not built with. reqBytes and
+// the returned response are protobuf wire-format messages.
+type UnknownServiceHandler func(ctx context.Context, fullMethod string, reqBytes []byte) ([]byte, error)
+
+// UnknownStreamHandler serves streaming calls of methods that have no
+// handler. See UnknownServiceStream.
+type UnknownStreamHandler func(ctx context.Context, fullMethod string, stream UnknownServiceStream) error
+
+// UnknownServiceStream is a streaming call served by an UnknownStreamHandler.
+// Messages are in protobuf wire format.
+//
+// For server-streaming calls Recv returns the single request; for
+// client-streaming calls the first message sent is the response and later
+// sends return io.EOF.
+type UnknownServiceStream interface {
+	// Context returns the context of the call.
+	Context() context.Context
+
+	// Recv returns the next request message, or io.EOF once the caller has
+	// finished sending.
+	Recv() ([]byte, error)
+
+	// Send delivers a response message to the caller. It returns
+	// context.Canceled once the caller stops receiving.
+	Send([]byte) error
+}
+
+// SetUnknownServiceHandler sets the unary unknown-service handler of the
+// default Runtime. See Runtime.SetUnknownServiceHandler.
+func SetUnknownServiceHandler(h UnknownServiceHandler) {
+	defaultRuntime.SetUnknownServiceHandler(h)
+}
+
+// SetUnknownServiceHandler sets the handler serving unary calls that would
+// otherwise fail because no handler is registered, like
+// grpc.UnknownServiceHandler. A nil h removes it.
+//
+// It is called by generated adaptors whose lookup fails with
+// ErrServiceNotRegistered and no remote is registered for the service (see
+// RegisterRemote), and by grpcrt.ClientConn, connectrt.HTTPHandler and
+// grpcrt.NewServer for methods missing from the method table.
+func (rt *Runtime) SetUnknownServiceHandler(h UnknownServiceHandler) {
+	rt.unknownMu.Lock()
+	defer rt.unknownMu.Unlock()
+	rt.unknownService = h
+}
+
+// SetUnknownStreamHandler sets the streaming unknown-service handler of the
+// default Runtime. See Runtime.SetUnknownStreamHandler.
+func SetUnknownStreamHandler(h UnknownStreamHandler) {
+	defaultRuntime.SetUnknownStreamHandler(h)
+}
+
+// SetUnknownStreamHandler sets the handler serving streaming calls that would
+// otherwise fail because no handler is registered. It is called in the same
+// places as the handler set with SetUnknownServiceHandler. A nil h removes it.
+//
+// grpcrt.NewServer cannot tell unary from streaming calls of unknown methods;
+// it hands them to h if set and to the unary handler otherwise. Likewise
+// connectrt.HTTPHandler serves Connect unary requests with the unary handler
+// and all other requests of unknown methods with h.
+func (rt *Runtime) SetUnknownStreamHandler(h UnknownStreamHandler) {
+	rt.unknownMu.Lock()
+	defer rt.unknownMu.Unlock()
+	rt.unknownStream = h
+}
+
+func (rt *Runtime) unknownHandlers() (UnknownServiceHandler, UnknownStreamHandler) {
+	rt.unknownMu.RLock()
+	defer rt.unknownMu.RUnlock()
+	return rt.unknownService, rt.unknownStream
+}
+
+// unknownServiceRemote is the Remote RemoteFallback selects to hand a call to
+// the unknown-service handlers of the Runtime it runs in.
+var unknownServiceRemote = &Remote{unknown: true}
+
+// unknownStreamHandlerFor returns the unknown stream handler of the Runtime
+// ctx runs in, or ErrServiceNotRegistered if there is none.
+func unknownStreamHandlerFor(ctx context.Context) (UnknownStreamHandler, error) {
+	_, h := RuntimeFromContext(ctx).unknownHandlers()
+	if h == nil {
+		return nil, ErrServiceNotRegistered
+	}
+	return h, nil
+}
+
+type unknownServiceStream struct {
+	ctx  context.Context
+	recv func() ([]byte, error)
+	send func([]byte) error
+}
+
+func (s *unknownServiceStream) Context() context.Context { return s.ctx }
+func (s *unknownServiceStream) Recv() ([]byte, error)    { return s.recv() }
+func (s *unknownServiceStream) Send(b []byte) error      { return s.send(b) }
+
+// recvSession returns a Recv func reading the messages sent on session.
+func recvSession[Req any](session StreamSession) func() ([]byte, error) {
+	in := &goStream[Req, struct{}]{session: session}
+	return func() ([]byte, error) {
+		req, err := in.Recv()
+		if err != nil {
+			return nil, err
+		}
+		return marshalMessage(req)
+	}
+}
+
+// sendSession returns a Send func delivering messages to session's onRead.
+func sendSession[Res any](session StreamSession) func([]byte) error {
+	return func(b []byte) error {
+		resp := new(Res)
+		if err := unmarshalMessage(b, resp); err != nil {
+			return err
+		}
+		if onRead := session.OnRead(); onRead != nil && !onRead(resp) {
+			return context.Canceled
+		}
+		return nil
+	}
+}
+
+func forwardUnknownUnary[Req, Res any](ctx context.Context, fullMethod string, req *Req) (*Res, error) {
+	h, _ := RuntimeFromContext(ctx).unknownHandlers()
+	if h == nil {
+		return nil, ErrServiceNotRegistered
+	}
+	reqBytes, err := marshalMessage(req)
+	if err != nil {
+		return nil, err
+	}
+	respBytes, err := h(ctx, fullMethod, reqBytes)
+	if err != nil {
+		return nil, err
+	}
+	resp := new(Res)
+	if err := unmarshalMessage(respBytes, resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func forwardUnknownClientStream[Req, Res any](ctx context.Context, session StreamSession, fullMethod string) (*Res, error) {
+	h, err := unknownStreamHandlerFor(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var resp *Res
+	stream := &unknownServiceStream{
+		ctx:  ctx,
+		recv: recvSession[Req](session),
+		send: func(b []byte) error {
+			if resp != nil {
+				return io.EOF
+			}
+			r := new(Res)
+			if err := unmarshalMessage(b, r); err != nil {
+				return err
+			}
+			resp = r
+			return nil
+		},
+	}
+	if err := h(ctx, fullMethod, stream); err != nil {
+		return nil, err
+	}
+	if resp == nil {
+		return nil, fmt.Errorf("rpcruntime: unknown stream handler sent no response for %s", fullMethod)
+	}
+	return resp, nil
+}
+
+func forwardUnknownServerStream[Req, Res any](ctx context.Context, session StreamSession, fullMethod string, req *Req) error {
+	h, err := unknownStreamHandlerFor(ctx)
+	if err != nil {
+		return err
+	}
+	received := false
+	stream := &unknownServiceStream{
+		ctx: ctx,
+		recv: func() ([]byte, error) {
+			if received {
+				return nil, io.EOF
+			}
+			received = true
+			return marshalMessage(req)
+		},
+		send: sendSession[Res](session),
+	}
+	return h(ctx, fullMethod, stream)
+}
+
+func forwardUnknownBidiStream[Req, Res any](ctx context.Context, session StreamSession, fullMethod string) error {
+	h, err := unknownStreamHandlerFor(ctx)
+	if err != nil {
+		return err
+	}
+	stream := &unknownServiceStream{
+		ctx:  ctx,
+		recv: recvSession[Req](session),
+		send: sendSession[Res](session),
+	}
+	return h(ctx, fullMethod, stream)
+}
+
+// UnknownMethodDesc describes fullMethod, missing from the method table, as a
+// method of the given kind served by the unknown-service handlers of rt. ok is
+// false if rt has no handler for kind.
+//
+// Its messages are *emptypb.Empty, which carries any wire-format payload as
+// unknown fields and marshals back to the same bytes.
+func (rt *Runtime) UnknownMethodDesc(fullMethod string, kind MethodKind) (desc MethodDesc, ok bool) {
+	unary, stream := rt.unknownHandlers()
+	remote := unknownServiceRemote
+	if kind == MethodKindUnary {
+		if unary == nil {
+			return MethodDesc{}, false
+		}
+		return NewUnaryMethodDesc(fullMethod, func(ctx context.Context, req *emptypb.Empty) (*emptypb.Empty, error) {
+			return ForwardUnary[emptypb.Empty, emptypb.Empty](ctx, remote, fullMethod, req)
+		}), true
+	}
+	if stream == nil {
+		return MethodDesc{}, false
+	}
+
+	send := func(handle uint64, req *emptypb.Empty) error {
+		return SendToStream(StreamHandle(handle), req)
+	}
+	switch kind {
+	case MethodKindClientStream:
+		return NewClientStreamMethodDesc(
+			fullMethod,
+			func(ctx context.Context) (uint64, error) {
+				return ForwardClientStream[emptypb.Empty, emptypb.Empty](ctx, remote, fullMethod)
+			},
+			send,
+			func(handle uint64) (*emptypb.Empty, error) {
+				resp, err := FinishClientStream(StreamHandle(handle))
+				if err != nil {
+					return nil, err
+				}
+				return resp.(*emptypb.Empty), nil
+			},
+		), true
+	case MethodKindServerStream:
+		return NewServerStreamMethodDesc(
+			fullMethod,
+			func(ctx context.Context, req *emptypb.Empty, onRead func(*emptypb.Empty) bool, onDone func(error)) error {
+				return ForwardServerStream(ctx, remote, fullMethod, req, onRead, onDone)
+			},
+		), true
+	default:
+		return NewBidiStreamMethodDesc(
+			fullMethod,
+			func(ctx context.Context, onRead func(*emptypb.Empty) bool, onDone func(error)) (uint64, error) {
+				return ForwardBidiStream[emptypb.Empty, emptypb.Empty](ctx, remote, fullMethod, onRead, onDone)
+			},
+			send,
+			func(handle uint64) error { return CloseSendCh(StreamHandle(handle)) },
+		), true
+	}
+}
@@ -0,0 +1,139 @@
+package rpcruntime
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+const unknownTestMethod = "/rpc.test.UnknownService/Echo"
+
+// newUnknownTestRuntime returns a Runtime whose unknown-service handlers
+// answer StringValue requests with "<method>:<value>" and echo stream messages
+// with an "echo:" prefix.
+func newUnknownTestRuntime() *Runtime {
+	rt := NewRuntime()
+	rt.SetUnknownServiceHandler(func(_ context.Context, fullMethod string, reqBytes []byte) ([]byte, error) {
+		req := &wrapperspb.StringValue{}
+		if err := proto.Unmarshal(reqBytes, req); err != nil {
+			return nil, err
+		}
+		return proto.Marshal(wrapperspb.String(fullMethod + ":" + req.GetValue()))
+	})
+	rt.SetUnknownStreamHandler(func(_ context.Context, _ string, stream UnknownServiceStream) error {
+		for {
+			b, err := stream.Recv()
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+			req := &wrapperspb.StringValue{}
+			if err := proto.Unmarshal(b, req); err != nil {
+				return err
+			}
+			resp, _ := proto.Marshal(wrapperspb.String("echo:" + req.GetValue()))
+			if err := stream.Send(resp); err != nil {
+				return err
+			}
+		}
+	})
+	return rt
+}
+
+// rawMessage returns msg in the wire format UnknownMethodDesc works with:
+// an empty message holding the fields of msg as unknown fields.
+func rawMessage(t *testing.T, msg proto.Message) *emptypb.Empty {
+	t.Helper()
+	b, err := proto.Marshal(msg)
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	raw := &emptypb.Empty{}
+	if err := proto.Unmarshal(b, raw); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	return raw
+}
+
+// stringValue decodes a raw message returned by an UnknownMethodDesc.
+func stringValue(t *testing.T, raw any) string {
+	t.Helper()
+	b, err := proto.Marshal(raw.(*emptypb.Empty))
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	v := &wrapperspb.StringValue{}
+	if err := proto.Unmarshal(b, v); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	return v.GetValue()
+}
+
+func TestUnknownMethodDescUnary(t *testing.T) {
+	rt := newUnknownTestRuntime()
+	desc, ok := rt.UnknownMethodDesc(unknownTestMethod, MethodKindUnary)
+	if !ok || desc.Kind() != MethodKindUnary || desc.FullMethod() != unknownTestMethod {
+		t.Fatalf("UnknownMethodDesc = %v, %v", desc.FullMethod(), ok)
+	}
+	resp, err := desc.Unary(WithRuntime(context.Background(), rt), rawMessage(t, wrapperspb.String("hi")))
+	if err != nil {
+		t.Fatalf("Unary failed: %v", err)
+	}
+	if got := stringValue(t, resp); got != unknownTestMethod+":hi" {
+		t.Errorf("unexpected reply %q", got)
+	}
+
+	if _, ok := NewRuntime().UnknownMethodDesc(unknownTestMethod, MethodKindUnary); ok {
+		t.Error("expected no method without an unknown-service handler")
+	}
+	if _, ok := rt.UnknownMethodDesc(unknownTestMethod, MethodKindUnary); !ok {
+		t.Error("expected the unary handler to stay set")
+	}
+	rt.SetUnknownStreamHandler(nil)
+	if _, ok := rt.UnknownMethodDesc(unknownTestMethod, MethodKindBidiStream); ok {
+		t.Error("expected no stream method without an unknown-stream handler")
+	}
+}
+
+func TestUnknownMethodDescBidiStream(t *testing.T) {
+	rt := newUnknownTestRuntime()
+	desc, ok := rt.UnknownMethodDesc(unknownTestMethod, MethodKindBidiStream)
+	if !ok || desc.Kind() != MethodKindBidiStream {
+		t.Fatal("expected a bidi-stream method")
+	}
+
+	var got []string
+	done := make(chan error, 1)
+	handle, err := desc.StartBidiStream(
+		WithRuntime(context.Background(), rt),
+		func(resp any) bool {
+			got = append(got, stringValue(t, resp))
+			return true
+		},
+		func(err error) { done <- err },
+	)
+	if err != nil {
+		t.Fatalf("StartBidiStream failed: %v", err)
+	}
+	for _, v := range []string{"a", "b"} {
+		if err := desc.Send(handle, rawMessage(t, wrapperspb.String(v))); err != nil {
+			t.Fatalf("Send failed: %v", err)
+		}
+	}
+	if err := desc.CloseSend(handle); err != nil {
+		t.Fatalf("CloseSend failed: %v", err)
+	}
+	if err := <-done; err != nil {
+		t.Fatalf("stream failed: %v", err)
+	}
+	if len(got) != 2 || got[0] != "echo:a" || got[1] != "echo:b" {
+		t.Errorf("unexpected replies %v", got)
+	}
+}